@@ -70,9 +70,9 @@ func main() {
 	}
 
 	ffmpegPath, ffprobePath := ffmpeg.GetPaths(nil)
-	encoder := ffmpeg.NewEncoder(ffmpegPath)
+	encoder := ffmpeg.NewEncoder(ffmpegPath, nil)
 	// don't need to InitHWSupport, phashing doesn't use hw acceleration
-	ffprobe := ffmpeg.FFProbe(ffprobePath)
+	ffprobe := ffmpeg.NewFFProbe(ffprobePath, nil)
 
 	for _, item := range args {
 		if err := printPhash(encoder, ffprobe, item, quiet); err != nil {
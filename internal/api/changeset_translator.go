@@ -346,6 +346,24 @@ func (t changesetTranslator) updateStashIDs(value []models.StashID, field string
 	}
 }
 
+// updateAutoTagRules returns the auto-tag rules to set, if the field is
+// present. A null value removes the rules.
+func (t changesetTranslator) updateAutoTagRules(value *models.AutoTagRules, field string) (*models.AutoTagRules, error) {
+	if !t.hasField(field) {
+		return nil, nil
+	}
+
+	if value == nil {
+		return &models.AutoTagRules{}, nil
+	}
+
+	if err := value.Validate(); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
 func (t changesetTranslator) relatedMovies(value []models.SceneMovieInput) (models.RelatedMovies, error) {
 	moviesScenes, err := models.MoviesScenesFromInput(value)
 	if err != nil {
@@ -93,6 +93,12 @@ func (r *Resolver) ImageFile() ImageFileResolver {
 func (r *Resolver) SavedFilter() SavedFilterResolver {
 	return &savedFilterResolver{r}
 }
+func (r *Resolver) PendingSceneChange() PendingSceneChangeResolver {
+	return &pendingSceneChangeResolver{r}
+}
+func (r *Resolver) SceneOverlap() SceneOverlapResolver {
+	return &sceneOverlapResolver{r}
+}
 func (r *Resolver) Plugin() PluginResolver {
 	return &pluginResolver{r}
 }
@@ -117,6 +123,8 @@ type galleryFileResolver struct{ *Resolver }
 type videoFileResolver struct{ *Resolver }
 type imageFileResolver struct{ *Resolver }
 type savedFilterResolver struct{ *Resolver }
+type pendingSceneChangeResolver struct{ *Resolver }
+type sceneOverlapResolver struct{ *Resolver }
 type pluginResolver struct{ *Resolver }
 type configResultResolver struct{ *Resolver }
 
@@ -23,3 +23,21 @@ func (r *configResultResolver) Plugins(ctx context.Context, obj *ConfigResult, i
 
 	return ret, nil
 }
+
+func (r *configResultResolver) Scrapers(ctx context.Context, obj *ConfigResult, include []string) (map[string]map[string]interface{}, error) {
+	if len(include) == 0 {
+		ret := config.GetInstance().GetAllScraperConfiguration()
+		return ret, nil
+	}
+
+	ret := make(map[string]map[string]interface{})
+
+	for _, scraper := range include {
+		c := config.GetInstance().GetScraperConfiguration(scraper)
+		if len(c) > 0 {
+			ret[scraper] = c
+		}
+	}
+
+	return ret, nil
+}
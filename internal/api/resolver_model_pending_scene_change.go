@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/api/loaders"
+	"github.com/stashapp/stash/internal/identify"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *pendingSceneChangeResolver) Scene(ctx context.Context, obj *models.PendingSceneChange) (*models.Scene, error) {
+	return loaders.From(ctx).SceneByID.Load(obj.SceneID)
+}
+
+func (r *pendingSceneChangeResolver) Fields(ctx context.Context, obj *models.PendingSceneChange) ([]*identify.ProposedField, error) {
+	p, err := identify.DecodeProposal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Fields, nil
+}
+
+func (r *pendingSceneChangeResolver) NewStudio(ctx context.Context, obj *models.PendingSceneChange) (*string, error) {
+	p, err := identify.DecodeProposal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.NewStudio, nil
+}
+
+func (r *pendingSceneChangeResolver) NewPerformers(ctx context.Context, obj *models.PendingSceneChange) ([]string, error) {
+	p, err := identify.DecodeProposal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.NewPerformers, nil
+}
+
+func (r *pendingSceneChangeResolver) NewTags(ctx context.Context, obj *models.PendingSceneChange) ([]string, error) {
+	p, err := identify.DecodeProposal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.NewTags, nil
+}
@@ -0,0 +1,16 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/api/loaders"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *sceneOverlapResolver) Scene(ctx context.Context, obj *models.SceneOverlap) (*models.Scene, error) {
+	return loaders.From(ctx).SceneByID.Load(obj.SceneID)
+}
+
+func (r *sceneOverlapResolver) OtherScene(ctx context.Context, obj *models.SceneOverlap) (*models.Scene, error) {
+	return loaders.From(ctx).SceneByID.Load(obj.OtherSceneID)
+}
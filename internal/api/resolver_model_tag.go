@@ -44,6 +44,18 @@ func (r *tagResolver) Aliases(ctx context.Context, obj *models.Tag) (ret []strin
 	return ret, err
 }
 
+func (r *tagResolver) StashIds(ctx context.Context, obj *models.Tag) (ret []*models.StashID, err error) {
+	var stashIDs []models.StashID
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		stashIDs, err = r.repository.Tag.GetStashIDs(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return stashIDsSliceToPtrSlice(stashIDs), nil
+}
+
 func (r *tagResolver) SceneCount(ctx context.Context, obj *models.Tag, depth *int) (ret int, err error) {
 	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
 		ret, err = scene.CountByTagID(ctx, r.repository.Scene, obj.ID, depth)
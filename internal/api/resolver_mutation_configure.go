@@ -6,12 +6,15 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"strconv"
 
 	"github.com/stashapp/stash/internal/manager"
 	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/file/remote"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
 )
 
 var ErrOverriddenConfig = errors.New("cannot set overridden value")
@@ -40,12 +43,26 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 					break
 				}
 			}
-			if isNew {
+			if s.Remote != nil {
+				// the password is not returned to clients, so keep the stored
+				// password if it is not set
+				if s.Remote.Password == "" {
+					s.Remote.Password = storedRemotePassword(existingPaths, s.Path)
+				}
+
+				// always validate remote storage, since the credentials may have changed
+				if err := validateRemoteStorage(*s.Remote); err != nil {
+					return makeConfigGeneralResult(), fmt.Errorf("validating remote storage of %s: %w", s.Path, err)
+				}
+			} else if isNew {
 				exists, err := fsutil.DirExists(s.Path)
 				if !exists {
 					return makeConfigGeneralResult(), err
 				}
 			}
+			if err := r.validateStashSettings(ctx, s.StashSettings); err != nil {
+				return makeConfigGeneralResult(), fmt.Errorf("validating settings of %s: %w", s.Path, err)
+			}
 		}
 		c.Set(config.Stash, input.Stashes)
 	}
@@ -194,6 +211,13 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 		c.Set(config.CalculateMD5, *input.CalculateMd5)
 	}
 
+	if input.PhashSegmentInterval != nil {
+		if *input.PhashSegmentInterval < 0 {
+			return makeConfigGeneralResult(), errors.New("phash segment interval must not be negative")
+		}
+		c.Set(config.PhashSegmentInterval, *input.PhashSegmentInterval)
+	}
+
 	if input.ParallelTasks != nil {
 		c.Set(config.ParallelTasks, *input.ParallelTasks)
 	}
@@ -329,6 +353,20 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 		c.Set(config.CreateGalleriesFromFolders, input.CreateGalleriesFromFolders)
 	}
 
+	refreshLibraryWatcher := input.Stashes != nil
+	if input.WatchLibrary != nil {
+		c.Set(config.WatchLibrary, input.WatchLibrary)
+		refreshLibraryWatcher = true
+	}
+	if input.WatchLibraryPoll != nil {
+		c.Set(config.WatchLibraryPoll, input.WatchLibraryPoll)
+		refreshLibraryWatcher = true
+	}
+	if input.WatchLibraryPollInterval != nil {
+		c.Set(config.WatchLibraryPollInterval, input.WatchLibraryPollInterval)
+		refreshLibraryWatcher = true
+	}
+
 	if input.CustomPerformerImageLocation != nil {
 		c.Set(config.CustomPerformerImageLocation, *input.CustomPerformerImageLocation)
 		initCustomPerformerImages(*input.CustomPerformerImageLocation)
@@ -395,10 +433,92 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 	if refreshPluginSource {
 		manager.GetInstance().RefreshPluginSourceManager()
 	}
+	if input.Stashes != nil {
+		manager.GetInstance().RefreshRemoteStorage()
+	}
+	if refreshLibraryWatcher {
+		manager.GetInstance().RefreshLibraryWatcher()
+	}
 
 	return makeConfigGeneralResult(), nil
 }
 
+// storedRemotePassword returns the stored remote storage password of the
+// library path, or an empty string if it has none.
+func storedRemotePassword(stashes config.StashConfigs, path string) string {
+	for _, s := range stashes {
+		if s.Path == path && s.Remote != nil {
+			return s.Remote.Password
+		}
+	}
+
+	return ""
+}
+
+// validateRemoteStorage returns an error if the root of the remote storage
+// cannot be read.
+func validateRemoteStorage(c remote.Config) error {
+	b, root, err := remote.Connect(c)
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	info, err := b.Stat(root)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", c.URL)
+	}
+
+	return nil
+}
+
+// validateStashSettings returns an error if the exclusion patterns of a
+// library path are invalid, or if its default tags or studio do not exist.
+func (r *mutationResolver) validateStashSettings(ctx context.Context, s config.StashSettings) error {
+	for _, p := range s.VideoExcludes {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("video exclusion pattern '%v' invalid: %w", p, err)
+		}
+	}
+	for _, p := range s.ImageExcludes {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("image/gallery exclusion pattern '%v' invalid: %w", p, err)
+		}
+	}
+
+	tagIDs, err := stringslice.StringSliceToIntSlice(s.DefaultTagIDs)
+	if err != nil {
+		return fmt.Errorf("converting default tag ids: %w", err)
+	}
+
+	var studioIDs []int
+	if s.DefaultStudioID != nil {
+		studioID, err := strconv.Atoi(*s.DefaultStudioID)
+		if err != nil {
+			return fmt.Errorf("converting default studio id: %w", err)
+		}
+		studioIDs = append(studioIDs, studioID)
+	}
+
+	if len(tagIDs) == 0 && len(studioIDs) == 0 {
+		return nil
+	}
+
+	return r.withReadTxn(ctx, func(ctx context.Context) error {
+		if _, err := r.repository.Tag.FindMany(ctx, tagIDs); err != nil {
+			return fmt.Errorf("finding default tags: %w", err)
+		}
+		if _, err := r.repository.Studio.FindMany(ctx, studioIDs); err != nil {
+			return fmt.Errorf("finding default studio: %w", err)
+		}
+		return nil
+	})
+}
+
 func (r *mutationResolver) ConfigureInterface(ctx context.Context, input ConfigInterfaceInput) (*ConfigInterfaceResult, error) {
 	c := config.GetInstance()
 
@@ -669,3 +789,14 @@ func (r *mutationResolver) ConfigurePlugin(ctx context.Context, pluginID string,
 
 	return c.GetPluginConfiguration(pluginID), nil
 }
+
+func (r *mutationResolver) ConfigureScraper(ctx context.Context, scraperID string, input map[string]interface{}) (map[string]interface{}, error) {
+	c := config.GetInstance()
+	c.SetScraperConfiguration(scraperID, input)
+
+	if err := c.Write(); err != nil {
+		return c.GetScraperConfiguration(scraperID), err
+	}
+
+	return c.GetScraperConfiguration(scraperID), nil
+}
@@ -8,15 +8,19 @@ import (
 	"github.com/stashapp/stash/internal/manager"
 	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/image"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/sliceutil"
 	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+	"github.com/stashapp/stash/pkg/utils"
 )
 
 func (r *mutationResolver) MoveFiles(ctx context.Context, input MoveFilesInput) (bool, error) {
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		fileStore := r.repository.File
 		folderStore := r.repository.Folder
-		mover := file.NewMover(fileStore, folderStore)
+		mover := manager.GetInstance().NewFileMover(fileStore, folderStore)
 		mover.RegisterHooks(ctx)
 
 		var (
@@ -149,7 +153,7 @@ func (r *mutationResolver) DeleteFiles(ctx context.Context, ids []string) (ret b
 		return false, fmt.Errorf("converting ids: %w", err)
 	}
 
-	fileDeleter := file.NewDeleter()
+	fileDeleter := manager.GetInstance().NewFileDeleter()
 	destroyer := &file.ZipDestroyer{
 		FileDestroyer:   r.repository.File,
 		FolderDestroyer: r.repository.Folder,
@@ -243,6 +247,12 @@ func (r *mutationResolver) FileSetFingerprints(ctx context.Context, input FileSe
 				v = vInt
 			}
 
+			if i.Type == models.FingerprintTypePhashSegments {
+				if _, err := utils.ParsePhashSegments(*i.Value); err != nil {
+					return false, fmt.Errorf("invalid phash segments: %w", err)
+				}
+			}
+
 			fingerprints = append(fingerprints, models.Fingerprint{
 				Type:        i.Type,
 				Fingerprint: v,
@@ -272,3 +282,438 @@ func (r *mutationResolver) FileSetFingerprints(ctx context.Context, input FileSe
 
 	return true, nil
 }
+
+func (r *mutationResolver) ResolveDuplicateFiles(ctx context.Context, input ResolveDuplicateFilesInput) (bool, error) {
+	if !input.Resolution.IsValid() {
+		return false, fmt.Errorf("invalid resolution %q", input.Resolution)
+	}
+
+	var fingerprintType string
+	if input.FingerprintType != nil {
+		fingerprintType = *input.FingerprintType
+		if !sliceutil.Contains(duplicateFingerprintTypes, fingerprintType) {
+			return false, fmt.Errorf("invalid fingerprint type %q", fingerprintType)
+		}
+	}
+
+	keepIDInt, err := strconv.Atoi(input.KeepID)
+	if err != nil {
+		return false, fmt.Errorf("converting keep id: %w", err)
+	}
+	keepID := models.FileID(keepIDInt)
+
+	fileIDs, err := stringslice.StringSliceToIntSlice(input.Ids)
+	if err != nil {
+		return false, fmt.Errorf("converting ids: %w", err)
+	}
+
+	mgr := manager.GetInstance()
+	fileDeleter := manager.GetInstance().NewFileDeleter()
+	d := &duplicateFileResolver{
+		mutationResolver: r,
+		sceneDeleter: &scene.FileDeleter{
+			Deleter:        fileDeleter,
+			FileNamingAlgo: mgr.Config.GetVideoFileNamingAlgorithm(),
+			Paths:          mgr.Paths,
+		},
+		imageDeleter: &image.FileDeleter{
+			Deleter: fileDeleter,
+			Paths:   mgr.Paths,
+		},
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		var (
+			files []models.File
+			err   error
+		)
+		d.keep, files, err = r.findDuplicateFilesToResolve(ctx, keepID, fileIDs, fingerprintType)
+		if err != nil {
+			return err
+		}
+
+		for _, f := range files {
+			switch input.Resolution {
+			case DuplicateFileResolutionDelete:
+				err = d.delete(ctx, f)
+			case DuplicateFileResolutionMerge:
+				err = d.merge(ctx, f)
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		fileDeleter.Rollback()
+		return false, err
+	}
+
+	// perform the post-commit actions
+	fileDeleter.Commit()
+
+	return true, nil
+}
+
+// findDuplicateFilesToResolve returns the kept file and the files to resolve.
+// An error is returned if any of the files does not share an exact hash, or
+// a fingerprint of fingerprintType, with the kept file.
+func (r *mutationResolver) findDuplicateFilesToResolve(ctx context.Context, keepID models.FileID, fileIDs []int, fingerprintType string) (models.File, []models.File, error) {
+	qb := r.repository.File
+
+	keep, err := qb.Find(ctx, keepID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("finding file %d: %w", keepID, err)
+	}
+	if len(keep) == 0 {
+		return nil, nil, fmt.Errorf("file with id %d not found", keepID)
+	}
+
+	var ret []models.File
+	for _, fileIDInt := range fileIDs {
+		fileID := models.FileID(fileIDInt)
+		if fileID == keepID {
+			continue
+		}
+
+		f, err := qb.Find(ctx, fileID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("finding file %d: %w", fileID, err)
+		}
+		if len(f) == 0 {
+			return nil, nil, fmt.Errorf("file with id %d not found", fileID)
+		}
+
+		if !sharesFingerprint(keep[0], f[0], fingerprintType) {
+			return nil, nil, fmt.Errorf("file %s does not share a fingerprint with %s", f[0].Base().Path, keep[0].Base().Path)
+		}
+
+		ret = append(ret, f[0])
+	}
+
+	return keep[0], ret, nil
+}
+
+// exactFingerprintTypes are the fingerprint types that identify files with
+// the same content.
+var exactFingerprintTypes = []string{
+	models.FingerprintTypeMD5,
+	models.FingerprintTypeOshash,
+}
+
+// sharesFingerprint returns true if a and b have the same exact hash, or the
+// same fingerprint of fingerprintType. Perceptual hashes only match if they
+// are of fingerprintType, since similar files may have the same phash.
+func sharesFingerprint(a, b models.File, fingerprintType string) bool {
+	for _, fp := range a.Base().Fingerprints {
+		if fp.Type != fingerprintType && !sliceutil.Contains(exactFingerprintTypes, fp.Type) {
+			continue
+		}
+
+		other := b.Base().Fingerprints.For(fp.Type)
+		if other != nil && other.Value() == fp.Value() {
+			return true
+		}
+	}
+
+	return false
+}
+
+type duplicateFileResolver struct {
+	*mutationResolver
+
+	keep         models.File
+	sceneDeleter *scene.FileDeleter
+	imageDeleter *image.FileDeleter
+}
+
+// otherFileID returns the ID of the file to use as the primary file in place of f.
+// The kept file is preferred if present in files.
+func (d *duplicateFileResolver) otherFileID(files []models.File, f models.File) models.FileID {
+	var ret models.FileID
+	for _, ff := range files {
+		id := ff.Base().ID
+		if id == d.keep.Base().ID {
+			return id
+		}
+		if ret == 0 && id != f.Base().ID {
+			ret = id
+		}
+	}
+
+	return ret
+}
+
+// delete deletes the file f, destroying the scenes, images and galleries that would
+// be left without files.
+func (d *duplicateFileResolver) delete(ctx context.Context, f models.File) error {
+	path := f.Base().Path
+	fileID := f.Base().ID
+
+	if f.Base().ZipFileID != nil {
+		return fmt.Errorf("cannot delete %s: file is in a zip file", path)
+	}
+
+	const (
+		deleteGenerated = true
+		deleteFile      = false
+	)
+
+	scenes, err := d.repository.Scene.FindByFileID(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("finding scenes for file %s: %w", path, err)
+	}
+
+	for _, s := range scenes {
+		if err := s.LoadFiles(ctx, d.repository.Scene); err != nil {
+			return err
+		}
+
+		var files []models.File
+		for _, vf := range s.Files.List() {
+			files = append(files, vf)
+		}
+
+		switch {
+		case len(files) == 1:
+			if err := d.sceneService.Destroy(ctx, s, d.sceneDeleter, deleteGenerated, deleteFile); err != nil {
+				return fmt.Errorf("destroying scene %d: %w", s.ID, err)
+			}
+		case s.PrimaryFileID != nil && *s.PrimaryFileID == fileID:
+			partial := models.NewScenePartial()
+			primaryID := d.otherFileID(files, f)
+			partial.PrimaryFileID = &primaryID
+			if _, err := d.repository.Scene.UpdatePartial(ctx, s.ID, partial); err != nil {
+				return fmt.Errorf("updating primary file of scene %d: %w", s.ID, err)
+			}
+		}
+	}
+
+	images, err := d.repository.Image.FindByFileID(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("finding images for file %s: %w", path, err)
+	}
+
+	for _, i := range images {
+		if err := i.LoadFiles(ctx, d.repository.Image); err != nil {
+			return err
+		}
+
+		files := i.Files.List()
+		switch {
+		case len(files) == 1:
+			if err := d.imageService.Destroy(ctx, i, d.imageDeleter, deleteGenerated, deleteFile); err != nil {
+				return fmt.Errorf("destroying image %d: %w", i.ID, err)
+			}
+		case i.PrimaryFileID != nil && *i.PrimaryFileID == fileID:
+			partial := models.NewImagePartial()
+			primaryID := d.otherFileID(files, f)
+			partial.PrimaryFileID = &primaryID
+			if _, err := d.repository.Image.UpdatePartial(ctx, i.ID, partial); err != nil {
+				return fmt.Errorf("updating primary file of image %d: %w", i.ID, err)
+			}
+		}
+	}
+
+	galleries, err := d.repository.Gallery.FindByFileID(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("finding galleries for file %s: %w", path, err)
+	}
+
+	for _, g := range galleries {
+		if err := g.LoadFiles(ctx, d.repository.Gallery); err != nil {
+			return err
+		}
+
+		files := g.Files.List()
+		switch {
+		case len(files) == 1:
+			if _, err := d.galleryService.Destroy(ctx, g, d.imageDeleter, deleteGenerated, deleteFile); err != nil {
+				return fmt.Errorf("destroying gallery %d: %w", g.ID, err)
+			}
+		case g.PrimaryFileID != nil && *g.PrimaryFileID == fileID:
+			partial := models.NewGalleryPartial()
+			primaryID := d.otherFileID(files, f)
+			partial.PrimaryFileID = &primaryID
+			if _, err := d.repository.Gallery.UpdatePartial(ctx, g.ID, partial); err != nil {
+				return fmt.Errorf("updating primary file of gallery %d: %w", g.ID, err)
+			}
+		}
+	}
+
+	// destroy images in the file if it is a zip file
+	if _, err := d.imageService.DestroyZipImages(ctx, f, d.imageDeleter, deleteGenerated); err != nil {
+		return fmt.Errorf("destroying images in %s: %w", path, err)
+	}
+
+	destroyer := &file.ZipDestroyer{
+		FileDestroyer:   d.repository.File,
+		FolderDestroyer: d.repository.Folder,
+	}
+
+	if err := destroyer.DestroyZip(ctx, f, d.sceneDeleter.Deleter, true); err != nil {
+		return fmt.Errorf("deleting file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// merge merges the scenes and images of the file f into those of the kept file.
+// The file f is not deleted.
+func (d *duplicateFileResolver) merge(ctx context.Context, f models.File) error {
+	// check for galleries before changing anything
+	galleries, err := d.repository.Gallery.FindByFileID(ctx, f.Base().ID)
+	if err != nil {
+		return fmt.Errorf("finding galleries for file %s: %w", f.Base().Path, err)
+	}
+
+	if len(galleries) > 0 {
+		return fmt.Errorf("cannot merge %s: merging galleries is not supported", f.Base().Path)
+	}
+
+	if err := d.mergeScenes(ctx, f); err != nil {
+		return err
+	}
+
+	return d.mergeImages(ctx, f)
+}
+
+// mergeDestinationIndex returns the index of the object to merge into, out
+// of the objects of the kept file with the given primary file IDs. This is
+// the only object, or else the only object whose primary file is the kept
+// file. Returns -1 if there is no such object.
+func (d *duplicateFileResolver) mergeDestinationIndex(primaryFileIDs []*models.FileID) int {
+	if len(primaryFileIDs) == 1 {
+		return 0
+	}
+
+	keepID := d.keep.Base().ID
+	ret := -1
+	for i, id := range primaryFileIDs {
+		if id != nil && *id == keepID {
+			if ret != -1 {
+				return -1
+			}
+			ret = i
+		}
+	}
+
+	return ret
+}
+
+func (d *duplicateFileResolver) mergeScenes(ctx context.Context, f models.File) error {
+	qb := d.repository.Scene
+
+	sources, err := qb.FindByFileID(ctx, f.Base().ID)
+	if err != nil {
+		return fmt.Errorf("finding scenes for file %s: %w", f.Base().Path, err)
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+
+	dests, err := qb.FindByFileID(ctx, d.keep.Base().ID)
+	if err != nil {
+		return fmt.Errorf("finding scenes for file %s: %w", d.keep.Base().Path, err)
+	}
+	if len(dests) == 0 {
+		return fmt.Errorf("cannot merge %s: %s has no scene", f.Base().Path, d.keep.Base().Path)
+	}
+
+	destIndex := d.mergeDestinationIndex(sliceutil.Map(dests, func(s *models.Scene) *models.FileID { return s.PrimaryFileID }))
+	if destIndex == -1 {
+		return fmt.Errorf("cannot merge %s: %s is in %d scenes, and is not the primary file of exactly one of them", f.Base().Path, d.keep.Base().Path, len(dests))
+	}
+
+	dest := dests[destIndex]
+
+	var (
+		sourceIDs    []int
+		tagIDs       []int
+		performerIDs []int
+		galleryIDs   []int
+	)
+
+	for _, s := range sources {
+		if s.ID == dest.ID {
+			continue
+		}
+
+		if err := s.LoadTagIDs(ctx, qb); err != nil {
+			return err
+		}
+		if err := s.LoadPerformerIDs(ctx, qb); err != nil {
+			return err
+		}
+		if err := s.LoadGalleryIDs(ctx, qb); err != nil {
+			return err
+		}
+
+		sourceIDs = append(sourceIDs, s.ID)
+		tagIDs = sliceutil.AppendUniques(tagIDs, s.TagIDs.List())
+		performerIDs = sliceutil.AppendUniques(performerIDs, s.PerformerIDs.List())
+		galleryIDs = sliceutil.AppendUniques(galleryIDs, s.GalleryIDs.List())
+	}
+
+	if len(sourceIDs) == 0 {
+		return nil
+	}
+
+	values := models.NewScenePartial()
+	values.TagIDs = &models.UpdateIDs{
+		IDs:  tagIDs,
+		Mode: models.RelationshipUpdateModeAdd,
+	}
+	values.PerformerIDs = &models.UpdateIDs{
+		IDs:  performerIDs,
+		Mode: models.RelationshipUpdateModeAdd,
+	}
+	values.GalleryIDs = &models.UpdateIDs{
+		IDs:  galleryIDs,
+		Mode: models.RelationshipUpdateModeAdd,
+	}
+
+	return d.sceneService.Merge(ctx, sourceIDs, dest.ID, values)
+}
+
+func (d *duplicateFileResolver) mergeImages(ctx context.Context, f models.File) error {
+	qb := d.repository.Image
+
+	sources, err := qb.FindByFileID(ctx, f.Base().ID)
+	if err != nil {
+		return fmt.Errorf("finding images for file %s: %w", f.Base().Path, err)
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+
+	dests, err := qb.FindByFileID(ctx, d.keep.Base().ID)
+	if err != nil {
+		return fmt.Errorf("finding images for file %s: %w", d.keep.Base().Path, err)
+	}
+	if len(dests) == 0 {
+		return fmt.Errorf("cannot merge %s: %s has no image", f.Base().Path, d.keep.Base().Path)
+	}
+
+	destIndex := d.mergeDestinationIndex(sliceutil.Map(dests, func(i *models.Image) *models.FileID { return i.PrimaryFileID }))
+	if destIndex == -1 {
+		return fmt.Errorf("cannot merge %s: %s is in %d images, and is not the primary file of exactly one of them", f.Base().Path, d.keep.Base().Path, len(dests))
+	}
+
+	dest := dests[destIndex]
+
+	var sourceIDs []int
+	for _, i := range sources {
+		if i.ID != dest.ID {
+			sourceIDs = append(sourceIDs, i.ID)
+		}
+	}
+
+	if len(sourceIDs) == 0 {
+		return nil
+	}
+
+	return d.imageService.Merge(ctx, sourceIDs, dest.ID)
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFindDuplicateFilesToResolve(t *testing.T) {
+	const (
+		keepID  = models.FileID(1)
+		sameID  = models.FileID(2)
+		phashID = models.FileID(3)
+	)
+
+	makeFile := func(id models.FileID, md5 string, phash int64) models.File {
+		return &models.BaseFile{
+			ID:   id,
+			Path: md5,
+			Fingerprints: models.Fingerprints{
+				{Type: models.FingerprintTypeMD5, Fingerprint: md5},
+				{Type: models.FingerprintTypePhash, Fingerprint: phash},
+			},
+		}
+	}
+
+	db := mocks.NewDatabase()
+	db.File.On("Find", mock.Anything, keepID).Return([]models.File{makeFile(keepID, "md5", 1)}, nil)
+	db.File.On("Find", mock.Anything, sameID).Return([]models.File{makeFile(sameID, "md5", 2)}, nil)
+	db.File.On("Find", mock.Anything, phashID).Return([]models.File{makeFile(phashID, "other", 1)}, nil)
+
+	r := &mutationResolver{newResolver(db)}
+
+	tests := []struct {
+		name            string
+		fileIDs         []int
+		fingerprintType string
+		wantIDs         []models.FileID
+		wantErr         bool
+	}{
+		{"same md5", []int{int(keepID), int(sameID)}, "", []models.FileID{sameID}, false},
+		{"same phash only", []int{int(phashID)}, "", nil, true},
+		{"same phash only found by md5", []int{int(sameID), int(phashID)}, models.FingerprintTypeMD5, nil, true},
+		{"same phash only found by phash", []int{int(phashID)}, models.FingerprintTypePhash, []models.FileID{phashID}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keep, files, err := r.findDuplicateFilesToResolve(testCtx, keepID, tt.fileIDs, tt.fingerprintType)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, keepID, keep.Base().ID)
+
+			var ids []models.FileID
+			for _, f := range files {
+				ids = append(ids, f.Base().ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
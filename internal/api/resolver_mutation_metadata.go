@@ -86,6 +86,26 @@ func (r *mutationResolver) MetadataAutoTag(ctx context.Context, input manager.Au
 	return strconv.Itoa(jobID), nil
 }
 
+func (r *mutationResolver) ExportAutoTagReport(ctx context.Context, format manager.AutoTagReportFormat) (string, error) {
+	downloadHash, name, err := manager.GetInstance().ExportAutoTagReport(format)
+	if err != nil {
+		return "", err
+	}
+
+	baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
+
+	return baseURL + "/downloads/" + downloadHash + "/" + name, nil
+}
+
+func (r *mutationResolver) ApplyAutoTagReport(ctx context.Context, input manager.ApplyAutoTagReportInput) (string, error) {
+	jobID, err := manager.GetInstance().ApplyAutoTagReport(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
 func (r *mutationResolver) MetadataIdentify(ctx context.Context, input identify.Options) (string, error) {
 	t := manager.CreateIdentifyJob(input)
 	jobID := manager.GetInstance().JobManager.Add(ctx, "Identifying...", t)
@@ -93,6 +113,20 @@ func (r *mutationResolver) MetadataIdentify(ctx context.Context, input identify.
 	return strconv.Itoa(jobID), nil
 }
 
+func (r *mutationResolver) MetadataIdentifyPerformers(ctx context.Context, input identify.PerformerOptions) (string, error) {
+	t := manager.CreateIdentifyPerformersJob(input)
+	jobID := manager.GetInstance().JobManager.Add(ctx, "Identifying performers...", t)
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataIdentifyStudios(ctx context.Context, input identify.StudioOptions) (string, error) {
+	t := manager.CreateIdentifyStudiosJob(input)
+	jobID := manager.GetInstance().JobManager.Add(ctx, "Identifying studios...", t)
+
+	return strconv.Itoa(jobID), nil
+}
+
 func (r *mutationResolver) MetadataClean(ctx context.Context, input manager.CleanMetadataInput) (string, error) {
 	jobID := manager.GetInstance().Clean(ctx, input)
 	return strconv.Itoa(jobID), nil
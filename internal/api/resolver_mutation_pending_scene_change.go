@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/internal/identify"
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+func (r *mutationResolver) AcceptPendingSceneChanges(ctx context.Context, input AcceptPendingSceneChangesInput) (bool, error) {
+	ids, err := stringslice.StringSliceToIntSlice(input.Ids)
+	if err != nil {
+		return false, fmt.Errorf("converting ids: %w", err)
+	}
+
+	var changes []*models.PendingSceneChange
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		changes, err = r.repository.PendingSceneChange.FindMany(ctx, ids)
+		return err
+	}); err != nil {
+		return false, err
+	}
+
+	// fetch images before starting the transaction
+	proposals := make([]*identify.Proposal, len(changes))
+	for i, c := range changes {
+		proposals[i], err = identify.DecodeProposal(c)
+		if err != nil {
+			return false, err
+		}
+
+		proposals[i].FetchImages(ctx, input.Fields)
+	}
+
+	identifier := identify.SceneIdentifier{
+		TxnManager:                  r.repository.TxnManager,
+		SceneReaderUpdater:          r.repository.Scene,
+		StudioReaderWriter:          r.repository.Studio,
+		PerformerCreator:            r.repository.Performer,
+		TagFinderCreator:            r.repository.Tag,
+		MovieCreator:                r.repository.Movie,
+		SceneMarkerFinderCreator:    r.repository.SceneMarker,
+		SceneUpdatePostHookExecutor: manager.GetInstance().PluginCache,
+
+		FingerprintSubmissionUpdater: r.repository.FingerprintSubmission,
+	}
+
+	// all changes are applied, or none are
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		for i, c := range changes {
+			scene, err := r.repository.Scene.Find(ctx, c.SceneID)
+			if err != nil {
+				return err
+			}
+
+			if scene == nil {
+				return fmt.Errorf("scene with id %d not found", c.SceneID)
+			}
+
+			if err := identifier.ApplyProposal(ctx, scene, c.Source, proposals[i], input.Fields); err != nil {
+				return fmt.Errorf("applying pending scene change %d: %w", c.ID, err)
+			}
+		}
+
+		return r.repository.PendingSceneChange.Destroy(ctx, ids)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) RejectPendingSceneChanges(ctx context.Context, ids []string) (bool, error) {
+	idInts, err := stringslice.StringSliceToIntSlice(ids)
+	if err != nil {
+		return false, fmt.Errorf("converting ids: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		return r.repository.PendingSceneChange.Destroy(ctx, idInts)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -58,6 +58,11 @@ func (r *mutationResolver) PerformerCreate(ctx context.Context, input models.Per
 	newPerformer.IgnoreAutoTag = translator.bool(input.IgnoreAutoTag)
 	newPerformer.StashIDs = models.NewRelatedStashIDs(input.StashIds)
 
+	if err := input.AutoTagRules.Validate(); err != nil {
+		return nil, err
+	}
+	newPerformer.AutoTagRules = input.AutoTagRules
+
 	var err error
 
 	newPerformer.Birthdate, err = translator.datePtr(input.Birthdate)
@@ -149,6 +154,11 @@ func (r *mutationResolver) PerformerUpdate(ctx context.Context, input models.Per
 	updatedPerformer.IgnoreAutoTag = translator.optionalBool(input.IgnoreAutoTag, "ignore_auto_tag")
 	updatedPerformer.StashIDs = translator.updateStashIDs(input.StashIds, "stash_ids")
 
+	updatedPerformer.AutoTagRules, err = translator.updateAutoTagRules(input.AutoTagRules, "auto_tag_rules")
+	if err != nil {
+		return nil, err
+	}
+
 	updatedPerformer.Birthdate, err = translator.optionalDate(input.Birthdate, "birthdate")
 	if err != nil {
 		return nil, fmt.Errorf("converting birthdate: %w", err)
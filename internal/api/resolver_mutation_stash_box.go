@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/stashapp/stash/internal/manager"
 	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/scraper/stashbox"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
 )
 
 func (r *mutationResolver) SubmitStashBoxFingerprints(ctx context.Context, input StashBoxFingerprintSubmissionInput) (bool, error) {
@@ -19,8 +22,50 @@ func (r *mutationResolver) SubmitStashBoxFingerprints(ctx context.Context, input
 	}
 
 	client := stashbox.NewClient(*boxes[input.StashBoxIndex], r.stashboxRepository())
+	endpoint := boxes[input.StashBoxIndex].Endpoint
 
-	return client.SubmitStashBoxFingerprints(ctx, input.SceneIds, boxes[input.StashBoxIndex].Endpoint)
+	submitted, err := client.SubmitStashBoxFingerprints(ctx, input.SceneIds, endpoint)
+	if err != nil || !submitted {
+		return submitted, err
+	}
+
+	ids, err := stringslice.StringSliceToIntSlice(input.SceneIds)
+	if err != nil {
+		return false, err
+	}
+
+	// mark the queued submissions of the scenes as submitted
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.FingerprintSubmission
+		now := time.Now()
+
+		for _, id := range ids {
+			submissions, err := qb.FindBySceneID(ctx, id)
+			if err != nil {
+				return err
+			}
+
+			for _, s := range submissions {
+				if s.Endpoint != endpoint || s.Status == models.FingerprintSubmissionStatusSubmitted {
+					continue
+				}
+
+				s.Status = models.FingerprintSubmissionStatusSubmitted
+				s.Error = ""
+				s.LastAttemptAt = &now
+				s.UpdatedAt = now
+				if err := qb.Update(ctx, s); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
 
 func (r *mutationResolver) StashBoxBatchPerformerTag(ctx context.Context, input manager.StashBoxBatchTagInput) (string, error) {
@@ -107,3 +152,182 @@ func (r *mutationResolver) SubmitStashBoxPerformerDraft(ctx context.Context, inp
 
 	return res, err
 }
+
+func (r *mutationResolver) StashBoxSync(ctx context.Context, input manager.StashBoxSyncInput) (string, error) {
+	t, err := manager.CreateStashBoxSyncJob(input)
+	if err != nil {
+		return "", err
+	}
+
+	jobID := manager.GetInstance().JobManager.Add(ctx, "Syncing with stash-box...", t)
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) StashBoxImportTags(ctx context.Context, input manager.StashBoxTagImportInput) (string, error) {
+	t, err := manager.CreateStashBoxTagImportJob(input)
+	if err != nil {
+		return "", err
+	}
+
+	jobID := manager.GetInstance().JobManager.Add(ctx, "Importing tags from stash-box...", t)
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) StashBoxSubmitFingerprintQueue(ctx context.Context, input manager.StashBoxFingerprintQueueInput) (string, error) {
+	t, err := manager.CreateStashBoxFingerprintQueueJob(input)
+	if err != nil {
+		return "", err
+	}
+
+	jobID := manager.GetInstance().JobManager.Add(ctx, "Submitting queued fingerprints to stash-box...", t)
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) RequeueFingerprintSubmissions(ctx context.Context, input RequeueFingerprintSubmissionsInput) (bool, error) {
+	boxes := config.GetInstance().GetStashBoxes()
+
+	if input.StashBoxIndex < 0 || input.StashBoxIndex >= len(boxes) {
+		return false, fmt.Errorf("invalid stash_box_index %d", input.StashBoxIndex)
+	}
+
+	ids, err := stringslice.StringSliceToIntSlice(input.SceneIds)
+	if err != nil {
+		return false, fmt.Errorf("converting ids: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		return r.repository.FingerprintSubmission.Requeue(ctx, ids, boxes[input.StashBoxIndex].Endpoint)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) SubmitStashBoxStudioDraft(ctx context.Context, input StashBoxDraftSubmissionInput) (*string, error) {
+	boxes := config.GetInstance().GetStashBoxes()
+
+	if input.StashBoxIndex < 0 || input.StashBoxIndex >= len(boxes) {
+		return nil, fmt.Errorf("invalid stash_box_index %d", input.StashBoxIndex)
+	}
+
+	client := stashbox.NewClient(*boxes[input.StashBoxIndex], r.stashboxRepository())
+
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("converting id: %w", err)
+	}
+
+	var res *string
+	err = r.withReadTxn(ctx, func(ctx context.Context) error {
+		studio, err := r.repository.Studio.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if studio == nil {
+			return fmt.Errorf("studio with id %d not found", id)
+		}
+
+		res, err = client.SubmitStudioDraft(ctx, studio, boxes[input.StashBoxIndex].Endpoint)
+		return err
+	})
+
+	return res, err
+}
+
+func (r *mutationResolver) SubmitStashBoxSceneEdit(ctx context.Context, input StashBoxEditSubmissionInput) (*string, error) {
+	boxes := config.GetInstance().GetStashBoxes()
+
+	if input.StashBoxIndex < 0 || input.StashBoxIndex >= len(boxes) {
+		return nil, fmt.Errorf("invalid stash_box_index %d", input.StashBoxIndex)
+	}
+
+	client := stashbox.NewClient(*boxes[input.StashBoxIndex], r.stashboxRepository())
+
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("converting id: %w", err)
+	}
+
+	var res *string
+	err = r.withReadTxn(ctx, func(ctx context.Context) error {
+		scene, err := r.repository.Scene.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if scene == nil {
+			return fmt.Errorf("scene with id %d not found", id)
+		}
+
+		res, err = client.SubmitSceneEdit(ctx, scene, boxes[input.StashBoxIndex].Endpoint, input.Comment)
+		return err
+	})
+
+	return res, err
+}
+
+func (r *mutationResolver) SubmitStashBoxPerformerEdit(ctx context.Context, input StashBoxEditSubmissionInput) (*string, error) {
+	boxes := config.GetInstance().GetStashBoxes()
+
+	if input.StashBoxIndex < 0 || input.StashBoxIndex >= len(boxes) {
+		return nil, fmt.Errorf("invalid stash_box_index %d", input.StashBoxIndex)
+	}
+
+	client := stashbox.NewClient(*boxes[input.StashBoxIndex], r.stashboxRepository())
+
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("converting id: %w", err)
+	}
+
+	var res *string
+	err = r.withReadTxn(ctx, func(ctx context.Context) error {
+		performer, err := r.repository.Performer.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if performer == nil {
+			return fmt.Errorf("performer with id %d not found", id)
+		}
+
+		res, err = client.SubmitPerformerEdit(ctx, performer, boxes[input.StashBoxIndex].Endpoint, input.Comment)
+		return err
+	})
+
+	return res, err
+}
+
+func (r *mutationResolver) SubmitStashBoxStudioEdit(ctx context.Context, input StashBoxEditSubmissionInput) (*string, error) {
+	boxes := config.GetInstance().GetStashBoxes()
+
+	if input.StashBoxIndex < 0 || input.StashBoxIndex >= len(boxes) {
+		return nil, fmt.Errorf("invalid stash_box_index %d", input.StashBoxIndex)
+	}
+
+	client := stashbox.NewClient(*boxes[input.StashBoxIndex], r.stashboxRepository())
+
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("converting id: %w", err)
+	}
+
+	var res *string
+	err = r.withReadTxn(ctx, func(ctx context.Context) error {
+		studio, err := r.repository.Studio.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if studio == nil {
+			return fmt.Errorf("studio with id %d not found", id)
+		}
+
+		res, err = client.SubmitStudioEdit(ctx, studio, boxes[input.StashBoxIndex].Endpoint, input.Comment)
+		return err
+	})
+
+	return res, err
+}
@@ -40,6 +40,11 @@ func (r *mutationResolver) StudioCreate(ctx context.Context, input models.Studio
 	newStudio.Aliases = models.NewRelatedStrings(input.Aliases)
 	newStudio.StashIDs = models.NewRelatedStashIDs(input.StashIds)
 
+	if err := input.AutoTagRules.Validate(); err != nil {
+		return nil, err
+	}
+	newStudio.AutoTagRules = input.AutoTagRules
+
 	var err error
 
 	newStudio.ParentID, err = translator.intPtrFromString(input.ParentID)
@@ -107,6 +112,11 @@ func (r *mutationResolver) StudioUpdate(ctx context.Context, input models.Studio
 	updatedStudio.Aliases = translator.updateStrings(input.Aliases, "aliases")
 	updatedStudio.StashIDs = translator.updateStashIDs(input.StashIds, "stash_ids")
 
+	updatedStudio.AutoTagRules, err = translator.updateAutoTagRules(input.AutoTagRules, "auto_tag_rules")
+	if err != nil {
+		return nil, err
+	}
+
 	updatedStudio.ParentID, err = translator.optionalIntFromString(input.ParentID, "parent_id")
 	if err != nil {
 		return nil, fmt.Errorf("converting parent id: %w", err)
@@ -36,6 +36,11 @@ func (r *mutationResolver) TagCreate(ctx context.Context, input TagCreateInput)
 	newTag.Description = translator.string(input.Description)
 	newTag.IgnoreAutoTag = translator.bool(input.IgnoreAutoTag)
 
+	if err := input.AutoTagRules.Validate(); err != nil {
+		return nil, err
+	}
+	newTag.AutoTagRules = input.AutoTagRules
+
 	var err error
 
 	var parentIDs []int
@@ -94,6 +99,12 @@ func (r *mutationResolver) TagCreate(ctx context.Context, input TagCreateInput)
 			}
 		}
 
+		if len(input.StashIds) > 0 {
+			if err := qb.UpdateStashIDs(ctx, newTag.ID, stashIDsPtrSliceToSlice(input.StashIds)); err != nil {
+				return err
+			}
+		}
+
 		if len(parentIDs) > 0 {
 			if err := qb.UpdateParentTags(ctx, newTag.ID, parentIDs); err != nil {
 				return err
@@ -139,6 +150,11 @@ func (r *mutationResolver) TagUpdate(ctx context.Context, input TagUpdateInput)
 	updatedTag.IgnoreAutoTag = translator.optionalBool(input.IgnoreAutoTag, "ignore_auto_tag")
 	updatedTag.Description = translator.optionalString(input.Description, "description")
 
+	updatedTag.AutoTagRules, err = translator.updateAutoTagRules(input.AutoTagRules, "auto_tag_rules")
+	if err != nil {
+		return nil, err
+	}
+
 	var parentIDs []int
 	if translator.hasField("parent_ids") {
 		parentIDs, err = stringslice.StringSliceToIntSlice(input.ParentIds)
@@ -209,6 +225,12 @@ func (r *mutationResolver) TagUpdate(ctx context.Context, input TagUpdateInput)
 			}
 		}
 
+		if translator.hasField("stash_ids") {
+			if err := qb.UpdateStashIDs(ctx, tagID, stashIDsPtrSliceToSlice(input.StashIds)); err != nil {
+				return err
+			}
+		}
+
 		if parentIDs != nil {
 			if err := qb.UpdateParentTags(ctx, tagID, parentIDs); err != nil {
 				return err
@@ -93,6 +93,7 @@ func makeConfigGeneralResult() *ConfigGeneralResult {
 		BlobsStorage:                  config.GetBlobsStorage(),
 		CalculateMd5:                  config.IsCalculateMD5(),
 		VideoFileNamingAlgorithm:      config.GetVideoFileNamingAlgorithm(),
+		PhashSegmentInterval:          config.GetPhashSegmentInterval(),
 		ParallelTasks:                 config.GetParallelTasks(),
 		PreviewAudio:                  config.GetPreviewAudio(),
 		PreviewSegments:               config.GetPreviewSegments(),
@@ -118,6 +119,9 @@ func makeConfigGeneralResult() *ConfigGeneralResult {
 		ImageExtensions:               config.GetImageExtensions(),
 		GalleryExtensions:             config.GetGalleryExtensions(),
 		CreateGalleriesFromFolders:    config.GetCreateGalleriesFromFolders(),
+		WatchLibrary:                  config.GetWatchLibrary(),
+		WatchLibraryPoll:              config.GetWatchLibraryPoll(),
+		WatchLibraryPollInterval:      config.GetWatchLibraryPollInterval(),
 		Excludes:                      config.GetExcludes(),
 		ImageExcludes:                 config.GetImageExcludes(),
 		CustomPerformerImageLocation:  &customPerformerImageLocation,
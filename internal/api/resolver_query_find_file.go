@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil"
+)
+
+// duplicateFingerprintTypes are the fingerprint types that duplicate files
+// can be found by.
+var duplicateFingerprintTypes = []string{
+	models.FingerprintTypeMD5,
+	models.FingerprintTypeOshash,
+	models.FingerprintTypePhash,
+}
+
+func (r *queryResolver) FindDuplicateFiles(ctx context.Context, fingerprintType string, filter *models.FindFilterType) (ret *FindDuplicateFilesResultType, err error) {
+	if !sliceutil.Contains(duplicateFingerprintTypes, fingerprintType) {
+		return nil, fmt.Errorf("invalid fingerprint type %q", fingerprintType)
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		groups, count, err := r.repository.File.FindDuplicates(ctx, fingerprintType, filter)
+		if err != nil {
+			return err
+		}
+
+		ret = &FindDuplicateFilesResultType{
+			Count: count,
+		}
+		ret.Groups, err = r.newDuplicateFileGroups(ctx, fingerprintType, groups)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// newDuplicateFileGroups returns the groups with the scenes, images and
+// galleries of their files. The objects of all files are found at once.
+func (r *queryResolver) newDuplicateFileGroups(ctx context.Context, fingerprintType string, groups [][]models.File) ([]*DuplicateFileGroup, error) {
+	var fileIDs []models.FileID
+	for _, group := range groups {
+		for _, f := range group {
+			fileIDs = append(fileIDs, f.Base().ID)
+		}
+	}
+
+	if len(fileIDs) == 0 {
+		return nil, nil
+	}
+
+	scenes, err := r.repository.Scene.FindByFileIDs(ctx, fileIDs)
+	if err != nil {
+		return nil, fmt.Errorf("finding scenes: %w", err)
+	}
+	sceneFileIDs, err := r.repository.Scene.GetManyFileIDs(ctx, sliceutil.Map(scenes, func(s *models.Scene) int { return s.ID }))
+	if err != nil {
+		return nil, fmt.Errorf("finding scene files: %w", err)
+	}
+
+	images, err := r.repository.Image.FindByFileIDs(ctx, fileIDs)
+	if err != nil {
+		return nil, fmt.Errorf("finding images: %w", err)
+	}
+	imageFileIDs, err := r.repository.Image.GetManyFileIDs(ctx, sliceutil.Map(images, func(i *models.Image) int { return i.ID }))
+	if err != nil {
+		return nil, fmt.Errorf("finding image files: %w", err)
+	}
+
+	galleries, err := r.repository.Gallery.FindByFileIDs(ctx, fileIDs)
+	if err != nil {
+		return nil, fmt.Errorf("finding galleries: %w", err)
+	}
+	galleryFileIDs, err := r.repository.Gallery.GetManyFileIDs(ctx, sliceutil.Map(galleries, func(g *models.Gallery) int { return g.ID }))
+	if err != nil {
+		return nil, fmt.Errorf("finding gallery files: %w", err)
+	}
+
+	scenesByFile := groupByFileID(scenes, sceneFileIDs)
+	imagesByFile := groupByFileID(images, imageFileIDs)
+	galleriesByFile := groupByFileID(galleries, galleryFileIDs)
+
+	ret := make([]*DuplicateFileGroup, len(groups))
+	for i, group := range groups {
+		g := &DuplicateFileGroup{
+			Fingerprint: group[0].Base().Fingerprints.For(fingerprintType),
+		}
+
+		for _, f := range group {
+			id := f.Base().ID
+			g.Files = append(g.Files, &DuplicateFile{
+				File:      convertBaseFile(f),
+				Scenes:    scenesByFile[id],
+				Images:    imagesByFile[id],
+				Galleries: galleriesByFile[id],
+			})
+		}
+
+		ret[i] = g
+	}
+
+	return ret, nil
+}
+
+// groupByFileID returns the objects of each file, given the file IDs of
+// each object.
+func groupByFileID[T any](objs []T, fileIDs [][]models.FileID) map[models.FileID][]T {
+	ret := make(map[models.FileID][]T)
+	for i, o := range objs {
+		for _, id := range fileIDs[i] {
+			ret[id] = append(ret[id], o)
+		}
+	}
+
+	return ret
+}
@@ -85,6 +85,21 @@ func (r *queryResolver) FindImages(ctx context.Context, imageFilter *models.Imag
 	return ret, nil
 }
 
+func (r *queryResolver) FindDuplicateImages(ctx context.Context, distance *int) (ret [][]*models.Image, err error) {
+	dist := 0
+	if distance != nil {
+		dist = *distance
+	}
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Image.FindDuplicates(ctx, dist)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
 func (r *queryResolver) AllImages(ctx context.Context) (ret []*models.Image, err error) {
 	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
 		ret, err = r.repository.Image.All(ctx)
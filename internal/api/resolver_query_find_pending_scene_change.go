@@ -0,0 +1,27 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *queryResolver) FindPendingSceneChanges(ctx context.Context, filter *models.FindFilterType) (ret *FindPendingSceneChangesResultType, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		changes, total, err := r.repository.PendingSceneChange.Query(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		ret = &FindPendingSceneChangesResultType{
+			Count:               total,
+			PendingSceneChanges: changes,
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/scene"
 	"github.com/stashapp/stash/pkg/sliceutil"
+	"github.com/stashapp/stash/pkg/utils"
 )
 
 func (r *queryResolver) FindScene(ctx context.Context, id *string, checksum *string) (*models.Scene, error) {
@@ -214,7 +215,14 @@ func (r *queryResolver) ParseSceneFilenames(ctx context.Context, filter *models.
 	return ret, nil
 }
 
-func (r *queryResolver) FindDuplicateScenes(ctx context.Context, distance *int, durationDiff *float64) (ret [][]*models.Scene, err error) {
+// defaultMinSceneOverlap is the default minimum duration in seconds of overlaps between scenes.
+const defaultMinSceneOverlap = 30.
+
+func (r *queryResolver) FindDuplicateScenes(ctx context.Context, distance *int, durationDiff *float64, mode *DuplicateSceneMatchMode, minOverlap *float64) (ret [][]*models.Scene, err error) {
+	if mode != nil && *mode == DuplicateSceneMatchModePartial {
+		return r.findPartialDuplicateScenes(ctx, distance, minOverlap)
+	}
+
 	dist := 0
 	durDiff := -1.
 	if distance != nil {
@@ -233,6 +241,62 @@ func (r *queryResolver) FindDuplicateScenes(ctx context.Context, distance *int,
 	return ret, nil
 }
 
+func (r *queryResolver) findPartialDuplicateScenes(ctx context.Context, distance *int, minOverlap *float64) (ret [][]*models.Scene, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		overlaps, err := r.findSceneOverlaps(ctx, distance, minOverlap)
+		if err != nil {
+			return err
+		}
+
+		var groups []utils.SegmentOverlap
+		for _, o := range overlaps {
+			groups = append(groups, utils.SegmentOverlap{
+				ID:      o.SceneID,
+				OtherID: o.OtherSceneID,
+			})
+		}
+
+		for _, ids := range utils.GroupSegmentOverlaps(groups) {
+			scenes, err := r.repository.Scene.FindMany(ctx, ids)
+			if err != nil {
+				return err
+			}
+
+			ret = append(ret, scenes)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *queryResolver) FindSceneOverlaps(ctx context.Context, distance *int, minOverlap *float64) (ret []*models.SceneOverlap, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.findSceneOverlaps(ctx, distance, minOverlap)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *queryResolver) findSceneOverlaps(ctx context.Context, distance *int, minOverlap *float64) ([]*models.SceneOverlap, error) {
+	dist := 0
+	minOver := defaultMinSceneOverlap
+	if distance != nil {
+		dist = *distance
+	}
+	if minOverlap != nil {
+		minOver = *minOverlap
+	}
+
+	return r.repository.Scene.FindSegmentOverlaps(ctx, dist, minOver)
+}
+
 func (r *queryResolver) AllScenes(ctx context.Context) (ret []*models.Scene, err error) {
 	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
 		ret, err = r.repository.Scene.All(ctx)
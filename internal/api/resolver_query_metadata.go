@@ -4,8 +4,13 @@ import (
 	"context"
 
 	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/models"
 )
 
 func (r *queryResolver) SystemStatus(ctx context.Context) (*manager.SystemStatus, error) {
 	return manager.GetInstance().GetSystemStatus(), nil
 }
+
+func (r *queryResolver) AutoTagReport(ctx context.Context, filter *models.FindFilterType) (*manager.AutoTagReport, error) {
+	return manager.GetInstance().GetAutoTagReport(filter), nil
+}
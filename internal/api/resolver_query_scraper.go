@@ -165,14 +165,24 @@ func (r *queryResolver) ScrapeSingleScene(ctx context.Context, source scraper.So
 		var c scraper.ScrapedContent
 		var content []scraper.ScrapedContent
 
+		// an explicit fragment takes precedence, and scenes are only scraped by
+		// fingerprint if the scraper does not support fragments
 		switch {
-		case input.SceneID != nil:
-			c, err = r.scraperCache().ScrapeID(ctx, *source.ScraperID, sceneID, scraper.ScrapeContentTypeScene)
+		case input.SceneInput != nil:
+			c, err = r.scraperCache().ScrapeFragment(ctx, *source.ScraperID, scraper.Input{Scene: input.SceneInput})
 			if c != nil {
 				content = []scraper.ScrapedContent{c}
 			}
-		case input.SceneInput != nil:
-			c, err = r.scraperCache().ScrapeFragment(ctx, *source.ScraperID, scraper.Input{Scene: input.SceneInput})
+		case input.SceneID != nil && !r.scraperCache().SupportsSceneFragments(*source.ScraperID) && r.scraperCache().SupportsSceneFingerprints(*source.ScraperID):
+			var results [][]*scraper.ScrapedScene
+			results, err = r.scraperCache().ScrapeSceneFingerprints(ctx, *source.ScraperID, []int{sceneID})
+			if len(results) > 0 {
+				for _, s := range results[0] {
+					content = append(content, s)
+				}
+			}
+		case input.SceneID != nil:
+			c, err = r.scraperCache().ScrapeID(ctx, *source.ScraperID, sceneID, scraper.ScrapeContentTypeScene)
 			if c != nil {
 				content = []scraper.ScrapedContent{c}
 			}
@@ -219,7 +229,12 @@ func (r *queryResolver) ScrapeSingleScene(ctx context.Context, source scraper.So
 
 func (r *queryResolver) ScrapeMultiScenes(ctx context.Context, source scraper.Source, input ScrapeMultiScenesInput) ([][]*scraper.ScrapedScene, error) {
 	if source.ScraperID != nil {
-		return nil, ErrNotImplemented
+		sceneIDs, err := stringslice.StringSliceToIntSlice(input.SceneIds)
+		if err != nil {
+			return nil, err
+		}
+
+		return r.scraperCache().ScrapeSceneFingerprints(ctx, *source.ScraperID, sceneIDs)
 	} else if source.StashBoxIndex != nil {
 		client, err := r.getStashBoxClient(*source.StashBoxIndex)
 		if err != nil {
@@ -260,7 +275,20 @@ func (r *queryResolver) ScrapeSingleStudio(ctx context.Context, source scraper.S
 		return nil, nil
 	}
 
-	return nil, errors.New("stash_box_index must be set")
+	if source.ScraperID != nil {
+		if input.Query == nil {
+			return nil, fmt.Errorf("%w: query must be set", ErrInput)
+		}
+
+		content, err := r.scraperCache().ScrapeName(ctx, *source.ScraperID, *input.Query, scraper.ScrapeContentTypeStudio)
+		if err != nil {
+			return nil, err
+		}
+
+		return marshalScrapedStudios(content)
+	}
+
+	return nil, errors.New("scraper_id or stash_box_index must be set")
 }
 
 func (r *queryResolver) ScrapeSinglePerformer(ctx context.Context, source scraper.Source, input ScrapeSinglePerformerInput) ([]*models.ScrapedPerformer, error) {
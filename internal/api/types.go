@@ -13,3 +13,12 @@ func stashIDsSliceToPtrSlice(v []models.StashID) []*models.StashID {
 
 	return ret
 }
+
+func stashIDsPtrSliceToSlice(v []*models.StashID) []models.StashID {
+	ret := make([]models.StashID, len(v))
+	for i, vv := range v {
+		ret[i] = *vv
+	}
+
+	return ret
+}
@@ -38,6 +38,7 @@ func getGalleryFileTagger(s *models.Gallery, cache *match.Cache) tagger {
 		Type:    "gallery",
 		Name:    s.DisplayName(),
 		Path:    path,
+		Title:   s.Title,
 		trimExt: trimExt,
 		cache:   cache,
 	}
@@ -30,6 +30,7 @@ func getImageFileTagger(s *models.Image, cache *match.Cache) tagger {
 		Type:  "image",
 		Name:  s.DisplayName(),
 		Path:  s.Path,
+		Title: s.Title,
 		cache: cache,
 	}
 }
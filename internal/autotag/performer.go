@@ -35,6 +35,7 @@ func getPerformerTaggers(p *models.Performer, cache *match.Cache) []tagger {
 		ID:    p.ID,
 		Type:  "performer",
 		Name:  p.Name,
+		rules: p.AutoTagRules,
 		cache: cache,
 	}}
 
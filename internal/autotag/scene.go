@@ -30,6 +30,7 @@ func getSceneFileTagger(s *models.Scene, cache *match.Cache) tagger {
 		Type:  "scene",
 		Name:  s.DisplayName(),
 		Path:  s.Path,
+		Title: s.Title,
 		cache: cache,
 	}
 }
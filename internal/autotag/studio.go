@@ -64,14 +64,16 @@ func getStudioTagger(p *models.Studio, aliases []string, cache *match.Cache) []t
 		ID:    p.ID,
 		Type:  "studio",
 		Name:  p.Name,
+		rules: p.AutoTagRules,
 		cache: cache,
 	}}
 
 	for _, a := range aliases {
 		ret = append(ret, tagger{
-			ID:   p.ID,
-			Type: "studio",
-			Name: a,
+			ID:    p.ID,
+			Type:  "studio",
+			Name:  a,
+			rules: p.AutoTagRules,
 		})
 	}
 
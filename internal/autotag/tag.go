@@ -35,6 +35,7 @@ func getTagTaggers(p *models.Tag, aliases []string, cache *match.Cache) []tagger
 		ID:    p.ID,
 		Type:  "tag",
 		Name:  p.Name,
+		rules: p.AutoTagRules,
 		cache: cache,
 	}}
 
@@ -43,6 +44,7 @@ func getTagTaggers(p *models.Tag, aliases []string, cache *match.Cache) []tagger
 			ID:    p.ID,
 			Type:  "tag",
 			Name:  a,
+			rules: p.AutoTagRules,
 			cache: cache,
 		})
 	}
@@ -33,8 +33,12 @@ type tagger struct {
 	Type    string
 	Name    string
 	Path    string
+	Title   string
 	trimExt bool
 
+	// auto-tag rules of the performer, studio or tag being tagged
+	rules *models.AutoTagRules
+
 	cache *match.Cache
 }
 
@@ -52,7 +56,7 @@ func (t *tagger) addLog(otherType, otherName string) {
 }
 
 func (t *tagger) tagPerformers(ctx context.Context, performerReader models.PerformerAutoTagQueryer, addFunc addLinkFunc) error {
-	others, err := match.PathToPerformers(ctx, t.Path, performerReader, t.cache, t.trimExt)
+	others, err := match.PathToPerformers(ctx, t.Path, t.Title, performerReader, t.cache, t.trimExt)
 	if err != nil {
 		return err
 	}
@@ -73,7 +77,7 @@ func (t *tagger) tagPerformers(ctx context.Context, performerReader models.Perfo
 }
 
 func (t *tagger) tagStudios(ctx context.Context, studioReader models.StudioAutoTagQueryer, addFunc addLinkFunc) error {
-	studio, err := match.PathToStudio(ctx, t.Path, studioReader, t.cache, t.trimExt)
+	studio, err := match.PathToStudio(ctx, t.Path, t.Title, studioReader, t.cache, t.trimExt)
 	if err != nil {
 		return err
 	}
@@ -94,7 +98,7 @@ func (t *tagger) tagStudios(ctx context.Context, studioReader models.StudioAutoT
 }
 
 func (t *tagger) tagTags(ctx context.Context, tagReader models.TagAutoTagQueryer, addFunc addLinkFunc) error {
-	others, err := match.PathToTags(ctx, t.Path, tagReader, t.cache, t.trimExt)
+	others, err := match.PathToTags(ctx, t.Path, t.Title, tagReader, t.cache, t.trimExt)
 	if err != nil {
 		return err
 	}
@@ -115,7 +119,7 @@ func (t *tagger) tagTags(ctx context.Context, tagReader models.TagAutoTagQueryer
 }
 
 func (t *tagger) tagScenes(ctx context.Context, paths []string, sceneReader models.SceneQueryer, addFunc addSceneLinkFunc) error {
-	return match.PathToScenesFn(ctx, t.Name, paths, sceneReader, func(ctx context.Context, p *models.Scene) error {
+	return match.PathToScenesFn(ctx, t.Name, t.rules, paths, sceneReader, func(ctx context.Context, p *models.Scene) error {
 		added, err := addFunc(p)
 
 		if err != nil {
@@ -131,7 +135,7 @@ func (t *tagger) tagScenes(ctx context.Context, paths []string, sceneReader mode
 }
 
 func (t *tagger) tagImages(ctx context.Context, paths []string, imageReader models.ImageQueryer, addFunc addImageLinkFunc) error {
-	return match.PathToImagesFn(ctx, t.Name, paths, imageReader, func(ctx context.Context, p *models.Image) error {
+	return match.PathToImagesFn(ctx, t.Name, t.rules, paths, imageReader, func(ctx context.Context, p *models.Image) error {
 		added, err := addFunc(p)
 
 		if err != nil {
@@ -147,7 +151,7 @@ func (t *tagger) tagImages(ctx context.Context, paths []string, imageReader mode
 }
 
 func (t *tagger) tagGalleries(ctx context.Context, paths []string, galleryReader models.GalleryQueryer, addFunc addGalleryLinkFunc) error {
-	return match.PathToGalleriesFn(ctx, t.Name, paths, galleryReader, func(ctx context.Context, p *models.Gallery) error {
+	return match.PathToGalleriesFn(ctx, t.Name, t.rules, paths, galleryReader, func(ctx context.Context, p *models.Gallery) error {
 		added, err := addFunc(p)
 
 		if err != nil {
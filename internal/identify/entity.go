@@ -0,0 +1,131 @@
+package identify
+
+import (
+	"bytes"
+	"image"
+	"strings"
+
+	// register image formats for decoding image dimensions
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+// nameMatches returns true if the scraped name matches the name or one of
+// the aliases of an existing object, ignoring case.
+func nameMatches(scraped string, name string, aliases []string) bool {
+	scraped = strings.TrimSpace(scraped)
+	if scraped == "" {
+		return false
+	}
+
+	if strings.EqualFold(scraped, name) {
+		return true
+	}
+
+	for _, a := range aliases {
+		if strings.EqualFold(scraped, a) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getAliasesUpdate returns the update to make to the aliases of an object
+// with the given name, or nil if the aliases should not be changed. scraped
+// is a comma-separated list of aliases. Aliases matching the name are
+// excluded. MERGE adds any missing aliases, OVERWRITE replaces the existing
+// aliases.
+func getAliasesUpdate(strategy *FieldOptions, name string, existing []string, scraped *string) *models.UpdateStrings {
+	if scraped == nil || !shouldSetSingleValueField(strategy, false) {
+		return nil
+	}
+
+	var aliases []string
+	if isMergeStrategy(strategy) {
+		aliases = append(aliases, existing...)
+	}
+
+	for _, a := range stringslice.FromString(*scraped, ",") {
+		a = strings.TrimSpace(a)
+		if a == "" || nameMatches(a, name, aliases) {
+			continue
+		}
+
+		aliases = append(aliases, a)
+	}
+
+	if aliasesSame(existing, aliases) {
+		return nil
+	}
+
+	return &models.UpdateStrings{
+		Values: aliases,
+		Mode:   models.RelationshipUpdateModeSet,
+	}
+}
+
+func aliasesSame(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !strings.EqualFold(a[i], b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// shouldSetImage returns true if the existing image should be replaced with
+// the scraped image. IGNORE never sets the image and OVERWRITE always sets
+// it. Otherwise, the image is only set if there is no existing image, or if
+// the scraped image has a higher resolution than the existing image.
+func shouldSetImage(strategy *FieldOptions, existing []byte, scraped []byte) bool {
+	if len(scraped) == 0 || bytes.Equal(existing, scraped) {
+		return false
+	}
+
+	switch getFieldStrategy(strategy) {
+	case FieldStrategyIgnore:
+		return false
+	case FieldStrategyOverwrite:
+		return true
+	}
+
+	if len(existing) == 0 {
+		return true
+	}
+
+	scrapedPixels, ok := imagePixels(scraped)
+	if !ok {
+		// don't replace a valid image with one that can't be read
+		return false
+	}
+
+	existingPixels, ok := imagePixels(existing)
+	if !ok {
+		return true
+	}
+
+	return scrapedPixels > existingPixels
+}
+
+// imagePixels returns the number of pixels of the encoded image. Returns
+// false if the image cannot be decoded.
+func imagePixels(data []byte) (int, bool) {
+	c, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, false
+	}
+
+	return c.Width * c.Height, true
+}
@@ -0,0 +1,99 @@
+package identify
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func testPNG(width, height int) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
+func Test_getAliasesUpdate(t *testing.T) {
+	const name = "name"
+
+	var (
+		scraped      = "alias2, Alias1,NAME, alias3"
+		scrapedSame  = "alias1"
+		scrapedEmpty = ""
+		existing     = []string{"alias1"}
+		merge        = &FieldOptions{Strategy: FieldStrategyMerge}
+		overwrite    = &FieldOptions{Strategy: FieldStrategyOverwrite}
+		ignore       = &FieldOptions{Strategy: FieldStrategyIgnore}
+		setAliases   = func(v ...string) *models.UpdateStrings {
+			return &models.UpdateStrings{
+				Values: v,
+				Mode:   models.RelationshipUpdateModeSet,
+			}
+		}
+	)
+
+	tests := []struct {
+		name     string
+		strategy *FieldOptions
+		existing []string
+		scraped  *string
+		want     *models.UpdateStrings
+	}{
+		{"nil scraped", merge, existing, nil, nil},
+		{"ignore", ignore, existing, &scraped, nil},
+		{"default merge", nil, existing, &scraped, setAliases("alias1", "alias2", "alias3")},
+		{"merge", merge, existing, &scraped, setAliases("alias1", "alias2", "alias3")},
+		{"merge same", merge, existing, &scrapedSame, nil},
+		{"overwrite", overwrite, existing, &scraped, setAliases("alias2", "Alias1", "alias3")},
+		{"overwrite same", overwrite, existing, &scrapedSame, nil},
+		{"overwrite empty", overwrite, existing, &scrapedEmpty, setAliases()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getAliasesUpdate(tt.strategy, name, tt.existing, tt.scraped)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_shouldSetImage(t *testing.T) {
+	var (
+		small   = testPNG(10, 10)
+		large   = testPNG(20, 20)
+		invalid = []byte("invalid")
+
+		merge     = &FieldOptions{Strategy: FieldStrategyMerge}
+		overwrite = &FieldOptions{Strategy: FieldStrategyOverwrite}
+		ignore    = &FieldOptions{Strategy: FieldStrategyIgnore}
+	)
+
+	tests := []struct {
+		name     string
+		strategy *FieldOptions
+		existing []byte
+		scraped  []byte
+		want     bool
+	}{
+		{"no scraped image", merge, small, nil, false},
+		{"no existing image", merge, nil, small, true},
+		{"same image", overwrite, small, small, false},
+		{"ignore", ignore, nil, small, false},
+		{"overwrite smaller", overwrite, large, small, true},
+		{"default larger", nil, small, large, true},
+		{"merge larger", merge, small, large, true},
+		{"merge smaller", merge, large, small, false},
+		{"merge invalid existing", merge, invalid, small, true},
+		{"merge invalid scraped", merge, small, invalid, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := shouldSetImage(tt.strategy, tt.existing, tt.scraped)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,356 @@
+package identify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/gallery"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper"
+	"github.com/stashapp/stash/pkg/sliceutil"
+	"github.com/stashapp/stash/pkg/txn"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+type GalleryScraper interface {
+	ScrapeGalleries(ctx context.Context, galleryID int) ([]*scraper.ScrapedGallery, error)
+}
+
+type GalleryUpdatePostHookExecutor interface {
+	ExecuteGalleryUpdatePostHooks(ctx context.Context, input models.GalleryUpdateInput, inputFields []string)
+}
+
+type GalleryReaderUpdater interface {
+	models.GalleryUpdater
+	models.PerformerIDLoader
+	models.TagIDLoader
+	models.URLLoader
+}
+
+// GalleryIdentifier identifies galleries using the gallery scrapers of the
+// sources. Sources without a gallery scraper are skipped. Candidates are not
+// scored, so the first result of the first source returning results is used.
+type GalleryIdentifier struct {
+	TxnManager           txn.Manager
+	GalleryReaderUpdater GalleryReaderUpdater
+	StudioReaderWriter   models.StudioReaderWriter
+	PerformerCreator     PerformerCreator
+	TagFinderCreator     models.TagFinderCreator
+
+	DefaultOptions                *MetadataOptions
+	Sources                       []ScraperSource
+	GalleryUpdatePostHookExecutor GalleryUpdatePostHookExecutor
+}
+
+type galleryScrapeResult struct {
+	result *scraper.ScrapedGallery
+	source ScraperSource
+}
+
+func (t *GalleryIdentifier) Identify(ctx context.Context, g *models.Gallery) error {
+	result, err := t.scrapeGallery(ctx, g)
+	var multipleMatchErr *MultipleMatchesFoundError
+	if err != nil {
+		if !errors.As(err, &multipleMatchErr) {
+			return err
+		}
+	}
+
+	if result == nil {
+		if multipleMatchErr != nil {
+			logger.Debugf("Identify skipped because multiple results returned for %s", g.DisplayName())
+
+			// find if the gallery should be tagged for multiple results
+			options := mergeOptions(t.DefaultOptions, multipleMatchErr.Source)
+			if options.SkipMultipleMatchTag != nil && len(*options.SkipMultipleMatchTag) > 0 {
+				return t.addTagToGallery(ctx, g, *options.SkipMultipleMatchTag)
+			}
+		} else {
+			logger.Debugf("Unable to identify %s", g.DisplayName())
+		}
+		return nil
+	}
+
+	// results were found, modify the gallery
+	if err := t.modifyGallery(ctx, g, result); err != nil {
+		return fmt.Errorf("error modifying gallery: %v", err)
+	}
+
+	return nil
+}
+
+func (t *GalleryIdentifier) scrapeGallery(ctx context.Context, g *models.Gallery) (*galleryScrapeResult, error) {
+	// iterate through the input sources
+	for _, source := range t.Sources {
+		if source.GalleryScraper == nil {
+			continue
+		}
+
+		// scrape using the source
+		results, err := source.GalleryScraper.ScrapeGalleries(ctx, g.ID)
+		if err != nil {
+			logger.Errorf("error scraping from %v: %v", source.GalleryScraper, err)
+			continue
+		}
+
+		if len(results) == 0 {
+			continue
+		}
+
+		options := mergeOptions(t.DefaultOptions, source)
+		if len(results) > 1 && utils.IsTrue(options.SkipMultipleMatches) {
+			return nil, &MultipleMatchesFoundError{
+				Source: source,
+			}
+		}
+
+		return &galleryScrapeResult{
+			result: results[0],
+			source: source,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+func (t *GalleryIdentifier) loadGalleryRelationships(ctx context.Context, g *models.Gallery) error {
+	if err := g.LoadURLs(ctx, t.GalleryReaderUpdater); err != nil {
+		return err
+	}
+	if err := g.LoadPerformerIDs(ctx, t.GalleryReaderUpdater); err != nil {
+		return err
+	}
+	if err := g.LoadTagIDs(ctx, t.GalleryReaderUpdater); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getGalleryPartial returns the changes to make to the gallery, creating any
+// missing objects. Gallery relationships must be loaded.
+func (t *GalleryIdentifier) getGalleryPartial(ctx context.Context, g *models.Gallery, result *galleryScrapeResult) (*models.GalleryPartial, error) {
+	fieldOptions := mergeFieldOptions(t.DefaultOptions, result.source)
+	options := mergeOptions(t.DefaultOptions, result.source)
+	endpoint := result.source.RemoteSite
+	scraped := result.result
+
+	ret := getGalleryPartial(g, scraped, fieldOptions, utils.IsTrue(options.SetOrganized))
+
+	studioID, err := getStudioID(ctx, t.StudioReaderWriter, endpoint, g.StudioID, scraped.Studio, fieldOptions["studio"])
+	if err != nil {
+		return nil, fmt.Errorf("error getting studio: %w", err)
+	}
+	if studioID != nil {
+		ret.StudioID = models.NewOptionalInt(*studioID)
+	}
+
+	includeMalePerformers := true
+	if options.IncludeMalePerformers != nil {
+		includeMalePerformers = *options.IncludeMalePerformers
+	}
+
+	addSkipSingleNamePerformerTag := false
+	performerIDs, err := getPerformerIDs(ctx, t.PerformerCreator, endpoint, g.PerformerIDs.List(), scraped.Performers, fieldOptions["performers"], !includeMalePerformers, utils.IsTrue(options.SkipSingleNamePerformers))
+	if err != nil {
+		if errors.Is(err, ErrSkipSingleNamePerformer) {
+			addSkipSingleNamePerformerTag = true
+		} else {
+			return nil, err
+		}
+	}
+	if performerIDs != nil {
+		ret.PerformerIDs = &models.UpdateIDs{
+			IDs:  performerIDs,
+			Mode: models.RelationshipUpdateModeSet,
+		}
+	}
+
+	tagIDs, err := getTagIDs(ctx, t.TagFinderCreator, g.TagIDs.List(), scraped.Tags, fieldOptions["tags"])
+	if err != nil {
+		return nil, err
+	}
+	if addSkipSingleNamePerformerTag && options.SkipSingleNamePerformerTag != nil {
+		tagID, err := strconv.Atoi(*options.SkipSingleNamePerformerTag)
+		if err != nil {
+			return nil, fmt.Errorf("error converting tag ID %s: %w", *options.SkipSingleNamePerformerTag, err)
+		}
+
+		if tagIDs == nil {
+			tagIDs = g.TagIDs.List()
+		}
+		tagIDs = sliceutil.AppendUnique(tagIDs, tagID)
+	}
+	if tagIDs != nil && !sliceutil.SliceSame(g.TagIDs.List(), tagIDs) {
+		ret.TagIDs = &models.UpdateIDs{
+			IDs:  tagIDs,
+			Mode: models.RelationshipUpdateModeSet,
+		}
+	}
+
+	return &ret, nil
+}
+
+func (t *GalleryIdentifier) modifyGallery(ctx context.Context, g *models.Gallery, result *galleryScrapeResult) error {
+	var partial *models.GalleryPartial
+	if err := txn.WithTxn(ctx, t.TxnManager, func(ctx context.Context) error {
+		if err := t.loadGalleryRelationships(ctx, g); err != nil {
+			return err
+		}
+
+		var err error
+		partial, err = t.getGalleryPartial(ctx, g, result)
+		if err != nil {
+			return err
+		}
+
+		// don't update anything if nothing was set
+		if galleryPartialIsEmpty(*partial) {
+			logger.Debugf("Nothing to set for %s", g.DisplayName())
+			return nil
+		}
+
+		if _, err := t.GalleryReaderUpdater.UpdatePartial(ctx, g.ID, *partial); err != nil {
+			return fmt.Errorf("error updating gallery: %w", err)
+		}
+
+		as := ""
+		if partial.Title.Set {
+			as = fmt.Sprintf(" as %s", partial.Title.Value)
+		}
+		logger.Infof("Successfully identified %s%s using %s", g.DisplayName(), as, result.source.Name)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// fire post-update hooks
+	if partial != nil && !galleryPartialIsEmpty(*partial) {
+		updateInput := galleryUpdateInput(g.ID, *partial)
+		fields := utils.NotNilFields(updateInput, "json")
+		t.GalleryUpdatePostHookExecutor.ExecuteGalleryUpdatePostHooks(ctx, updateInput, fields)
+	}
+
+	return nil
+}
+
+func (t *GalleryIdentifier) addTagToGallery(ctx context.Context, g *models.Gallery, tagToAdd string) error {
+	return txn.WithTxn(ctx, t.TxnManager, func(ctx context.Context) error {
+		tagID, err := strconv.Atoi(tagToAdd)
+		if err != nil {
+			return fmt.Errorf("error converting tag ID %s: %w", tagToAdd, err)
+		}
+
+		if err := g.LoadTagIDs(ctx, t.GalleryReaderUpdater); err != nil {
+			return err
+		}
+
+		if sliceutil.Contains(g.TagIDs.List(), tagID) {
+			// skip if the gallery was already tagged
+			return nil
+		}
+
+		if err := gallery.AddTag(ctx, t.GalleryReaderUpdater, g, tagID); err != nil {
+			return err
+		}
+
+		logger.Infof("Added tag id %s to skipped gallery %s", tagToAdd, g.DisplayName())
+
+		return nil
+	})
+}
+
+func getGalleryPartial(g *models.Gallery, scraped *scraper.ScrapedGallery, fieldOptions map[string]*FieldOptions, setOrganized bool) models.GalleryPartial {
+	partial := models.NewGalleryPartial()
+
+	if v := getStringFieldValue(fieldOptions["title"], g.Title, scraped.Title, false); v != nil {
+		partial.Title = models.NewOptionalString(*v)
+	}
+	if v := getStringFieldValue(fieldOptions["code"], g.Code, scraped.Code, false); v != nil {
+		partial.Code = models.NewOptionalString(*v)
+	}
+	if scraped.Date != nil && (g.Date == nil || g.Date.String() != *scraped.Date) {
+		if shouldSetSingleValueField(fieldOptions["date"], g.Date != nil) {
+			d, err := models.ParseDate(*scraped.Date)
+			if err == nil {
+				partial.Date = models.NewOptionalDate(d)
+			}
+		}
+	}
+	if v := getStringFieldValue(fieldOptions["details"], g.Details, scraped.Details, true); v != nil {
+		partial.Details = models.NewOptionalString(*v)
+	}
+	if v := getStringFieldValue(fieldOptions["photographer"], g.Photographer, scraped.Photographer, false); v != nil {
+		partial.Photographer = models.NewOptionalString(*v)
+	}
+
+	urls := scraped.URLs
+	if len(urls) == 0 && scraped.URL != nil {
+		urls = []string{*scraped.URL}
+	}
+	if len(urls) > 0 && shouldSetSingleValueField(fieldOptions["url"], false) {
+		switch getFieldStrategy(fieldOptions["url"]) {
+		case FieldStrategyOverwrite:
+			// only overwrite if not equal
+			if len(sliceutil.Exclude(urls, g.URLs.List())) != 0 {
+				partial.URLs = &models.UpdateStrings{
+					Values: urls,
+					Mode:   models.RelationshipUpdateModeSet,
+				}
+			}
+		default:
+			// if merge, add if not already present
+			merged := sliceutil.AppendUniques(g.URLs.List(), urls)
+
+			if len(merged) != len(g.URLs.List()) {
+				partial.URLs = &models.UpdateStrings{
+					Values: merged,
+					Mode:   models.RelationshipUpdateModeSet,
+				}
+			}
+		}
+	}
+
+	if setOrganized && !g.Organized {
+		partial.Organized = models.NewOptionalBool(true)
+	}
+
+	return partial
+}
+
+func galleryPartialIsEmpty(p models.GalleryPartial) bool {
+	// UpdatedAt is always set, so ignore it
+	p.UpdatedAt = models.OptionalTime{}
+	return p == models.GalleryPartial{}
+}
+
+func galleryUpdateInput(id int, p models.GalleryPartial) models.GalleryUpdateInput {
+	var dateStr *string
+	if p.Date.Set {
+		v := p.Date.Value.String()
+		dateStr = &v
+	}
+
+	var urls []string
+	if p.URLs != nil {
+		urls = p.URLs.Values
+	}
+
+	return models.GalleryUpdateInput{
+		ID:           strconv.Itoa(id),
+		Title:        p.Title.Ptr(),
+		Code:         p.Code.Ptr(),
+		Urls:         urls,
+		Date:         dateStr,
+		Details:      p.Details.Ptr(),
+		Photographer: p.Photographer.Ptr(),
+		Organized:    p.Organized.Ptr(),
+		StudioID:     p.StudioID.StringPtr(),
+		TagIds:       p.TagIDs.IDStrings(),
+		PerformerIds: p.PerformerIDs.IDStrings(),
+	}
+}
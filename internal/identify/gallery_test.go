@@ -0,0 +1,197 @@
+package identify
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stashapp/stash/pkg/scraper"
+	"github.com/stashapp/stash/pkg/sliceutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockGalleryScraper struct {
+	errIDs  []int
+	results map[int][]*scraper.ScrapedGallery
+}
+
+func (s mockGalleryScraper) ScrapeGalleries(ctx context.Context, galleryID int) ([]*scraper.ScrapedGallery, error) {
+	if sliceutil.Contains(s.errIDs, galleryID) {
+		return nil, errors.New("scrape gallery error")
+	}
+	return s.results[galleryID], nil
+}
+
+func TestGalleryIdentifier_Identify(t *testing.T) {
+	const (
+		errID = iota + 1
+		missingID
+		foundID
+		secondSourceID
+		multiFoundID
+	)
+
+	var (
+		skipMultipleTagID    = 10
+		skipMultipleTagIDStr = strconv.Itoa(skipMultipleTagID)
+
+		scrapedTitle  = "scrapedTitle"
+		scrapedTitle2 = "scrapedTitle2"
+
+		boolTrue = true
+	)
+
+	sources := []ScraperSource{
+		{
+			// scene only source is skipped
+			Name: "scene only",
+			Scraper: mockSceneScraper{
+				errIDs: []int{foundID},
+			},
+		},
+		{
+			Name: "first",
+			GalleryScraper: mockGalleryScraper{
+				errIDs: []int{errID},
+				results: map[int][]*scraper.ScrapedGallery{
+					foundID: {{Title: &scrapedTitle}},
+					multiFoundID: {
+						{Title: &scrapedTitle},
+						{Title: &scrapedTitle2},
+					},
+				},
+			},
+		},
+		{
+			Name: "second",
+			GalleryScraper: mockGalleryScraper{
+				results: map[int][]*scraper.ScrapedGallery{
+					secondSourceID: {{Title: &scrapedTitle2}},
+				},
+			},
+		},
+	}
+
+	db := mocks.NewDatabase()
+
+	db.Gallery.On("GetURLs", mock.Anything, mock.Anything).Return(nil, nil)
+	db.Gallery.On("GetPerformerIDs", mock.Anything, mock.Anything).Return(nil, nil)
+	db.Gallery.On("GetTagIDs", mock.Anything, mock.Anything).Return(nil, nil)
+
+	titleUpdated := func(id int, title string) {
+		db.Gallery.On("UpdatePartial", mock.Anything, id, mock.MatchedBy(func(p models.GalleryPartial) bool {
+			return p.Title.Value == title
+		})).Return(nil, nil).Once()
+	}
+	titleUpdated(foundID, scrapedTitle)
+	titleUpdated(secondSourceID, scrapedTitle2)
+
+	db.Gallery.On("UpdatePartial", mock.Anything, multiFoundID, mock.MatchedBy(func(p models.GalleryPartial) bool {
+		return p.TagIDs != nil && sliceutil.Contains(p.TagIDs.IDs, skipMultipleTagID) && p.TagIDs.Mode == models.RelationshipUpdateModeAdd
+	})).Return(nil, nil).Once()
+
+	identifier := GalleryIdentifier{
+		TxnManager:           db,
+		GalleryReaderUpdater: db.Gallery,
+		DefaultOptions: &MetadataOptions{
+			SkipMultipleMatches:  &boolTrue,
+			SkipMultipleMatchTag: &skipMultipleTagIDStr,
+		},
+		Sources:                       sources,
+		GalleryUpdatePostHookExecutor: mockHookExecutor{},
+	}
+
+	for _, id := range []int{errID, missingID, foundID, secondSourceID, multiFoundID} {
+		g := &models.Gallery{ID: id}
+		if err := identifier.Identify(testCtx, g); err != nil {
+			t.Errorf("GalleryIdentifier.Identify() id %d error = %v", id, err)
+		}
+	}
+
+	db.AssertExpectations(t)
+}
+
+func Test_getGalleryPartial(t *testing.T) {
+	var (
+		originalTitle        = "originalTitle"
+		originalPhotographer = "originalPhotographer"
+		originalURL          = "originalURL"
+
+		scrapedTitle        = "scrapedTitle"
+		scrapedCode         = "scrapedCode"
+		scrapedPhotographer = "scrapedPhotographer"
+		scrapedDate         = "2021-01-01"
+		scrapedURL          = "scrapedURL"
+	)
+
+	scrapedDateObj, _ := models.ParseDate(scrapedDate)
+
+	g := &models.Gallery{
+		Title:        originalTitle,
+		Photographer: originalPhotographer,
+		URLs:         models.NewRelatedStrings([]string{originalURL}),
+	}
+
+	scraped := &scraper.ScrapedGallery{
+		Title:        &scrapedTitle,
+		Code:         &scrapedCode,
+		Photographer: &scrapedPhotographer,
+		Date:         &scrapedDate,
+		URL:          &scrapedURL,
+	}
+
+	tests := []struct {
+		name         string
+		fieldOptions map[string]*FieldOptions
+		setOrganized bool
+		want         models.GalleryPartial
+	}{
+		{
+			"merge",
+			nil,
+			false,
+			models.GalleryPartial{
+				Code: models.NewOptionalString(scrapedCode),
+				Date: models.NewOptionalDate(scrapedDateObj),
+				URLs: &models.UpdateStrings{
+					Values: []string{originalURL, scrapedURL},
+					Mode:   models.RelationshipUpdateModeSet,
+				},
+			},
+		},
+		{
+			"overwrite and organized",
+			map[string]*FieldOptions{
+				"title":        {Strategy: FieldStrategyOverwrite},
+				"photographer": {Strategy: FieldStrategyOverwrite},
+				"code":         {Strategy: FieldStrategyIgnore},
+				"date":         {Strategy: FieldStrategyIgnore},
+				"url":          {Strategy: FieldStrategyOverwrite},
+			},
+			true,
+			models.GalleryPartial{
+				Title:        models.NewOptionalString(scrapedTitle),
+				Photographer: models.NewOptionalString(scrapedPhotographer),
+				URLs: &models.UpdateStrings{
+					Values: []string{scrapedURL},
+					Mode:   models.RelationshipUpdateModeSet,
+				},
+				Organized: models.NewOptionalBool(true),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getGalleryPartial(g, scraped, tt.fieldOptions, tt.setOrganized)
+
+			// ignore updated at
+			got.UpdatedAt = models.OptionalTime{}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
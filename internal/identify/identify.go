@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
@@ -36,10 +37,17 @@ type SceneUpdatePostHookExecutor interface {
 }
 
 type ScraperSource struct {
-	Name       string
-	Options    *MetadataOptions
-	Scraper    SceneScraper
-	RemoteSite string
+	Name    string
+	Options *MetadataOptions
+	Scraper SceneScraper
+	// optional - sources without a gallery scraper are skipped when
+	// identifying galleries
+	GalleryScraper GalleryScraper
+	// optional - sources without a performer or studio scraper are skipped
+	// when identifying performers or studios
+	PerformerScraper PerformerScraper
+	StudioScraper    StudioScraper
+	RemoteSite       string
 }
 
 type SceneIdentifier struct {
@@ -48,17 +56,40 @@ type SceneIdentifier struct {
 	StudioReaderWriter models.StudioReaderWriter
 	PerformerCreator   PerformerCreator
 	TagFinderCreator   models.TagFinderCreator
+	MovieCreator       MovieCreator
+	// optional - required to identify markers
+	SceneMarkerFinderCreator SceneMarkerFinderCreator
 
 	DefaultOptions              *MetadataOptions
 	Sources                     []ScraperSource
 	SceneUpdatePostHookExecutor SceneUpdatePostHookExecutor
+	// optional - receives the match report of each scene
+	SceneReporter SceneReporter
+
+	// if true, changes are stored as pending changes instead of being applied
+	Review                    bool
+	PendingSceneChangeCreator models.PendingSceneChangeCreator
+	// kept in sync when the stash ids of a scene are set
+	FingerprintSubmissionUpdater models.FingerprintSubmissionUpdater
 }
 
 func (t *SceneIdentifier) Identify(ctx context.Context, scene *models.Scene) error {
-	result, err := t.scrapeScene(ctx, scene)
+	report := newSceneReport(scene)
+	if t.SceneReporter != nil {
+		defer t.SceneReporter.ReportScene(report)
+	}
+
+	// images are fetched when proposed changes are accepted
+	scrapeCtx := ctx
+	if t.Review {
+		scrapeCtx = scraper.WithImageURLs(ctx)
+	}
+
+	result, err := t.scrapeScene(scrapeCtx, scene, report)
 	var multipleMatchErr *MultipleMatchesFoundError
 	if err != nil {
 		if !errors.As(err, &multipleMatchErr) {
+			report.addError(err)
 			return err
 		}
 	}
@@ -73,18 +104,31 @@ func (t *SceneIdentifier) Identify(ctx context.Context, scene *models.Scene) err
 				// Tag it with the multiple results tag
 				err := t.addTagToScene(ctx, scene, *options.SkipMultipleMatchTag)
 				if err != nil {
+					report.addError(err)
 					return err
 				}
 				return nil
 			}
 		} else {
-			logger.Debugf("Unable to identify %s", scene.Path)
+			logger.Debugf("Unable to identify %s: %s", scene.Path, report.Reason)
 		}
 		return nil
 	}
 
+	logger.Debugf("Identify %s: %s", scene.Path, report.Reason)
+
+	if t.Review {
+		if err := t.proposeChanges(ctx, scene, result); err != nil {
+			report.addError(err)
+			return fmt.Errorf("error proposing scene changes: %v", err)
+		}
+
+		return nil
+	}
+
 	// results were found, modify the scene
 	if err := t.modifyScene(ctx, scene, result); err != nil {
+		report.addError(err)
 		return fmt.Errorf("error modifying scene: %v", err)
 	}
 
@@ -96,30 +140,63 @@ type scrapeResult struct {
 	source ScraperSource
 }
 
-func (t *SceneIdentifier) scrapeScene(ctx context.Context, scene *models.Scene) (*scrapeResult, error) {
+// scrapeScene iterates through the sources in order, and returns the best
+// scoring candidate of the first source that returns a candidate meeting its
+// match threshold. The candidates considered are added to the report.
+func (t *SceneIdentifier) scrapeScene(ctx context.Context, scene *models.Scene, report *SceneReport) (*scrapeResult, error) {
+	scoreInput := newSceneScoreInput(scene)
+
 	// iterate through the input sources
 	for _, source := range t.Sources {
 		// scrape using the source
 		results, err := source.Scraper.ScrapeScenes(ctx, scene.ID)
 		if err != nil {
 			logger.Errorf("error scraping from %v: %v", source.Scraper, err)
+			report.addError(fmt.Errorf("scraping from %s: %w", source.Name, err))
 			continue
 		}
 
-		if len(results) > 0 {
-			options := t.getOptions(source)
-			if len(results) > 1 && utils.IsTrue(options.SkipMultipleMatches) {
-				return nil, &MultipleMatchesFoundError{
-					Source: source,
-				}
-			} else {
-				// if results were found then return
-				return &scrapeResult{
-					result: results[0],
-					source: source,
-				}, nil
+		if len(results) == 0 {
+			continue
+		}
+
+		options := t.getOptions(source)
+		threshold := 0.0
+		if options.MatchThreshold != nil {
+			threshold = *options.MatchThreshold
+		}
+
+		candidates := scoreCandidates(scene, scoreInput, source, results)
+
+		var accepted []scoredCandidate
+		for _, c := range candidates {
+			report.Candidates = append(report.Candidates, c.report)
+			if c.report.Score.Score >= threshold {
+				c.report.Accepted = true
+				accepted = append(accepted, c)
 			}
 		}
+
+		if len(accepted) == 0 {
+			report.setBelowThreshold()
+			continue
+		}
+
+		if len(accepted) > 1 && utils.IsTrue(options.SkipMultipleMatches) {
+			report.setMultipleMatches(source, len(accepted))
+			return nil, &MultipleMatchesFoundError{
+				Source: source,
+			}
+		}
+
+		// candidates are sorted by score, so the first is the best
+		best := accepted[0]
+		report.setMatched(best.report, len(accepted))
+
+		return &scrapeResult{
+			result: best.result,
+			source: source,
+		}, nil
 	}
 
 	return nil, nil
@@ -127,9 +204,18 @@ func (t *SceneIdentifier) scrapeScene(ctx context.Context, scene *models.Scene)
 
 // Returns a MetadataOptions object with any default options overwritten by source specific options
 func (t *SceneIdentifier) getOptions(source ScraperSource) MetadataOptions {
+	return mergeOptions(t.DefaultOptions, source)
+}
+
+// Returns the field options of the source, falling back to the default field options
+func (t *SceneIdentifier) getFieldOptions(source ScraperSource) map[string]*FieldOptions {
+	return mergeFieldOptions(t.DefaultOptions, source)
+}
+
+func mergeOptions(defaults *MetadataOptions, source ScraperSource) MetadataOptions {
 	var options MetadataOptions
-	if t.DefaultOptions != nil {
-		options = *t.DefaultOptions
+	if defaults != nil {
+		options = *defaults
 	}
 	if source.Options == nil {
 		return options
@@ -156,24 +242,31 @@ func (t *SceneIdentifier) getOptions(source ScraperSource) MetadataOptions {
 	if source.Options.SkipSingleNamePerformerTag != nil && len(*source.Options.SkipSingleNamePerformerTag) > 0 {
 		options.SkipSingleNamePerformerTag = source.Options.SkipSingleNamePerformerTag
 	}
+	if source.Options.MatchThreshold != nil {
+		options.MatchThreshold = source.Options.MatchThreshold
+	}
 
 	return options
 }
 
+func mergeFieldOptions(defaults *MetadataOptions, source ScraperSource) map[string]*FieldOptions {
+	allOptions := []MetadataOptions{}
+	if source.Options != nil {
+		allOptions = append(allOptions, *source.Options)
+	}
+	if defaults != nil {
+		allOptions = append(allOptions, *defaults)
+	}
+
+	return getFieldOptions(allOptions)
+}
+
 func (t *SceneIdentifier) getSceneUpdater(ctx context.Context, s *models.Scene, result *scrapeResult) (*scene.UpdateSet, error) {
 	ret := &scene.UpdateSet{
 		ID: s.ID,
 	}
 
-	allOptions := []MetadataOptions{}
-	if result.source.Options != nil {
-		allOptions = append(allOptions, *result.source.Options)
-	}
-	if t.DefaultOptions != nil {
-		allOptions = append(allOptions, *t.DefaultOptions)
-	}
-
-	fieldOptions := getFieldOptions(allOptions)
+	fieldOptions := t.getFieldOptions(result.source)
 	options := t.getOptions(result.source)
 
 	scraped := result.result
@@ -183,6 +276,7 @@ func (t *SceneIdentifier) getSceneUpdater(ctx context.Context, s *models.Scene,
 		studioReaderWriter:       t.StudioReaderWriter,
 		performerCreator:         t.PerformerCreator,
 		tagCreator:               t.TagFinderCreator,
+		movieCreator:             t.MovieCreator,
 		scene:                    s,
 		result:                   result,
 		fieldOptions:             fieldOptions,
@@ -241,6 +335,17 @@ func (t *SceneIdentifier) getSceneUpdater(ctx context.Context, s *models.Scene,
 		}
 	}
 
+	movies, err := rel.movies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if movies != nil {
+		ret.Partial.MovieIDs = &models.UpdateMovieIDs{
+			Movies: movies,
+			Mode:   models.RelationshipUpdateModeSet,
+		}
+	}
+
 	stashIDs, err := rel.stashIDs(ctx)
 	if err != nil {
 		return nil, err
@@ -262,59 +367,108 @@ func (t *SceneIdentifier) getSceneUpdater(ctx context.Context, s *models.Scene,
 	return ret, nil
 }
 
+func (t *SceneIdentifier) loadSceneRelationships(ctx context.Context, s *models.Scene) error {
+	if err := s.LoadURLs(ctx, t.SceneReaderUpdater); err != nil {
+		return err
+	}
+	if err := s.LoadPerformerIDs(ctx, t.SceneReaderUpdater); err != nil {
+		return err
+	}
+	if err := s.LoadTagIDs(ctx, t.SceneReaderUpdater); err != nil {
+		return err
+	}
+	if err := s.LoadStashIDs(ctx, t.SceneReaderUpdater); err != nil {
+		return err
+	}
+	if err := s.LoadMovies(ctx, t.SceneReaderUpdater); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (t *SceneIdentifier) modifyScene(ctx context.Context, s *models.Scene, result *scrapeResult) error {
 	var updater *scene.UpdateSet
 	if err := txn.WithTxn(ctx, t.TxnManager, func(ctx context.Context) error {
-		// load scene relationships
-		if err := s.LoadURLs(ctx, t.SceneReaderUpdater); err != nil {
-			return err
-		}
-		if err := s.LoadPerformerIDs(ctx, t.SceneReaderUpdater); err != nil {
-			return err
-		}
-		if err := s.LoadTagIDs(ctx, t.SceneReaderUpdater); err != nil {
-			return err
-		}
-		if err := s.LoadStashIDs(ctx, t.SceneReaderUpdater); err != nil {
-			return err
-		}
-
 		var err error
-		updater, err = t.getSceneUpdater(ctx, s, result)
-		if err != nil {
-			return err
-		}
+		updater, err = t.updateScene(ctx, s, result)
+		return err
+	}); err != nil {
+		return err
+	}
 
-		// don't update anything if nothing was set
-		if updater.IsEmpty() {
-			logger.Debugf("Nothing to set for %s", s.Path)
-			return nil
-		}
+	t.executePostHooks(ctx, updater)
+
+	return nil
+}
+
+// updateScene applies the result to the scene. It must be called within a
+// transaction. Returns the update that was applied.
+func (t *SceneIdentifier) updateScene(ctx context.Context, s *models.Scene, result *scrapeResult) (*scene.UpdateSet, error) {
+	if err := t.loadSceneRelationships(ctx, s); err != nil {
+		return nil, err
+	}
+
+	updater, err := t.getSceneUpdater(ctx, s, result)
+	if err != nil {
+		return nil, err
+	}
+
+	markers, err := t.getMarkers(ctx, s, result, false)
+	if err != nil {
+		return nil, err
+	}
 
+	// don't update anything if nothing was set
+	if updater.IsEmpty() && len(markers) == 0 {
+		logger.Debugf("Nothing to set for %s", s.Path)
+		return updater, nil
+	}
+
+	if !updater.IsEmpty() {
 		if _, err := updater.Update(ctx, t.SceneReaderUpdater); err != nil {
-			return fmt.Errorf("error updating scene: %w", err)
+			return nil, fmt.Errorf("error updating scene: %w", err)
 		}
 
-		as := ""
-		title := updater.Partial.Title
-		if title.Ptr() != nil {
-			as = fmt.Sprintf(" as %s", title.Value)
+		if updater.Partial.StashIDs != nil {
+			if err := t.FingerprintSubmissionUpdater.SyncScene(ctx, s.ID); err != nil {
+				return nil, fmt.Errorf("error syncing fingerprint submissions: %w", err)
+			}
 		}
-		logger.Infof("Successfully identified %s%s using %s", s.Path, as, result.source.Name)
+	}
 
-		return nil
-	}); err != nil {
-		return err
+	for _, m := range markers {
+		if err := t.SceneMarkerFinderCreator.Create(ctx, m.marker); err != nil {
+			return nil, fmt.Errorf("error creating scene marker: %w", err)
+		}
+	}
+
+	as := ""
+	title := updater.Partial.Title
+	if title.Ptr() != nil {
+		as = fmt.Sprintf(" as %s", title.Value)
 	}
+	logger.Infof("Successfully identified %s%s using %s", s.Path, as, result.source.Name)
+
+	return updater, nil
+}
 
-	// fire post-update hooks
+// executePostHooks fires the post-update hooks of the scene update.
+func (t *SceneIdentifier) executePostHooks(ctx context.Context, updater *scene.UpdateSet) {
 	if !updater.IsEmpty() {
 		updateInput := updater.UpdateInput()
 		fields := utils.NotNilFields(updateInput, "json")
 		t.SceneUpdatePostHookExecutor.ExecuteSceneUpdatePostHooks(ctx, updateInput, fields)
 	}
+}
 
-	return nil
+func (t *SceneIdentifier) getMarkers(ctx context.Context, s *models.Scene, result *scrapeResult, dryRun bool) ([]newMarker, error) {
+	if t.SceneMarkerFinderCreator == nil {
+		return nil, nil
+	}
+
+	fieldOptions := t.getFieldOptions(result.source)
+	return getMarkers(ctx, t.SceneMarkerFinderCreator, t.TagFinderCreator, s.ID, result.result.Markers, fieldOptions["markers"], dryRun)
 }
 
 func (t *SceneIdentifier) addTagToScene(ctx context.Context, s *models.Scene, tagToAdd string) error {
@@ -369,10 +523,8 @@ func getFieldOptions(options []MetadataOptions) map[string]*FieldOptions {
 func getScenePartial(scene *models.Scene, scraped *scraper.ScrapedScene, fieldOptions map[string]*FieldOptions, setOrganized bool) models.ScenePartial {
 	partial := models.ScenePartial{}
 
-	if scraped.Title != nil && (scene.Title != *scraped.Title) {
-		if shouldSetSingleValueField(fieldOptions["title"], scene.Title != "") {
-			partial.Title = models.NewOptionalString(*scraped.Title)
-		}
+	if v := getStringFieldValue(fieldOptions["title"], scene.Title, scraped.Title, false); v != nil {
+		partial.Title = models.NewOptionalString(*v)
 	}
 	if scraped.Date != nil && (scene.Date == nil || scene.Date.String() != *scraped.Date) {
 		if shouldSetSingleValueField(fieldOptions["date"], scene.Date != nil) {
@@ -382,10 +534,8 @@ func getScenePartial(scene *models.Scene, scraped *scraper.ScrapedScene, fieldOp
 			}
 		}
 	}
-	if scraped.Details != nil && (scene.Details != *scraped.Details) {
-		if shouldSetSingleValueField(fieldOptions["details"], scene.Details != "") {
-			partial.Details = models.NewOptionalString(*scraped.Details)
-		}
+	if v := getStringFieldValue(fieldOptions["details"], scene.Details, scraped.Details, true); v != nil {
+		partial.Details = models.NewOptionalString(*v)
 	}
 	if len(scraped.URLs) > 0 && shouldSetSingleValueField(fieldOptions["url"], false) {
 		// if overwrite, then set over the top
@@ -398,7 +548,7 @@ func getScenePartial(scene *models.Scene, scraped *scraper.ScrapedScene, fieldOp
 					Mode:   models.RelationshipUpdateModeSet,
 				}
 			}
-		case FieldStrategyMerge:
+		default:
 			// if merge, add if not already present
 			urls := sliceutil.AppendUniques(scene.URLs.List(), scraped.URLs)
 
@@ -410,15 +560,11 @@ func getScenePartial(scene *models.Scene, scraped *scraper.ScrapedScene, fieldOp
 			}
 		}
 	}
-	if scraped.Director != nil && (scene.Director != *scraped.Director) {
-		if shouldSetSingleValueField(fieldOptions["director"], scene.Director != "") {
-			partial.Director = models.NewOptionalString(*scraped.Director)
-		}
+	if v := getStringFieldValue(fieldOptions["director"], scene.Director, scraped.Director, false); v != nil {
+		partial.Director = models.NewOptionalString(*v)
 	}
-	if scraped.Code != nil && (scene.Code != *scraped.Code) {
-		if shouldSetSingleValueField(fieldOptions["code"], scene.Code != "") {
-			partial.Code = models.NewOptionalString(*scraped.Code)
-		}
+	if v := getStringFieldValue(fieldOptions["code"], scene.Code, scraped.Code, false); v != nil {
+		partial.Code = models.NewOptionalString(*v)
 	}
 
 	if setOrganized && !scene.Organized {
@@ -439,6 +585,52 @@ func getFieldStrategy(strategy *FieldOptions) FieldStrategy {
 	return fs
 }
 
+// isMergeStrategy returns true if existing values of multi-value fields are
+// kept. All strategies other than IGNORE and OVERWRITE merge multi-value fields.
+func isMergeStrategy(strategy *FieldOptions) bool {
+	fs := getFieldStrategy(strategy)
+	return fs != FieldStrategyIgnore && fs != FieldStrategyOverwrite
+}
+
+// getStringFieldValue returns the value to set a text field to, or nil if the
+// field should not be set. The APPEND strategy is only applied if canAppend is
+// true, otherwise it behaves as MERGE.
+func getStringFieldValue(strategy *FieldOptions, existing string, scraped *string, canAppend bool) *string {
+	if scraped == nil || *scraped == existing {
+		return nil
+	}
+
+	switch getFieldStrategy(strategy) {
+	case FieldStrategyIgnore:
+		return nil
+	case FieldStrategyOverwrite:
+		return scraped
+	case FieldStrategyOverwriteIfShorter:
+		if len(existing) < len(*scraped) {
+			return scraped
+		}
+		return nil
+	case FieldStrategyAppend:
+		if !canAppend || existing == "" {
+			break
+		}
+
+		// don't append if already present
+		if strings.Contains(existing, *scraped) {
+			return nil
+		}
+
+		ret := existing + "\n\n" + *scraped
+		return &ret
+	}
+
+	if existing == "" {
+		return scraped
+	}
+
+	return nil
+}
+
 func shouldSetSingleValueField(strategy *FieldOptions, hasExistingValue bool) bool {
 	// if unset then default to MERGE
 	fs := getFieldStrategy(strategy)
@@ -35,6 +35,9 @@ type mockHookExecutor struct {
 func (s mockHookExecutor) ExecuteSceneUpdatePostHooks(ctx context.Context, input models.SceneUpdateInput, inputFields []string) {
 }
 
+func (s mockHookExecutor) ExecuteGalleryUpdatePostHooks(ctx context.Context, input models.GalleryUpdateInput, inputFields []string) {
+}
+
 func TestSceneIdentifier_Identify(t *testing.T) {
 	const (
 		errID1 = iota
@@ -204,6 +207,7 @@ func TestSceneIdentifier_Identify(t *testing.T) {
 				PerformerIDs: models.NewRelatedIDs([]int{}),
 				TagIDs:       models.NewRelatedIDs([]int{}),
 				StashIDs:     models.NewRelatedStashIDs([]models.StashID{}),
+				Movies:       models.NewRelatedMovies([]models.MoviesScenes{}),
 			}
 			if err := identifier.Identify(testCtx, scene); (err != nil) != tt.wantErr {
 				t.Errorf("SceneIdentifier.Identify() error = %v, wantErr %v", err, tt.wantErr)
@@ -248,6 +252,7 @@ func TestSceneIdentifier_modifyScene(t *testing.T) {
 					PerformerIDs: models.NewRelatedIDs([]int{}),
 					TagIDs:       models.NewRelatedIDs([]int{}),
 					StashIDs:     models.NewRelatedStashIDs([]models.StashID{}),
+					Movies:       models.NewRelatedMovies([]models.MoviesScenes{}),
 				},
 				&scrapeResult{
 					result: &scraper.ScrapedScene{},
@@ -616,3 +621,42 @@ func Test_shouldSetSingleValueField(t *testing.T) {
 		})
 	}
 }
+
+func Test_getStringFieldValue(t *testing.T) {
+	const (
+		existing = "existing"
+		longer   = "longer value"
+		shorter  = "short"
+	)
+
+	strPtr := func(v string) *string { return &v }
+
+	tests := []struct {
+		name      string
+		strategy  FieldStrategy
+		existing  string
+		scraped   *string
+		canAppend bool
+		want      *string
+	}{
+		{"nil scraped", FieldStrategyOverwrite, existing, nil, false, nil},
+		{"same value", FieldStrategyOverwrite, existing, strPtr(existing), false, nil},
+		{"ignore", FieldStrategyIgnore, "", strPtr(longer), false, nil},
+		{"merge existing", FieldStrategyMerge, existing, strPtr(longer), false, nil},
+		{"merge empty", FieldStrategyMerge, "", strPtr(longer), false, strPtr(longer)},
+		{"overwrite", FieldStrategyOverwrite, existing, strPtr(shorter), false, strPtr(shorter)},
+		{"overwrite if shorter longer", FieldStrategyOverwriteIfShorter, existing, strPtr(longer), false, strPtr(longer)},
+		{"overwrite if shorter shorter", FieldStrategyOverwriteIfShorter, existing, strPtr(shorter), false, nil},
+		{"overwrite if shorter empty", FieldStrategyOverwriteIfShorter, "", strPtr(shorter), false, strPtr(shorter)},
+		{"append", FieldStrategyAppend, existing, strPtr(longer), true, strPtr(existing + "\n\n" + longer)},
+		{"append empty", FieldStrategyAppend, "", strPtr(longer), true, strPtr(longer)},
+		{"append already present", FieldStrategyAppend, existing + "\n\n" + longer, strPtr(longer), true, nil},
+		{"append not allowed", FieldStrategyAppend, existing, strPtr(longer), false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getStringFieldValue(&FieldOptions{Strategy: tt.strategy}, tt.existing, tt.scraped, tt.canAppend)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
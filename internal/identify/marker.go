@@ -0,0 +1,146 @@
+package identify
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+type SceneMarkerFinderCreator interface {
+	FindBySceneID(ctx context.Context, sceneID int) ([]*models.SceneMarker, error)
+	models.SceneMarkerCreator
+}
+
+// newMarker is a scraped marker to be created.
+type newMarker struct {
+	marker     *models.SceneMarker
+	primaryTag string
+}
+
+func (m newMarker) String() string {
+	ret := fmt.Sprintf("%s at %.0fs", m.primaryTag, m.marker.Seconds)
+	if m.marker.Title != "" {
+		ret = m.marker.Title + " - " + ret
+	}
+
+	return ret
+}
+
+// matches returns true if the marker has the same start time and primary tag.
+// Tags that are yet to be created have an id of 0, so are matched by name.
+func (m newMarker) matches(seconds float64, tag *models.Tag) bool {
+	if math.Abs(m.marker.Seconds-seconds) >= 1 {
+		return false
+	}
+
+	if tag.ID == 0 {
+		return m.marker.PrimaryTagID == 0 && strings.EqualFold(m.primaryTag, tag.Name)
+	}
+
+	return m.marker.PrimaryTagID == tag.ID
+}
+
+// markerExists returns true if a marker with the same start time and primary
+// tag is in the list. Markers with a primary tag id of 0 have a tag that is
+// yet to be created, and are never matched.
+func markerExists(markers []*models.SceneMarker, seconds float64, primaryTagID int) bool {
+	if primaryTagID == 0 {
+		return false
+	}
+
+	for _, m := range markers {
+		if m.PrimaryTagID == primaryTagID && math.Abs(m.Seconds-seconds) < 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newMarkerExists returns true if a marker matching the start time and
+// primary tag is in the list.
+func newMarkerExists(markers []newMarker, seconds float64, tag *models.Tag) bool {
+	for _, m := range markers {
+		if m.matches(seconds, tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getMarkers returns the scraped markers to create for the scene. Markers
+// are only ever added - existing markers are not removed. Markers without a
+// valid start time or primary tag are skipped. Missing primary tags are
+// created if permitted, unless dryRun is true, in which case the primary tag
+// id of the returned marker is 0.
+func getMarkers(ctx context.Context, r SceneMarkerFinderCreator, tagFinderCreator models.TagFinderCreator, sceneID int, scraped []*models.ScrapedSceneMarker, fieldStrategy *FieldOptions, dryRun bool) ([]newMarker, error) {
+	if len(scraped) == 0 || !shouldSetSingleValueField(fieldStrategy, false) {
+		return nil, nil
+	}
+
+	createMissing := fieldStrategy != nil && utils.IsTrue(fieldStrategy.CreateMissing)
+
+	existing, err := r.FindBySceneID(ctx, sceneID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding scene markers: %w", err)
+	}
+
+	var ret []newMarker
+	for _, m := range scraped {
+		if m.Seconds == nil || m.PrimaryTag == nil || *m.PrimaryTag == "" {
+			continue
+		}
+
+		seconds, err := strconv.ParseFloat(*m.Seconds, 64)
+		if err != nil {
+			logger.Warnf("Skipping scraped marker with invalid time %q", *m.Seconds)
+			continue
+		}
+
+		tag, err := tagFinderCreator.FindByName(ctx, *m.PrimaryTag, true)
+		if err != nil {
+			return nil, fmt.Errorf("error finding tag %s: %w", *m.PrimaryTag, err)
+		}
+
+		if tag == nil {
+			if !createMissing {
+				continue
+			}
+
+			newTag := models.NewTag()
+			newTag.Name = *m.PrimaryTag
+			if !dryRun {
+				if err := tagFinderCreator.Create(ctx, &newTag); err != nil {
+					return nil, fmt.Errorf("error creating tag: %w", err)
+				}
+			}
+			tag = &newTag
+		}
+
+		if markerExists(existing, seconds, tag.ID) || newMarkerExists(ret, seconds, tag) {
+			continue
+		}
+
+		marker := models.NewSceneMarker()
+		marker.SceneID = sceneID
+		marker.Seconds = seconds
+		marker.PrimaryTagID = tag.ID
+		if m.Title != nil {
+			marker.Title = *m.Title
+		}
+
+		ret = append(ret, newMarker{
+			marker:     &marker,
+			primaryTag: tag.Name,
+		})
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,155 @@
+package identify
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_getMarkers(t *testing.T) {
+	const (
+		sceneID       = 1
+		existingTagID = 2
+		createdTagID  = 3
+	)
+
+	var (
+		existingTag = "existing"
+		missingTag  = "missing"
+		title       = "title"
+		existingAt  = "10"
+		newAt       = "20.5"
+		invalidAt   = "invalid"
+		boolTrue    = true
+	)
+
+	db := mocks.NewDatabase()
+	db.SceneMarker.On("FindBySceneID", testCtx, sceneID).Return([]*models.SceneMarker{
+		{SceneID: sceneID, Seconds: 10, PrimaryTagID: existingTagID},
+	}, nil)
+	db.Tag.On("FindByName", testCtx, existingTag, true).Return(&models.Tag{ID: existingTagID, Name: existingTag}, nil)
+	db.Tag.On("FindByName", testCtx, missingTag, true).Return(nil, nil)
+	db.Tag.On("Create", testCtx, mock.MatchedBy(func(t *models.Tag) bool {
+		return t.Name == missingTag
+	})).Run(func(args mock.Arguments) {
+		t := args.Get(1).(*models.Tag)
+		t.ID = createdTagID
+	}).Return(nil)
+
+	scraped := []*models.ScrapedSceneMarker{
+		// exists
+		{Seconds: &existingAt, PrimaryTag: &existingTag},
+		// invalid
+		{Seconds: &invalidAt, PrimaryTag: &existingTag},
+		{Seconds: &newAt},
+		// new
+		{Title: &title, Seconds: &newAt, PrimaryTag: &existingTag},
+		// duplicate
+		{Seconds: &newAt, PrimaryTag: &existingTag},
+		// missing tag
+		{Seconds: &existingAt, PrimaryTag: &missingTag},
+	}
+
+	type marker struct {
+		title        string
+		seconds      float64
+		primaryTagID int
+	}
+
+	tests := []struct {
+		name         string
+		fieldOptions *FieldOptions
+		dryRun       bool
+		want         []marker
+	}{
+		{
+			"ignore",
+			&FieldOptions{Strategy: FieldStrategyIgnore},
+			false,
+			nil,
+		},
+		{
+			"merge",
+			nil,
+			false,
+			[]marker{{title, 20.5, existingTagID}},
+		},
+		{
+			"dry run create missing",
+			&FieldOptions{Strategy: FieldStrategyMerge, CreateMissing: &boolTrue},
+			true,
+			[]marker{{title, 20.5, existingTagID}, {"", 10, 0}},
+		},
+		{
+			"create missing",
+			&FieldOptions{Strategy: FieldStrategyMerge, CreateMissing: &boolTrue},
+			false,
+			[]marker{{title, 20.5, existingTagID}, {"", 10, createdTagID}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getMarkers(testCtx, db.SceneMarker, db.Tag, sceneID, scraped, tt.fieldOptions, tt.dryRun)
+			if err != nil {
+				t.Errorf("getMarkers() error = %v", err)
+				return
+			}
+
+			var gotMarkers []marker
+			for _, m := range got {
+				assert.Equal(t, sceneID, m.marker.SceneID)
+				gotMarkers = append(gotMarkers, marker{m.marker.Title, m.marker.Seconds, m.marker.PrimaryTagID})
+			}
+			assert.Equal(t, tt.want, gotMarkers)
+		})
+	}
+
+	// tag is only created when not a dry run
+	db.Tag.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func Test_getMarkers_dryRunMissingTagDuplicates(t *testing.T) {
+	const sceneID = 1
+
+	var (
+		missingTag   = "missing"
+		otherCaseTag = "Missing"
+		otherTag     = "other"
+		at           = "10"
+		closeAt      = "10.5"
+		laterAt      = "30"
+		boolTrue     = true
+	)
+
+	db := mocks.NewDatabase()
+	db.SceneMarker.On("FindBySceneID", testCtx, sceneID).Return(nil, nil)
+	db.Tag.On("FindByName", testCtx, mock.Anything, true).Return(nil, nil)
+
+	scraped := []*models.ScrapedSceneMarker{
+		{Seconds: &at, PrimaryTag: &missingTag},
+		// duplicates of the first marker
+		{Seconds: &closeAt, PrimaryTag: &missingTag},
+		{Seconds: &at, PrimaryTag: &otherCaseTag},
+		// different tag or time
+		{Seconds: &at, PrimaryTag: &otherTag},
+		{Seconds: &laterAt, PrimaryTag: &missingTag},
+	}
+
+	fieldOptions := &FieldOptions{Strategy: FieldStrategyMerge, CreateMissing: &boolTrue}
+	got, err := getMarkers(testCtx, db.SceneMarker, db.Tag, sceneID, scraped, fieldOptions, true)
+	if err != nil {
+		t.Errorf("getMarkers() error = %v", err)
+		return
+	}
+
+	var names []string
+	for _, m := range got {
+		names = append(names, m.String())
+	}
+	assert.Equal(t, []string{"missing at 10s", "other at 10s", "missing at 30s"}, names)
+
+	db.Tag.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,94 @@
+package identify
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+type MovieCreator interface {
+	models.MovieGetter
+	models.MovieCreator
+	UpdateFrontImage(ctx context.Context, movieID int, frontImage []byte) error
+	UpdateBackImage(ctx context.Context, movieID int, backImage []byte) error
+}
+
+func getMovieID(ctx context.Context, w MovieCreator, m *models.ScrapedMovie, createMissing bool) (*int, error) {
+	if m.StoredID != nil {
+		// existing movie, just add it
+		movieID, err := strconv.Atoi(*m.StoredID)
+		if err != nil {
+			return nil, fmt.Errorf("error converting movie ID %s: %w", *m.StoredID, err)
+		}
+
+		return &movieID, nil
+	} else if createMissing && m.Name != nil { // name is mandatory
+		return createMissingMovie(ctx, w, m)
+	}
+
+	return nil, nil
+}
+
+func createMissingMovie(ctx context.Context, w MovieCreator, m *models.ScrapedMovie) (*int, error) {
+	newMovie := models.NewMovie()
+	newMovie.Name = *m.Name
+
+	if m.Aliases != nil {
+		newMovie.Aliases = *m.Aliases
+	}
+	if m.Director != nil {
+		newMovie.Director = *m.Director
+	}
+	if m.Synopsis != nil {
+		newMovie.Synopsis = *m.Synopsis
+	}
+	if m.URL != nil {
+		newMovie.URL = *m.URL
+	}
+	if m.Date != nil {
+		d, err := models.ParseDate(*m.Date)
+		if err == nil {
+			newMovie.Date = &d
+		}
+	}
+	if m.Duration != nil {
+		// only set if the duration is scraped in seconds
+		duration, err := strconv.Atoi(*m.Duration)
+		if err == nil {
+			newMovie.Duration = &duration
+		}
+	}
+	if m.Studio != nil && m.Studio.StoredID != nil {
+		studioID, err := strconv.Atoi(*m.Studio.StoredID)
+		if err == nil {
+			newMovie.StudioID = &studioID
+		}
+	}
+
+	if err := w.Create(ctx, &newMovie); err != nil {
+		return nil, fmt.Errorf("error creating movie: %w", err)
+	}
+
+	if m.FrontImage != nil && *m.FrontImage != "" {
+		image, err := utils.ProcessImageInput(ctx, *m.FrontImage)
+		if err != nil {
+			logger.Warnf("Error processing front image of movie %s: %v", newMovie.Name, err)
+		} else if err := w.UpdateFrontImage(ctx, newMovie.ID, image); err != nil {
+			return nil, err
+		}
+	}
+	if m.BackImage != nil && *m.BackImage != "" {
+		image, err := utils.ProcessImageInput(ctx, *m.BackImage)
+		if err != nil {
+			logger.Warnf("Error processing back image of movie %s: %v", newMovie.Name, err)
+		} else if err := w.UpdateBackImage(ctx, newMovie.ID, image); err != nil {
+			return nil, err
+		}
+	}
+
+	return &newMovie.ID, nil
+}
@@ -15,14 +15,39 @@ type Source struct {
 }
 
 type Options struct {
-	// An ordered list of sources to identify items with. Only the first source that finds a match is used.
+	// An ordered list of sources to identify items with. Only the first source that finds a match meeting its match threshold is used.
 	Sources []*Source `json:"sources"`
 	// Options defined here override the configured defaults
 	Options *MetadataOptions `json:"options"`
 	// scene ids to identify
 	SceneIDs []string `json:"sceneIDs"`
-	// paths of scenes to identify - ignored if scene ids are set
+	// gallery ids to identify
+	GalleryIDs []string `json:"galleryIDs"`
+	// paths of scenes to identify - ignored if scene or gallery ids are set
 	Paths []string `json:"paths"`
+	// if true, galleries in the paths are identified as well as scenes - ignored if scene or gallery ids are set
+	IncludeGalleries *bool `json:"includeGalleries"`
+	// if true, proposed changes are stored for review instead of being applied.
+	// Only supported for scenes - galleries are not identified in review mode.
+	Review *bool `json:"review"`
+}
+
+type PerformerOptions struct {
+	// An ordered list of sources to identify performers with. Only the first source that finds a match is used.
+	Sources []*Source `json:"sources"`
+	// Only FieldOptions and SkipMultipleMatches are used
+	Options *MetadataOptions `json:"options"`
+	// performer ids to identify - all performers are identified if not set
+	PerformerIDs []string `json:"performerIDs"`
+}
+
+type StudioOptions struct {
+	// An ordered list of sources to identify studios with. Only the first source that finds a match is used.
+	Sources []*Source `json:"sources"`
+	// Only FieldOptions and SkipMultipleMatches are used
+	Options *MetadataOptions `json:"options"`
+	// studio ids to identify - all studios are identified if not set
+	StudioIDs []string `json:"studioIDs"`
 }
 
 type MetadataOptions struct {
@@ -41,13 +66,18 @@ type MetadataOptions struct {
 	SkipSingleNamePerformers *bool `json:"skipSingleNamePerformers"`
 	// ID of tag to tag skipped single name performers with
 	SkipSingleNamePerformerTag *string `json:"skipSingleNamePerformerTag"`
+	// minimum score between 0 and 1 for a candidate to be accepted - defaults to 0
+	MatchThreshold *float64 `json:"matchThreshold"`
 }
 
 type FieldOptions struct {
 	Field    string        `json:"field"`
 	Strategy FieldStrategy `json:"strategy"`
-	// creates missing objects if needed - only applicable for performers, tags and studios
+	// creates missing objects if needed - only applicable for performers, tags, studios, movies and markers
 	CreateMissing *bool `json:"createMissing"`
+	// performers only - if true, a scraped performer with a disambiguation is
+	// only matched to an existing performer with the same disambiguation
+	StrictDisambiguation *bool `json:"strictDisambiguation"`
 }
 
 type FieldStrategy string
@@ -62,17 +92,26 @@ const (
 	//   For multi-value fields, any existing values are removed and replaced with the
 	//   scraped values.
 	FieldStrategyOverwrite FieldStrategy = "OVERWRITE"
+	// For text fields, replaces the value if the existing value is empty or
+	// shorter than the scraped value.
+	// Otherwise behaves as MERGE.
+	FieldStrategyOverwriteIfShorter FieldStrategy = "OVERWRITE_IF_SHORTER"
+	// For details, appends the scraped value to the existing value.
+	// Otherwise behaves as MERGE.
+	FieldStrategyAppend FieldStrategy = "APPEND"
 )
 
 var AllFieldStrategy = []FieldStrategy{
 	FieldStrategyIgnore,
 	FieldStrategyMerge,
 	FieldStrategyOverwrite,
+	FieldStrategyOverwriteIfShorter,
+	FieldStrategyAppend,
 }
 
 func (e FieldStrategy) IsValid() bool {
 	switch e {
-	case FieldStrategyIgnore, FieldStrategyMerge, FieldStrategyOverwrite:
+	case FieldStrategyIgnore, FieldStrategyMerge, FieldStrategyOverwrite, FieldStrategyOverwriteIfShorter, FieldStrategyAppend:
 		return true
 	}
 	return false
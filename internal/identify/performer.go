@@ -11,10 +11,12 @@ import (
 
 type PerformerCreator interface {
 	models.PerformerCreator
+	models.PerformerGetter
+	FindByNames(ctx context.Context, names []string, nocase bool) ([]*models.Performer, error)
 	UpdateImage(ctx context.Context, performerID int, image []byte) error
 }
 
-func getPerformerID(ctx context.Context, endpoint string, w PerformerCreator, p *models.ScrapedPerformer, createMissing bool, skipSingleNamePerformers bool) (*int, error) {
+func getPerformerID(ctx context.Context, endpoint string, w PerformerCreator, p *models.ScrapedPerformer, createMissing bool, skipSingleNamePerformers bool, strictDisambiguation bool) (*int, error) {
 	if p.StoredID != nil {
 		// existing performer, just add it
 		performerID, err := strconv.Atoi(*p.StoredID)
@@ -22,18 +24,75 @@ func getPerformerID(ctx context.Context, endpoint string, w PerformerCreator, p
 			return nil, fmt.Errorf("error converting performer ID %s: %w", *p.StoredID, err)
 		}
 
-		return &performerID, nil
-	} else if createMissing && p.Name != nil { // name is mandatory
+		matched, err := matchesDisambiguation(ctx, w, performerID, p, strictDisambiguation)
+		if err != nil {
+			return nil, err
+		}
+
+		if matched {
+			return &performerID, nil
+		}
+	}
+
+	if createMissing && p.Name != nil { // name is mandatory
 		// skip single name performers with no disambiguation
 		if skipSingleNamePerformers && !strings.Contains(*p.Name, " ") && (p.Disambiguation == nil || len(*p.Disambiguation) == 0) {
 			return nil, ErrSkipSingleNamePerformer
 		}
+
+		// don't create a duplicate of an existing performer
+		existingID, err := findPerformerByDisambiguation(ctx, w, *p.Name, p.Disambiguation)
+		if err != nil {
+			return nil, err
+		}
+		if existingID != nil {
+			return existingID, nil
+		}
+
 		return createMissingPerformer(ctx, endpoint, w, p)
 	}
 
 	return nil, nil
 }
 
+// matchesDisambiguation returns false if strictDisambiguation is true and the
+// scraped performer has a disambiguation that differs from the disambiguation
+// of the performer with the provided id.
+func matchesDisambiguation(ctx context.Context, w PerformerCreator, performerID int, p *models.ScrapedPerformer, strictDisambiguation bool) (bool, error) {
+	if !strictDisambiguation || p.Disambiguation == nil || *p.Disambiguation == "" {
+		return true, nil
+	}
+
+	existing, err := w.Find(ctx, performerID)
+	if err != nil {
+		return false, fmt.Errorf("error finding performer %d: %w", performerID, err)
+	}
+
+	return existing != nil && strings.EqualFold(existing.Disambiguation, *p.Disambiguation), nil
+}
+
+// findPerformerByDisambiguation returns the id of the performer with the
+// provided name and disambiguation. Returns nil if not found.
+func findPerformerByDisambiguation(ctx context.Context, w PerformerCreator, name string, disambiguation *string) (*int, error) {
+	performers, err := w.FindByNames(ctx, []string{name}, true)
+	if err != nil {
+		return nil, fmt.Errorf("error finding performers named %s: %w", name, err)
+	}
+
+	d := ""
+	if disambiguation != nil {
+		d = *disambiguation
+	}
+
+	for _, existing := range performers {
+		if strings.EqualFold(existing.Disambiguation, d) {
+			return &existing.ID, nil
+		}
+	}
+
+	return nil, nil
+}
+
 func createMissingPerformer(ctx context.Context, endpoint string, w PerformerCreator, p *models.ScrapedPerformer) (*int, error) {
 	newPerformer := p.ToPerformer(endpoint, nil)
 	performerImage, err := p.GetImage(ctx, nil)
@@ -0,0 +1,312 @@
+package identify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/performer"
+	"github.com/stashapp/stash/pkg/txn"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+type PerformerScraper interface {
+	ScrapePerformersByName(ctx context.Context, name string) ([]*models.ScrapedPerformer, error)
+	// returns nil if the url cannot be scraped
+	ScrapePerformerByURL(ctx context.Context, url string) (*models.ScrapedPerformer, error)
+}
+
+// PerformerIdentifier identifies performers using the performer scrapers of
+// the sources. Sources without a performer scraper are skipped.
+//
+// Performers with a URL are scraped using the URL. Otherwise, performers are
+// scraped by name, and only results with a name or alias matching the
+// performer are considered. The first source returning a match is used.
+type PerformerIdentifier struct {
+	TxnManager            txn.Manager
+	PerformerReaderWriter models.PerformerReaderWriter
+	TagFinderCreator      models.TagFinderCreator
+	DefaultOptions        *MetadataOptions
+	Sources               []ScraperSource
+}
+
+type performerScrapeResult struct {
+	result *models.ScrapedPerformer
+	source ScraperSource
+}
+
+func (t *PerformerIdentifier) Identify(ctx context.Context, p *models.Performer) error {
+	if err := txn.WithReadTxn(ctx, t.TxnManager, func(ctx context.Context) error {
+		return p.LoadAliases(ctx, t.PerformerReaderWriter)
+	}); err != nil {
+		return fmt.Errorf("loading aliases: %w", err)
+	}
+
+	result, err := t.scrapePerformer(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	if result == nil {
+		logger.Debugf("Unable to identify performer %s", p.Name)
+		return nil
+	}
+
+	if err := t.modifyPerformer(ctx, p, result); err != nil {
+		return fmt.Errorf("error modifying performer: %w", err)
+	}
+
+	return nil
+}
+
+func (t *PerformerIdentifier) scrapePerformer(ctx context.Context, p *models.Performer) (*performerScrapeResult, error) {
+	for _, source := range t.Sources {
+		s := source.PerformerScraper
+		if s == nil {
+			continue
+		}
+
+		result, err := t.scrapeWithSource(ctx, p, source)
+		if err != nil {
+			var multipleMatchErr *MultipleMatchesFoundError
+			if errors.As(err, &multipleMatchErr) {
+				logger.Debugf("Identify skipped because multiple results returned for performer %s", p.Name)
+				return nil, nil
+			}
+
+			logger.Errorf("error scraping from %v: %v", s, err)
+			continue
+		}
+
+		if result != nil {
+			return &performerScrapeResult{
+				result: result,
+				source: source,
+			}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (t *PerformerIdentifier) scrapeWithSource(ctx context.Context, p *models.Performer, source ScraperSource) (*models.ScrapedPerformer, error) {
+	s := source.PerformerScraper
+
+	if p.URL != "" {
+		result, err := s.ScrapePerformerByURL(ctx, p.URL)
+		if err != nil {
+			return nil, err
+		}
+
+		if result != nil {
+			return result, nil
+		}
+	}
+
+	results, err := s.ScrapePerformersByName(ctx, p.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*models.ScrapedPerformer
+	for _, r := range results {
+		if r.Name == nil || !nameMatches(*r.Name, p.Name, p.Aliases.List()) {
+			continue
+		}
+
+		// a different disambiguation indicates a different performer
+		if r.Disambiguation != nil && *r.Disambiguation != "" && p.Disambiguation != "" && *r.Disambiguation != p.Disambiguation {
+			continue
+		}
+
+		matches = append(matches, r)
+	}
+
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	options := mergeOptions(t.DefaultOptions, source)
+	if len(matches) > 1 && utils.IsTrue(options.SkipMultipleMatches) {
+		return nil, &MultipleMatchesFoundError{
+			Source: source,
+		}
+	}
+
+	match := matches[0]
+
+	// name search results are often incomplete, so scrape the full details
+	// using the url if possible
+	if match.URL != nil && *match.URL != "" {
+		result, err := s.ScrapePerformerByURL(ctx, *match.URL)
+		if err != nil {
+			return nil, err
+		}
+
+		if result != nil {
+			return result, nil
+		}
+	}
+
+	return match, nil
+}
+
+func (t *PerformerIdentifier) modifyPerformer(ctx context.Context, p *models.Performer, result *performerScrapeResult) error {
+	fieldOptions := mergeFieldOptions(t.DefaultOptions, result.source)
+	scraped := result.result
+
+	var image []byte
+	if getFieldStrategy(fieldOptions["image"]) != FieldStrategyIgnore {
+		var err error
+		image, err = scraped.GetImage(ctx, nil)
+		if err != nil {
+			logger.Warnf("Error processing scraped image for performer %s: %v", p.Name, err)
+		}
+	}
+
+	return txn.WithTxn(ctx, t.TxnManager, func(ctx context.Context) error {
+		qb := t.PerformerReaderWriter
+
+		if err := p.LoadTagIDs(ctx, qb); err != nil {
+			return err
+		}
+
+		partial := getPerformerPartial(p, scraped, fieldOptions)
+
+		tagIDs, err := getTagIDs(ctx, t.TagFinderCreator, p.TagIDs.List(), scraped.Tags, fieldOptions["tags"])
+		if err != nil {
+			return err
+		}
+		if tagIDs != nil {
+			partial.TagIDs = &models.UpdateIDs{
+				IDs:  tagIDs,
+				Mode: models.RelationshipUpdateModeSet,
+			}
+		}
+
+		setImage := false
+		if len(image) > 0 {
+			existing, err := qb.GetImage(ctx, p.ID)
+			if err != nil {
+				return fmt.Errorf("getting existing image: %w", err)
+			}
+			setImage = shouldSetImage(fieldOptions["image"], existing, image)
+		}
+
+		if performerPartialIsEmpty(partial) && !setImage {
+			logger.Debugf("Nothing to set for performer %s", p.Name)
+			return nil
+		}
+
+		if err := performer.ValidateUpdate(ctx, p.ID, partial, qb); err != nil {
+			return err
+		}
+
+		if _, err := qb.UpdatePartial(ctx, p.ID, partial); err != nil {
+			return fmt.Errorf("error updating performer: %w", err)
+		}
+
+		if setImage {
+			if err := qb.UpdateImage(ctx, p.ID, image); err != nil {
+				return fmt.Errorf("error updating performer image: %w", err)
+			}
+		}
+
+		logger.Infof("Successfully identified performer %s using %s", p.Name, result.source.Name)
+
+		return nil
+	})
+}
+
+// getPerformerPartial returns the changes to make to the performer. The
+// performer name is never changed. Performer aliases must be loaded.
+func getPerformerPartial(p *models.Performer, scraped *models.ScrapedPerformer, fieldOptions map[string]*FieldOptions) models.PerformerPartial {
+	partial := models.NewPerformerPartial()
+
+	setString := func(field string, existing string, scraped *string, canAppend bool) models.OptionalString {
+		if v := getStringFieldValue(fieldOptions[field], existing, scraped, canAppend); v != nil {
+			return models.NewOptionalString(*v)
+		}
+		return models.OptionalString{}
+	}
+
+	partial.Disambiguation = setString("disambiguation", p.Disambiguation, scraped.Disambiguation, false)
+	partial.URL = setString("url", p.URL, scraped.URL, false)
+	partial.Twitter = setString("twitter", p.Twitter, scraped.Twitter, false)
+	partial.Instagram = setString("instagram", p.Instagram, scraped.Instagram, false)
+	partial.Ethnicity = setString("ethnicity", p.Ethnicity, scraped.Ethnicity, false)
+	partial.EyeColor = setString("eye_color", p.EyeColor, scraped.EyeColor, false)
+	partial.HairColor = setString("hair_color", p.HairColor, scraped.HairColor, false)
+	partial.Measurements = setString("measurements", p.Measurements, scraped.Measurements, false)
+	partial.FakeTits = setString("fake_tits", p.FakeTits, scraped.FakeTits, false)
+	partial.CareerLength = setString("career_length", p.CareerLength, scraped.CareerLength, false)
+	partial.Tattoos = setString("tattoos", p.Tattoos, scraped.Tattoos, false)
+	partial.Piercings = setString("piercings", p.Piercings, scraped.Piercings, false)
+	partial.Details = setString("details", p.Details, scraped.Details, true)
+
+	if scraped.Country != nil && *scraped.Country != p.Country && shouldSetSingleValueField(fieldOptions["country"], p.Country != "") {
+		partial.Country = models.NewOptionalString(*scraped.Country)
+	}
+
+	if scraped.Gender != nil && shouldSetSingleValueField(fieldOptions["gender"], p.Gender != nil) {
+		v := models.GenderEnum(*scraped.Gender)
+		if v.IsValid() && (p.Gender == nil || *p.Gender != v) {
+			partial.Gender = models.NewOptionalString(v.String())
+		}
+	}
+
+	if scraped.Circumcised != nil && shouldSetSingleValueField(fieldOptions["circumcised"], p.Circumcised != nil) {
+		v := models.CircumisedEnum(*scraped.Circumcised)
+		if v.IsValid() && (p.Circumcised == nil || *p.Circumcised != v) {
+			partial.Circumcised = models.NewOptionalString(v.String())
+		}
+	}
+
+	if scraped.Birthdate != nil && shouldSetSingleValueField(fieldOptions["birthdate"], p.Birthdate != nil) {
+		d, err := models.ParseDate(*scraped.Birthdate)
+		if err == nil && (p.Birthdate == nil || *p.Birthdate != d) {
+			partial.Birthdate = models.NewOptionalDate(d)
+		}
+	}
+
+	if scraped.DeathDate != nil && shouldSetSingleValueField(fieldOptions["death_date"], p.DeathDate != nil) {
+		d, err := models.ParseDate(*scraped.DeathDate)
+		if err == nil && (p.DeathDate == nil || *p.DeathDate != d) {
+			partial.DeathDate = models.NewOptionalDate(d)
+		}
+	}
+
+	if scraped.Height != nil && shouldSetSingleValueField(fieldOptions["height"], p.Height != nil) {
+		h, err := strconv.Atoi(*scraped.Height)
+		if err == nil && (p.Height == nil || *p.Height != h) {
+			partial.Height = models.NewOptionalInt(h)
+		}
+	}
+
+	if scraped.Weight != nil && shouldSetSingleValueField(fieldOptions["weight"], p.Weight != nil) {
+		w, err := strconv.Atoi(*scraped.Weight)
+		if err == nil && (p.Weight == nil || *p.Weight != w) {
+			partial.Weight = models.NewOptionalInt(w)
+		}
+	}
+
+	if scraped.PenisLength != nil && shouldSetSingleValueField(fieldOptions["penis_length"], p.PenisLength != nil) {
+		l, err := strconv.ParseFloat(*scraped.PenisLength, 64)
+		if err == nil && (p.PenisLength == nil || *p.PenisLength != l) {
+			partial.PenisLength = models.NewOptionalFloat64(l)
+		}
+	}
+
+	partial.Aliases = getAliasesUpdate(fieldOptions["aliases"], p.Name, p.Aliases.List(), scraped.Aliases)
+
+	return partial
+}
+
+func performerPartialIsEmpty(p models.PerformerPartial) bool {
+	// UpdatedAt is always set, so ignore it
+	p.UpdatedAt = models.OptionalTime{}
+	return p == models.PerformerPartial{}
+}
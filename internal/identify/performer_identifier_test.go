@@ -0,0 +1,268 @@
+package identify
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stashapp/stash/pkg/sliceutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockPerformerScraper struct {
+	errNames []string
+	byName   map[string][]*models.ScrapedPerformer
+	byURL    map[string]*models.ScrapedPerformer
+}
+
+func (s mockPerformerScraper) ScrapePerformersByName(ctx context.Context, name string) ([]*models.ScrapedPerformer, error) {
+	if sliceutil.Contains(s.errNames, name) {
+		return nil, errors.New("scrape performer error")
+	}
+	return s.byName[name], nil
+}
+
+func (s mockPerformerScraper) ScrapePerformerByURL(ctx context.Context, url string) (*models.ScrapedPerformer, error) {
+	return s.byURL[url], nil
+}
+
+func TestPerformerIdentifier_Identify(t *testing.T) {
+	const (
+		errID = iota + 1
+		missingID
+		urlID
+		nameID
+		aliasID
+		secondSourceID
+		multiFoundID
+		disambiguationID
+	)
+
+	var (
+		errName            = "errName"
+		missingName        = "missingName"
+		urlName            = "urlName"
+		nameName           = "nameName"
+		aliasName          = "aliasName"
+		alias              = "alias"
+		secondSourceName   = "secondSourceName"
+		multiFoundName     = "multiFoundName"
+		disambiguationName = "disambiguationName"
+
+		performerURL  = "performerURL"
+		detailsURL    = "detailsURL"
+		otherName     = "otherName"
+		disambig      = "disambig"
+		otherDisambig = "otherDisambig"
+
+		urlDetails    = "urlDetails"
+		nameDetails   = "nameDetails"
+		searchDetails = "searchDetails"
+		aliasDetails  = "aliasDetails"
+		secondDetails = "secondDetails"
+
+		boolTrue = true
+	)
+
+	upperName := "NAMENAME"
+	image := testPNG(10, 10)
+	imageData := "data:image/png;base64," + base64.StdEncoding.EncodeToString(image)
+
+	sources := []ScraperSource{
+		{
+			// gallery only source is skipped
+			Name: "gallery only",
+			GalleryScraper: mockGalleryScraper{
+				errIDs: []int{urlID},
+			},
+		},
+		{
+			Name: "first",
+			PerformerScraper: mockPerformerScraper{
+				errNames: []string{errName},
+				byName: map[string][]*models.ScrapedPerformer{
+					nameName: {
+						{Name: &otherName, Details: &searchDetails},
+						{Name: &upperName, URL: &detailsURL, Details: &searchDetails},
+					},
+					aliasName: {
+						{Name: &alias, Details: &aliasDetails},
+					},
+					multiFoundName: {
+						{Name: &multiFoundName},
+						{Name: &multiFoundName},
+					},
+					disambiguationName: {
+						{Name: &disambiguationName, Disambiguation: &otherDisambig},
+					},
+				},
+				byURL: map[string]*models.ScrapedPerformer{
+					performerURL: {Name: &urlName, Details: &urlDetails, Images: []string{imageData}},
+					detailsURL:   {Name: &nameName, Details: &nameDetails},
+				},
+			},
+		},
+		{
+			Name: "second",
+			PerformerScraper: mockPerformerScraper{
+				byName: map[string][]*models.ScrapedPerformer{
+					secondSourceName: {{Name: &secondSourceName, Details: &secondDetails}},
+				},
+			},
+		},
+	}
+
+	db := mocks.NewDatabase()
+
+	db.Performer.On("GetAliases", mock.Anything, aliasID).Return([]string{alias}, nil)
+	db.Performer.On("GetAliases", mock.Anything, mock.Anything).Return(nil, nil)
+	db.Performer.On("GetTagIDs", mock.Anything, mock.Anything).Return(nil, nil)
+	db.Performer.On("GetImage", mock.Anything, mock.Anything).Return(nil, nil)
+
+	detailsUpdated := func(id int, details string) {
+		db.Performer.On("Find", mock.Anything, id).Return(&models.Performer{ID: id}, nil).Once()
+		db.Performer.On("UpdatePartial", mock.Anything, id, mock.MatchedBy(func(p models.PerformerPartial) bool {
+			return p.Details.Value == details
+		})).Return(nil, nil).Once()
+	}
+	detailsUpdated(urlID, urlDetails)
+	db.Performer.On("UpdateImage", mock.Anything, urlID, image).Return(nil).Once()
+	detailsUpdated(nameID, nameDetails)
+	detailsUpdated(aliasID, aliasDetails)
+	detailsUpdated(secondSourceID, secondDetails)
+
+	identifier := PerformerIdentifier{
+		TxnManager:            db,
+		PerformerReaderWriter: db.Performer,
+		TagFinderCreator:      db.Tag,
+		DefaultOptions: &MetadataOptions{
+			SkipMultipleMatches: &boolTrue,
+		},
+		Sources: sources,
+	}
+
+	performers := []*models.Performer{
+		{ID: errID, Name: errName},
+		{ID: missingID, Name: missingName},
+		{ID: urlID, Name: urlName, URL: performerURL},
+		{ID: nameID, Name: nameName},
+		{ID: aliasID, Name: aliasName},
+		{ID: secondSourceID, Name: secondSourceName},
+		{ID: multiFoundID, Name: multiFoundName},
+		{ID: disambiguationID, Name: disambiguationName, Disambiguation: disambig},
+	}
+
+	for _, p := range performers {
+		if err := identifier.Identify(testCtx, p); err != nil {
+			t.Errorf("PerformerIdentifier.Identify() id %d error = %v", p.ID, err)
+		}
+	}
+
+	db.AssertExpectations(t)
+}
+
+func Test_getPerformerPartial(t *testing.T) {
+	var (
+		originalDetails = "originalDetails"
+		originalCountry = "originalCountry"
+		originalHeight  = 150
+		originalAlias   = "originalAlias"
+
+		scrapedDetails   = "scrapedDetails"
+		scrapedCountry   = "scrapedCountry"
+		scrapedHeight    = "160"
+		scrapedBirthdate = "2000-01-01"
+		scrapedGender    = models.GenderEnumFemale.String()
+		invalidGender    = "invalid"
+		scrapedAliases   = "scrapedAlias, originalAlias"
+		scrapedTwitter   = "scrapedTwitter"
+	)
+
+	scrapedBirthdateObj, _ := models.ParseDate(scrapedBirthdate)
+
+	p := &models.Performer{
+		Name:    "name",
+		Details: originalDetails,
+		Country: originalCountry,
+		Height:  &originalHeight,
+		Aliases: models.NewRelatedStrings([]string{originalAlias}),
+	}
+
+	scraped := &models.ScrapedPerformer{
+		Details:   &scrapedDetails,
+		Country:   &scrapedCountry,
+		Height:    &scrapedHeight,
+		Birthdate: &scrapedBirthdate,
+		Gender:    &scrapedGender,
+		Aliases:   &scrapedAliases,
+		Twitter:   &scrapedTwitter,
+	}
+
+	overwrite := &FieldOptions{Strategy: FieldStrategyOverwrite}
+	ignore := &FieldOptions{Strategy: FieldStrategyIgnore}
+
+	tests := []struct {
+		name         string
+		scraped      *models.ScrapedPerformer
+		fieldOptions map[string]*FieldOptions
+		want         models.PerformerPartial
+	}{
+		{
+			"merge",
+			scraped,
+			nil,
+			models.PerformerPartial{
+				Birthdate: models.NewOptionalDate(scrapedBirthdateObj),
+				Gender:    models.NewOptionalString(scrapedGender),
+				Twitter:   models.NewOptionalString(scrapedTwitter),
+				Aliases: &models.UpdateStrings{
+					Values: []string{originalAlias, "scrapedAlias"},
+					Mode:   models.RelationshipUpdateModeSet,
+				},
+			},
+		},
+		{
+			"overwrite",
+			scraped,
+			map[string]*FieldOptions{
+				"details": overwrite,
+				"country": overwrite,
+				"height":  overwrite,
+				"aliases": overwrite,
+				"twitter": ignore,
+			},
+			models.PerformerPartial{
+				Details:   models.NewOptionalString(scrapedDetails),
+				Country:   models.NewOptionalString(scrapedCountry),
+				Height:    models.NewOptionalInt(160),
+				Birthdate: models.NewOptionalDate(scrapedBirthdateObj),
+				Gender:    models.NewOptionalString(scrapedGender),
+				Aliases: &models.UpdateStrings{
+					Values: []string{"scrapedAlias", originalAlias},
+					Mode:   models.RelationshipUpdateModeSet,
+				},
+			},
+		},
+		{
+			"invalid gender",
+			&models.ScrapedPerformer{
+				Gender: &invalidGender,
+			},
+			nil,
+			models.PerformerPartial{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getPerformerPartial(p, tt.scraped, tt.fieldOptions)
+
+			// UpdatedAt is always set
+			got.UpdatedAt = models.OptionalTime{}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -21,19 +21,36 @@ func Test_getPerformerID(t *testing.T) {
 	validStoredID := 1
 	remoteSiteID := "2"
 	name := "name"
+	existingName := "existing name"
+	existingID := 3
+	disambiguation := "disambiguation"
+	otherDisambiguation := "other"
+	createdID := 4
 
 	db := mocks.NewDatabase()
 
 	db.Performer.On("Create", testCtx, mock.Anything).Run(func(args mock.Arguments) {
 		p := args.Get(1).(*models.Performer)
 		p.ID = validStoredID
+		if p.Disambiguation != "" {
+			p.ID = createdID
+		}
 	}).Return(nil)
+	db.Performer.On("FindByNames", testCtx, []string{existingName}, true).Return([]*models.Performer{
+		{ID: existingID, Name: existingName, Disambiguation: disambiguation},
+	}, nil)
+	db.Performer.On("FindByNames", testCtx, mock.Anything, true).Return(nil, nil)
+	db.Performer.On("Find", testCtx, validStoredID).Return(&models.Performer{
+		ID:             validStoredID,
+		Disambiguation: disambiguation,
+	}, nil)
 
 	type args struct {
-		endpoint       string
-		p              *models.ScrapedPerformer
-		createMissing  bool
-		skipSingleName bool
+		endpoint             string
+		p                    *models.ScrapedPerformer
+		createMissing        bool
+		skipSingleName       bool
+		strictDisambiguation bool
 	}
 	tests := []struct {
 		name    string
@@ -48,6 +65,7 @@ func Test_getPerformerID(t *testing.T) {
 				&models.ScrapedPerformer{},
 				false,
 				false,
+				false,
 			},
 			nil,
 			false,
@@ -61,6 +79,7 @@ func Test_getPerformerID(t *testing.T) {
 				},
 				false,
 				false,
+				false,
 			},
 			nil,
 			true,
@@ -74,6 +93,7 @@ func Test_getPerformerID(t *testing.T) {
 				},
 				false,
 				false,
+				false,
 			},
 			&validStoredID,
 			false,
@@ -87,6 +107,7 @@ func Test_getPerformerID(t *testing.T) {
 				},
 				false,
 				false,
+				false,
 			},
 			nil,
 			false,
@@ -98,6 +119,7 @@ func Test_getPerformerID(t *testing.T) {
 				&models.ScrapedPerformer{},
 				true,
 				false,
+				false,
 			},
 			nil,
 			false,
@@ -111,6 +133,7 @@ func Test_getPerformerID(t *testing.T) {
 				},
 				true,
 				true,
+				false,
 			},
 			nil,
 			true,
@@ -125,14 +148,78 @@ func Test_getPerformerID(t *testing.T) {
 				},
 				true,
 				false,
+				false,
 			},
 			&validStoredID,
 			false,
 		},
+		{
+			"existing name and disambiguation creating",
+			args{
+				emptyEndpoint,
+				&models.ScrapedPerformer{
+					Name:           &existingName,
+					Disambiguation: &disambiguation,
+				},
+				true,
+				false,
+				false,
+			},
+			&existingID,
+			false,
+		},
+		{
+			"stored id matching disambiguation",
+			args{
+				emptyEndpoint,
+				&models.ScrapedPerformer{
+					Name:           &name,
+					StoredID:       &validStoredIDStr,
+					Disambiguation: &disambiguation,
+				},
+				false,
+				false,
+				true,
+			},
+			&validStoredID,
+			false,
+		},
+		{
+			"stored id different disambiguation not creating",
+			args{
+				emptyEndpoint,
+				&models.ScrapedPerformer{
+					Name:           &name,
+					StoredID:       &validStoredIDStr,
+					Disambiguation: &otherDisambiguation,
+				},
+				false,
+				false,
+				true,
+			},
+			nil,
+			false,
+		},
+		{
+			"stored id different disambiguation creating",
+			args{
+				emptyEndpoint,
+				&models.ScrapedPerformer{
+					Name:           &name,
+					StoredID:       &validStoredIDStr,
+					Disambiguation: &otherDisambiguation,
+				},
+				true,
+				false,
+				true,
+			},
+			&createdID,
+			false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := getPerformerID(testCtx, tt.args.endpoint, db.Performer, tt.args.p, tt.args.createMissing, tt.args.skipSingleName)
+			got, err := getPerformerID(testCtx, tt.args.endpoint, db.Performer, tt.args.p, tt.args.createMissing, tt.args.skipSingleName, tt.args.strictDisambiguation)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("getPerformerID() error = %v, wantErr %v", err, tt.wantErr)
 				return
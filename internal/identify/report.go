@@ -0,0 +1,161 @@
+package identify
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper"
+)
+
+type Outcome string
+
+const (
+	// A candidate was chosen and applied to the scene.
+	OutcomeMatched Outcome = "MATCHED"
+	// No source returned any candidates.
+	OutcomeNoMatch Outcome = "NO_MATCH"
+	// Candidates were returned, but none met the match threshold.
+	OutcomeBelowThreshold Outcome = "BELOW_THRESHOLD"
+	// More than one candidate met the match threshold and multiple matches
+	// are skipped.
+	OutcomeMultipleMatches Outcome = "MULTIPLE_MATCHES"
+)
+
+// CandidateReport summarises a scraped scene considered as a match for a scene.
+type CandidateReport struct {
+	Source       string     `json:"source"`
+	Title        string     `json:"title,omitempty"`
+	RemoteSiteID string     `json:"remote_site_id,omitempty"`
+	Score        MatchScore `json:"score"`
+	// the fields of the scene that the candidate has different values for.
+	// Relationships and the cover image are included if the candidate has any.
+	Fields []string `json:"fields,omitempty"`
+	// true if the score met the match threshold of the source
+	Accepted bool `json:"accepted"`
+	// true if the candidate was used to update the scene
+	Chosen bool `json:"chosen"`
+}
+
+func newCandidateReport(source ScraperSource, s *models.Scene, result *scraper.ScrapedScene, score MatchScore) *CandidateReport {
+	ret := &CandidateReport{
+		Source: source.Name,
+		Score:  score,
+		Fields: candidateFields(s, result),
+	}
+
+	if result.Title != nil {
+		ret.Title = *result.Title
+	}
+	if result.RemoteSiteID != nil {
+		ret.RemoteSiteID = *result.RemoteSiteID
+	}
+
+	return ret
+}
+
+func candidateFields(s *models.Scene, result *scraper.ScrapedScene) []string {
+	var ret []string
+
+	addString := func(field string, v *string, current string) {
+		if v != nil && *v != "" && *v != current {
+			ret = append(ret, field)
+		}
+	}
+
+	addString("title", result.Title, s.Title)
+	addString("code", result.Code, s.Code)
+	addString("details", result.Details, s.Details)
+	addString("director", result.Director, s.Director)
+
+	currentDate := ""
+	if s.Date != nil {
+		currentDate = s.Date.String()
+	}
+	addString("date", result.Date, currentDate)
+
+	if len(result.URLs) > 0 || result.URL != nil {
+		ret = append(ret, "urls")
+	}
+	if result.Studio != nil && (s.StudioID == nil || result.Studio.StoredID == nil || *result.Studio.StoredID != strconv.Itoa(*s.StudioID)) {
+		ret = append(ret, "studio")
+	}
+	if len(result.Performers) > 0 {
+		ret = append(ret, "performers")
+	}
+	if len(result.Tags) > 0 {
+		ret = append(ret, "tags")
+	}
+	if len(result.Movies) > 0 {
+		ret = append(ret, "movies")
+	}
+	if len(result.Markers) > 0 {
+		ret = append(ret, "markers")
+	}
+	if result.Image != nil {
+		ret = append(ret, "cover_image")
+	}
+
+	return ret
+}
+
+// SceneReport describes the candidates considered when identifying a scene,
+// and why one was chosen or none was.
+type SceneReport struct {
+	SceneID    int                `json:"scene_id"`
+	Path       string             `json:"path"`
+	Outcome    Outcome            `json:"outcome"`
+	Reason     string             `json:"reason"`
+	Candidates []*CandidateReport `json:"candidates"`
+	// errors encountered while scraping or updating the scene
+	Errors []string `json:"errors,omitempty"`
+}
+
+func newSceneReport(s *models.Scene) *SceneReport {
+	return &SceneReport{
+		SceneID: s.ID,
+		Path:    s.Path,
+		Outcome: OutcomeNoMatch,
+		Reason:  "no candidates were found",
+	}
+}
+
+func (r *SceneReport) addError(err error) {
+	r.Errors = append(r.Errors, err.Error())
+}
+
+func (r *SceneReport) bestScore() float64 {
+	var ret float64
+	for _, c := range r.Candidates {
+		if c.Score.Score > ret {
+			ret = c.Score.Score
+		}
+	}
+
+	return ret
+}
+
+func (r *SceneReport) setBelowThreshold() {
+	r.Outcome = OutcomeBelowThreshold
+	r.Reason = fmt.Sprintf("no candidate met the match threshold - best score was %.2f", r.bestScore())
+}
+
+func (r *SceneReport) setMatched(c *CandidateReport, accepted int) {
+	c.Chosen = true
+	r.Outcome = OutcomeMatched
+	if accepted > 1 {
+		r.Reason = fmt.Sprintf("chose %q from %s with the highest score %.2f of %d accepted candidates", c.Title, c.Source, c.Score.Score, accepted)
+	} else {
+		r.Reason = fmt.Sprintf("chose %q from %s with score %.2f", c.Title, c.Source, c.Score.Score)
+	}
+}
+
+func (r *SceneReport) setMultipleMatches(source ScraperSource, accepted int) {
+	r.Outcome = OutcomeMultipleMatches
+	r.Reason = fmt.Sprintf("skipped because %d candidates from %s met the match threshold", accepted, source.Name)
+}
+
+// SceneReporter receives the report of each identified scene.
+type SceneReporter interface {
+	ReportScene(report *SceneReport)
+}
@@ -0,0 +1,605 @@
+package identify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper"
+	"github.com/stashapp/stash/pkg/sliceutil"
+	"github.com/stashapp/stash/pkg/txn"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+const (
+	proposedFieldCoverImage = "cover_image"
+	proposedFieldOrganized  = "organized"
+)
+
+// fields that may be proposed, other than the cover image and organized flag
+var proposedFieldOptions = []string{
+	"title",
+	"date",
+	"details",
+	"url",
+	"director",
+	"code",
+	"studio",
+	"performers",
+	"tags",
+	"movies",
+	"markers",
+	"stash_ids",
+}
+
+// Proposal is a set of changes to a scene proposed by the identify task when
+// run in review mode. Proposals are stored as pending scene changes, and are
+// applied when accepted.
+type Proposal struct {
+	// the scraped scene that the changes were derived from
+	Result     *scraper.ScrapedScene `json:"result"`
+	RemoteSite string                `json:"remote_site,omitempty"`
+	// options of the source, merged with the defaults
+	Options MetadataOptions `json:"options"`
+
+	Fields []*ProposedField `json:"fields"`
+	// objects that will be created when the proposal is accepted
+	NewStudio     *string  `json:"new_studio,omitempty"`
+	NewPerformers []string `json:"new_performers,omitempty"`
+	NewTags       []string `json:"new_tags,omitempty"`
+}
+
+// ProposedField is a proposed change to a single scene field.
+type ProposedField struct {
+	Field    string `json:"field"`
+	Current  string `json:"current,omitempty"`
+	Proposed string `json:"proposed"`
+}
+
+func (p *Proposal) add(field string, current string, proposed string) {
+	p.Fields = append(p.Fields, &ProposedField{
+		Field:    field,
+		Current:  current,
+		Proposed: proposed,
+	})
+}
+
+// DecodeProposal decodes the proposal stored in a pending scene change.
+func DecodeProposal(c *models.PendingSceneChange) (*Proposal, error) {
+	var ret Proposal
+	if err := json.Unmarshal([]byte(c.Data), &ret); err != nil {
+		return nil, fmt.Errorf("decoding pending scene change %d: %w", c.ID, err)
+	}
+
+	return &ret, nil
+}
+
+func (t *SceneIdentifier) proposeChanges(ctx context.Context, s *models.Scene, result *scrapeResult) error {
+	return txn.WithTxn(ctx, t.TxnManager, func(ctx context.Context) error {
+		if err := t.loadSceneRelationships(ctx, s); err != nil {
+			return err
+		}
+
+		proposal, err := t.getProposal(ctx, s, result)
+		if err != nil {
+			return err
+		}
+
+		if len(proposal.Fields) == 0 {
+			logger.Debugf("Nothing to propose for %s", s.Path)
+			return nil
+		}
+
+		data, err := json.Marshal(proposal)
+		if err != nil {
+			return fmt.Errorf("encoding proposal: %w", err)
+		}
+
+		if err := t.PendingSceneChangeCreator.Create(ctx, &models.PendingSceneChange{
+			SceneID:   s.ID,
+			Source:    result.source.Name,
+			Data:      string(data),
+			CreatedAt: time.Now(),
+		}); err != nil {
+			return fmt.Errorf("creating pending scene change: %w", err)
+		}
+
+		logger.Infof("Proposed %d changes to %s using %s", len(proposal.Fields), s.Path, result.source.Name)
+
+		return nil
+	})
+}
+
+// getProposal returns the changes that would be made to the scene by the
+// result, without creating any missing objects. Scene relationships must be
+// loaded.
+func (t *SceneIdentifier) getProposal(ctx context.Context, s *models.Scene, result *scrapeResult) (*Proposal, error) {
+	options := t.getOptions(result.source)
+	fieldOptions := t.getFieldOptions(result.source)
+
+	// store the merged field options so that the proposal can be applied
+	// without the original sources
+	options.FieldOptions = nil
+	for _, f := range fieldOptions {
+		options.FieldOptions = append(options.FieldOptions, f)
+	}
+	sort.Slice(options.FieldOptions, func(i, j int) bool {
+		return options.FieldOptions[i].Field < options.FieldOptions[j].Field
+	})
+
+	scraped := result.result
+	ret := &Proposal{
+		Result:     scraped,
+		RemoteSite: result.source.RemoteSite,
+		Options:    options,
+	}
+
+	partial := getScenePartial(s, scraped, fieldOptions, utils.IsTrue(options.SetOrganized))
+	if partial.Title.Set {
+		ret.add("title", s.Title, partial.Title.Value)
+	}
+	if partial.Date.Set {
+		current := ""
+		if s.Date != nil {
+			current = s.Date.String()
+		}
+		ret.add("date", current, partial.Date.Value.String())
+	}
+	if partial.Details.Set {
+		ret.add("details", s.Details, partial.Details.Value)
+	}
+	if partial.URLs != nil {
+		ret.add("url", strings.Join(s.URLs.List(), ", "), strings.Join(partial.URLs.Values, ", "))
+	}
+	if partial.Director.Set {
+		ret.add("director", s.Director, partial.Director.Value)
+	}
+	if partial.Code.Set {
+		ret.add("code", s.Code, partial.Code.Value)
+	}
+
+	if err := t.proposeStudio(ctx, ret, s, scraped.Studio, fieldOptions["studio"]); err != nil {
+		return nil, err
+	}
+
+	current, err := t.currentRelationships(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	includeMalePerformers := options.IncludeMalePerformers == nil || *options.IncludeMalePerformers
+	ret.proposePerformers(s, current, scraped.Performers, fieldOptions["performers"], includeMalePerformers, utils.IsTrue(options.SkipSingleNamePerformers))
+	ret.proposeTags(s, current, scraped.Tags, fieldOptions["tags"])
+
+	ret.proposeMovies(s, current, scraped.Movies, fieldOptions["movies"])
+
+	markers, err := t.getMarkers(ctx, s, result, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(markers) > 0 {
+		var proposed []string
+		for _, m := range markers {
+			proposed = append(proposed, m.String())
+		}
+		ret.add("markers", "", strings.Join(proposed, ", "))
+	}
+
+	rel := sceneRelationships{
+		scene:        s,
+		result:       result,
+		fieldOptions: fieldOptions,
+	}
+	stashIDs, err := rel.stashIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if stashIDs != nil {
+		ret.add("stash_ids", "", result.source.RemoteSite+": "+*scraped.RemoteSiteID)
+	}
+
+	if utils.IsTrue(options.SetCoverImage) && scraped.Image != nil && *scraped.Image != "" {
+		setCover, err := t.shouldProposeCover(ctx, s, fieldOptions[proposedFieldCoverImage])
+		if err != nil {
+			return nil, err
+		}
+
+		if setCover {
+			proposed := *scraped.Image
+			if !strings.HasPrefix(proposed, "http") {
+				proposed = "(image data)"
+			}
+			ret.add(proposedFieldCoverImage, "", proposed)
+		}
+	}
+
+	if partial.Organized.Set {
+		ret.add(proposedFieldOrganized, "false", "true")
+	}
+
+	return ret, nil
+}
+
+func (t *SceneIdentifier) shouldProposeCover(ctx context.Context, s *models.Scene, fieldStrategy *FieldOptions) (bool, error) {
+	// the cover is overwritten if no strategy is set
+	if fieldStrategy == nil {
+		return true, nil
+	}
+
+	if getFieldStrategy(fieldStrategy) == FieldStrategyOverwrite || getFieldStrategy(fieldStrategy) == FieldStrategyIgnore {
+		return shouldSetSingleValueField(fieldStrategy, true), nil
+	}
+
+	existing, err := t.SceneReaderUpdater.GetCover(ctx, s.ID)
+	if err != nil {
+		return false, fmt.Errorf("getting scene cover: %w", err)
+	}
+
+	return len(existing) == 0, nil
+}
+
+func (t *SceneIdentifier) proposeStudio(ctx context.Context, p *Proposal, s *models.Scene, scraped *models.ScrapedStudio, fieldStrategy *FieldOptions) error {
+	if scraped == nil || !shouldSetSingleValueField(fieldStrategy, s.StudioID != nil) {
+		return nil
+	}
+
+	existing := s.StudioID != nil && scraped.StoredID != nil && *scraped.StoredID == strconv.Itoa(*s.StudioID)
+	createMissing := fieldStrategy != nil && utils.IsTrue(fieldStrategy.CreateMissing)
+	if existing || (scraped.StoredID == nil && !createMissing) {
+		return nil
+	}
+
+	current := ""
+	if s.StudioID != nil {
+		studio, err := t.StudioReaderWriter.Find(ctx, *s.StudioID)
+		if err != nil {
+			return fmt.Errorf("finding studio: %w", err)
+		}
+		if studio != nil {
+			current = studio.Name
+		}
+	}
+
+	if scraped.StoredID == nil {
+		p.NewStudio = &scraped.Name
+	}
+
+	p.add("studio", current, scraped.Name)
+
+	return nil
+}
+
+// currentRelationships contains the names of the existing relationships of a
+// scene, joined for display.
+type currentRelationships struct {
+	performers string
+	tags       string
+	movies     string
+}
+
+func (t *SceneIdentifier) currentRelationships(ctx context.Context, s *models.Scene) (currentRelationships, error) {
+	var ret currentRelationships
+
+	if ids := s.PerformerIDs.List(); len(ids) > 0 {
+		performers, err := t.PerformerCreator.FindMany(ctx, ids)
+		if err != nil {
+			return ret, fmt.Errorf("finding performers: %w", err)
+		}
+
+		var names []string
+		for _, p := range performers {
+			names = append(names, p.Name)
+		}
+		ret.performers = strings.Join(names, ", ")
+	}
+
+	if ids := s.TagIDs.List(); len(ids) > 0 {
+		tags, err := t.TagFinderCreator.FindMany(ctx, ids)
+		if err != nil {
+			return ret, fmt.Errorf("finding tags: %w", err)
+		}
+
+		var names []string
+		for _, t := range tags {
+			names = append(names, t.Name)
+		}
+		ret.tags = strings.Join(names, ", ")
+	}
+
+	if sceneMovies := s.Movies.List(); len(sceneMovies) > 0 {
+		var ids []int
+		for _, m := range sceneMovies {
+			ids = append(ids, m.MovieID)
+		}
+
+		movies, err := t.MovieCreator.FindMany(ctx, ids)
+		if err != nil {
+			return ret, fmt.Errorf("finding movies: %w", err)
+		}
+
+		var names []string
+		for i, m := range movies {
+			name := m.Name
+			if index := sceneMovies[i].SceneIndex; index != nil {
+				name += " #" + strconv.Itoa(*index)
+			}
+			names = append(names, name)
+		}
+		ret.movies = strings.Join(names, ", ")
+	}
+
+	return ret, nil
+}
+
+func (p *Proposal) proposePerformers(s *models.Scene, current currentRelationships, scraped []*models.ScrapedPerformer, fieldStrategy *FieldOptions, includeMale bool, skipSingleName bool) {
+	if len(scraped) == 0 || !shouldSetSingleValueField(fieldStrategy, false) {
+		return
+	}
+
+	createMissing := fieldStrategy != nil && utils.IsTrue(fieldStrategy.CreateMissing)
+	existing := s.PerformerIDs.List()
+
+	var names []string
+	var ids []int
+	changed := false
+	for _, sp := range scraped {
+		if sp.Name == nil || (!includeMale && sp.Gender != nil && strings.EqualFold(*sp.Gender, models.GenderEnumMale.String())) {
+			continue
+		}
+
+		if sp.StoredID != nil {
+			id, err := strconv.Atoi(*sp.StoredID)
+			if err != nil {
+				continue
+			}
+
+			ids = append(ids, id)
+			changed = changed || !sliceutil.Contains(existing, id)
+		} else if createMissing {
+			if skipSingleName && !strings.Contains(*sp.Name, " ") && (sp.Disambiguation == nil || len(*sp.Disambiguation) == 0) {
+				continue
+			}
+
+			p.NewPerformers = append(p.NewPerformers, *sp.Name)
+			changed = true
+		} else {
+			continue
+		}
+
+		names = append(names, *sp.Name)
+	}
+
+	// overwriting removes any performers not in the scraped list
+	if getFieldStrategy(fieldStrategy) == FieldStrategyOverwrite && len(sliceutil.Exclude(existing, ids)) > 0 {
+		changed = true
+	}
+
+	if changed {
+		p.add("performers", current.performers, strings.Join(names, ", "))
+	}
+}
+
+func (p *Proposal) proposeTags(s *models.Scene, current currentRelationships, scraped []*models.ScrapedTag, fieldStrategy *FieldOptions) {
+	if len(scraped) == 0 || !shouldSetSingleValueField(fieldStrategy, false) {
+		return
+	}
+
+	createMissing := fieldStrategy != nil && utils.IsTrue(fieldStrategy.CreateMissing)
+	existing := s.TagIDs.List()
+
+	var names []string
+	var ids []int
+	changed := false
+	for _, st := range scraped {
+		if st.StoredID != nil {
+			id, err := strconv.Atoi(*st.StoredID)
+			if err != nil {
+				continue
+			}
+
+			ids = append(ids, id)
+			changed = changed || !sliceutil.Contains(existing, id)
+		} else if createMissing {
+			p.NewTags = append(p.NewTags, st.Name)
+			changed = true
+		} else {
+			continue
+		}
+
+		names = append(names, st.Name)
+	}
+
+	// overwriting removes any tags not in the scraped list
+	if getFieldStrategy(fieldStrategy) == FieldStrategyOverwrite && len(sliceutil.Exclude(existing, ids)) > 0 {
+		changed = true
+	}
+
+	if changed {
+		p.add("tags", current.tags, strings.Join(names, ", "))
+	}
+}
+
+func (p *Proposal) proposeMovies(s *models.Scene, current currentRelationships, scraped []*models.ScrapedMovie, fieldStrategy *FieldOptions) {
+	if len(scraped) == 0 || !shouldSetSingleValueField(fieldStrategy, false) {
+		return
+	}
+
+	createMissing := fieldStrategy != nil && utils.IsTrue(fieldStrategy.CreateMissing)
+	existing := s.Movies.List()
+
+	var names []string
+	var ids []int
+	changed := false
+	for _, sm := range scraped {
+		if sm.Name == nil {
+			continue
+		}
+
+		if sm.StoredID != nil {
+			id, err := strconv.Atoi(*sm.StoredID)
+			if err != nil {
+				continue
+			}
+
+			ids = append(ids, id)
+			current := s.Movies.ForID(id)
+			changed = changed || current == nil || (sm.SceneIndex != nil && (current.SceneIndex == nil || strconv.Itoa(*current.SceneIndex) != *sm.SceneIndex))
+		} else if createMissing {
+			changed = true
+		} else {
+			continue
+		}
+
+		name := *sm.Name
+		if sm.SceneIndex != nil {
+			name += " #" + *sm.SceneIndex
+		}
+		names = append(names, name)
+	}
+
+	// overwriting removes any movies not in the scraped list
+	if getFieldStrategy(fieldStrategy) == FieldStrategyOverwrite {
+		for _, m := range existing {
+			if !sliceutil.Contains(ids, m.MovieID) {
+				changed = true
+			}
+		}
+	}
+
+	if changed {
+		p.add("movies", current.movies, strings.Join(names, ", "))
+	}
+}
+
+// restrictFields returns the proposal options changed so that only the
+// provided fields are set.
+func (p *Proposal) restrictFields(fields []string) MetadataOptions {
+	ret := p.Options
+	ret.FieldOptions = nil
+
+	existing := getFieldOptions([]MetadataOptions{p.Options})
+	for _, f := range proposedFieldOptions {
+		if sliceutil.Contains(fields, f) {
+			if o := existing[f]; o != nil {
+				ret.FieldOptions = append(ret.FieldOptions, o)
+			}
+			continue
+		}
+
+		ret.FieldOptions = append(ret.FieldOptions, &FieldOptions{
+			Field:    f,
+			Strategy: FieldStrategyIgnore,
+		})
+	}
+
+	if !sliceutil.Contains(fields, proposedFieldCoverImage) {
+		setCoverImage := false
+		ret.SetCoverImage = &setCoverImage
+	} else if o := existing[proposedFieldCoverImage]; o != nil {
+		ret.FieldOptions = append(ret.FieldOptions, o)
+	}
+	if !sliceutil.Contains(fields, proposedFieldOrganized) {
+		setOrganized := false
+		ret.SetOrganized = &setOrganized
+	}
+
+	return ret
+}
+
+// FetchImages downloads the images of the proposal that are stored as URLs.
+// Images that cannot be fetched are removed. If fields is not nil, then the
+// cover image is only fetched if it is included in fields. This should be
+// called before ApplyProposal, outside of a transaction.
+func (p *Proposal) FetchImages(ctx context.Context, fields []string) {
+	r := p.Result
+	if r == nil {
+		return
+	}
+
+	if fields == nil || sliceutil.Contains(fields, proposedFieldCoverImage) {
+		fetchImage(ctx, &r.Image)
+	} else {
+		r.Image = nil
+	}
+
+	if r.Studio != nil {
+		fetchImage(ctx, &r.Studio.Image)
+		r.Studio.Images = nil
+		if r.Studio.Parent != nil {
+			fetchImage(ctx, &r.Studio.Parent.Image)
+			r.Studio.Parent.Images = nil
+		}
+	}
+
+	for _, sp := range r.Performers {
+		// only the first image is used
+		if sp.Image == nil && len(sp.Images) > 0 {
+			sp.Image = &sp.Images[0]
+		}
+		sp.Images = nil
+		fetchImage(ctx, &sp.Image)
+	}
+
+	for _, sm := range r.Movies {
+		fetchImage(ctx, &sm.FrontImage)
+		fetchImage(ctx, &sm.BackImage)
+	}
+}
+
+// fetchImage replaces the image URL in v with the image data.
+func fetchImage(ctx context.Context, v **string) {
+	if *v == nil || !strings.HasPrefix(**v, "http") {
+		return
+	}
+
+	url := **v
+	data, err := utils.ReadImageFromURL(ctx, url)
+	if err != nil {
+		logger.Warnf("Could not fetch image %s: %v", url, err)
+		*v = nil
+		return
+	}
+
+	img := utils.GetBase64StringFromData(data)
+	*v = &img
+}
+
+// ApplyProposal applies the changes of a proposal from the named source to the
+// scene, creating any missing objects. If fields is not nil, then only the
+// provided fields are applied. The DefaultOptions and Sources of the
+// identifier are not used. It must be called within a transaction, and the
+// post-update hooks are executed once the transaction is committed. Images
+// should be fetched beforehand using FetchImages.
+func (t *SceneIdentifier) ApplyProposal(ctx context.Context, s *models.Scene, source string, p *Proposal, fields []string) error {
+	options := p.Options
+	if fields != nil {
+		options = p.restrictFields(fields)
+	}
+
+	identifier := *t
+	identifier.DefaultOptions = nil
+
+	updater, err := identifier.updateScene(ctx, s, &scrapeResult{
+		result: p.Result,
+		source: ScraperSource{
+			Name:       source,
+			Options:    &options,
+			RemoteSite: p.RemoteSite,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	txn.AddPostCommitHook(ctx, func(ctx context.Context) {
+		identifier.executePostHooks(ctx, updater)
+	})
+
+	return nil
+}
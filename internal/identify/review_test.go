@@ -0,0 +1,183 @@
+package identify
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stashapp/stash/pkg/scraper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSceneIdentifier_getProposal(t *testing.T) {
+	const (
+		existingPerformerID = 1
+		newPerformerName    = "New Performer"
+		newTagName          = "New Tag"
+	)
+
+	var (
+		title          = "title"
+		existingName   = "Existing Performer"
+		existingIDStr  = "1"
+		existingTagStr = "2"
+		newName        = newPerformerName
+		boolTrue       = true
+		boolFalse      = false
+	)
+
+	db := mocks.NewDatabase()
+	db.Performer.On("FindMany", testCtx, []int{existingPerformerID}).Return([]*models.Performer{
+		{ID: existingPerformerID, Name: "Current Performer"},
+	}, nil)
+
+	identifier := SceneIdentifier{
+		PerformerCreator: db.Performer,
+		DefaultOptions: &MetadataOptions{
+			SetOrganized:  &boolTrue,
+			SetCoverImage: &boolFalse,
+			FieldOptions: []*FieldOptions{
+				{Field: "performers", Strategy: FieldStrategyMerge, CreateMissing: &boolTrue},
+				{Field: "tags", Strategy: FieldStrategyMerge, CreateMissing: &boolTrue},
+			},
+		},
+	}
+
+	s := &models.Scene{
+		ID:           1,
+		URLs:         models.NewRelatedStrings([]string{}),
+		PerformerIDs: models.NewRelatedIDs([]int{existingPerformerID}),
+		TagIDs:       models.NewRelatedIDs([]int{}),
+		StashIDs:     models.NewRelatedStashIDs([]models.StashID{}),
+		Movies:       models.NewRelatedMovies([]models.MoviesScenes{}),
+	}
+
+	result := &scrapeResult{
+		result: &scraper.ScrapedScene{
+			Title: &title,
+			Performers: []*models.ScrapedPerformer{
+				{Name: &existingName, StoredID: &existingIDStr},
+				{Name: &newName},
+			},
+			Tags: []*models.ScrapedTag{
+				{Name: "Existing Tag", StoredID: &existingTagStr},
+				{Name: newTagName},
+			},
+		},
+		source: ScraperSource{Name: "source"},
+	}
+
+	p, err := identifier.getProposal(testCtx, s, result)
+	if err != nil {
+		t.Fatalf("getProposal() error = %v", err)
+	}
+
+	assert.Equal(t, []*ProposedField{
+		{Field: "title", Proposed: title},
+		{Field: "performers", Current: "Current Performer", Proposed: "Existing Performer, New Performer"},
+		{Field: "tags", Proposed: "Existing Tag, New Tag"},
+		{Field: proposedFieldOrganized, Current: "false", Proposed: "true"},
+	}, p.Fields)
+	assert.Equal(t, []string{newPerformerName}, p.NewPerformers)
+	assert.Equal(t, []string{newTagName}, p.NewTags)
+	assert.Nil(t, p.NewStudio)
+
+	// proposal options include the merged field options
+	fieldOptions := getFieldOptions([]MetadataOptions{p.Options})
+	assert.True(t, *fieldOptions["performers"].CreateMissing)
+
+	// restricting fields ignores the other fields
+	restricted := getFieldOptions([]MetadataOptions{p.restrictFields([]string{"title", "performers"})})
+	assert.Equal(t, FieldStrategyMerge, restricted["performers"].Strategy)
+	assert.True(t, *restricted["performers"].CreateMissing)
+	assert.Nil(t, restricted["title"])
+	assert.Equal(t, FieldStrategyIgnore, restricted["tags"].Strategy)
+	assert.Equal(t, FieldStrategyIgnore, restricted["date"].Strategy)
+	assert.False(t, *p.restrictFields(nil).SetOrganized)
+}
+
+func TestSceneIdentifier_Identify_review(t *testing.T) {
+	const sceneID = 1
+
+	var (
+		title    = "title"
+		boolTrue = true
+	)
+
+	db := mocks.NewDatabase()
+	db.Scene.On("GetURLs", mock.Anything, sceneID).Return(nil, nil)
+	db.Scene.On("GetPerformerIDs", mock.Anything, sceneID).Return(nil, nil)
+	db.Scene.On("GetTagIDs", mock.Anything, sceneID).Return(nil, nil)
+	db.Scene.On("GetStashIDs", mock.Anything, sceneID).Return(nil, nil)
+	db.Scene.On("GetMovies", mock.Anything, sceneID).Return(nil, nil)
+
+	db.PendingSceneChange.On("Create", mock.Anything, mock.MatchedBy(func(c *models.PendingSceneChange) bool {
+		if c.SceneID != sceneID || c.Source != "source" {
+			return false
+		}
+
+		p, err := DecodeProposal(c)
+		return err == nil && len(p.Fields) == 1 && p.Fields[0].Proposed == title
+	})).Return(nil).Once()
+
+	identifier := SceneIdentifier{
+		TxnManager:         db,
+		SceneReaderUpdater: db.Scene,
+		DefaultOptions: &MetadataOptions{
+			SetCoverImage: new(bool),
+		},
+		Sources: []ScraperSource{
+			{
+				Name: "source",
+				Scraper: mockSceneScraper{
+					results: map[int][]*scraper.ScrapedScene{
+						sceneID: {{Title: &title}},
+					},
+				},
+			},
+		},
+		SceneUpdatePostHookExecutor: mockHookExecutor{},
+		Review:                      boolTrue,
+		PendingSceneChangeCreator:   db.PendingSceneChange,
+	}
+
+	if err := identifier.Identify(testCtx, &models.Scene{ID: sceneID}); err != nil {
+		t.Errorf("SceneIdentifier.Identify() error = %v", err)
+	}
+
+	// scene must not be updated
+	db.AssertExpectations(t)
+	db.Scene.AssertNotCalled(t, "UpdatePartial", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProposal_FetchImages(t *testing.T) {
+	const imageData = "data:image/png;base64,aW1hZ2U="
+
+	var (
+		data       = imageData
+		invalidURL = "http://invalid.invalid/image.png"
+	)
+
+	p := &Proposal{
+		Result: &scraper.ScrapedScene{
+			Image: &invalidURL,
+			Performers: []*models.ScrapedPerformer{
+				{Image: &data, Images: []string{data}},
+			},
+		},
+	}
+
+	// cover image is not fetched unless it is accepted
+	p.FetchImages(testCtx, []string{"title"})
+	assert.Nil(t, p.Result.Image)
+
+	// image data is kept
+	assert.Equal(t, imageData, *p.Result.Performers[0].Image)
+	assert.Nil(t, p.Result.Performers[0].Images)
+
+	// images that cannot be fetched are removed
+	p.Result.Image = &invalidURL
+	p.FetchImages(testCtx, nil)
+	assert.Nil(t, p.Result.Image)
+}
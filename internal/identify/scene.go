@@ -3,10 +3,8 @@ package identify
 import (
 	"bytes"
 	"context"
-	"errors"
 	"fmt"
 	"strconv"
-	"strings"
 
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
@@ -25,6 +23,7 @@ type SceneReaderUpdater interface {
 	models.TagIDLoader
 	models.StashIDLoader
 	models.URLLoader
+	models.SceneMovieLoader
 }
 
 type sceneRelationships struct {
@@ -32,6 +31,7 @@ type sceneRelationships struct {
 	studioReaderWriter       models.StudioReaderWriter
 	performerCreator         PerformerCreator
 	tagCreator               models.TagCreator
+	movieCreator             MovieCreator
 	scene                    *models.Scene
 	result                   *scrapeResult
 	fieldOptions             map[string]*FieldOptions
@@ -39,38 +39,20 @@ type sceneRelationships struct {
 }
 
 func (g sceneRelationships) studio(ctx context.Context) (*int, error) {
-	existingID := g.scene.StudioID
-	fieldStrategy := g.fieldOptions["studio"]
-	createMissing := fieldStrategy != nil && utils.IsTrue(fieldStrategy.CreateMissing)
-
-	scraped := g.result.result.Studio
-	endpoint := g.result.source.RemoteSite
-
-	if scraped == nil || !shouldSetSingleValueField(fieldStrategy, existingID != nil) {
-		return nil, nil
-	}
-
-	if scraped.StoredID != nil {
-		// existing studio, just set it
-		studioID, err := strconv.Atoi(*scraped.StoredID)
-		if err != nil {
-			return nil, fmt.Errorf("error converting studio ID %s: %w", *scraped.StoredID, err)
-		}
+	return getStudioID(ctx, g.studioReaderWriter, g.result.source.RemoteSite, g.scene.StudioID, g.result.result.Studio, g.fieldOptions["studio"])
+}
 
-		// only return value if different to current
-		if existingID == nil || *existingID != studioID {
-			return &studioID, nil
-		}
-	} else if createMissing {
-		return createMissingStudio(ctx, endpoint, g.studioReaderWriter, scraped)
-	}
+func (g sceneRelationships) performers(ctx context.Context, ignoreMale bool) ([]int, error) {
+	return getPerformerIDs(ctx, g.performerCreator, g.result.source.RemoteSite, g.scene.PerformerIDs.List(), g.result.result.Performers, g.fieldOptions["performers"], ignoreMale, g.skipSingleNamePerformers)
+}
 
-	return nil, nil
+func (g sceneRelationships) tags(ctx context.Context) ([]int, error) {
+	return getTagIDs(ctx, g.tagCreator, g.scene.TagIDs.List(), g.result.result.Tags, g.fieldOptions["tags"])
 }
 
-func (g sceneRelationships) performers(ctx context.Context, ignoreMale bool) ([]int, error) {
-	fieldStrategy := g.fieldOptions["performers"]
-	scraped := g.result.result.Performers
+func (g sceneRelationships) movies(ctx context.Context) ([]models.MoviesScenes, error) {
+	fieldStrategy := g.fieldOptions["movies"]
+	scraped := g.result.result.Movies
 
 	// just check if ignored
 	if len(scraped) == 0 || !shouldSetSingleValueField(fieldStrategy, false) {
@@ -78,108 +60,91 @@ func (g sceneRelationships) performers(ctx context.Context, ignoreMale bool) ([]
 	}
 
 	createMissing := fieldStrategy != nil && utils.IsTrue(fieldStrategy.CreateMissing)
-	strategy := FieldStrategyMerge
-	if fieldStrategy != nil {
-		strategy = fieldStrategy.Strategy
-	}
 
-	endpoint := g.result.source.RemoteSite
+	var movies []models.MoviesScenes
+	originalMovies := g.scene.Movies.List()
 
-	var performerIDs []int
-	originalPerformerIDs := g.scene.PerformerIDs.List()
-
-	if strategy == FieldStrategyMerge {
+	if isMergeStrategy(fieldStrategy) {
 		// add to existing
-		performerIDs = originalPerformerIDs
+		// make a copy so we don't modify the original
+		movies = append(movies, originalMovies...)
 	}
 
-	singleNamePerformerSkipped := false
+	for _, m := range scraped {
+		movieID, err := getMovieID(ctx, g.movieCreator, m, createMissing)
+		if err != nil {
+			return nil, err
+		}
 
-	for _, p := range scraped {
-		if ignoreMale && p.Gender != nil && strings.EqualFold(*p.Gender, models.GenderEnumMale.String()) {
+		if movieID == nil {
 			continue
 		}
 
-		performerID, err := getPerformerID(ctx, endpoint, g.performerCreator, p, createMissing, g.skipSingleNamePerformers)
-		if err != nil {
-			if errors.Is(err, ErrSkipSingleNamePerformer) {
-				singleNamePerformerSkipped = true
-				continue
+		var sceneIndex *int
+		if m.SceneIndex != nil {
+			index, err := strconv.Atoi(*m.SceneIndex)
+			if err != nil {
+				logger.Warnf("Ignoring invalid scene index %q", *m.SceneIndex)
+			} else {
+				sceneIndex = &index
 			}
-			return nil, err
 		}
 
-		if performerID != nil {
-			performerIDs = sliceutil.AppendUnique(performerIDs, *performerID)
+		found := false
+		for i := range movies {
+			if movies[i].MovieID == *movieID {
+				found = true
+				// scraped scene index replaces the existing one
+				if sceneIndex != nil {
+					movies[i].SceneIndex = sceneIndex
+				}
+			}
 		}
-	}
 
-	// don't return if nothing was added
-	if sliceutil.SliceSame(originalPerformerIDs, performerIDs) {
-		if singleNamePerformerSkipped {
-			return nil, ErrSkipSingleNamePerformer
+		if !found {
+			movies = append(movies, models.MoviesScenes{
+				MovieID:    *movieID,
+				SceneIndex: sceneIndex,
+			})
 		}
-		return nil, nil
-	}
-
-	if singleNamePerformerSkipped {
-		return performerIDs, ErrSkipSingleNamePerformer
 	}
-	return performerIDs, nil
-}
-
-func (g sceneRelationships) tags(ctx context.Context) ([]int, error) {
-	fieldStrategy := g.fieldOptions["tags"]
-	scraped := g.result.result.Tags
-	target := g.scene
 
-	// just check if ignored
-	if len(scraped) == 0 || !shouldSetSingleValueField(fieldStrategy, false) {
+	// don't return if nothing was changed
+	if moviesSame(originalMovies, movies) {
 		return nil, nil
 	}
 
-	createMissing := fieldStrategy != nil && utils.IsTrue(fieldStrategy.CreateMissing)
-	strategy := FieldStrategyMerge
-	if fieldStrategy != nil {
-		strategy = fieldStrategy.Strategy
-	}
-
-	var tagIDs []int
-	originalTagIDs := target.TagIDs.List()
+	return movies, nil
+}
 
-	if strategy == FieldStrategyMerge {
-		// add to existing
-		tagIDs = originalTagIDs
+func moviesSame(a []models.MoviesScenes, b []models.MoviesScenes) bool {
+	if len(a) != len(b) {
+		return false
 	}
 
-	for _, t := range scraped {
-		if t.StoredID != nil {
-			// existing tag, just add it
-			tagID, err := strconv.ParseInt(*t.StoredID, 10, 64)
-			if err != nil {
-				return nil, fmt.Errorf("error converting tag ID %s: %w", *t.StoredID, err)
-			}
-
-			tagIDs = sliceutil.AppendUnique(tagIDs, int(tagID))
-		} else if createMissing {
-			newTag := models.NewTag()
-			newTag.Name = t.Name
-
-			err := g.tagCreator.Create(ctx, &newTag)
-			if err != nil {
-				return nil, fmt.Errorf("error creating tag: %w", err)
+	for _, aa := range a {
+		found := false
+		for _, bb := range b {
+			if aa.MovieID == bb.MovieID && intPtrEqual(aa.SceneIndex, bb.SceneIndex) {
+				found = true
+				break
 			}
+		}
 
-			tagIDs = append(tagIDs, newTag.ID)
+		if !found {
+			return false
 		}
 	}
 
-	// don't return if nothing was added
-	if sliceutil.SliceSame(originalTagIDs, tagIDs) {
-		return nil, nil
+	return true
+}
+
+func intPtrEqual(a *int, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
 	}
 
-	return tagIDs, nil
+	return *a == *b
 }
 
 func (g sceneRelationships) stashIDs(ctx context.Context) ([]models.StashID, error) {
@@ -194,15 +159,10 @@ func (g sceneRelationships) stashIDs(ctx context.Context) ([]models.StashID, err
 		return nil, nil
 	}
 
-	strategy := FieldStrategyMerge
-	if fieldStrategy != nil {
-		strategy = fieldStrategy.Strategy
-	}
-
 	var stashIDs []models.StashID
 	originalStashIDs := target.StashIDs.List()
 
-	if strategy == FieldStrategyMerge {
+	if isMergeStrategy(fieldStrategy) {
 		// add to existing
 		// make a copy so we don't modify the original
 		stashIDs = append(stashIDs, originalStashIDs...)
@@ -237,17 +197,22 @@ func (g sceneRelationships) stashIDs(ctx context.Context) ([]models.StashID, err
 
 func (g sceneRelationships) cover(ctx context.Context) ([]byte, error) {
 	scraped := g.result.result.Image
+	fieldStrategy := g.fieldOptions[proposedFieldCoverImage]
 
 	if scraped == nil || *scraped == "" {
 		return nil, nil
 	}
 
-	// always overwrite if present
+	// overwrite if present, unless a strategy is set for the cover
 	existingCover, err := g.sceneReader.GetCover(ctx, g.scene.ID)
 	if err != nil {
 		logger.Errorf("Error getting scene cover: %v", err)
 	}
 
+	if fieldStrategy != nil && !shouldSetSingleValueField(fieldStrategy, len(existingCover) > 0) {
+		return nil, nil
+	}
+
 	data, err := utils.ProcessImageInput(ctx, *scraped)
 	if err != nil {
 		return nil, fmt.Errorf("error processing image input: %w", err)
@@ -10,6 +10,7 @@ import (
 	"github.com/stashapp/stash/pkg/models/mocks"
 	"github.com/stashapp/stash/pkg/scraper"
 	"github.com/stashapp/stash/pkg/utils"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -783,3 +784,165 @@ func Test_sceneRelationships_cover(t *testing.T) {
 		})
 	}
 }
+
+func Test_sceneRelationships_coverStrategy(t *testing.T) {
+	const (
+		sceneWithCover = iota + 1
+		sceneWithoutCover
+	)
+	existingData := []byte("existingData")
+	newData := []byte("newData")
+	newDataEncoded := "data:image/png;base64," + utils.GetBase64StringFromData(newData)
+
+	db := mocks.NewDatabase()
+
+	db.Scene.On("GetCover", testCtx, sceneWithCover).Return(existingData, nil)
+	db.Scene.On("GetCover", testCtx, sceneWithoutCover).Return(nil, nil)
+
+	tests := []struct {
+		name     string
+		sceneID  int
+		strategy FieldStrategy
+		want     []byte
+	}{
+		{"merge with cover", sceneWithCover, FieldStrategyMerge, nil},
+		{"merge without cover", sceneWithoutCover, FieldStrategyMerge, newData},
+		{"overwrite with cover", sceneWithCover, FieldStrategyOverwrite, newData},
+		{"ignore without cover", sceneWithoutCover, FieldStrategyIgnore, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := sceneRelationships{
+				sceneReader: db.Scene,
+				fieldOptions: map[string]*FieldOptions{
+					"cover_image": {Strategy: tt.strategy},
+				},
+				scene: &models.Scene{ID: tt.sceneID},
+				result: &scrapeResult{
+					result: &scraper.ScrapedScene{
+						Image: &newDataEncoded,
+					},
+				},
+			}
+
+			got, err := tr.cover(testCtx)
+			if err != nil {
+				t.Errorf("sceneRelationships.cover() error = %v", err)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_sceneRelationships_movies(t *testing.T) {
+	const (
+		existingMovieID = iota + 1
+		scrapedMovieID
+		createdMovieID
+	)
+
+	var (
+		existingMovieIDStr = strconv.Itoa(existingMovieID)
+		scrapedMovieIDStr  = strconv.Itoa(scrapedMovieID)
+		newMovieName       = "new movie"
+		sceneIndex         = "2"
+		sceneIndexInt      = 2
+		existingIndex      = 1
+		boolTrue           = true
+	)
+
+	db := mocks.NewDatabase()
+	db.Movie.On("Create", testCtx, mock.MatchedBy(func(m *models.Movie) bool {
+		return m.Name == newMovieName
+	})).Run(func(args mock.Arguments) {
+		m := args.Get(1).(*models.Movie)
+		m.ID = createdMovieID
+	}).Return(nil)
+
+	existing := []models.MoviesScenes{
+		{MovieID: existingMovieID, SceneIndex: &existingIndex},
+	}
+
+	tests := []struct {
+		name         string
+		fieldOptions *FieldOptions
+		scraped      []*models.ScrapedMovie
+		want         []models.MoviesScenes
+	}{
+		{
+			"ignore",
+			&FieldOptions{Strategy: FieldStrategyIgnore},
+			[]*models.ScrapedMovie{{StoredID: &scrapedMovieIDStr}},
+			nil,
+		},
+		{
+			"same movie without index",
+			nil,
+			[]*models.ScrapedMovie{{StoredID: &existingMovieIDStr}},
+			nil,
+		},
+		{
+			"merge with scene index",
+			nil,
+			[]*models.ScrapedMovie{{StoredID: &scrapedMovieIDStr, SceneIndex: &sceneIndex}},
+			[]models.MoviesScenes{
+				{MovieID: existingMovieID, SceneIndex: &existingIndex},
+				{MovieID: scrapedMovieID, SceneIndex: &sceneIndexInt},
+			},
+		},
+		{
+			"update scene index",
+			nil,
+			[]*models.ScrapedMovie{{StoredID: &existingMovieIDStr, SceneIndex: &sceneIndex}},
+			[]models.MoviesScenes{
+				{MovieID: existingMovieID, SceneIndex: &sceneIndexInt},
+			},
+		},
+		{
+			"overwrite missing not created",
+			&FieldOptions{Strategy: FieldStrategyOverwrite},
+			[]*models.ScrapedMovie{{Name: &newMovieName}, {StoredID: &scrapedMovieIDStr}},
+			[]models.MoviesScenes{
+				{MovieID: scrapedMovieID},
+			},
+		},
+		{
+			"create missing",
+			&FieldOptions{Strategy: FieldStrategyMerge, CreateMissing: &boolTrue},
+			[]*models.ScrapedMovie{{Name: &newMovieName}},
+			[]models.MoviesScenes{
+				{MovieID: existingMovieID, SceneIndex: &existingIndex},
+				{MovieID: createdMovieID},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := sceneRelationships{
+				movieCreator: db.Movie,
+				fieldOptions: map[string]*FieldOptions{
+					"movies": tt.fieldOptions,
+				},
+				scene: &models.Scene{
+					Movies: models.NewRelatedMovies(existing),
+				},
+				result: &scrapeResult{
+					result: &scraper.ScrapedScene{
+						Movies: tt.scraped,
+					},
+				},
+			}
+
+			got, err := tr.movies(testCtx)
+			if err != nil {
+				t.Errorf("sceneRelationships.movies() error = %v", err)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+
+			// existing list must not be modified
+			assert.Equal(t, existingIndex, *existing[0].SceneIndex)
+		})
+	}
+}
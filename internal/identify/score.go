@@ -0,0 +1,234 @@
+package identify
+
+import (
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/corona10/goimagehash"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// weights of the individual signals that make up a match score.
+// The weights add up to 1.
+const (
+	fingerprintWeight = 0.4
+	phashWeight       = 0.2
+	durationWeight    = 0.15
+	titleWeight       = 0.15
+	studioWeight      = 0.1
+)
+
+const (
+	// phash distances above this are not considered a match
+	maxPhashDistance = 10
+	// duration differences in seconds above this are not considered a match
+	maxDurationDelta = 30
+)
+
+// MatchScore is the confidence that a scraped scene matches a scene.
+type MatchScore struct {
+	// Score is the weighted total of the individual signals, between 0 and 1.
+	Score float64 `json:"score"`
+	// number of MD5 and oshash fingerprints that match the scene file
+	FingerprintMatches int `json:"fingerprint_matches"`
+	// smallest distance between the scene phash and the scraped phashes
+	PhashDistance *int `json:"phash_distance,omitempty"`
+	// difference in seconds between the scene and scraped durations
+	DurationDelta *int `json:"duration_delta,omitempty"`
+	// similarity between the scraped title and the scene filename, between 0 and 1
+	TitleSimilarity *float64 `json:"title_similarity,omitempty"`
+	// true if the scraped studio matches the studio of the scene
+	StudioMatch bool `json:"studio_match"`
+}
+
+// sceneScoreInput contains the scene values that scraped scenes are compared
+// against.
+type sceneScoreInput struct {
+	checksum string
+	oshash   string
+	phash    *goimagehash.ImageHash
+	duration float64
+	filename string
+	studioID *int
+}
+
+func newSceneScoreInput(s *models.Scene) sceneScoreInput {
+	ret := sceneScoreInput{
+		studioID: s.StudioID,
+	}
+
+	// file signals are only available if the files are loaded
+	if !s.Files.PrimaryLoaded() || s.Files.Primary() == nil {
+		return ret
+	}
+
+	f := s.Files.Primary()
+	ret.checksum = f.Fingerprints.GetString(models.FingerprintTypeMD5)
+	ret.oshash = f.Fingerprints.GetString(models.FingerprintTypeOshash)
+	ret.duration = f.Duration
+	ret.filename = strings.TrimSuffix(f.Basename, filepath.Ext(f.Basename))
+
+	if phash := f.Fingerprints.GetInt64(models.FingerprintTypePhash); phash != 0 {
+		ret.phash = goimagehash.NewImageHash(uint64(phash), goimagehash.PHash)
+	}
+
+	return ret
+}
+
+func (i sceneScoreInput) score(scraped *scraper.ScrapedScene) MatchScore {
+	var ret MatchScore
+
+	var durations []int
+	if scraped.Duration != nil {
+		durations = append(durations, *scraped.Duration)
+	}
+
+	for _, fp := range scraped.Fingerprints {
+		if fp.Duration > 0 {
+			durations = append(durations, fp.Duration)
+		}
+
+		switch strings.ToLower(fp.Algorithm) {
+		case models.FingerprintTypeMD5:
+			if i.checksum != "" && strings.EqualFold(i.checksum, fp.Hash) {
+				ret.FingerprintMatches++
+			}
+		case models.FingerprintTypeOshash:
+			if i.oshash != "" && strings.EqualFold(i.oshash, fp.Hash) {
+				ret.FingerprintMatches++
+			}
+		case models.FingerprintTypePhash:
+			if i.phash == nil {
+				continue
+			}
+
+			phash, err := utils.StringToPhash(fp.Hash)
+			if err != nil {
+				continue
+			}
+
+			distance, err := i.phash.Distance(goimagehash.NewImageHash(uint64(phash), goimagehash.PHash))
+			if err == nil && (ret.PhashDistance == nil || distance < *ret.PhashDistance) {
+				ret.PhashDistance = &distance
+			}
+		}
+	}
+
+	if i.duration > 0 {
+		for _, d := range durations {
+			delta := int(math.Round(math.Abs(i.duration - float64(d))))
+			if ret.DurationDelta == nil || delta < *ret.DurationDelta {
+				ret.DurationDelta = &delta
+			}
+		}
+	}
+
+	if i.filename != "" && scraped.Title != nil && *scraped.Title != "" {
+		similarity := titleSimilarity(*scraped.Title, i.filename)
+		ret.TitleSimilarity = &similarity
+	}
+
+	if i.studioID != nil && scraped.Studio != nil && scraped.Studio.StoredID != nil {
+		ret.StudioMatch = *scraped.Studio.StoredID == strconv.Itoa(*i.studioID)
+	}
+
+	ret.Score = ret.total()
+
+	return ret
+}
+
+func (s MatchScore) total() float64 {
+	var ret float64
+
+	if s.FingerprintMatches > 0 {
+		ret += fingerprintWeight
+	}
+	if s.PhashDistance != nil {
+		ret += phashWeight * linearScore(float64(*s.PhashDistance), maxPhashDistance)
+	}
+	if s.DurationDelta != nil {
+		ret += durationWeight * linearScore(float64(*s.DurationDelta), maxDurationDelta)
+	}
+	if s.TitleSimilarity != nil {
+		ret += titleWeight * *s.TitleSimilarity
+	}
+	if s.StudioMatch {
+		ret += studioWeight
+	}
+
+	// round to avoid floating point noise when comparing against thresholds
+	return math.Round(ret*1000) / 1000
+}
+
+// linearScore returns 1 for a difference of 0, decreasing linearly to 0 at
+// the maximum difference.
+func linearScore(diff float64, max float64) float64 {
+	if diff >= max {
+		return 0
+	}
+
+	return 1 - diff/max
+}
+
+func titleWords(s string) map[string]struct{} {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	ret := make(map[string]struct{})
+	for _, w := range words {
+		ret[w] = struct{}{}
+	}
+
+	return ret
+}
+
+// titleSimilarity returns the Sørensen–Dice coefficient of the words in the
+// title and the filename.
+func titleSimilarity(title string, filename string) float64 {
+	a := titleWords(title)
+	b := titleWords(filename)
+
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	common := 0
+	for w := range a {
+		if _, found := b[w]; found {
+			common++
+		}
+	}
+
+	return 2 * float64(common) / float64(len(a)+len(b))
+}
+
+// scoredCandidate is a scraped scene with its report.
+type scoredCandidate struct {
+	report *CandidateReport
+	result *scraper.ScrapedScene
+}
+
+// scoreCandidates scores the results of a source, ordered by descending score.
+// Candidates with equal scores retain the order returned by the source.
+func scoreCandidates(s *models.Scene, input sceneScoreInput, source ScraperSource, results []*scraper.ScrapedScene) []scoredCandidate {
+	ret := make([]scoredCandidate, len(results))
+	for i, r := range results {
+		ret[i] = scoredCandidate{
+			report: newCandidateReport(source, s, r, input.score(r)),
+			result: r,
+		}
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].report.Score.Score > ret[j].report.Score.Score
+	})
+
+	return ret
+}
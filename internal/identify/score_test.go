@@ -0,0 +1,266 @@
+package identify
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_titleSimilarity(t *testing.T) {
+	tests := []struct {
+		name     string
+		title    string
+		filename string
+		want     float64
+	}{
+		{"identical", "Scene Title", "scene.title", 1},
+		{"partial", "Scene Title", "studio.scene.title.1080p", 2 * 2.0 / 6},
+		{"none", "Scene Title", "other", 0},
+		{"empty", "", "other", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, titleSimilarity(tt.title, tt.filename), 0.001)
+		})
+	}
+}
+
+func makeScoreScene() *models.Scene {
+	studioID := 5
+	return &models.Scene{
+		ID:       1,
+		StudioID: &studioID,
+		Files: models.NewRelatedVideoFiles([]*models.VideoFile{
+			{
+				BaseFile: &models.BaseFile{
+					Basename: "studio - scene title.mp4",
+					Fingerprints: models.Fingerprints{
+						{Type: models.FingerprintTypeOshash, Fingerprint: "abc"},
+						{Type: models.FingerprintTypePhash, Fingerprint: int64(0xff)},
+					},
+				},
+				Duration: 600,
+			},
+		}),
+	}
+}
+
+func Test_sceneScoreInput_score(t *testing.T) {
+	var (
+		title      = "Scene Title"
+		otherTitle = "Something Else"
+		studioID   = "5"
+		duration   = 610
+		input      = newSceneScoreInput(makeScoreScene())
+	)
+
+	intPtr := func(v int) *int { return &v }
+	floatPtr := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name    string
+		scraped *scraper.ScrapedScene
+		want    MatchScore
+	}{
+		{
+			"no signals",
+			&scraper.ScrapedScene{},
+			MatchScore{},
+		},
+		{
+			"all signals",
+			&scraper.ScrapedScene{
+				Title:  &title,
+				Studio: &models.ScrapedStudio{StoredID: &studioID},
+				Fingerprints: []*models.StashBoxFingerprint{
+					{Algorithm: "OSHASH", Hash: "abc", Duration: 600},
+					{Algorithm: "PHASH", Hash: "ff", Duration: 600},
+				},
+			},
+			MatchScore{
+				Score:              0.97,
+				FingerprintMatches: 1,
+				PhashDistance:      intPtr(0),
+				DurationDelta:      intPtr(0),
+				TitleSimilarity:    floatPtr(2 * 2.0 / 5),
+				StudioMatch:        true,
+			},
+		},
+		{
+			"partial signals",
+			&scraper.ScrapedScene{
+				Title:    &otherTitle,
+				Duration: &duration,
+				Fingerprints: []*models.StashBoxFingerprint{
+					// distance of 5
+					{Algorithm: "PHASH", Hash: "e0"},
+				},
+			},
+			MatchScore{
+				Score:           0.2,
+				PhashDistance:   intPtr(5),
+				DurationDelta:   intPtr(10),
+				TitleSimilarity: floatPtr(0),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := input.score(tt.scraped)
+			if tt.want.TitleSimilarity != nil && got.TitleSimilarity != nil {
+				assert.InDelta(t, *tt.want.TitleSimilarity, *got.TitleSimilarity, 0.001)
+				got.TitleSimilarity = tt.want.TitleSimilarity
+			}
+
+			// all signals are weighted, so compare the total separately
+			assert.InDelta(t, tt.want.Score, got.Score, 0.01)
+			got.Score = tt.want.Score
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSceneIdentifier_scrapeScene(t *testing.T) {
+	var (
+		oshashTitle = "Oshash Match"
+		titleMatch  = "Scene Title"
+		noMatch     = "No Match"
+
+		threshold    = 0.3
+		lowThreshold = 0.1
+		boolTrue     = true
+	)
+
+	matching := &scraper.ScrapedScene{
+		Title: &oshashTitle,
+		Fingerprints: []*models.StashBoxFingerprint{
+			{Algorithm: "OSHASH", Hash: "abc"},
+		},
+	}
+
+	sources := []ScraperSource{
+		{
+			Name: "first",
+			Scraper: mockSceneScraper{
+				results: map[int][]*scraper.ScrapedScene{
+					1: {{Title: &noMatch}},
+				},
+			},
+		},
+		{
+			Name: "second",
+			Scraper: mockSceneScraper{
+				results: map[int][]*scraper.ScrapedScene{
+					1: {{Title: &titleMatch}, matching},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		options     *MetadataOptions
+		wantTitle   string
+		wantOutcome Outcome
+		wantErr     bool
+	}{
+		{
+			"first source without threshold",
+			&MetadataOptions{},
+			noMatch,
+			OutcomeMatched,
+			false,
+		},
+		{
+			"best candidate of first accepted source",
+			&MetadataOptions{MatchThreshold: &threshold},
+			oshashTitle,
+			OutcomeMatched,
+			false,
+		},
+		{
+			"multiple accepted",
+			&MetadataOptions{MatchThreshold: &lowThreshold, SkipMultipleMatches: &boolTrue},
+			"",
+			OutcomeMultipleMatches,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identifier := SceneIdentifier{
+				DefaultOptions: tt.options,
+				Sources:        sources,
+			}
+
+			scene := makeScoreScene()
+			report := newSceneReport(scene)
+			result, err := identifier.scrapeScene(testCtx, scene, report)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SceneIdentifier.scrapeScene() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			assert.Equal(t, tt.wantOutcome, report.Outcome)
+
+			if tt.wantTitle == "" {
+				assert.Nil(t, result)
+				return
+			}
+
+			if assert.NotNil(t, result) {
+				assert.Equal(t, tt.wantTitle, *result.result.Title)
+			}
+
+			var chosen []string
+			for _, c := range report.Candidates {
+				if c.Chosen {
+					chosen = append(chosen, c.Title)
+				}
+			}
+			assert.Equal(t, []string{tt.wantTitle}, chosen)
+		})
+	}
+}
+
+func TestCandidateFields(t *testing.T) {
+	var (
+		title      = "Scene Title"
+		otherTitle = "Other Title"
+		details    = "details"
+		studioID   = 1
+		storedID   = "1"
+		otherID    = "2"
+	)
+
+	scene := &models.Scene{
+		Title:    title,
+		StudioID: &studioID,
+	}
+
+	tests := []struct {
+		name   string
+		result *scraper.ScrapedScene
+		want   []string
+	}{
+		{"unchanged", &scraper.ScrapedScene{Title: &title, Studio: &models.ScrapedStudio{StoredID: &storedID}}, nil},
+		{"title", &scraper.ScrapedScene{Title: &otherTitle}, []string{"title"}},
+		{"details", &scraper.ScrapedScene{Details: &details}, []string{"details"}},
+		{"studio", &scraper.ScrapedScene{Studio: &models.ScrapedStudio{StoredID: &otherID}}, []string{"studio"}},
+		{"relationships", &scraper.ScrapedScene{
+			Performers: []*models.ScrapedPerformer{{}},
+			Tags:       []*models.ScrapedTag{{}},
+		}, []string{"performers", "tags"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, candidateFields(scene, tt.result))
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package identify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/studio"
+	"github.com/stashapp/stash/pkg/txn"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+type StudioScraper interface {
+	ScrapeStudiosByName(ctx context.Context, name string) ([]*models.ScrapedStudio, error)
+	// returns nil if the url cannot be scraped
+	ScrapeStudioByURL(ctx context.Context, url string) (*models.ScrapedStudio, error)
+}
+
+// StudioIdentifier identifies studios using the studio scrapers of the
+// sources. Sources without a studio scraper are skipped.
+//
+// Studios with a URL are scraped using the URL. Otherwise, studios are
+// scraped by name, and only results with a name matching the name or an
+// alias of the studio are considered. The first source returning a match is
+// used.
+type StudioIdentifier struct {
+	TxnManager         txn.Manager
+	StudioReaderWriter models.StudioReaderWriter
+	DefaultOptions     *MetadataOptions
+	Sources            []ScraperSource
+}
+
+type studioScrapeResult struct {
+	result *models.ScrapedStudio
+	source ScraperSource
+}
+
+func (t *StudioIdentifier) Identify(ctx context.Context, s *models.Studio) error {
+	if err := txn.WithReadTxn(ctx, t.TxnManager, func(ctx context.Context) error {
+		return s.LoadAliases(ctx, t.StudioReaderWriter)
+	}); err != nil {
+		return fmt.Errorf("loading aliases: %w", err)
+	}
+
+	result, err := t.scrapeStudio(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	if result == nil {
+		logger.Debugf("Unable to identify studio %s", s.Name)
+		return nil
+	}
+
+	if err := t.modifyStudio(ctx, s, result); err != nil {
+		return fmt.Errorf("error modifying studio: %w", err)
+	}
+
+	return nil
+}
+
+func (t *StudioIdentifier) scrapeStudio(ctx context.Context, s *models.Studio) (*studioScrapeResult, error) {
+	for _, source := range t.Sources {
+		if source.StudioScraper == nil {
+			continue
+		}
+
+		result, err := t.scrapeWithSource(ctx, s, source)
+		if err != nil {
+			var multipleMatchErr *MultipleMatchesFoundError
+			if errors.As(err, &multipleMatchErr) {
+				logger.Debugf("Identify skipped because multiple results returned for studio %s", s.Name)
+				return nil, nil
+			}
+
+			logger.Errorf("error scraping from %v: %v", source.StudioScraper, err)
+			continue
+		}
+
+		if result != nil {
+			return &studioScrapeResult{
+				result: result,
+				source: source,
+			}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (t *StudioIdentifier) scrapeWithSource(ctx context.Context, s *models.Studio, source ScraperSource) (*models.ScrapedStudio, error) {
+	ss := source.StudioScraper
+
+	if s.URL != "" {
+		result, err := ss.ScrapeStudioByURL(ctx, s.URL)
+		if err != nil {
+			return nil, err
+		}
+
+		if result != nil {
+			return result, nil
+		}
+	}
+
+	results, err := ss.ScrapeStudiosByName(ctx, s.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*models.ScrapedStudio
+	for _, r := range results {
+		if nameMatches(r.Name, s.Name, s.Aliases.List()) {
+			matches = append(matches, r)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	options := mergeOptions(t.DefaultOptions, source)
+	if len(matches) > 1 && utils.IsTrue(options.SkipMultipleMatches) {
+		return nil, &MultipleMatchesFoundError{
+			Source: source,
+		}
+	}
+
+	match := matches[0]
+
+	// name search results are often incomplete, so scrape the full details
+	// using the url if possible
+	if match.URL != nil && *match.URL != "" {
+		result, err := ss.ScrapeStudioByURL(ctx, *match.URL)
+		if err != nil {
+			return nil, err
+		}
+
+		if result != nil {
+			return result, nil
+		}
+	}
+
+	return match, nil
+}
+
+func (t *StudioIdentifier) modifyStudio(ctx context.Context, s *models.Studio, result *studioScrapeResult) error {
+	fieldOptions := mergeFieldOptions(t.DefaultOptions, result.source)
+	scraped := result.result
+
+	var image []byte
+	if getFieldStrategy(fieldOptions["image"]) != FieldStrategyIgnore {
+		var err error
+		image, err = scraped.GetImage(ctx, nil)
+		if err != nil {
+			logger.Warnf("Error processing scraped image for studio %s: %v", s.Name, err)
+		}
+	}
+
+	return txn.WithTxn(ctx, t.TxnManager, func(ctx context.Context) error {
+		qb := t.StudioReaderWriter
+
+		partial := getStudioPartial(s, scraped, fieldOptions)
+
+		parentID, err := getStudioID(ctx, qb, result.source.RemoteSite, s.ParentID, scraped.Parent, fieldOptions["parent_studio"])
+		if err != nil {
+			return fmt.Errorf("error getting parent studio: %w", err)
+		}
+		// never set a studio as its own parent
+		if parentID != nil && *parentID != s.ID {
+			partial.ParentID = models.NewOptionalInt(*parentID)
+		}
+
+		setImage := false
+		if len(image) > 0 {
+			existing, err := qb.GetImage(ctx, s.ID)
+			if err != nil {
+				return fmt.Errorf("getting existing image: %w", err)
+			}
+			setImage = shouldSetImage(fieldOptions["image"], existing, image)
+		}
+
+		if studioPartialIsEmpty(partial) && !setImage {
+			logger.Debugf("Nothing to set for studio %s", s.Name)
+			return nil
+		}
+
+		if err := studio.ValidateModify(ctx, partial, qb); err != nil {
+			return err
+		}
+
+		if _, err := qb.UpdatePartial(ctx, partial); err != nil {
+			return fmt.Errorf("error updating studio: %w", err)
+		}
+
+		if setImage {
+			if err := qb.UpdateImage(ctx, s.ID, image); err != nil {
+				return fmt.Errorf("error updating studio image: %w", err)
+			}
+		}
+
+		logger.Infof("Successfully identified studio %s using %s", s.Name, result.source.Name)
+
+		return nil
+	})
+}
+
+// getStudioPartial returns the changes to make to the studio fields. The
+// studio name is never changed. Studio aliases must be loaded.
+func getStudioPartial(s *models.Studio, scraped *models.ScrapedStudio, fieldOptions map[string]*FieldOptions) models.StudioPartial {
+	partial := models.NewStudioPartial()
+	partial.ID = s.ID
+
+	if v := getStringFieldValue(fieldOptions["url"], s.URL, scraped.URL, false); v != nil {
+		partial.URL = models.NewOptionalString(*v)
+	}
+	if v := getStringFieldValue(fieldOptions["details"], s.Details, scraped.Details, true); v != nil {
+		partial.Details = models.NewOptionalString(*v)
+	}
+
+	partial.Aliases = getAliasesUpdate(fieldOptions["aliases"], s.Name, s.Aliases.List(), scraped.Aliases)
+
+	return partial
+}
+
+func studioPartialIsEmpty(p models.StudioPartial) bool {
+	// ID and UpdatedAt are always set, so ignore them
+	p.ID = 0
+	p.UpdatedAt = models.OptionalTime{}
+	return p == models.StudioPartial{}
+}
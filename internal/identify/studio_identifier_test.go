@@ -0,0 +1,179 @@
+package identify
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockStudioScraper struct {
+	byName map[string][]*models.ScrapedStudio
+	byURL  map[string]*models.ScrapedStudio
+}
+
+func (s mockStudioScraper) ScrapeStudiosByName(ctx context.Context, name string) ([]*models.ScrapedStudio, error) {
+	return s.byName[name], nil
+}
+
+func (s mockStudioScraper) ScrapeStudioByURL(ctx context.Context, url string) (*models.ScrapedStudio, error) {
+	return s.byURL[url], nil
+}
+
+func TestStudioIdentifier_Identify(t *testing.T) {
+	const (
+		missingID = iota + 1
+		urlID
+		nameID
+		ownParentID
+		parentID
+	)
+
+	var (
+		missingName   = "missingName"
+		urlName       = "urlName"
+		nameName      = "nameName"
+		ownParentName = "ownParentName"
+
+		studioURL      = "studioURL"
+		urlDetails     = "urlDetails"
+		nameDetails    = "nameDetails"
+		parentIDStr    = strconv.Itoa(parentID)
+		ownParentIDStr = strconv.Itoa(ownParentID)
+	)
+
+	sources := []ScraperSource{
+		{
+			Name: "first",
+			StudioScraper: mockStudioScraper{
+				byName: map[string][]*models.ScrapedStudio{
+					nameName: {
+						{
+							Name:    nameName,
+							Details: &nameDetails,
+							Parent: &models.ScrapedStudio{
+								StoredID: &parentIDStr,
+							},
+						},
+					},
+					ownParentName: {
+						{
+							Name: ownParentName,
+							Parent: &models.ScrapedStudio{
+								StoredID: &ownParentIDStr,
+							},
+						},
+					},
+				},
+				byURL: map[string]*models.ScrapedStudio{
+					studioURL: {Name: urlName, Details: &urlDetails},
+				},
+			},
+		},
+	}
+
+	db := mocks.NewDatabase()
+
+	db.Studio.On("GetAliases", mock.Anything, mock.Anything).Return(nil, nil)
+	db.Studio.On("Find", mock.Anything, parentID).Return(&models.Studio{ID: parentID}, nil)
+
+	db.Studio.On("Find", mock.Anything, urlID).Return(&models.Studio{ID: urlID}, nil).Once()
+	db.Studio.On("UpdatePartial", mock.Anything, mock.MatchedBy(func(p models.StudioPartial) bool {
+		return p.ID == urlID && p.Details.Value == urlDetails && !p.ParentID.Set
+	})).Return(nil, nil).Once()
+
+	db.Studio.On("Find", mock.Anything, nameID).Return(&models.Studio{ID: nameID}, nil).Once()
+	db.Studio.On("UpdatePartial", mock.Anything, mock.MatchedBy(func(p models.StudioPartial) bool {
+		return p.ID == nameID && p.Details.Value == nameDetails && p.ParentID.Value == parentID
+	})).Return(nil, nil).Once()
+
+	identifier := StudioIdentifier{
+		TxnManager:         db,
+		StudioReaderWriter: db.Studio,
+		Sources:            sources,
+	}
+
+	studios := []*models.Studio{
+		{ID: missingID, Name: missingName},
+		{ID: urlID, Name: urlName, URL: studioURL},
+		{ID: nameID, Name: nameName},
+		// studio is never set as its own parent, so nothing is updated
+		{ID: ownParentID, Name: ownParentName},
+	}
+
+	for _, s := range studios {
+		if err := identifier.Identify(testCtx, s); err != nil {
+			t.Errorf("StudioIdentifier.Identify() id %d error = %v", s.ID, err)
+		}
+	}
+
+	db.AssertExpectations(t)
+}
+
+func Test_getStudioPartial(t *testing.T) {
+	var (
+		originalURL     = "originalURL"
+		originalDetails = "originalDetails"
+		scrapedURL      = "scrapedURL"
+		scrapedDetails  = "scrapedDetails"
+		scrapedAliases  = "name,scrapedAlias"
+	)
+
+	s := &models.Studio{
+		ID:      1,
+		Name:    "name",
+		URL:     originalURL,
+		Details: originalDetails,
+		Aliases: models.NewRelatedStrings([]string{}),
+	}
+
+	scraped := &models.ScrapedStudio{
+		URL:     &scrapedURL,
+		Details: &scrapedDetails,
+		Aliases: &scrapedAliases,
+	}
+
+	tests := []struct {
+		name         string
+		fieldOptions map[string]*FieldOptions
+		want         models.StudioPartial
+	}{
+		{
+			"merge",
+			nil,
+			models.StudioPartial{
+				ID: 1,
+				Aliases: &models.UpdateStrings{
+					Values: []string{"scrapedAlias"},
+					Mode:   models.RelationshipUpdateModeSet,
+				},
+			},
+		},
+		{
+			"overwrite and append",
+			map[string]*FieldOptions{
+				"url":     {Strategy: FieldStrategyOverwrite},
+				"details": {Strategy: FieldStrategyAppend},
+				"aliases": {Strategy: FieldStrategyIgnore},
+			},
+			models.StudioPartial{
+				ID:      1,
+				URL:     models.NewOptionalString(scrapedURL),
+				Details: models.NewOptionalString(originalDetails + "\n\n" + scrapedDetails),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getStudioPartial(s, scraped, tt.fieldOptions)
+
+			// UpdatedAt is always set
+			got.UpdatedAt = models.OptionalTime{}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
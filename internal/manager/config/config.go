@@ -52,6 +52,13 @@ const (
 	GalleryExtensions          = "gallery_extensions"
 	CreateGalleriesFromFolders = "create_galleries_from_folders"
 
+	// WatchLibrary is the config key used to determine if the stash paths
+	// are watched for changes, which are then scanned automatically.
+	WatchLibrary                    = "watch_library"
+	WatchLibraryPoll                = "watch_library_poll"
+	WatchLibraryPollInterval        = "watch_library_poll_interval"
+	watchLibraryPollIntervalDefault = 60
+
 	// CalculateMD5 is the config key used to determine if MD5 should be calculated
 	// for video files.
 	CalculateMD5 = "calculate_md5"
@@ -81,6 +88,8 @@ const (
 	PreviewAudio        = "preview_audio"
 	previewAudioDefault = true
 
+	PhashSegmentInterval = "phash_segment_interval"
+
 	PreviewSegmentDuration        = "preview_segment_duration"
 	previewSegmentDurationDefault = 0.75
 
@@ -126,6 +135,8 @@ const (
 	ScraperCertCheck          = "scraper_cert_check"
 	ScraperCDPPath            = "scraper_cdp_path"
 	ScraperExcludeTagPatterns = "scraper_exclude_tag_patterns"
+	ScrapersSetting           = "scrapers.settings"
+	ScrapersSettingPrefix     = ScrapersSetting + "."
 
 	// stash-box options
 	StashBoxes = "stash_boxes"
@@ -266,7 +277,7 @@ const (
 var (
 	defaultVideoExtensions   = []string{"m4v", "mp4", "mov", "wmv", "avi", "mpg", "mpeg", "rmvb", "rm", "flv", "asf", "mkv", "webm"}
 	defaultImageExtensions   = []string{"png", "jpg", "jpeg", "gif", "webp"}
-	defaultGalleryExtensions = []string{"zip", "cbz"}
+	defaultGalleryExtensions = []string{"zip", "cbz", "7z", "cb7", "rar", "cbr", "tar", "cbt", "tar.gz", "tgz"}
 	defaultMenuItems         = []string{"scenes", "images", "movies", "markers", "galleries", "performers", "studios", "tags"}
 )
 
@@ -645,6 +656,27 @@ func (i *Config) GetCreateGalleriesFromFolders() bool {
 	return i.getBool(CreateGalleriesFromFolders)
 }
 
+func (i *Config) GetWatchLibrary() bool {
+	return i.getBool(WatchLibrary)
+}
+
+// GetWatchLibraryPoll returns true if the stash paths should be polled for
+// changes rather than using filesystem notifications. Network filesystems
+// are always polled.
+func (i *Config) GetWatchLibraryPoll() bool {
+	return i.getBool(WatchLibraryPoll)
+}
+
+// GetWatchLibraryPollInterval returns the interval in seconds between polls
+// of the stash paths.
+func (i *Config) GetWatchLibraryPollInterval() int {
+	ret := i.getInt(WatchLibraryPollInterval)
+	if ret <= 0 {
+		ret = watchLibraryPollIntervalDefault
+	}
+	return ret
+}
+
 func (i *Config) GetLanguage() string {
 	ret := i.getString(Language)
 
@@ -715,6 +747,53 @@ func (i *Config) GetScraperExcludeTagPatterns() []string {
 	return i.getStringSlice(ScraperExcludeTagPatterns)
 }
 
+func (i *Config) GetAllScraperConfiguration() map[string]map[string]interface{} {
+	i.RLock()
+	defer i.RUnlock()
+
+	ret := make(map[string]map[string]interface{})
+
+	sub := i.viper(ScrapersSetting).GetStringMap(ScrapersSetting)
+	if sub == nil {
+		return ret
+	}
+
+	for scraper := range sub {
+		// HACK: viper changes map keys to case insensitive values, so the workaround is to
+		// convert map keys to snake case for storage
+		name := fromSnakeCase(scraper)
+		ret[name] = fromSnakeCaseMap(i.viper(ScrapersSetting).GetStringMap(ScrapersSettingPrefix + scraper))
+	}
+
+	return ret
+}
+
+func (i *Config) GetScraperConfiguration(scraperID string) map[string]interface{} {
+	i.RLock()
+	defer i.RUnlock()
+
+	key := ScrapersSettingPrefix + toSnakeCase(scraperID)
+
+	// HACK: viper changes map keys to case insensitive values, so the workaround is to
+	// convert map keys to snake case for storage
+	v := i.viper(key).GetStringMap(key)
+
+	return fromSnakeCaseMap(v)
+}
+
+func (i *Config) SetScraperConfiguration(scraperID string, v map[string]interface{}) {
+	i.Lock()
+	defer i.Unlock()
+
+	scraperID = toSnakeCase(scraperID)
+
+	key := ScrapersSettingPrefix + scraperID
+
+	// HACK: viper changes map keys to case insensitive values, so the workaround is to
+	// convert map keys to snake case for storage
+	i.viper(key).Set(key, toSnakeCaseMap(v))
+}
+
 func (i *Config) GetStashBoxes() []*models.StashBox {
 	var boxes []*models.StashBox
 	if err := i.unmarshalKey(StashBoxes, &boxes); err != nil {
@@ -822,6 +901,12 @@ func (i *Config) GetPreviewSegmentDuration() float64 {
 	return i.getFloat64(PreviewSegmentDuration)
 }
 
+// GetPhashSegmentInterval returns the interval in seconds between the frames
+// of video phash segments. Phash segments are not generated if 0.
+func (i *Config) GetPhashSegmentInterval() float64 {
+	return i.getFloat64(PhashSegmentInterval)
+}
+
 // GetParallelTasks returns the number of parallel tasks that should be started
 // by scan or generate task.
 func (i *Config) GetParallelTasks() int {
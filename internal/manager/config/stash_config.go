@@ -3,6 +3,7 @@ package config
 import (
 	"path/filepath"
 
+	"github.com/stashapp/stash/pkg/file/remote"
 	"github.com/stashapp/stash/pkg/fsutil"
 )
 
@@ -11,12 +12,58 @@ type StashConfigInput struct {
 	Path         string `json:"path"`
 	ExcludeVideo bool   `json:"excludeVideo"`
 	ExcludeImage bool   `json:"excludeImage"`
+	// Remote is the remote storage of the path. Path is used as the local
+	// path of the remote files if set.
+	Remote *remote.Config `json:"remote"`
+
+	StashSettings `mapstructure:",squash" yaml:",inline"`
 }
 
 type StashConfig struct {
 	Path         string `json:"path"`
 	ExcludeVideo bool   `json:"excludeVideo"`
 	ExcludeImage bool   `json:"excludeImage"`
+	// Remote is the remote storage of the path. Path is used as the local
+	// path of the remote files if set.
+	Remote *remote.Config `json:"remote"`
+
+	StashSettings `mapstructure:",squash" yaml:",inline"`
+}
+
+// StashSettings are the settings of a library path that override the
+// global settings for the files in the path.
+type StashSettings struct {
+	// ScanGenerate overrides the generators of the scan task for the files
+	// in the path. The generators of the scan task are used if nil.
+	ScanGenerate *ScanGenerateOptions `json:"scanGenerate" yaml:"scangenerate,omitempty"`
+	// DefaultTagIDs are added to scenes, images and galleries created by the scan.
+	DefaultTagIDs []string `json:"defaultTagIds" yaml:"defaulttagids,omitempty"`
+	// DefaultStudioID is set on scenes, images and galleries created by the scan.
+	DefaultStudioID *string `json:"defaultStudioId" yaml:"defaultstudioid,omitempty"`
+	// CreateGalleriesFromFolders overrides the global setting if not nil.
+	CreateGalleriesFromFolders *bool `json:"createGalleriesFromFolders" yaml:"creategalleriesfromfolders,omitempty"`
+	// ReadOnly prevents files in the path from being deleted or moved.
+	ReadOnly bool `json:"readOnly" yaml:"readonly,omitempty"`
+	// VideoExcludes are exclude patterns for video files, in addition to
+	// the global patterns.
+	VideoExcludes []string `json:"videoExcludes" yaml:"videoexcludes,omitempty"`
+	// ImageExcludes are exclude patterns for image and gallery files, in
+	// addition to the global patterns.
+	ImageExcludes []string `json:"imageExcludes" yaml:"imageexcludes,omitempty"`
+	// ScanPriority determines the order in which library paths are scanned.
+	// Paths with a higher priority are scanned first.
+	ScanPriority int `json:"scanPriority" yaml:"scanpriority,omitempty"`
+}
+
+// IsCreateGalleriesFromFolders returns true if galleries should be created
+// from the folders of the path. def is returned if the path does not
+// override the global setting.
+func (s StashSettings) IsCreateGalleriesFromFolders(def bool) bool {
+	if s.CreateGalleriesFromFolders != nil {
+		return *s.CreateGalleriesFromFolders
+	}
+
+	return def
 }
 
 type StashConfigs []*StashConfig
@@ -38,3 +85,14 @@ func (s StashConfigs) GetStashFromDirPath(dirPath string) *StashConfig {
 	}
 	return nil
 }
+
+// GetReadOnlyPaths returns the paths of the read-only library paths.
+func (s StashConfigs) GetReadOnlyPaths() []string {
+	var ret []string
+	for _, f := range s {
+		if f.ReadOnly {
+			ret = append(ret, f.Path)
+		}
+	}
+	return ret
+}
@@ -1,5 +1,7 @@
 package config
 
+import "github.com/stashapp/stash/pkg/models"
+
 type ScanMetadataOptions struct {
 	// Generate scene covers during scan
 	ScanGenerateCovers bool `json:"scanGenerateCovers"`
@@ -15,6 +17,43 @@ type ScanMetadataOptions struct {
 	ScanGenerateThumbnails bool `json:"scanGenerateThumbnails"`
 	// Generate image thumbnails during scan
 	ScanGenerateClipPreviews bool `json:"scanGenerateClipPreviews"`
+	// Read embedded video metadata and nfo/json sidecar files during scan
+	ScanReadFileMetadata bool `json:"scanReadFileMetadata"`
+	// Whether file metadata replaces existing values. Defaults to EXISTING
+	ScanFileMetadataPrecedence *models.FileMetadataPrecedence `json:"scanFileMetadataPrecedence"`
+	// Create performers, studios and tags from file metadata that do not exist
+	ScanFileMetadataCreateMissing bool `json:"scanFileMetadataCreateMissing"`
+}
+
+// ScanGenerateOptions are the generators run during scan.
+type ScanGenerateOptions struct {
+	// Generate scene covers
+	Covers bool `json:"covers" yaml:"covers"`
+	// Generate previews
+	Previews bool `json:"previews" yaml:"previews"`
+	// Generate image previews
+	ImagePreviews bool `json:"imagePreviews" yaml:"imagepreviews"`
+	// Generate sprites
+	Sprites bool `json:"sprites" yaml:"sprites"`
+	// Generate phashes
+	Phashes bool `json:"phashes" yaml:"phashes"`
+	// Generate image thumbnails
+	Thumbnails bool `json:"thumbnails" yaml:"thumbnails"`
+	// Generate image clip previews
+	ClipPreviews bool `json:"clipPreviews" yaml:"clippreviews"`
+}
+
+// WithGenerate returns a copy of the options with the generators replaced
+// by g.
+func (o ScanMetadataOptions) WithGenerate(g ScanGenerateOptions) ScanMetadataOptions {
+	o.ScanGenerateCovers = g.Covers
+	o.ScanGeneratePreviews = g.Previews
+	o.ScanGenerateImagePreviews = g.ImagePreviews
+	o.ScanGenerateSprites = g.Sprites
+	o.ScanGeneratePhashes = g.Phashes
+	o.ScanGenerateThumbnails = g.Thumbnails
+	o.ScanGenerateClipPreviews = g.ClipPreviews
+	return o
 }
 
 type AutoTagMetadataOptions struct {
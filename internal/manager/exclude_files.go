@@ -4,6 +4,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/pkg/logger"
 )
 
@@ -87,3 +88,38 @@ func matchFileSimple(file string, regExps []*regexp.Regexp) bool {
 	}
 	return false
 }
+
+// stashExcludes holds the compiled exclusion patterns of library paths,
+// keyed by path.
+type stashExcludes map[string]excludeRegexps
+
+type excludeRegexps struct {
+	video []*regexp.Regexp
+	image []*regexp.Regexp
+}
+
+func newStashExcludes(stashPaths config.StashConfigs) stashExcludes {
+	ret := make(stashExcludes)
+	for _, s := range stashPaths {
+		if len(s.VideoExcludes) > 0 || len(s.ImageExcludes) > 0 {
+			ret[s.Path] = excludeRegexps{
+				video: generateRegexps(s.VideoExcludes),
+				image: generateRegexps(s.ImageExcludes),
+			}
+		}
+	}
+
+	return ret
+}
+
+// matchVideo returns true if path matches the video exclusion patterns of
+// the library path s.
+func (e stashExcludes) matchVideo(s *config.StashConfig, path string) bool {
+	return matchFileRegex(path, e[s.Path].video)
+}
+
+// matchImage returns true if path matches the image exclusion patterns of
+// the library path s.
+func (e stashExcludes) matchImage(s *config.StashConfig, path string) bool {
+	return matchFileRegex(path, e[s.Path].image)
+}
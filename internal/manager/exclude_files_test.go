@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/pkg/logger"
 )
 
@@ -96,3 +99,41 @@ func runMatch(filenames []string, patterns []string, expCount int) error {
 
 	return nil
 }
+
+func TestStashExcludes(t *testing.T) {
+	videos := &config.StashConfig{Path: "/stash/videos"}
+	videos.VideoExcludes = []string{"/sample/"}
+	images := &config.StashConfig{Path: "/stash/images"}
+	images.ImageExcludes = []string{"\\.gif$"}
+
+	e := newStashExcludes(config.StashConfigs{videos, images})
+
+	assert.True(t, e.matchVideo(videos, "/stash/videos/sample/file.mp4"))
+	assert.False(t, e.matchVideo(videos, "/stash/videos/file.mp4"))
+	assert.False(t, e.matchImage(videos, "/stash/videos/sample/file.jpg"))
+
+	assert.True(t, e.matchImage(images, "/stash/images/FILE.GIF"))
+	assert.False(t, e.matchVideo(images, "/stash/images/sample/file.mp4"))
+}
+
+func TestSortScanPaths(t *testing.T) {
+	newStash := func(path string, priority int) *config.StashConfig {
+		ret := &config.StashConfig{Path: path}
+		ret.ScanPriority = priority
+		return ret
+	}
+
+	got := sortScanPaths(config.StashConfigs{
+		newStash("a", 0),
+		newStash("b", 10),
+		newStash("c", -1),
+		newStash("d", 0),
+	})
+
+	var paths []string
+	for _, p := range got {
+		paths = append(paths, p.Path)
+	}
+
+	assert.Equal(t, []string{"b", "a", "d", "c"}, paths)
+}
@@ -27,7 +27,7 @@ func (c *fingerprintCalculator) calculateOshash(f *models.BaseFile, o file.Opene
 
 	rc, isRC := r.(io.ReadSeeker)
 	if !isRC {
-		return nil, errors.New("cannot calculate oshash for non-readcloser")
+		return nil, errors.New("cannot calculate oshash for non-seekable file (videos in archives must be stored without compression)")
 	}
 
 	hash, err := oshash.FromReader(rc, f.Size)
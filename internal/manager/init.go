@@ -14,6 +14,8 @@ import (
 	"github.com/stashapp/stash/internal/log"
 	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/file/remote"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/gallery"
 	"github.com/stashapp/stash/pkg/image"
@@ -45,12 +47,13 @@ func Initialize(cfg *config.Config, l *log.Logger) (*Manager, error) {
 	pluginCache := plugin.NewCache(cfg)
 
 	sceneService := &scene.Service{
-		File:             db.File,
-		Repository:       db.Scene,
-		MarkerRepository: db.SceneMarker,
-		PluginCache:      pluginCache,
-		Paths:            mgrPaths,
-		Config:           cfg,
+		File:                  db.File,
+		Repository:            db.Scene,
+		MarkerRepository:      db.SceneMarker,
+		FingerprintSubmission: db.FingerprintSubmission,
+		PluginCache:           pluginCache,
+		Paths:                 mgrPaths,
+		Config:                cfg,
 	}
 
 	imageService := &image.Service{
@@ -98,6 +101,8 @@ func Initialize(cfg *config.Config, l *log.Logger) (*Manager, error) {
 		GalleryService: galleryService,
 
 		scanSubs: &subscriptionManager{},
+
+		FS: remote.NewFS(&file.OsFS{}),
 	}
 
 	if !cfg.IsNewSystem() {
@@ -191,6 +196,8 @@ func (s *Manager) postInit(ctx context.Context) error {
 
 	s.RefreshStreamManager()
 	s.RefreshDLNA()
+	s.RefreshRemoteStorage()
+	s.RefreshLibraryWatcher()
 
 	s.SetBlobStoreOptions()
 
@@ -287,8 +294,15 @@ The ffmpeg and ffprobe binaries should be placed in %s.
 		}
 	}
 
-	s.FFMpeg = ffmpeg.NewEncoder(ffmpegPath)
-	s.FFProbe = ffmpeg.FFProbe(ffprobePath)
+	// remote and archived files are read through the remote storage proxy,
+	// which is started before ffmpeg is initialized
+	var remoteInput ffmpeg.RemoteInput
+	if s.remoteProxy != nil {
+		remoteInput = s.remoteProxy
+	}
+
+	s.FFMpeg = ffmpeg.NewEncoder(ffmpegPath, remoteInput)
+	s.FFProbe = ffmpeg.NewFFProbe(ffprobePath, remoteInput)
 
 	s.FFMpeg.InitHWSupport(ctx)
 	s.RefreshStreamManager()
@@ -0,0 +1,111 @@
+package manager
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+type libraryWatcher struct {
+	mutex  sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (w *libraryWatcher) start(fw *file.Watcher) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.stopLocked()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		if err := fw.Watch(ctx); err != nil {
+			logger.Errorf("Error watching library: %v", err)
+		}
+	}()
+
+	w.cancel = cancel
+	w.done = done
+}
+
+func (w *libraryWatcher) stop() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.stopLocked()
+}
+
+func (w *libraryWatcher) stopLocked() {
+	if w.cancel == nil {
+		return
+	}
+
+	w.cancel()
+	<-w.done
+
+	w.cancel = nil
+	w.done = nil
+}
+
+// RefreshLibraryWatcher starts, restarts or stops watching the stash paths
+// for changes as needed.
+func (s *Manager) RefreshLibraryWatcher() {
+	var paths []string
+	for _, p := range s.Config.GetStashPaths() {
+		// remote storage cannot be watched
+		if p.Remote != nil {
+			continue
+		}
+
+		paths = append(paths, p.Path)
+	}
+
+	if !s.Config.GetWatchLibrary() || len(paths) == 0 {
+		s.libraryWatcher.stop()
+		return
+	}
+
+	s.libraryWatcher.start(&file.Watcher{
+		Paths:        paths,
+		Poll:         s.Config.GetWatchLibraryPoll(),
+		PollInterval: time.Duration(s.Config.GetWatchLibraryPollInterval()) * time.Second,
+		OnChange:     s.onLibraryChange,
+	})
+}
+
+// onLibraryChange queues a scan of the changed paths, followed by a clean of
+// the removed paths. The clean runs after the scan, which detects moved files
+// and folders and updates them to their new paths, so only the files and
+// folders that were actually removed are cleaned.
+func (s *Manager) onLibraryChange(_ context.Context, changes file.WatchChanges) {
+	// jobs must not be cancelled when the watcher is stopped
+	ctx := context.Background()
+
+	if len(changes.Updated) > 0 {
+		input := ScanMetadataInput{
+			Paths: changes.Updated,
+		}
+		if defaults := s.Config.GetDefaultScanSettings(); defaults != nil {
+			input.ScanMetadataOptions = *defaults
+		}
+
+		logger.Infof("Library changed, scanning %d paths", len(changes.Updated))
+		if _, err := s.Scan(ctx, input); err != nil {
+			logger.Errorf("Error scanning changed paths: %v", err)
+		}
+	}
+
+	if len(changes.Removed) > 0 {
+		logger.Infof("Library changed, cleaning %d removed paths", len(changes.Removed))
+		s.Clean(ctx, CleanMetadataInput{
+			Paths: changes.Removed,
+		})
+	}
+}
@@ -14,6 +14,8 @@ import (
 	"github.com/stashapp/stash/internal/log"
 	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/file/remote"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/job"
 	"github.com/stashapp/stash/pkg/logger"
@@ -61,6 +63,15 @@ type Manager struct {
 	GalleryService GalleryService
 
 	scanSubs *subscriptionManager
+
+	// report of the last auto-tag dry run
+	autoTagReport autoTagReportStore
+
+	libraryWatcher libraryWatcher
+
+	// FS reads the files of the stash paths, including those on remote storage
+	FS          *remote.FS
+	remoteProxy *remote.Proxy
 }
 
 var instance *Manager
@@ -147,6 +158,22 @@ func (s *Manager) RefreshDLNA() {
 	}
 }
 
+// NewFileDeleter returns a file deleter that does not delete files in
+// read-only library paths.
+func (s *Manager) NewFileDeleter() *file.Deleter {
+	ret := file.NewDeleter()
+	ret.ReadOnlyPaths = s.Config.GetStashPaths().GetReadOnlyPaths()
+	return ret
+}
+
+// NewFileMover returns a file mover that does not move files into or out
+// of read-only library paths.
+func (s *Manager) NewFileMover(fileStore models.FileFinderUpdater, folderStore models.FolderReaderWriter) *file.Mover {
+	ret := file.NewMover(fileStore, folderStore)
+	ret.ReadOnlyPaths = s.Config.GetStashPaths().GetReadOnlyPaths()
+	return ret
+}
+
 func createPackageManager(localPath string, srcPathGetter pkg.SourcePathGetter) *pkg.Manager {
 	const timeout = 10 * time.Second
 	httpClient := &http.Client{
@@ -288,7 +315,7 @@ func (s *Manager) Setup(ctx context.Context, input SetupInput) error {
 }
 
 func (s *Manager) validateFFmpeg() error {
-	if s.FFMpeg == nil || s.FFProbe == "" {
+	if s.FFMpeg == nil || s.FFProbe.Path() == "" {
 		return errors.New("missing ffmpeg and/or ffprobe")
 	}
 	return nil
@@ -449,6 +476,9 @@ func (s *Manager) Shutdown() {
 		s.StreamManager = nil
 	}
 
+	s.libraryWatcher.stop()
+	s.stopRemoteStorage()
+
 	err := s.Database.Close()
 	if err != nil {
 		logger.Errorf("Error closing database: %s", err)
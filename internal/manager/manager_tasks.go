@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -47,11 +48,14 @@ func isImage(pathname string) bool {
 	return fsutil.MatchExtension(pathname, imgExt)
 }
 
+// getScanPaths returns the stash paths of the input paths, or all stash
+// paths if none are provided. Paths are ordered by scan priority, highest
+// first.
 func getScanPaths(inputPaths []string) []*config.StashConfig {
 	stashPaths := config.GetInstance().GetStashPaths()
 
 	if len(inputPaths) == 0 {
-		return stashPaths
+		return sortScanPaths(stashPaths)
 	}
 
 	var ret config.StashConfigs
@@ -68,7 +72,16 @@ func getScanPaths(inputPaths []string) []*config.StashConfig {
 		ret = append(ret, &ss)
 	}
 
-	return ret
+	return sortScanPaths(ret)
+}
+
+// sortScanPaths sorts the paths by scan priority, highest first. Paths with
+// the same priority keep their configured order.
+func sortScanPaths(paths config.StashConfigs) config.StashConfigs {
+	sort.SliceStable(paths, func(i, j int) bool {
+		return paths[i].ScanPriority > paths[j].ScanPriority
+	})
+	return paths
 }
 
 // ScanSubscribe subscribes to a notification that is triggered when a
@@ -82,6 +95,10 @@ type ScanMetadataInput struct {
 
 	config.ScanMetadataOptions `mapstructure:",squash"`
 
+	// Scan the files of all folders, including folders that are unchanged
+	// since the last scan
+	FullRescan bool `json:"fullRescan"`
+
 	// Filter options for the scan
 	Filter *ScanMetaDataFilterInput `json:"filter"`
 }
@@ -97,12 +114,19 @@ func (s *Manager) Scan(ctx context.Context, input ScanMetadataInput) (int, error
 		return 0, err
 	}
 
+	var probes *video.ProbeCache
+	if input.ScanReadFileMetadata {
+		// keep the ffprobe results to read the embedded metadata
+		probes = video.NewProbeCache()
+	}
+
 	scanner := &file.Scanner{
 		Repository: file.NewRepository(s.Repository),
 		FileDecorators: []file.Decorator{
 			&file.FilteredDecorator{
 				Decorator: &video.Decorator{
 					FFProbe: s.FFProbe,
+					Probes:  probes,
 				},
 				Filter: file.FilterFunc(videoFileFilter),
 			},
@@ -114,13 +138,14 @@ func (s *Manager) Scan(ctx context.Context, input ScanMetadataInput) (int, error
 			},
 		},
 		FingerprintCalculator: &fingerprintCalculator{s.Config},
-		FS:                    &file.OsFS{},
+		FS:                    s.FS,
 	}
 
 	scanJob := ScanJob{
 		scanner:       scanner,
 		input:         input,
 		subscriptions: s.scanSubs,
+		probes:        probes,
 	}
 
 	return s.JobManager.Add(ctx, "Scanning...", &scanJob), nil
@@ -259,12 +284,17 @@ type AutoTagMetadataInput struct {
 	Studios []string `json:"studios"`
 	// IDs of tags to tag files with, or "*" for all
 	Tags []string `json:"tags"`
+	// Do a dry run. Don't apply any changes, generate a report of the proposed
+	// links instead
+	DryRun bool `json:"dryRun"`
 }
 
 func (s *Manager) AutoTag(ctx context.Context, input AutoTagMetadataInput) int {
 	j := autoTagJob{
 		repository: s.Repository,
 		input:      input,
+		reports:    &s.autoTagReport,
+		reportFile: s.Paths.Generated.AutoTagReport,
 	}
 
 	return s.JobManager.Add(ctx, "Auto-tagging...", &j)
@@ -278,7 +308,7 @@ type CleanMetadataInput struct {
 
 func (s *Manager) Clean(ctx context.Context, input CleanMetadataInput) int {
 	cleaner := &file.Cleaner{
-		FS:         &file.OsFS{},
+		FS:         s.FS,
 		Repository: file.NewRepository(s.Repository),
 		Handlers: []file.CleanHandler{
 			&cleanHandler{},
@@ -0,0 +1,51 @@
+package manager
+
+import (
+	"github.com/stashapp/stash/pkg/file/remote"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// RefreshRemoteStorage sets the stash paths on remote storage, and starts
+// the proxy used by ffmpeg to read remote and archived files if needed. The
+// proxy is passed to ffmpeg and ffprobe when they are initialized, so must be
+// started first.
+// Only archives within the stash paths are served by the proxy.
+// Call this when the stash paths change.
+func (s *Manager) RefreshRemoteStorage() {
+	var mounts []remote.Mount
+	var libraryPaths []string
+	for _, p := range s.Config.GetStashPaths() {
+		libraryPaths = append(libraryPaths, p.Path)
+		if p.Remote != nil {
+			mounts = append(mounts, remote.Mount{
+				Path:   p.Path,
+				Config: *p.Remote,
+			})
+		}
+	}
+
+	s.FS.SetMounts(mounts)
+
+	if s.remoteProxy == nil {
+		proxy := remote.NewProxy(s.FS)
+		if err := proxy.Start(); err != nil {
+			logger.Errorf("error starting remote storage proxy: %v", err)
+			return
+		}
+
+		s.remoteProxy = proxy
+	}
+
+	s.remoteProxy.SetLibraryPaths(libraryPaths)
+}
+
+func (s *Manager) stopRemoteStorage() {
+	if s.remoteProxy != nil {
+		if err := s.remoteProxy.Stop(); err != nil {
+			logger.Warnf("error stopping remote storage proxy: %v", err)
+		}
+		s.remoteProxy = nil
+	}
+
+	s.FS.Close()
+}
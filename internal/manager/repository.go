@@ -18,6 +18,7 @@ type SceneService interface {
 type ImageService interface {
 	Destroy(ctx context.Context, image *models.Image, fileDeleter *image.FileDeleter, deleteGenerated, deleteFile bool) error
 	DestroyZipImages(ctx context.Context, zipFile models.File, fileDeleter *image.FileDeleter, deleteGenerated bool) ([]*models.Image, error)
+	Merge(ctx context.Context, sourceIDs []int, destinationID int) error
 }
 
 type GalleryService interface {
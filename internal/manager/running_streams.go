@@ -47,6 +47,12 @@ func (s *SceneServer) StreamSceneDirect(scene *models.Scene, w http.ResponseWrit
 	sceneHash := scene.GetHash(config.GetInstance().GetVideoFileNamingAlgorithm())
 
 	filepath := GetInstance().Paths.Scene.GetStreamPath(scene.Path, sceneHash)
+
+	if fs := GetInstance().FS; fs.IsRemote(filepath) || fs.IsArchived(filepath) {
+		fs.ServeFile(w, r, filepath)
+		return
+	}
+
 	streamRequestCtx := ffmpeg.NewStreamRequestContext(w, r)
 
 	// #2579 - hijacking and closing the connection here causes video playback to fail in Safari
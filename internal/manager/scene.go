@@ -69,7 +69,7 @@ func GetVideoFileContainer(file *models.VideoFile) (ffmpeg.Container, error) {
 			return ffmpeg.Container(""), fmt.Errorf("error reading video file: %v", err)
 		}
 
-		return ffmpeg.MatchContainer(tmpVideoFile.Container, file.Path)
+		return ffprobe.MatchContainer(tmpVideoFile.Container, file.Path)
 	}
 
 	return container, nil
@@ -21,6 +21,8 @@ import (
 type autoTagJob struct {
 	repository models.Repository
 	input      AutoTagMetadataInput
+	reports    *autoTagReportStore
+	reportFile string
 
 	cache match.Cache
 }
@@ -29,6 +31,16 @@ func (j *autoTagJob) Execute(ctx context.Context, progress *job.Progress) {
 	begin := time.Now()
 
 	input := j.input
+
+	var recorder *autoTagRecorder
+	repository := j.repository
+	if input.DryRun {
+		// record the changes instead of applying them
+		recorder = newAutoTagRecorder()
+		j.repository = dryRunRepository(repository, recorder)
+		logger.Info("Running auto-tag in dry run mode. No changes will be made")
+	}
+
 	if j.isFileBasedAutoTag(input) {
 		// doing file-based auto-tag
 		j.autoTagFiles(ctx, progress, input.Paths, len(input.Performers) > 0, len(input.Studios) > 0, len(input.Tags) > 0)
@@ -37,6 +49,22 @@ func (j *autoTagJob) Execute(ctx context.Context, progress *job.Progress) {
 		j.autoTagSpecific(ctx, progress)
 	}
 
+	if recorder != nil && !job.IsCancelled(ctx) {
+		b := autoTagReportBuilder{repository: repository}
+		report, err := b.build(ctx, recorder.links)
+		if err != nil {
+			logger.Errorf("Error generating auto-tag report: %v", err)
+			return
+		}
+
+		if err := j.reports.set(j.reportFile, report); err != nil {
+			logger.Errorf("Error saving auto-tag report: %v", err)
+			return
+		}
+
+		logger.Infof("Auto-tag dry run found %d new links", len(report.Rows))
+	}
+
 	logger.Infof("Finished auto-tag after %s", time.Since(begin).String())
 }
 
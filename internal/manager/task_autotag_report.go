@@ -0,0 +1,718 @@
+package manager
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/gallery"
+	"github.com/stashapp/stash/pkg/hash"
+	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/match"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/sliceutil"
+)
+
+var (
+	// ErrNoAutoTagReport is returned when there is no auto-tag dry run report.
+	ErrNoAutoTagReport = errors.New("no auto-tag report available")
+	// ErrStaleAutoTagReport is returned when applying the rows of a report
+	// that has been replaced by a later dry run.
+	ErrStaleAutoTagReport = errors.New("auto-tag report has been replaced by a later dry run")
+)
+
+type AutoTagReportFormat string
+
+const (
+	AutoTagReportFormatCSV  AutoTagReportFormat = "CSV"
+	AutoTagReportFormatJSON AutoTagReportFormat = "JSON"
+)
+
+var AllAutoTagReportFormat = []AutoTagReportFormat{
+	AutoTagReportFormatCSV,
+	AutoTagReportFormatJSON,
+}
+
+func (e AutoTagReportFormat) IsValid() bool {
+	switch e {
+	case AutoTagReportFormatCSV, AutoTagReportFormatJSON:
+		return true
+	}
+	return false
+}
+
+func (e AutoTagReportFormat) String() string {
+	return string(e)
+}
+
+func (e *AutoTagReportFormat) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AutoTagReportFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AutoTagReportFormat", str)
+	}
+	return nil
+}
+
+func (e AutoTagReportFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+const (
+	autoTagObjectScene   = "scene"
+	autoTagObjectImage   = "image"
+	autoTagObjectGallery = "gallery"
+
+	autoTagEntityPerformer = "performer"
+	autoTagEntityStudio    = "studio"
+	autoTagEntityTag       = "tag"
+)
+
+// AutoTagReportRow is a link that an auto-tag dry run would have made.
+type AutoTagReportRow struct {
+	// Index of the row in the report
+	ID int `json:"id"`
+	// scene, image or gallery
+	ObjectType string `json:"object_type"`
+	ObjectID   int    `json:"object_id"`
+	Path       string `json:"path"`
+	// performer, studio or tag
+	EntityType  string `json:"entity_type"`
+	EntityID    int    `json:"entity_id"`
+	EntityName  string `json:"entity_name"`
+	MatchedText string `json:"matched_text"`
+}
+
+// AutoTagReport is the result of an auto-tag dry run.
+type AutoTagReport struct {
+	// Identifies the report, so that rows are not applied from a report
+	// that has been replaced
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	// Total number of rows
+	Count int                 `json:"count"`
+	Rows  []*AutoTagReportRow `json:"rows"`
+}
+
+// Page returns a copy of the report containing the rows of the page of
+// the filter.
+func (r *AutoTagReport) Page(filter *models.FindFilterType) *AutoTagReport {
+	ret := *r
+	if filter == nil || filter.IsGetAll() {
+		return &ret
+	}
+
+	perPage := filter.GetPageSize()
+	start := (filter.GetPage() - 1) * perPage
+	end := start + perPage
+	if start > len(r.Rows) {
+		start = len(r.Rows)
+	}
+	if end > len(r.Rows) {
+		end = len(r.Rows)
+	}
+
+	ret.Rows = r.Rows[start:end]
+	return &ret
+}
+
+var autoTagReportCSVHeader = []string{"id", "object_type", "object_id", "path", "entity_type", "entity_id", "entity_name", "matched_text"}
+
+func (r *AutoTagReport) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(autoTagReportCSVHeader); err != nil {
+		return err
+	}
+
+	for _, row := range r.Rows {
+		if err := cw.Write([]string{
+			strconv.Itoa(row.ID),
+			row.ObjectType,
+			strconv.Itoa(row.ObjectID),
+			row.Path,
+			row.EntityType,
+			strconv.Itoa(row.EntityID),
+			row.EntityName,
+			row.MatchedText,
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func (r *AutoTagReport) writeJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// Write writes the report to w in the given format.
+func (r *AutoTagReport) Write(w io.Writer, format AutoTagReportFormat) error {
+	switch format {
+	case AutoTagReportFormatCSV:
+		return r.writeCSV(w)
+	case AutoTagReportFormatJSON:
+		return r.writeJSON(w)
+	default:
+		return fmt.Errorf("%w: invalid report format %q", ErrInput, format)
+	}
+}
+
+// autoTagReportStore holds the report of the last auto-tag dry run. The
+// report is saved to file, so that it is kept across restarts.
+type autoTagReportStore struct {
+	report *AutoTagReport
+	loaded bool
+	mutex  sync.Mutex
+}
+
+// get returns the report, loading it from file on first use.
+func (s *autoTagReportStore) get(file string) *AutoTagReport {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.loaded {
+		s.loaded = true
+
+		r, err := readAutoTagReport(file)
+		if err != nil {
+			logger.Warnf("Error reading auto-tag report: %v", err)
+		}
+		s.report = r
+	}
+
+	return s.report
+}
+
+// set saves the report to file and replaces the current report.
+func (s *autoTagReportStore) set(file string, r *AutoTagReport) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := writeAutoTagReport(file, r); err != nil {
+		return err
+	}
+
+	s.report = r
+	s.loaded = true
+	return nil
+}
+
+func readAutoTagReport(file string) (*AutoTagReport, error) {
+	f, err := os.Open(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var ret AutoTagReport
+	if err := json.NewDecoder(f).Decode(&ret); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", file, err)
+	}
+
+	return &ret, nil
+}
+
+func writeAutoTagReport(file string, r *AutoTagReport) error {
+	// write to a temporary file first, so that the existing report is kept
+	// if writing fails
+	f, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+"*.tmp")
+	if err != nil {
+		return err
+	}
+
+	if err := json.NewEncoder(f).Encode(r); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return fmt.Errorf("writing %s: %w", file, err)
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return os.Rename(f.Name(), file)
+}
+
+// GetAutoTagReport returns the report of the last auto-tag dry run, with
+// the rows of the page of the filter, or nil if there is none.
+func (s *Manager) GetAutoTagReport(filter *models.FindFilterType) *AutoTagReport {
+	report := s.autoTagReport.get(s.Paths.Generated.AutoTagReport)
+	if report == nil {
+		return nil
+	}
+
+	return report.Page(filter)
+}
+
+// ExportAutoTagReport writes the report of the last auto-tag dry run to a
+// file and registers it for download. Returns the download hash and the
+// file name.
+func (s *Manager) ExportAutoTagReport(format AutoTagReportFormat) (string, string, error) {
+	report := s.autoTagReport.get(s.Paths.Generated.AutoTagReport)
+	if report == nil {
+		return "", "", ErrNoAutoTagReport
+	}
+
+	if !format.IsValid() {
+		return "", "", fmt.Errorf("%w: invalid report format %q", ErrInput, format)
+	}
+
+	ext := ".csv"
+	contentType := "text/csv"
+	if format == AutoTagReportFormatJSON {
+		ext = ".json"
+		contentType = "application/json"
+	}
+
+	if err := fsutil.EnsureDir(s.Paths.Generated.Downloads); err != nil {
+		return "", "", err
+	}
+
+	f, err := os.CreateTemp(s.Paths.Generated.Downloads, "autotag*"+ext)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+
+	if err := report.Write(f, format); err != nil {
+		return "", "", fmt.Errorf("writing auto-tag report: %w", err)
+	}
+
+	hash, err := s.DownloadStore.RegisterFile(f.Name(), contentType, false)
+	if err != nil {
+		return "", "", fmt.Errorf("error registering file for download: %w", err)
+	}
+
+	name := "autotag-report-" + report.CreatedAt.Format("20060102-150405") + ext
+	return hash, name, nil
+}
+
+type ApplyAutoTagReportInput struct {
+	// ID of the report that the rows are from
+	ReportID string `json:"report_id"`
+	// IDs of the report rows to apply, null for all
+	Rows []int `json:"rows"`
+}
+
+// ApplyAutoTagReport starts a job that applies the selected rows of the last
+// auto-tag dry run report. Returns ErrStaleAutoTagReport if the input is for
+// a different report.
+func (s *Manager) ApplyAutoTagReport(ctx context.Context, input ApplyAutoTagReportInput) (int, error) {
+	report := s.autoTagReport.get(s.Paths.Generated.AutoTagReport)
+	if report == nil {
+		return 0, ErrNoAutoTagReport
+	}
+
+	if input.ReportID != report.ID {
+		return 0, fmt.Errorf("%w: report %q", ErrStaleAutoTagReport, input.ReportID)
+	}
+
+	rows := report.Rows
+	if input.Rows != nil {
+		rows = nil
+		for _, id := range input.Rows {
+			if id < 0 || id >= len(report.Rows) {
+				return 0, fmt.Errorf("%w: invalid report row %d", ErrInput, id)
+			}
+			rows = append(rows, report.Rows[id])
+		}
+	}
+
+	j := applyAutoTagReportJob{
+		repository: s.Repository,
+		rows:       rows,
+	}
+
+	return s.JobManager.Add(ctx, "Applying auto-tag report...", &j), nil
+}
+
+// autoTagLink is a link recorded during an auto-tag dry run.
+type autoTagLink struct {
+	objectType string
+	objectID   int
+	entityType string
+	entityID   int
+}
+
+// autoTagRecorder records the links that auto-tagging would make, in the
+// order they were first proposed.
+type autoTagRecorder struct {
+	links []autoTagLink
+	seen  map[autoTagLink]bool
+	mutex sync.Mutex
+}
+
+func newAutoTagRecorder() *autoTagRecorder {
+	return &autoTagRecorder{
+		seen: make(map[autoTagLink]bool),
+	}
+}
+
+func (r *autoTagRecorder) record(objectType string, objectID int, entityType string, entityIDs ...int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, id := range entityIDs {
+		l := autoTagLink{
+			objectType: objectType,
+			objectID:   objectID,
+			entityType: entityType,
+			entityID:   id,
+		}
+
+		if !r.seen[l] {
+			r.seen[l] = true
+			r.links = append(r.links, l)
+		}
+	}
+}
+
+func (r *autoTagRecorder) recordPartial(objectType string, objectID int, performerIDs, tagIDs *models.UpdateIDs, studioID models.OptionalInt) {
+	if performerIDs != nil {
+		r.record(objectType, objectID, autoTagEntityPerformer, performerIDs.IDs...)
+	}
+	if tagIDs != nil {
+		r.record(objectType, objectID, autoTagEntityTag, tagIDs.IDs...)
+	}
+	if studioID.Set && !studioID.Null {
+		r.record(objectType, objectID, autoTagEntityStudio, studioID.Value)
+	}
+}
+
+// dryRunSceneWriter records scene updates instead of applying them.
+type dryRunSceneWriter struct {
+	models.SceneReaderWriter
+	recorder *autoTagRecorder
+}
+
+func (w *dryRunSceneWriter) UpdatePartial(ctx context.Context, id int, partial models.ScenePartial) (*models.Scene, error) {
+	w.recorder.recordPartial(autoTagObjectScene, id, partial.PerformerIDs, partial.TagIDs, partial.StudioID)
+	return w.Find(ctx, id)
+}
+
+// dryRunImageWriter records image updates instead of applying them.
+type dryRunImageWriter struct {
+	models.ImageReaderWriter
+	recorder *autoTagRecorder
+}
+
+func (w *dryRunImageWriter) UpdatePartial(ctx context.Context, id int, partial models.ImagePartial) (*models.Image, error) {
+	w.recorder.recordPartial(autoTagObjectImage, id, partial.PerformerIDs, partial.TagIDs, partial.StudioID)
+	return w.Find(ctx, id)
+}
+
+// dryRunGalleryWriter records gallery updates instead of applying them.
+type dryRunGalleryWriter struct {
+	models.GalleryReaderWriter
+	recorder *autoTagRecorder
+}
+
+func (w *dryRunGalleryWriter) UpdatePartial(ctx context.Context, id int, partial models.GalleryPartial) (*models.Gallery, error) {
+	w.recorder.recordPartial(autoTagObjectGallery, id, partial.PerformerIDs, partial.TagIDs, partial.StudioID)
+	return w.Find(ctx, id)
+}
+
+// dryRunRepository returns a copy of r where scene, image and gallery updates
+// are recorded instead of applied.
+func dryRunRepository(r models.Repository, recorder *autoTagRecorder) models.Repository {
+	r.Scene = &dryRunSceneWriter{SceneReaderWriter: r.Scene, recorder: recorder}
+	r.Image = &dryRunImageWriter{ImageReaderWriter: r.Image, recorder: recorder}
+	r.Gallery = &dryRunGalleryWriter{GalleryReaderWriter: r.Gallery, recorder: recorder}
+	return r
+}
+
+// autoTagEntity is the performer, studio or tag of a report row.
+type autoTagEntity struct {
+	name  string
+	names []string
+	rules *models.AutoTagRules
+}
+
+type autoTagReportBuilder struct {
+	repository models.Repository
+	entities   map[string]map[int]*autoTagEntity
+}
+
+func (b *autoTagReportBuilder) getEntity(ctx context.Context, entityType string, id int) (*autoTagEntity, error) {
+	if e := b.entities[entityType][id]; e != nil {
+		return e, nil
+	}
+
+	r := b.repository
+	var ret *autoTagEntity
+
+	switch entityType {
+	case autoTagEntityPerformer:
+		p, err := r.Performer.Find(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if p == nil {
+			return nil, nil
+		}
+		// performer aliases are not used for auto-tagging
+		ret = &autoTagEntity{name: p.Name, names: []string{p.Name}, rules: p.AutoTagRules}
+	case autoTagEntityStudio:
+		s, err := r.Studio.Find(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			return nil, nil
+		}
+		aliases, err := r.Studio.GetAliases(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		ret = &autoTagEntity{name: s.Name, names: append([]string{s.Name}, aliases...), rules: s.AutoTagRules}
+	case autoTagEntityTag:
+		t, err := r.Tag.Find(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if t == nil {
+			return nil, nil
+		}
+		aliases, err := r.Tag.GetAliases(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		ret = &autoTagEntity{name: t.Name, names: append([]string{t.Name}, aliases...), rules: t.AutoTagRules}
+	}
+
+	if b.entities[entityType] == nil {
+		b.entities[entityType] = make(map[int]*autoTagEntity)
+	}
+	b.entities[entityType][id] = ret
+	return ret, nil
+}
+
+func (b *autoTagReportBuilder) getObject(ctx context.Context, objectType string, id int) (path string, title string, err error) {
+	r := b.repository
+
+	switch objectType {
+	case autoTagObjectScene:
+		s, err := r.Scene.Find(ctx, id)
+		if err != nil || s == nil {
+			return "", "", err
+		}
+		return s.Path, s.Title, nil
+	case autoTagObjectImage:
+		i, err := r.Image.Find(ctx, id)
+		if err != nil || i == nil {
+			return "", "", err
+		}
+		return i.Path, i.Title, nil
+	case autoTagObjectGallery:
+		g, err := r.Gallery.Find(ctx, id)
+		if err != nil || g == nil {
+			return "", "", err
+		}
+		return g.Path, g.Title, nil
+	}
+
+	return "", "", nil
+}
+
+// build returns the report of the recorded links.
+func (b *autoTagReportBuilder) build(ctx context.Context, links []autoTagLink) (*AutoTagReport, error) {
+	id, err := hash.GenerateRandomKey(8)
+	if err != nil {
+		return nil, fmt.Errorf("generating report id: %w", err)
+	}
+
+	b.entities = make(map[string]map[int]*autoTagEntity)
+	ret := &AutoTagReport{
+		ID:        id,
+		CreatedAt: time.Now(),
+		Rows:      []*AutoTagReportRow{},
+	}
+
+	if err := b.repository.WithReadTxn(ctx, func(ctx context.Context) error {
+		for _, l := range links {
+			e, err := b.getEntity(ctx, l.entityType, l.entityID)
+			if err != nil {
+				return fmt.Errorf("getting %s %d: %w", l.entityType, l.entityID, err)
+			}
+
+			path, title, err := b.getObject(ctx, l.objectType, l.objectID)
+			if err != nil {
+				return fmt.Errorf("getting %s %d: %w", l.objectType, l.objectID, err)
+			}
+
+			row := &AutoTagReportRow{
+				ID:         len(ret.Rows),
+				ObjectType: l.objectType,
+				ObjectID:   l.objectID,
+				Path:       path,
+				EntityType: l.entityType,
+				EntityID:   l.entityID,
+			}
+
+			if e != nil {
+				row.EntityName = e.name
+				row.MatchedText = match.MatchedText(e.names, e.rules, path, title)
+			}
+
+			ret.Rows = append(ret.Rows, row)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	ret.Count = len(ret.Rows)
+	return ret, nil
+}
+
+type applyAutoTagReportJob struct {
+	repository models.Repository
+	rows       []*AutoTagReportRow
+}
+
+func (j *applyAutoTagReportJob) Execute(ctx context.Context, progress *job.Progress) {
+	progress.SetTotal(len(j.rows))
+
+	applied := 0
+	for _, row := range j.rows {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping due to user request")
+			return
+		}
+
+		progress.ExecuteTask(fmt.Sprintf("Adding %s %d to %s %d", row.EntityType, row.EntityID, row.ObjectType, row.ObjectID), func() {
+			var added bool
+			if err := j.repository.WithTxn(ctx, func(ctx context.Context) error {
+				var err error
+				added, err = j.applyRow(ctx, row)
+				return err
+			}); err != nil {
+				logger.Errorf("Error adding %s %d to %s %d: %v", row.EntityType, row.EntityID, row.ObjectType, row.ObjectID, err)
+				return
+			}
+
+			if added {
+				applied++
+				logger.Infof("Added %s '%s' to %s '%s'", row.EntityType, row.EntityName, row.ObjectType, row.Path)
+			}
+		})
+
+		progress.Increment()
+	}
+
+	logger.Infof("Applied %d of %d auto-tag report rows", applied, len(j.rows))
+}
+
+// applyRow adds the entity of the row to its object. Links that already
+// exist are skipped, as are studios of objects which already have a studio.
+func (j *applyAutoTagReportJob) applyRow(ctx context.Context, row *AutoTagReportRow) (bool, error) {
+	r := j.repository
+
+	switch row.ObjectType {
+	case autoTagObjectScene:
+		o, err := r.Scene.Find(ctx, row.ObjectID)
+		if err != nil || o == nil {
+			return false, err
+		}
+
+		switch row.EntityType {
+		case autoTagEntityPerformer:
+			if err := o.LoadPerformerIDs(ctx, r.Scene); err != nil || sliceutil.Contains(o.PerformerIDs.List(), row.EntityID) {
+				return false, err
+			}
+			return true, scene.AddPerformer(ctx, r.Scene, o, row.EntityID)
+		case autoTagEntityTag:
+			if err := o.LoadTagIDs(ctx, r.Scene); err != nil || sliceutil.Contains(o.TagIDs.List(), row.EntityID) {
+				return false, err
+			}
+			return true, scene.AddTag(ctx, r.Scene, o, row.EntityID)
+		case autoTagEntityStudio:
+			if o.StudioID != nil {
+				return false, nil
+			}
+			partial := models.NewScenePartial()
+			partial.StudioID = models.NewOptionalInt(row.EntityID)
+			_, err := r.Scene.UpdatePartial(ctx, o.ID, partial)
+			return err == nil, err
+		}
+	case autoTagObjectImage:
+		o, err := r.Image.Find(ctx, row.ObjectID)
+		if err != nil || o == nil {
+			return false, err
+		}
+
+		switch row.EntityType {
+		case autoTagEntityPerformer:
+			if err := o.LoadPerformerIDs(ctx, r.Image); err != nil || sliceutil.Contains(o.PerformerIDs.List(), row.EntityID) {
+				return false, err
+			}
+			return true, image.AddPerformer(ctx, r.Image, o, row.EntityID)
+		case autoTagEntityTag:
+			if err := o.LoadTagIDs(ctx, r.Image); err != nil || sliceutil.Contains(o.TagIDs.List(), row.EntityID) {
+				return false, err
+			}
+			return true, image.AddTag(ctx, r.Image, o, row.EntityID)
+		case autoTagEntityStudio:
+			if o.StudioID != nil {
+				return false, nil
+			}
+			partial := models.NewImagePartial()
+			partial.StudioID = models.NewOptionalInt(row.EntityID)
+			_, err := r.Image.UpdatePartial(ctx, o.ID, partial)
+			return err == nil, err
+		}
+	case autoTagObjectGallery:
+		o, err := r.Gallery.Find(ctx, row.ObjectID)
+		if err != nil || o == nil {
+			return false, err
+		}
+
+		switch row.EntityType {
+		case autoTagEntityPerformer:
+			if err := o.LoadPerformerIDs(ctx, r.Gallery); err != nil || sliceutil.Contains(o.PerformerIDs.List(), row.EntityID) {
+				return false, err
+			}
+			return true, gallery.AddPerformer(ctx, r.Gallery, o, row.EntityID)
+		case autoTagEntityTag:
+			if err := o.LoadTagIDs(ctx, r.Gallery); err != nil || sliceutil.Contains(o.TagIDs.List(), row.EntityID) {
+				return false, err
+			}
+			return true, gallery.AddTag(ctx, r.Gallery, o, row.EntityID)
+		case autoTagEntityStudio:
+			if o.StudioID != nil {
+				return false, nil
+			}
+			partial := models.NewGalleryPartial()
+			partial.StudioID = models.NewOptionalInt(row.EntityID)
+			_, err := r.Gallery.UpdatePartial(ctx, o.ID, partial)
+			return err == nil, err
+		}
+	}
+
+	return false, fmt.Errorf("invalid report row: %s %s", row.ObjectType, row.EntityType)
+}
@@ -0,0 +1,123 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/stashapp/stash/internal/autotag"
+	"github.com/stashapp/stash/pkg/match"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+)
+
+func TestAutoTagDryRun(t *testing.T) {
+	const (
+		sceneID     = 1
+		performerID = 2
+		path        = "/videos/Jane.Doe.scene.mp4"
+	)
+
+	ctx := context.Background()
+	db := mocks.NewDatabase()
+
+	s := &models.Scene{ID: sceneID, Path: path}
+	p := &models.Performer{ID: performerID, Name: "Jane Doe"}
+
+	db.Performer.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(nil, 0, nil)
+	db.Performer.On("QueryForAutoTag", mock.Anything, mock.Anything).Return([]*models.Performer{p}, nil)
+	db.Performer.On("Find", mock.Anything, performerID).Return(p, nil)
+	db.Scene.On("GetPerformerIDs", mock.Anything, sceneID).Return([]int{}, nil)
+	db.Scene.On("Find", mock.Anything, sceneID).Return(s, nil)
+
+	recorder := newAutoTagRecorder()
+	r := dryRunRepository(db.Repository(), recorder)
+
+	// tagging twice should only record the link once
+	for i := 0; i < 2; i++ {
+		s.PerformerIDs = models.RelatedIDs{}
+		err := autotag.ScenePerformers(ctx, s, r.Scene, r.Performer, &match.Cache{})
+		assert.NoError(t, err)
+	}
+
+	b := autoTagReportBuilder{repository: db.Repository()}
+	report, err := b.build(ctx, recorder.links)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, []*AutoTagReportRow{
+		{
+			ID:          0,
+			ObjectType:  autoTagObjectScene,
+			ObjectID:    sceneID,
+			Path:        path,
+			EntityType:  autoTagEntityPerformer,
+			EntityID:    performerID,
+			EntityName:  "Jane Doe",
+			MatchedText: "Jane.Doe",
+		},
+	}, report.Rows)
+
+	var buf bytes.Buffer
+	assert.NoError(t, report.Write(&buf, AutoTagReportFormatCSV))
+	assert.Equal(t, "id,object_type,object_id,path,entity_type,entity_id,entity_name,matched_text\n"+
+		"0,scene,1,/videos/Jane.Doe.scene.mp4,performer,2,Jane Doe,Jane.Doe\n", buf.String())
+
+	// UpdatePartial is not mocked, so this also asserts that nothing was written
+	db.AssertExpectations(t)
+}
+
+func TestAutoTagReport_Page(t *testing.T) {
+	report := &AutoTagReport{ID: "id", Count: 3}
+	for i := 0; i < 3; i++ {
+		report.Rows = append(report.Rows, &AutoTagReportRow{ID: i})
+	}
+
+	rowIDs := func(r *AutoTagReport) []int {
+		ret := []int{}
+		for _, row := range r.Rows {
+			ret = append(ret, row.ID)
+		}
+		return ret
+	}
+
+	page := func(page, perPage int) *models.FindFilterType {
+		return &models.FindFilterType{Page: &page, PerPage: &perPage}
+	}
+
+	assert.Equal(t, []int{0, 1, 2}, rowIDs(report.Page(nil)))
+	assert.Equal(t, []int{0, 1}, rowIDs(report.Page(page(1, 2))))
+	assert.Equal(t, []int{2}, rowIDs(report.Page(page(2, 2))))
+	assert.Equal(t, []int{}, rowIDs(report.Page(page(3, 2))))
+	assert.Equal(t, []int{0, 1, 2}, rowIDs(report.Page(page(1, -1))))
+
+	// the count and id are those of the whole report
+	p := report.Page(page(2, 2))
+	assert.Equal(t, "id", p.ID)
+	assert.Equal(t, 3, p.Count)
+}
+
+func TestAutoTagReportStore(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "autotag_report.json")
+
+	var s autoTagReportStore
+	assert.Nil(t, s.get(file))
+
+	report := &AutoTagReport{
+		ID:        "id",
+		CreatedAt: time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC),
+		Count:     1,
+		Rows:      []*AutoTagReportRow{{ID: 0, ObjectType: autoTagObjectScene, ObjectID: 1}},
+	}
+	assert.NoError(t, s.set(file, report))
+
+	// the report is kept across restarts
+	var loaded autoTagReportStore
+	assert.Equal(t, report, loaded.get(file))
+}
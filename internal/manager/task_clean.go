@@ -145,13 +145,15 @@ type cleanFilter struct {
 }
 
 func newCleanFilter(c *config.Config) *cleanFilter {
+	stashPaths := c.GetStashPaths()
 	return &cleanFilter{
 		scanFilter: scanFilter{
 			extensionConfig:   newExtensionConfig(c),
-			stashPaths:        c.GetStashPaths(),
+			stashPaths:        stashPaths,
 			generatedPath:     c.GetGeneratedPath(),
 			videoExcludeRegex: generateRegexps(c.GetExcludes()),
 			imageExcludeRegex: generateRegexps(c.GetImageExcludes()),
+			stashExcludes:     newStashExcludes(stashPaths),
 		},
 	}
 }
@@ -190,7 +192,7 @@ func (f *cleanFilter) Accept(ctx context.Context, path string, info fs.FileInfo)
 func (f *cleanFilter) shouldCleanFolder(path string, s *config.StashConfig) bool {
 	// only delete folders where it is excluded from everything
 	pathExcludeTest := path + string(filepath.Separator)
-	if (s.ExcludeVideo || matchFileRegex(pathExcludeTest, f.videoExcludeRegex)) && (s.ExcludeImage || matchFileRegex(pathExcludeTest, f.imageExcludeRegex)) {
+	if (s.ExcludeVideo || f.isVideoExcluded(s, pathExcludeTest)) && (s.ExcludeImage || f.isImageExcluded(s, pathExcludeTest)) {
 		logger.Infof("Folder is excluded from both video and image. Marking to clean: \"%s\"", path)
 		return true
 	}
@@ -218,7 +220,7 @@ func (f *cleanFilter) shouldCleanVideoFile(path string, stash *config.StashConfi
 		return true
 	}
 
-	if matchFileRegex(path, f.videoExcludeRegex) {
+	if f.isVideoExcluded(stash, path) {
 		logger.Infof("File matched regex. Marking to clean: \"%s\"", path)
 		return true
 	}
@@ -232,7 +234,7 @@ func (f *cleanFilter) shouldCleanGallery(path string, stash *config.StashConfig)
 		return true
 	}
 
-	if matchFileRegex(path, f.imageExcludeRegex) {
+	if f.isImageExcluded(stash, path) {
 		logger.Infof("File matched regex. Marking to clean: \"%s\"", path)
 		return true
 	}
@@ -246,7 +248,7 @@ func (f *cleanFilter) shouldCleanImage(path string, stash *config.StashConfig) b
 		return true
 	}
 
-	if matchFileRegex(path, f.imageExcludeRegex) {
+	if f.isImageExcluded(stash, path) {
 		logger.Infof("File matched regex. Marking to clean: \"%s\"", path)
 		return true
 	}
@@ -264,7 +264,7 @@ func (j *GenerateJob) queueTasks(ctx context.Context, g *generate.Generator, que
 	}
 
 	*findFilter.Page = 1
-	for more := j.input.ClipPreviews; more; {
+	for more := j.input.ClipPreviews || j.input.Phashes; more; {
 		if job.IsCancelled(ctx) {
 			return totals
 		}
@@ -438,6 +438,7 @@ func (j *GenerateJob) queueSceneJobs(ctx context.Context, g *generate.Generator,
 				File:                f,
 				fileNamingAlgorithm: j.fileNamingAlgo,
 				Overwrite:           j.overwrite,
+				SegmentInterval:     instance.Config.GetPhashSegmentInterval(),
 			}
 
 			if task.required() {
@@ -478,14 +479,38 @@ func (j *GenerateJob) queueMarkerJob(g *generate.Generator, marker *models.Scene
 }
 
 func (j *GenerateJob) queueImageJob(g *generate.Generator, image *models.Image, queue chan<- Task, totals *totalsGenerate) {
-	task := &GenerateClipPreviewTask{
-		Image:     *image,
-		Overwrite: j.overwrite,
+	if j.input.ClipPreviews {
+		task := &GenerateClipPreviewTask{
+			Image:     *image,
+			Overwrite: j.overwrite,
+		}
+
+		if task.required() {
+			totals.clipPreviews++
+			totals.tasks++
+			queue <- task
+		}
 	}
 
-	if task.required() {
-		totals.clipPreviews++
-		totals.tasks++
-		queue <- task
+	if j.input.Phashes {
+		// generate for all image files of the image
+		for _, f := range image.Files.List() {
+			imageFile, ok := f.(*models.ImageFile)
+			if !ok {
+				continue
+			}
+
+			task := &GenerateImagePhashTask{
+				repository: j.repository,
+				File:       imageFile,
+				Overwrite:  j.overwrite,
+			}
+
+			if task.required() {
+				totals.phashes++
+				totals.tasks++
+				queue <- task
+			}
+		}
 	}
 }
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/stashapp/stash/pkg/hash/imagephash"
 	"github.com/stashapp/stash/pkg/hash/videophash"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
 )
 
 type GeneratePhashTask struct {
@@ -14,6 +16,9 @@ type GeneratePhashTask struct {
 	File                *models.VideoFile
 	Overwrite           bool
 	fileNamingAlgorithm models.HashAlgorithm
+	// SegmentInterval is the interval in seconds between the frames of the
+	// phash segments. Phash segments are not generated if 0.
+	SegmentInterval float64
 }
 
 func (t *GeneratePhashTask) GetDescription() string {
@@ -21,14 +26,96 @@ func (t *GeneratePhashTask) GetDescription() string {
 }
 
 func (t *GeneratePhashTask) Start(ctx context.Context) {
+	var fingerprints []models.Fingerprint
+
+	if t.phashRequired() {
+		hash, err := videophash.Generate(instance.FFMpeg, t.File)
+		if err != nil {
+			logger.Errorf("error generating phash: %s", err.Error())
+			logErrorOutput(err)
+			return
+		}
+
+		fingerprints = append(fingerprints, models.Fingerprint{
+			Type:        models.FingerprintTypePhash,
+			Fingerprint: int64(*hash),
+		})
+	}
+
+	if t.segmentsRequired() {
+		segments, err := videophash.GenerateSegments(instance.FFMpeg, t.File, t.SegmentInterval)
+		if err != nil {
+			logger.Errorf("error generating phash segments: %s", err.Error())
+			logErrorOutput(err)
+		} else {
+			fingerprints = append(fingerprints, models.Fingerprint{
+				Type:        models.FingerprintTypePhashSegments,
+				Fingerprint: segments.String(),
+			})
+		}
+	}
+
+	if len(fingerprints) == 0 {
+		return
+	}
+
+	r := t.repository
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		for _, fp := range fingerprints {
+			t.File.Fingerprints = t.File.Fingerprints.AppendUnique(fp)
+		}
+
+		return r.File.Update(ctx, t.File)
+	}); err != nil && ctx.Err() == nil {
+		logger.Errorf("Error setting phash: %v", err)
+	}
+}
+
+func (t *GeneratePhashTask) required() bool {
+	return t.phashRequired() || t.segmentsRequired()
+}
+
+func (t *GeneratePhashTask) phashRequired() bool {
+	if t.Overwrite {
+		return true
+	}
+
+	return t.File.Fingerprints.Get(models.FingerprintTypePhash) == nil
+}
+
+func (t *GeneratePhashTask) segmentsRequired() bool {
+	if t.SegmentInterval <= 0 {
+		return false
+	}
+
+	if t.Overwrite {
+		return true
+	}
+
+	// regenerate if the interval has changed
+	existing := t.File.Fingerprints.GetString(models.FingerprintTypePhashSegments)
+	segments, err := utils.ParsePhashSegments(existing)
+	return err != nil || segments.Interval != t.SegmentInterval
+}
+
+type GenerateImagePhashTask struct {
+	repository models.Repository
+	File       *models.ImageFile
+	Overwrite  bool
+}
+
+func (t *GenerateImagePhashTask) GetDescription() string {
+	return fmt.Sprintf("Generating phash for %s", t.File.Path)
+}
+
+func (t *GenerateImagePhashTask) Start(ctx context.Context) {
 	if !t.required() {
 		return
 	}
 
-	hash, err := videophash.Generate(instance.FFMpeg, t.File)
+	hash, err := imagephash.Generate(instance.FS, t.File)
 	if err != nil {
 		logger.Errorf("error generating phash: %s", err.Error())
-		logErrorOutput(err)
 		return
 	}
 
@@ -46,7 +133,7 @@ func (t *GeneratePhashTask) Start(ctx context.Context) {
 	}
 }
 
-func (t *GeneratePhashTask) required() bool {
+func (t *GenerateImagePhashTask) required() bool {
 	if t.Overwrite {
 		return true
 	}
@@ -5,25 +5,51 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/stashapp/stash/internal/identify"
+	"github.com/stashapp/stash/pkg/gallery"
 	"github.com/stashapp/stash/pkg/job"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/scene"
 	"github.com/stashapp/stash/pkg/scraper"
 	"github.com/stashapp/stash/pkg/scraper/stashbox"
+	"github.com/stashapp/stash/pkg/sliceutil"
 	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+	"github.com/stashapp/stash/pkg/utils"
 )
 
 var ErrInput = errors.New("invalid request input")
 
+type identifyPostHookExecutor interface {
+	identify.SceneUpdatePostHookExecutor
+	identify.GalleryUpdatePostHookExecutor
+}
+
 type IdentifyJob struct {
-	postHookExecutor identify.SceneUpdatePostHookExecutor
+	postHookExecutor identifyPostHookExecutor
 	input            identify.Options
 
 	stashBoxes []*models.StashBox
 	progress   *job.Progress
+
+	reportMutex sync.Mutex
+	report      IdentifyReport
+}
+
+// maxIdentifyReportScenes is the maximum number of scene reports kept in the
+// identify job report.
+const maxIdentifyReportScenes = 1000
+
+// IdentifyReport is the report of an identify job.
+type IdentifyReport struct {
+	// number of scenes with each outcome
+	Outcomes map[identify.Outcome]int `json:"outcomes"`
+	// reports of the first maxIdentifyReportScenes scenes
+	Scenes []*identify.SceneReport `json:"scenes"`
+	// number of scene reports not included in Scenes
+	Omitted int `json:"omitted,omitempty"`
 }
 
 func CreateIdentifyJob(input identify.Options) *IdentifyJob {
@@ -48,13 +74,13 @@ func (j *IdentifyJob) Execute(ctx context.Context, progress *job.Progress) {
 		return
 	}
 
-	// if scene ids provided, use those
+	// if scene or gallery ids provided, use those
 	// otherwise, batch query for all scenes - ordering by path
 	// don't use a transaction to query scenes
 	r := instance.Repository
 	if err := r.WithDB(ctx, func(ctx context.Context) error {
-		if len(j.input.SceneIDs) == 0 {
-			return j.identifyAllScenes(ctx, sources)
+		if len(j.input.SceneIDs) == 0 && len(j.input.GalleryIDs) == 0 {
+			return j.identifyAll(ctx, sources)
 		}
 
 		sceneIDs, err := stringslice.StringSliceToIntSlice(j.input.SceneIDs)
@@ -62,7 +88,16 @@ func (j *IdentifyJob) Execute(ctx context.Context, progress *job.Progress) {
 			return fmt.Errorf("invalid scene IDs: %w", err)
 		}
 
-		progress.SetTotal(len(sceneIDs))
+		galleryIDs, err := stringslice.StringSliceToIntSlice(j.input.GalleryIDs)
+		if err != nil {
+			return fmt.Errorf("invalid gallery IDs: %w", err)
+		}
+
+		if j.skipGalleries() {
+			galleryIDs = nil
+		}
+
+		progress.SetTotal(len(sceneIDs) + len(galleryIDs))
 		for _, id := range sceneIDs {
 			if job.IsCancelled(ctx) {
 				break
@@ -82,12 +117,52 @@ func (j *IdentifyJob) Execute(ctx context.Context, progress *job.Progress) {
 			j.identifyScene(ctx, scene, sources)
 		}
 
+		for _, id := range galleryIDs {
+			if job.IsCancelled(ctx) {
+				break
+			}
+
+			gallery, err := r.Gallery.Find(ctx, id)
+			if err != nil {
+				return fmt.Errorf("finding gallery id %d: %w", id, err)
+			}
+
+			if gallery == nil {
+				return fmt.Errorf("gallery with id %d not found", id)
+			}
+
+			j.identifyGallery(ctx, gallery, sources)
+		}
+
 		return nil
 	}); err != nil {
 		logger.Errorf("Error encountered while identifying scenes: %v", err)
 	}
 }
 
+// skipGalleries returns true if galleries cannot be identified, since
+// review mode only supports scenes.
+func (j *IdentifyJob) skipGalleries() bool {
+	if utils.IsTrue(j.input.Review) {
+		logger.Warn("Galleries are not identified in review mode")
+		return true
+	}
+
+	return false
+}
+
+func (j *IdentifyJob) identifyAll(ctx context.Context, sources []identify.ScraperSource) error {
+	if err := j.identifyAllScenes(ctx, sources); err != nil {
+		return err
+	}
+
+	if !utils.IsTrue(j.input.IncludeGalleries) || j.skipGalleries() || job.IsCancelled(ctx) {
+		return nil
+	}
+
+	return j.identifyAllGalleries(ctx, sources)
+}
+
 func (j *IdentifyJob) identifyAllScenes(ctx context.Context, sources []identify.ScraperSource) error {
 	r := instance.Repository
 
@@ -135,16 +210,30 @@ func (j *IdentifyJob) identifyScene(ctx context.Context, s *models.Scene, source
 	var taskError error
 	j.progress.ExecuteTask("Identifying "+s.Path, func() {
 		r := instance.Repository
+
+		// files are required to score candidates
+		if err := s.LoadFiles(ctx, r.Scene); err != nil {
+			taskError = fmt.Errorf("loading files: %w", err)
+			return
+		}
+
 		task := identify.SceneIdentifier{
-			TxnManager:         r.TxnManager,
-			SceneReaderUpdater: r.Scene,
-			StudioReaderWriter: r.Studio,
-			PerformerCreator:   r.Performer,
-			TagFinderCreator:   r.Tag,
+			TxnManager:               r.TxnManager,
+			SceneReaderUpdater:       r.Scene,
+			StudioReaderWriter:       r.Studio,
+			PerformerCreator:         r.Performer,
+			TagFinderCreator:         r.Tag,
+			MovieCreator:             r.Movie,
+			SceneMarkerFinderCreator: r.SceneMarker,
 
 			DefaultOptions:              j.input.Options,
 			Sources:                     sources,
 			SceneUpdatePostHookExecutor: j.postHookExecutor,
+			SceneReporter:               j,
+
+			Review:                       utils.IsTrue(j.input.Review),
+			PendingSceneChangeCreator:    r.PendingSceneChange,
+			FingerprintSubmissionUpdater: r.FingerprintSubmission,
 		}
 
 		taskError = task.Identify(ctx, s)
@@ -157,6 +246,97 @@ func (j *IdentifyJob) identifyScene(ctx context.Context, s *models.Scene, source
 	j.progress.Increment()
 }
 
+func (j *IdentifyJob) identifyAllGalleries(ctx context.Context, sources []identify.ScraperSource) error {
+	r := instance.Repository
+
+	// exclude organised
+	organised := false
+	galleryFilter := gallery.FilterFromPaths(j.input.Paths)
+	galleryFilter.Organized = &organised
+
+	sort := "path"
+	findFilter := &models.FindFilterType{
+		Sort: &sort,
+	}
+
+	count, err := r.Gallery.QueryCount(ctx, galleryFilter, findFilter)
+	if err != nil {
+		return fmt.Errorf("error getting gallery count: %w", err)
+	}
+
+	j.progress.AddTotal(count)
+
+	return gallery.BatchProcess(ctx, r.Gallery, galleryFilter, findFilter, func(g *models.Gallery) error {
+		if job.IsCancelled(ctx) {
+			return nil
+		}
+
+		j.identifyGallery(ctx, g, sources)
+		return nil
+	})
+}
+
+func (j *IdentifyJob) identifyGallery(ctx context.Context, g *models.Gallery, sources []identify.ScraperSource) {
+	if job.IsCancelled(ctx) {
+		return
+	}
+
+	var taskError error
+	j.progress.ExecuteTask("Identifying "+g.DisplayName(), func() {
+		r := instance.Repository
+
+		task := identify.GalleryIdentifier{
+			TxnManager:           r.TxnManager,
+			GalleryReaderUpdater: r.Gallery,
+			StudioReaderWriter:   r.Studio,
+			PerformerCreator:     r.Performer,
+			TagFinderCreator:     r.Tag,
+
+			DefaultOptions:                j.input.Options,
+			Sources:                       sources,
+			GalleryUpdatePostHookExecutor: j.postHookExecutor,
+		}
+
+		taskError = task.Identify(ctx, g)
+	})
+
+	if taskError != nil {
+		logger.Errorf("Error encountered identifying %s: %v", g.DisplayName(), taskError)
+	}
+
+	j.progress.Increment()
+}
+
+// ReportScene adds the report of an identified scene to the job report.
+func (j *IdentifyJob) ReportScene(report *identify.SceneReport) {
+	j.reportMutex.Lock()
+	defer j.reportMutex.Unlock()
+
+	r := &j.report
+	if r.Outcomes == nil {
+		r.Outcomes = make(map[identify.Outcome]int)
+	}
+	r.Outcomes[report.Outcome]++
+
+	if len(r.Scenes) < maxIdentifyReportScenes {
+		r.Scenes = append(r.Scenes, report)
+	} else {
+		r.Omitted++
+	}
+
+	// publish a copy, since the report continues to be modified
+	published := IdentifyReport{
+		Outcomes: make(map[identify.Outcome]int, len(r.Outcomes)),
+		Scenes:   r.Scenes[:len(r.Scenes):len(r.Scenes)],
+		Omitted:  r.Omitted,
+	}
+	for k, v := range r.Outcomes {
+		published.Outcomes[k] = v
+	}
+
+	j.progress.SetReport(published)
+}
+
 func (j *IdentifyJob) getSources() ([]identify.ScraperSource, error) {
 	var ret []identify.ScraperSource
 	for _, source := range j.input.Sources {
@@ -183,12 +363,19 @@ func (j *IdentifyJob) getSources() ([]identify.ScraperSource, error) {
 			if s == nil {
 				return nil, fmt.Errorf("%w: scraper with id %q", models.ErrNotFound, scraperID)
 			}
+			ss := scraperSource{
+				cache:        instance.ScraperCache,
+				scraperID:    scraperID,
+				fingerprints: instance.ScraperCache.SupportsSceneFingerprints(scraperID),
+			}
 			src = identify.ScraperSource{
-				Name: s.Name,
-				Scraper: scraperSource{
-					cache:     instance.ScraperCache,
-					scraperID: scraperID,
-				},
+				Name:    s.Name,
+				Scraper: ss,
+			}
+
+			// galleries are scraped using their fragment
+			if s.Gallery != nil && sliceutil.Contains(s.Gallery.SupportedScrapes, scraper.ScrapeTypeFragment) {
+				src.GalleryScraper = ss
 			}
 		}
 
@@ -268,9 +455,24 @@ func (s stashboxSource) String() string {
 type scraperSource struct {
 	cache     *scraper.Cache
 	scraperID string
+	// use the fingerprint scraper if true
+	fingerprints bool
 }
 
 func (s scraperSource) ScrapeScenes(ctx context.Context, sceneID int) ([]*scraper.ScrapedScene, error) {
+	if s.fingerprints {
+		results, err := s.cache.ScrapeSceneFingerprints(ctx, s.scraperID, []int{sceneID})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(results) > 0 {
+			return results[0], nil
+		}
+
+		return nil, nil
+	}
+
 	content, err := s.cache.ScrapeID(ctx, s.scraperID, sceneID, scraper.ScrapeContentTypeScene)
 	if err != nil {
 		return nil, err
@@ -288,6 +490,122 @@ func (s scraperSource) ScrapeScenes(ctx context.Context, sceneID int) ([]*scrape
 	return nil, errors.New("could not convert content to scene")
 }
 
+func (s scraperSource) ScrapeGalleries(ctx context.Context, galleryID int) ([]*scraper.ScrapedGallery, error) {
+	content, err := s.cache.ScrapeID(ctx, s.scraperID, galleryID, scraper.ScrapeContentTypeGallery)
+	if err != nil {
+		return nil, err
+	}
+
+	// don't try to convert nil return value
+	if content == nil {
+		return nil, nil
+	}
+
+	if gallery, ok := content.(scraper.ScrapedGallery); ok {
+		return []*scraper.ScrapedGallery{&gallery}, nil
+	}
+
+	return nil, errors.New("could not convert content to gallery")
+}
+
+// supportsName returns true if the scraper can scrape the content type by name.
+func (s scraperSource) supportsName(ty scraper.ScrapeContentType) bool {
+	spec := s.cache.GetScraper(s.scraperID)
+	if spec == nil {
+		return false
+	}
+
+	var ss *scraper.ScraperSpec
+	switch ty {
+	case scraper.ScrapeContentTypePerformer:
+		ss = spec.Performer
+	case scraper.ScrapeContentTypeStudio:
+		ss = spec.Studio
+	}
+
+	return ss != nil && sliceutil.Contains(ss.SupportedScrapes, scraper.ScrapeTypeName)
+}
+
+func (s scraperSource) ScrapePerformersByName(ctx context.Context, name string) ([]*models.ScrapedPerformer, error) {
+	if !s.supportsName(scraper.ScrapeContentTypePerformer) {
+		return nil, nil
+	}
+
+	content, err := s.cache.ScrapeName(ctx, s.scraperID, name, scraper.ScrapeContentTypePerformer)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*models.ScrapedPerformer
+	for _, c := range content {
+		if p := toScrapedPerformer(c); p != nil {
+			ret = append(ret, p)
+		}
+	}
+
+	return ret, nil
+}
+
+func (s scraperSource) ScrapePerformerByURL(ctx context.Context, url string) (*models.ScrapedPerformer, error) {
+	content, err := s.cache.ScrapeURLWithScraper(ctx, s.scraperID, url, scraper.ScrapeContentTypePerformer)
+	if err != nil {
+		return nil, err
+	}
+
+	return toScrapedPerformer(content), nil
+}
+
+func toScrapedPerformer(content scraper.ScrapedContent) *models.ScrapedPerformer {
+	switch v := content.(type) {
+	case *models.ScrapedPerformer:
+		return v
+	case models.ScrapedPerformer:
+		return &v
+	}
+
+	return nil
+}
+
+func (s scraperSource) ScrapeStudiosByName(ctx context.Context, name string) ([]*models.ScrapedStudio, error) {
+	if !s.supportsName(scraper.ScrapeContentTypeStudio) {
+		return nil, nil
+	}
+
+	content, err := s.cache.ScrapeName(ctx, s.scraperID, name, scraper.ScrapeContentTypeStudio)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*models.ScrapedStudio
+	for _, c := range content {
+		if studio := toScrapedStudio(c); studio != nil {
+			ret = append(ret, studio)
+		}
+	}
+
+	return ret, nil
+}
+
+func (s scraperSource) ScrapeStudioByURL(ctx context.Context, url string) (*models.ScrapedStudio, error) {
+	content, err := s.cache.ScrapeURLWithScraper(ctx, s.scraperID, url, scraper.ScrapeContentTypeStudio)
+	if err != nil {
+		return nil, err
+	}
+
+	return toScrapedStudio(content), nil
+}
+
+func toScrapedStudio(content scraper.ScrapedContent) *models.ScrapedStudio {
+	switch v := content.(type) {
+	case *models.ScrapedStudio:
+		return v
+	case models.ScrapedStudio:
+		return &v
+	}
+
+	return nil
+}
+
 func (s scraperSource) String() string {
 	return fmt.Sprintf("scraper %s", s.scraperID)
 }
@@ -0,0 +1,124 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/internal/identify"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+// IdentifyPerformersJob identifies performers using performer scrapers.
+type IdentifyPerformersJob struct {
+	input identify.PerformerOptions
+}
+
+func CreateIdentifyPerformersJob(input identify.PerformerOptions) *IdentifyPerformersJob {
+	return &IdentifyPerformersJob{
+		input: input,
+	}
+}
+
+func (j *IdentifyPerformersJob) Execute(ctx context.Context, progress *job.Progress) {
+	sources, err := getEntitySources(j.input.Sources, scraper.ScrapeContentTypePerformer)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	// if no sources provided - just return
+	if len(sources) == 0 {
+		return
+	}
+
+	r := instance.Repository
+
+	var performers []*models.Performer
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		if len(j.input.PerformerIDs) == 0 {
+			performers, err = r.Performer.All(ctx)
+			return err
+		}
+
+		ids, err := stringslice.StringSliceToIntSlice(j.input.PerformerIDs)
+		if err != nil {
+			return fmt.Errorf("invalid performer IDs: %w", err)
+		}
+
+		performers, err = r.Performer.FindMany(ctx, ids)
+		return err
+	}); err != nil {
+		logger.Errorf("Error finding performers to identify: %v", err)
+		return
+	}
+
+	progress.SetTotal(len(performers))
+
+	task := identify.PerformerIdentifier{
+		TxnManager:            r.TxnManager,
+		PerformerReaderWriter: r.Performer,
+		TagFinderCreator:      r.Tag,
+		DefaultOptions:        j.input.Options,
+		Sources:               sources,
+	}
+
+	for _, p := range performers {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping due to user request")
+			return
+		}
+
+		progress.ExecuteTask("Identifying performer "+p.Name, func() {
+			if err := task.Identify(ctx, p); err != nil {
+				logger.Errorf("Error encountered identifying performer %s: %v", p.Name, err)
+			}
+		})
+
+		progress.Increment()
+	}
+}
+
+// getEntitySources returns the identify sources for performers or studios.
+// Only scrapers are supported as sources. Scrapers that do not support the
+// content type are skipped.
+func getEntitySources(input []*identify.Source, ty scraper.ScrapeContentType) ([]identify.ScraperSource, error) {
+	var ret []identify.ScraperSource
+	for _, source := range input {
+		if source.Source.ScraperID == nil {
+			return nil, fmt.Errorf("%w: only scraper sources are supported", ErrInput)
+		}
+
+		scraperID := *source.Source.ScraperID
+		s := instance.ScraperCache.GetScraper(scraperID)
+		if s == nil {
+			return nil, fmt.Errorf("%w: scraper with id %q", models.ErrNotFound, scraperID)
+		}
+
+		ss := scraperSource{
+			cache:     instance.ScraperCache,
+			scraperID: scraperID,
+		}
+		src := identify.ScraperSource{
+			Name:    s.Name,
+			Options: source.Options,
+		}
+
+		switch {
+		case ty == scraper.ScrapeContentTypePerformer && s.Performer != nil:
+			src.PerformerScraper = ss
+		case ty == scraper.ScrapeContentTypeStudio && s.Studio != nil:
+			src.StudioScraper = ss
+		default:
+			logger.Warnf("Scraper %s does not support %s scraping, skipping", s.Name, ty)
+			continue
+		}
+
+		ret = append(ret, src)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,82 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/internal/identify"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+// IdentifyStudiosJob identifies studios using studio scrapers.
+type IdentifyStudiosJob struct {
+	input identify.StudioOptions
+}
+
+func CreateIdentifyStudiosJob(input identify.StudioOptions) *IdentifyStudiosJob {
+	return &IdentifyStudiosJob{
+		input: input,
+	}
+}
+
+func (j *IdentifyStudiosJob) Execute(ctx context.Context, progress *job.Progress) {
+	sources, err := getEntitySources(j.input.Sources, scraper.ScrapeContentTypeStudio)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	// if no sources provided - just return
+	if len(sources) == 0 {
+		return
+	}
+
+	r := instance.Repository
+
+	var studios []*models.Studio
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		if len(j.input.StudioIDs) == 0 {
+			studios, err = r.Studio.All(ctx)
+			return err
+		}
+
+		ids, err := stringslice.StringSliceToIntSlice(j.input.StudioIDs)
+		if err != nil {
+			return fmt.Errorf("invalid studio IDs: %w", err)
+		}
+
+		studios, err = r.Studio.FindMany(ctx, ids)
+		return err
+	}); err != nil {
+		logger.Errorf("Error finding studios to identify: %v", err)
+		return
+	}
+
+	progress.SetTotal(len(studios))
+
+	task := identify.StudioIdentifier{
+		TxnManager:         r.TxnManager,
+		StudioReaderWriter: r.Studio,
+		DefaultOptions:     j.input.Options,
+		Sources:            sources,
+	}
+
+	for _, s := range studios {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping due to user request")
+			return
+		}
+
+		progress.ExecuteTask("Identifying studio "+s.Name, func() {
+			if err := task.Identify(ctx, s); err != nil {
+				logger.Errorf("Error encountered identifying studio %s: %v", s.Name, err)
+			}
+		})
+
+		progress.Increment()
+	}
+}
@@ -7,11 +7,13 @@ import (
 	"io/fs"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler/lru"
 	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/file/metadata"
 	"github.com/stashapp/stash/pkg/file/video"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/gallery"
@@ -26,13 +28,15 @@ import (
 )
 
 type scanner interface {
-	Scan(ctx context.Context, handlers []file.Handler, options file.ScanOptions, progressReporter file.ProgressReporter)
+	Scan(ctx context.Context, handlers []file.Handler, options file.ScanOptions, progressReporter file.ProgressReporter) file.ScanResult
 }
 
 type ScanJob struct {
 	scanner       scanner
 	input         ScanMetadataInput
 	subscriptions *subscriptionManager
+	// ffprobe results of the video decorator
+	probes *video.ProbeCache
 }
 
 func (j *ScanJob) Execute(ctx context.Context, progress *job.Progress) {
@@ -63,16 +67,26 @@ func (j *ScanJob) Execute(ctx context.Context, progress *job.Progress) {
 		minModTime = *j.input.Filter.MinModTime
 	}
 
-	j.scanner.Scan(ctx, getScanHandlers(j.input, taskQueue, progress), file.ScanOptions{
+	var changedFilters []file.Filter
+	if input.ScanReadFileMetadata {
+		// rescan files whose sidecar files have changed
+		changedFilters = append(changedFilters, &sidecarChangedFilter{fs: mgr.FS})
+	}
+
+	result := j.scanner.Scan(ctx, getScanHandlers(ctx, j.input, j.probes, taskQueue, progress), file.ScanOptions{
 		Paths:                  paths,
 		ScanFilters:            []file.PathFilter{newScanFilter(c, repo, minModTime)},
 		ZipFileExtensions:      c.GetGalleryExtensions(),
 		ParallelTasks:          c.GetParallelTasksWithAutoDetection(),
 		HandlerRequiredFilters: []file.Filter{newHandlerRequiredFilter(c, repo)},
+		ChangedFilters:         changedFilters,
+		FullRescan:             input.FullRescan,
 	}, progress)
 
 	taskQueue.Close()
 
+	progress.SetReport(result)
+
 	if job.IsCancelled(ctx) {
 		logger.Info("Stopping due to user request")
 		return
@@ -124,6 +138,9 @@ type handlerRequiredFilter struct {
 	FolderCache *lru.LRU
 
 	videoFileNamingAlgorithm models.HashAlgorithm
+
+	stashPaths                 config.StashConfigs
+	createGalleriesFromFolders bool
 }
 
 func newHandlerRequiredFilter(c *config.Config, repo models.Repository) *handlerRequiredFilter {
@@ -138,6 +155,9 @@ func newHandlerRequiredFilter(c *config.Config, repo models.Repository) *handler
 		CaptionUpdater:           repo.File,
 		FolderCache:              lru.New(processes * 2),
 		videoFileNamingAlgorithm: c.GetVideoFileNamingAlgorithm(),
+
+		stashPaths:                 c.GetStashPaths(),
+		createGalleriesFromFolders: c.GetCreateGalleriesFromFolders(),
 	}
 }
 
@@ -177,7 +197,7 @@ func (f *handlerRequiredFilter) Accept(ctx context.Context, ff models.File) bool
 	// if create galleries from folder is enabled and the file is not in a zip
 	// file, then check if there is a folder-based gallery for the file's
 	// directory
-	if isImageFile && instance.Config.GetCreateGalleriesFromFolders() && ff.Base().ZipFileID == nil {
+	if isImageFile && ff.Base().ZipFileID == nil && isCreateGalleriesFromFolders(f.stashPaths, filepath.Dir(path), f.createGalleriesFromFolders) {
 		// only do this for the first time it encounters the folder
 		// the first instance should create the gallery
 		_, found := f.FolderCache.Get(ctx, ff.Base().ParentFolderID.String())
@@ -236,19 +256,22 @@ type scanFilter struct {
 	generatedPath     string
 	videoExcludeRegex []*regexp.Regexp
 	imageExcludeRegex []*regexp.Regexp
+	stashExcludes     stashExcludes
 	minModTime        time.Time
 }
 
 func newScanFilter(c *config.Config, repo models.Repository, minModTime time.Time) *scanFilter {
+	stashPaths := c.GetStashPaths()
 	return &scanFilter{
 		extensionConfig:   newExtensionConfig(c),
 		txnManager:        repo.TxnManager,
 		FileFinder:        repo.File,
 		CaptionUpdater:    repo.File,
-		stashPaths:        c.GetStashPaths(),
+		stashPaths:        stashPaths,
 		generatedPath:     c.GetGeneratedPath(),
 		videoExcludeRegex: generateRegexps(c.GetExcludes()),
 		imageExcludeRegex: generateRegexps(c.GetImageExcludes()),
+		stashExcludes:     newStashExcludes(stashPaths),
 		minModTime:        minModTime,
 	}
 }
@@ -298,15 +321,15 @@ func (f *scanFilter) Accept(ctx context.Context, path string, info fs.FileInfo)
 	// shortcut: skip the directory entirely if it matches both exclusion patterns
 	// add a trailing separator so that it correctly matches against patterns like path/.*
 	pathExcludeTest := path + string(filepath.Separator)
-	if (matchFileRegex(pathExcludeTest, f.videoExcludeRegex)) && (s.ExcludeImage || matchFileRegex(pathExcludeTest, f.imageExcludeRegex)) {
+	if f.isVideoExcluded(s, pathExcludeTest) && (s.ExcludeImage || f.isImageExcluded(s, pathExcludeTest)) {
 		logger.Debugf("Skipping directory %s as it matches video and image exclusion patterns", path)
 		return false
 	}
 
-	if isVideoFile && (s.ExcludeVideo || matchFileRegex(path, f.videoExcludeRegex)) {
+	if isVideoFile && (s.ExcludeVideo || f.isVideoExcluded(s, path)) {
 		logger.Debugf("Skipping %s as it matches video exclusion patterns", path)
 		return false
-	} else if (isImageFile || isZipFile) && (s.ExcludeImage || matchFileRegex(path, f.imageExcludeRegex)) {
+	} else if (isImageFile || isZipFile) && (s.ExcludeImage || f.isImageExcluded(s, path)) {
 		logger.Debugf("Skipping %s as it matches image exclusion patterns", path)
 		return false
 	}
@@ -314,15 +337,115 @@ func (f *scanFilter) Accept(ctx context.Context, path string, info fs.FileInfo)
 	return true
 }
 
+func (f *scanFilter) isVideoExcluded(s *config.StashConfig, path string) bool {
+	return matchFileRegex(path, f.videoExcludeRegex) || f.stashExcludes.matchVideo(s, path)
+}
+
+func (f *scanFilter) isImageExcluded(s *config.StashConfig, path string) bool {
+	return matchFileRegex(path, f.imageExcludeRegex) || f.stashExcludes.matchImage(s, path)
+}
+
 type scanConfig struct {
-	isGenerateThumbnails   bool
-	isGenerateClipPreviews bool
+	stashPaths config.StashConfigs
 
 	createGalleriesFromFolders bool
 }
 
-func (c *scanConfig) GetCreateGalleriesFromFolders() bool {
-	return c.createGalleriesFromFolders
+func (c *scanConfig) GetCreateGalleriesFromFolders(path string) bool {
+	return isCreateGalleriesFromFolders(c.stashPaths, path, c.createGalleriesFromFolders)
+}
+
+// isCreateGalleriesFromFolders returns true if galleries should be created
+// from the folder at path, using the setting of its library path if set.
+func isCreateGalleriesFromFolders(stashPaths config.StashConfigs, path string, def bool) bool {
+	s := stashPaths.GetStashFromDirPath(path)
+	if s == nil {
+		return def
+	}
+
+	return s.IsCreateGalleriesFromFolders(def)
+}
+
+// getStashScanOptions returns the scan options of the file at path, using
+// the generators of its library path if set.
+func getStashScanOptions(stashPaths config.StashConfigs, options config.ScanMetadataOptions, path string) config.ScanMetadataOptions {
+	s := stashPaths.GetStashFromPath(path)
+	if s == nil || s.ScanGenerate == nil {
+		return options
+	}
+
+	return options.WithGenerate(*s.ScanGenerate)
+}
+
+// scanDefaults provides the default tags and studio of the library paths.
+type scanDefaults struct {
+	stashPaths config.StashConfigs
+	defaults   map[string]models.ScanDefaults
+}
+
+// newScanDefaults returns the defaults of the library paths. Tags and
+// studios that do not exist are ignored.
+func newScanDefaults(ctx context.Context, repo models.Repository, stashPaths config.StashConfigs) *scanDefaults {
+	ret := &scanDefaults{
+		stashPaths: stashPaths,
+		defaults:   make(map[string]models.ScanDefaults),
+	}
+
+	if err := txn.WithReadTxn(ctx, repo.TxnManager, func(ctx context.Context) error {
+		for _, s := range stashPaths {
+			ret.defaults[s.Path] = getStashScanDefaults(ctx, repo, s)
+		}
+		return nil
+	}); err != nil {
+		logger.Errorf("Error getting scan defaults: %v", err)
+	}
+
+	return ret
+}
+
+func getStashScanDefaults(ctx context.Context, repo models.Repository, s *config.StashConfig) models.ScanDefaults {
+	var ret models.ScanDefaults
+
+	for _, idStr := range s.DefaultTagIDs {
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			logger.Warnf("Ignoring invalid default tag id %q of %s", idStr, s.Path)
+			continue
+		}
+
+		if t, err := repo.Tag.Find(ctx, id); err != nil || t == nil {
+			logger.Warnf("Ignoring default tag %d of %s: tag not found", id, s.Path)
+			continue
+		}
+
+		ret.TagIDs = append(ret.TagIDs, id)
+	}
+
+	if s.DefaultStudioID != nil {
+		id, err := strconv.Atoi(*s.DefaultStudioID)
+		if err != nil {
+			logger.Warnf("Ignoring invalid default studio id %q of %s", *s.DefaultStudioID, s.Path)
+			return ret
+		}
+
+		if st, err := repo.Studio.Find(ctx, id); err != nil || st == nil {
+			logger.Warnf("Ignoring default studio %d of %s: studio not found", id, s.Path)
+			return ret
+		}
+
+		ret.StudioID = &id
+	}
+
+	return ret
+}
+
+func (d *scanDefaults) GetScanDefaults(path string) models.ScanDefaults {
+	s := d.stashPaths.GetStashFromPath(path)
+	if s == nil {
+		return models.ScanDefaults{}
+	}
+
+	return d.defaults[s.Path]
 }
 
 func videoFileFilter(ctx context.Context, f models.File) bool {
@@ -337,11 +460,61 @@ func galleryFileFilter(ctx context.Context, f models.File) bool {
 	return isZip(f.Base().Basename)
 }
 
-func getScanHandlers(options ScanMetadataInput, taskQueue *job.TaskQueue, progress *job.Progress) []file.Handler {
+// sidecarChangedFilter accepts video and zip files whose json or nfo
+// sidecar files were modified after the file was last updated.
+type sidecarChangedFilter struct {
+	fs models.FS
+}
+
+func (f *sidecarChangedFilter) Accept(ctx context.Context, ff models.File) bool {
+	base := ff.Base()
+
+	// changed sidecar files in zip files change the zip file, which is
+	// rescanned with its contents
+	if base.ZipFileID != nil || !(useAsVideo(base.Path) || isZip(base.Basename)) {
+		return false
+	}
+
+	return metadata.SidecarsModifiedAfter(f.fs, base.Path, base.UpdatedAt)
+}
+
+func getScanHandlers(ctx context.Context, options ScanMetadataInput, probes *video.ProbeCache, taskQueue *job.TaskQueue, progress *job.Progress) []file.Handler {
 	mgr := GetInstance()
 	c := mgr.Config
 	r := mgr.Repository
 	pluginCache := mgr.PluginCache
+	stashPaths := c.GetStashPaths()
+	defaults := newScanDefaults(ctx, r, stashPaths)
+
+	var sceneMetadataApplier scene.ScanMetadataApplier
+	var galleryMetadataApplier gallery.ScanMetadataApplier
+	if options.ScanReadFileMetadata {
+		precedence := models.FileMetadataPrecedenceExisting
+		if options.ScanFileMetadataPrecedence != nil {
+			precedence = *options.ScanFileMetadataPrecedence
+		}
+
+		resolver := &metadata.Resolver{
+			PerformerFinderCreator: r.Performer,
+			StudioFinderCreator:    r.Studio,
+			TagFinderCreator:       r.Tag,
+			CreateMissing:          options.ScanFileMetadataCreateMissing,
+		}
+
+		sceneMetadataApplier = &scene.FileMetadataApplier{
+			Updater:             r.Scene,
+			MarkerFinderCreator: r.SceneMarker,
+			Resolver:            resolver,
+			Precedence:          precedence,
+			FS:                  mgr.FS,
+		}
+		galleryMetadataApplier = &gallery.FileMetadataApplier{
+			Updater:    r.Gallery,
+			Resolver:   resolver,
+			Precedence: precedence,
+			FS:         mgr.FS,
+		}
+	}
 
 	return []file.Handler{
 		&file.FilteredHandler{
@@ -351,18 +524,19 @@ func getScanHandlers(options ScanMetadataInput, taskQueue *job.TaskQueue, progre
 				GalleryFinder:  r.Gallery,
 				ScanGenerator: &imageGenerators{
 					input:              options,
+					stashPaths:         stashPaths,
 					taskQueue:          taskQueue,
 					progress:           progress,
 					paths:              mgr.Paths,
 					sequentialScanning: c.GetSequentialScanning(),
 				},
 				ScanConfig: &scanConfig{
-					isGenerateThumbnails:       options.ScanGenerateThumbnails,
-					isGenerateClipPreviews:     options.ScanGenerateClipPreviews,
+					stashPaths:                 stashPaths,
 					createGalleriesFromFolders: c.GetCreateGalleriesFromFolders(),
 				},
-				PluginCache: pluginCache,
-				Paths:       instance.Paths,
+				ScanDefaults: defaults,
+				PluginCache:  pluginCache,
+				Paths:        instance.Paths,
 			},
 		},
 		&file.FilteredHandler{
@@ -372,6 +546,8 @@ func getScanHandlers(options ScanMetadataInput, taskQueue *job.TaskQueue, progre
 				SceneFinderUpdater: r.Scene,
 				ImageFinderUpdater: r.Image,
 				PluginCache:        pluginCache,
+				MetadataApplier:    galleryMetadataApplier,
+				ScanDefaults:       defaults,
 			},
 		},
 		&file.FilteredHandler{
@@ -382,6 +558,7 @@ func getScanHandlers(options ScanMetadataInput, taskQueue *job.TaskQueue, progre
 				PluginCache:    pluginCache,
 				ScanGenerator: &sceneGenerators{
 					input:               options,
+					stashPaths:          stashPaths,
 					taskQueue:           taskQueue,
 					progress:            progress,
 					paths:               mgr.Paths,
@@ -390,15 +567,19 @@ func getScanHandlers(options ScanMetadataInput, taskQueue *job.TaskQueue, progre
 				},
 				FileNamingAlgorithm: c.GetVideoFileNamingAlgorithm(),
 				Paths:               mgr.Paths,
+				MetadataApplier:     sceneMetadataApplier,
+				Probes:              probes,
+				ScanDefaults:        defaults,
 			},
 		},
 	}
 }
 
 type imageGenerators struct {
-	input     ScanMetadataInput
-	taskQueue *job.TaskQueue
-	progress  *job.Progress
+	input      ScanMetadataInput
+	stashPaths config.StashConfigs
+	taskQueue  *job.TaskQueue
+	progress   *job.Progress
 
 	paths              *paths.Paths
 	sequentialScanning bool
@@ -408,8 +589,8 @@ func (g *imageGenerators) Generate(ctx context.Context, i *models.Image, f model
 	const overwrite = false
 
 	progress := g.progress
-	t := g.input
 	path := f.Base().Path
+	t := getStashScanOptions(g.stashPaths, g.input.ScanMetadataOptions, path)
 
 	if t.ScanGenerateThumbnails {
 		// this should be quick, so always generate sequentially
@@ -418,6 +599,26 @@ func (g *imageGenerators) Generate(ctx context.Context, i *models.Image, f model
 		}
 	}
 
+	imageFile, isImage := f.(*models.ImageFile)
+	if isImage && t.ScanGeneratePhashes {
+		progress.AddTotal(1)
+		phashFn := func(ctx context.Context) {
+			taskPhash := GenerateImagePhashTask{
+				repository: GetInstance().Repository,
+				File:       imageFile,
+				Overwrite:  overwrite,
+			}
+			taskPhash.Start(ctx)
+			progress.Increment()
+		}
+
+		if g.sequentialScanning {
+			phashFn(ctx)
+		} else {
+			g.taskQueue.Add(fmt.Sprintf("Generating phash for %s", path), phashFn)
+		}
+	}
+
 	// avoid adding a task if the file isn't a video file
 	_, isVideo := f.(*models.VideoFile)
 	if isVideo && t.ScanGenerateClipPreviews {
@@ -477,6 +678,7 @@ func (g *imageGenerators) generateThumbnail(ctx context.Context, i *models.Image
 	}
 
 	encoder := image.NewThumbnailEncoder(mgr.FFMpeg, mgr.FFProbe, clipPreviewOptions)
+	encoder.FS = mgr.FS
 	data, err := encoder.GetThumbnail(f, models.DefaultGthumbWidth)
 
 	if err != nil {
@@ -496,9 +698,10 @@ func (g *imageGenerators) generateThumbnail(ctx context.Context, i *models.Image
 }
 
 type sceneGenerators struct {
-	input     ScanMetadataInput
-	taskQueue *job.TaskQueue
-	progress  *job.Progress
+	input      ScanMetadataInput
+	stashPaths config.StashConfigs
+	taskQueue  *job.TaskQueue
+	progress   *job.Progress
 
 	paths               *paths.Paths
 	fileNamingAlgorithm models.HashAlgorithm
@@ -509,8 +712,8 @@ func (g *sceneGenerators) Generate(ctx context.Context, s *models.Scene, f *mode
 	const overwrite = false
 
 	progress := g.progress
-	t := g.input
 	path := f.Path
+	t := getStashScanOptions(g.stashPaths, g.input.ScanMetadataOptions, path)
 
 	mgr := GetInstance()
 
@@ -541,6 +744,7 @@ func (g *sceneGenerators) Generate(ctx context.Context, s *models.Scene, f *mode
 				File:                f,
 				Overwrite:           overwrite,
 				fileNamingAlgorithm: g.fileNamingAlgorithm,
+				SegmentInterval:     mgr.Config.GetPhashSegmentInterval(),
 			}
 			taskPhash.Start(ctx)
 			progress.Increment()
@@ -0,0 +1,69 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper/stashbox"
+)
+
+type StashBoxFingerprintQueueInput struct {
+	// Index of the stash-box endpoint to submit fingerprints to
+	Endpoint int `json:"endpoint"`
+	// Queue the linked scenes whose fingerprints were never queued before
+	// submitting
+	QueueUnsubmitted *bool `json:"queue_unsubmitted"`
+}
+
+// StashBoxFingerprintQueueJob submits the queued fingerprint submissions of
+// a stash-box endpoint. The number of scenes in each state is added to the
+// job report.
+type StashBoxFingerprintQueueJob struct {
+	box              *models.StashBox
+	queueUnsubmitted bool
+}
+
+func CreateStashBoxFingerprintQueueJob(input StashBoxFingerprintQueueInput) (*StashBoxFingerprintQueueJob, error) {
+	boxes := config.GetInstance().GetStashBoxes()
+	if input.Endpoint < 0 || input.Endpoint >= len(boxes) {
+		return nil, fmt.Errorf("%w: invalid stash_box_index %d", ErrInput, input.Endpoint)
+	}
+
+	return &StashBoxFingerprintQueueJob{
+		box:              boxes[input.Endpoint],
+		queueUnsubmitted: input.QueueUnsubmitted != nil && *input.QueueUnsubmitted,
+	}, nil
+}
+
+func (j *StashBoxFingerprintQueueJob) Execute(ctx context.Context, progress *job.Progress) {
+	r := instance.Repository
+	queue := stashbox.FingerprintQueue{
+		Client:           stashbox.NewClient(*j.box, stashbox.NewRepository(r)),
+		Repository:       stashbox.NewFingerprintQueueRepository(r),
+		QueueUnsubmitted: j.queueUnsubmitted,
+		RetryDelay:       stashbox.DefaultFingerprintRetryDelay,
+	}
+
+	logger.Infof("Submitting queued fingerprints to stash-box %s", j.box.Endpoint)
+
+	result, err := queue.Submit(ctx, progress)
+	if result != nil {
+		progress.SetReport(result)
+	}
+
+	if err != nil {
+		logger.Errorf("Error submitting queued fingerprints: %v", err)
+		return
+	}
+
+	if job.IsCancelled(ctx) {
+		logger.Info("Stopping due to user request")
+		return
+	}
+
+	logger.Infof("Finished submitting queued fingerprints: %d submitted, %d to retry, %d failed", result.Submitted, result.Pending, result.Failed)
+}
@@ -0,0 +1,135 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper/stashbox"
+)
+
+type StashBoxSyncInput struct {
+	// Index of the stash-box endpoint to sync with
+	Endpoint int `json:"endpoint"`
+	// Entity types to sync. All types are synced if empty
+	Types []stashbox.SyncType `json:"types"`
+	// Strategies of individual fields. Fields without options are overwritten
+	FieldOptions []*stashbox.SyncFieldOptions `json:"field_options"`
+}
+
+// StashBoxSyncJob refreshes the entities linked to a stash-box endpoint with
+// the current upstream data. The changes made to each entity are added to
+// the job report.
+type StashBoxSyncJob struct {
+	box     *models.StashBox
+	options stashbox.SyncOptions
+
+	progress *job.Progress
+
+	reportMutex sync.Mutex
+	report      StashBoxSyncReport
+}
+
+// maxStashBoxSyncReportChanges is the maximum number of entity reports kept
+// in the stash-box sync job report.
+const maxStashBoxSyncReportChanges = 1000
+
+// StashBoxSyncReport is the report of a stash-box sync job.
+type StashBoxSyncReport struct {
+	// number of entities with each status
+	Statuses map[stashbox.SyncStatus]int `json:"statuses"`
+	// reports of the first maxStashBoxSyncReportChanges entities that were
+	// not unchanged
+	Changes []*stashbox.SyncReport `json:"changes"`
+	// number of entity reports not included in Changes
+	Omitted int `json:"omitted,omitempty"`
+}
+
+func CreateStashBoxSyncJob(input StashBoxSyncInput) (*StashBoxSyncJob, error) {
+	boxes := config.GetInstance().GetStashBoxes()
+	if input.Endpoint < 0 || input.Endpoint >= len(boxes) {
+		return nil, fmt.Errorf("%w: invalid stash_box_index %d", ErrInput, input.Endpoint)
+	}
+
+	return &StashBoxSyncJob{
+		box: boxes[input.Endpoint],
+		options: stashbox.SyncOptions{
+			Types:        input.Types,
+			FieldOptions: input.FieldOptions,
+		},
+	}, nil
+}
+
+func (j *StashBoxSyncJob) Execute(ctx context.Context, progress *job.Progress) {
+	j.progress = progress
+
+	r := instance.Repository
+	syncer := stashbox.Syncer{
+		Client:     stashbox.NewClient(*j.box, stashbox.NewRepository(r)),
+		Repository: stashbox.NewSyncRepository(r),
+		Options:    j.options,
+		Reporter:   j,
+	}
+
+	logger.Infof("Syncing with stash-box %s", j.box.Endpoint)
+
+	if err := syncer.Sync(ctx, progress); err != nil {
+		logger.Errorf("Error syncing with stash-box: %v", err)
+		return
+	}
+
+	if job.IsCancelled(ctx) {
+		logger.Info("Stopping due to user request")
+		return
+	}
+
+	j.reportMutex.Lock()
+	defer j.reportMutex.Unlock()
+
+	total := 0
+	for _, n := range j.report.Statuses {
+		total += n
+	}
+
+	logger.Infof("Finished syncing with stash-box: %d of %d entities updated", j.report.Statuses[stashbox.SyncStatusUpdated], total)
+}
+
+// ReportSync adds the report of a synced entity to the job report.
+func (j *StashBoxSyncJob) ReportSync(report *stashbox.SyncReport) {
+	if report.Status == stashbox.SyncStatusUpdated {
+		logger.Infof("Updated %s %s from stash-box: %v", report.Type, report.Name, report.ChangedFields)
+	}
+
+	j.reportMutex.Lock()
+	defer j.reportMutex.Unlock()
+
+	r := &j.report
+	if r.Statuses == nil {
+		r.Statuses = make(map[stashbox.SyncStatus]int)
+	}
+	r.Statuses[report.Status]++
+
+	if report.Status != stashbox.SyncStatusUnchanged {
+		if len(r.Changes) < maxStashBoxSyncReportChanges {
+			r.Changes = append(r.Changes, report)
+		} else {
+			r.Omitted++
+		}
+	}
+
+	// publish a copy, since the report continues to be modified
+	published := StashBoxSyncReport{
+		Statuses: make(map[stashbox.SyncStatus]int, len(r.Statuses)),
+		Changes:  r.Changes[:len(r.Changes):len(r.Changes)],
+		Omitted:  r.Omitted,
+	}
+	for k, v := range r.Statuses {
+		published.Statuses[k] = v
+	}
+
+	j.progress.SetReport(published)
+}
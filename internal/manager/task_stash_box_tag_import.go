@@ -0,0 +1,90 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper/stashbox"
+)
+
+type StashBoxTagImportInput struct {
+	// Index of the stash-box endpoint to import tags from
+	Endpoint int `json:"endpoint"`
+	// Add the stash-box category of each tag as a parent tag. Defaults to true
+	MapCategories *bool `json:"map_categories"`
+}
+
+// StashBoxTagImportJob imports the tags of a stash-box endpoint into the
+// local tags. The changes made to each tag are added to the job report.
+type StashBoxTagImportJob struct {
+	box           *models.StashBox
+	mapCategories bool
+
+	progress *job.Progress
+	reports  []*stashbox.TagImportReport
+}
+
+func CreateStashBoxTagImportJob(input StashBoxTagImportInput) (*StashBoxTagImportJob, error) {
+	boxes := config.GetInstance().GetStashBoxes()
+	if input.Endpoint < 0 || input.Endpoint >= len(boxes) {
+		return nil, fmt.Errorf("%w: invalid stash_box_index %d", ErrInput, input.Endpoint)
+	}
+
+	mapCategories := true
+	if input.MapCategories != nil {
+		mapCategories = *input.MapCategories
+	}
+
+	return &StashBoxTagImportJob{
+		box:           boxes[input.Endpoint],
+		mapCategories: mapCategories,
+	}, nil
+}
+
+func (j *StashBoxTagImportJob) Execute(ctx context.Context, progress *job.Progress) {
+	j.progress = progress
+
+	r := instance.Repository
+	importer := stashbox.TagImporter{
+		Client:        stashbox.NewClient(*j.box, stashbox.NewRepository(r)),
+		TxnManager:    r.TxnManager,
+		Tag:           r.Tag,
+		MapCategories: j.mapCategories,
+		Reporter:      j,
+	}
+
+	logger.Infof("Importing tags from stash-box %s", j.box.Endpoint)
+
+	if err := importer.Import(ctx, progress); err != nil {
+		logger.Errorf("Error importing tags from stash-box: %v", err)
+		return
+	}
+
+	if job.IsCancelled(ctx) {
+		logger.Info("Stopping due to user request")
+		return
+	}
+
+	created := 0
+	linked := 0
+	for _, report := range j.reports {
+		switch report.Status {
+		case stashbox.TagImportStatusCreated:
+			created++
+		case stashbox.TagImportStatusLinked:
+			linked++
+		}
+	}
+
+	logger.Infof("Finished importing %d tags from stash-box: %d created, %d linked", len(j.reports), created, linked)
+}
+
+// ReportTagImport adds the report of an imported tag to the job report.
+func (j *StashBoxTagImportJob) ReportTagImport(report *stashbox.TagImportReport) {
+	j.reports = append(j.reports, report)
+	j.progress.SetReport(j.reports)
+}
@@ -47,10 +47,11 @@ var ffprobeToContainer = map[string]Container{
 	MatroskaFfmpeg: Matroska,
 }
 
-func MatchContainer(format string, filePath string) (Container, error) { // match ffprobe string to our Container
+// MatchContainer matches the ffprobe format of the file at filePath to a Container.
+func (f *FFProbe) MatchContainer(format string, filePath string) (Container, error) {
 	container := ffprobeToContainer[format]
 	if container == Matroska {
-		return magicContainer(filePath) // use magic number instead of ffprobe for matroska,webm
+		return magicContainer(f.remote, filePath) // use magic number instead of ffprobe for matroska,webm
 	}
 	if container == "" { // if format is not in our Container list leave it as ffprobes reported format_name
 		container = Container(format)
@@ -12,18 +12,23 @@ import (
 type FFMpeg struct {
 	ffmpeg         string
 	hwCodecSupport []VideoCodec
+	remote         RemoteInput
 }
 
-// Creates a new FFMpeg encoder
-func NewEncoder(ffmpegPath string) *FFMpeg {
+// Creates a new FFMpeg encoder. remote provides the input files that are not
+// on the local filesystem, and may be nil.
+func NewEncoder(ffmpegPath string, remote RemoteInput) *FFMpeg {
 	ret := &FFMpeg{
 		ffmpeg: ffmpegPath,
+		remote: remote,
 	}
 
 	return ret
 }
 
 // Returns an exec.Cmd that can be used to run ffmpeg using args.
+// Input files that are not on the local filesystem are replaced with the
+// URLs of the remote input.
 func (f *FFMpeg) Command(ctx context.Context, args []string) *exec.Cmd {
-	return stashExec.CommandContext(ctx, string(f.ffmpeg), args...)
+	return stashExec.CommandContext(ctx, string(f.ffmpeg), resolveInputs(f.remote, args)...)
 }
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
-	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -73,12 +72,31 @@ func (v *VideoFile) TranscodeScale(maxSize int) (int, int) {
 }
 
 // FFProbe provides an interface to the ffprobe executable.
-type FFProbe string
+type FFProbe struct {
+	path   string
+	remote RemoteInput
+}
+
+// NewFFProbe returns an FFProbe running the executable at ffprobePath.
+// remote provides the input files that are not on the local filesystem, and
+// may be nil.
+func NewFFProbe(ffprobePath string, remote RemoteInput) FFProbe {
+	return FFProbe{
+		path:   ffprobePath,
+		remote: remote,
+	}
+}
+
+// Path returns the path of the ffprobe executable. Returns an empty string
+// if ffprobe is not configured.
+func (f FFProbe) Path() string {
+	return f.path
+}
 
 // NewVideoFile runs ffprobe on the given path and returns a VideoFile.
 func (f *FFProbe) NewVideoFile(videoPath string) (*VideoFile, error) {
-	args := []string{"-v", "quiet", "-print_format", "json", "-show_format", "-show_streams", "-show_error", videoPath}
-	cmd := exec.Command(string(*f), args...)
+	args := []string{"-v", "quiet", "-print_format", "json", "-show_format", "-show_streams", "-show_chapters", "-show_error", inputPath(f.remote, videoPath)}
+	cmd := exec.Command(f.path, args...)
 	out, err := cmd.Output()
 
 	if err != nil {
@@ -90,14 +108,14 @@ func (f *FFProbe) NewVideoFile(videoPath string) (*VideoFile, error) {
 		return nil, fmt.Errorf("error unmarshalling video data for <%s>: %s", videoPath, err.Error())
 	}
 
-	return parse(videoPath, probeJSON)
+	return f.parse(videoPath, probeJSON)
 }
 
 // GetReadFrameCount counts the actual frames of the video file.
 // Used when the frame count is missing or incorrect.
 func (f *FFProbe) GetReadFrameCount(path string) (int64, error) {
-	args := []string{"-v", "quiet", "-print_format", "json", "-count_frames", "-show_format", "-show_streams", "-show_error", path}
-	out, err := exec.Command(string(*f), args...).Output()
+	args := []string{"-v", "quiet", "-print_format", "json", "-count_frames", "-show_format", "-show_streams", "-show_error", inputPath(f.remote, path)}
+	out, err := exec.Command(f.path, args...).Output()
 
 	if err != nil {
 		return 0, fmt.Errorf("FFProbe encountered an error with <%s>.\nError JSON:\n%s\nError: %s", path, string(out), err.Error())
@@ -108,11 +126,11 @@ func (f *FFProbe) GetReadFrameCount(path string) (int64, error) {
 		return 0, fmt.Errorf("error unmarshalling video data for <%s>: %s", path, err.Error())
 	}
 
-	fc, err := parse(path, probeJSON)
+	fc, err := f.parse(path, probeJSON)
 	return fc.FrameCount, err
 }
 
-func parse(filePath string, probeJSON *FFProbeJSON) (*VideoFile, error) {
+func (f *FFProbe) parse(filePath string, probeJSON *FFProbeJSON) (*VideoFile, error) {
 	if probeJSON == nil {
 		return nil, fmt.Errorf("failed to get ffprobe json for <%s>", filePath)
 	}
@@ -133,7 +151,7 @@ func parse(filePath string, probeJSON *FFProbeJSON) (*VideoFile, error) {
 	result.Container = probeJSON.Format.FormatName
 	duration, _ := strconv.ParseFloat(probeJSON.Format.Duration, 64)
 	result.FileDuration = math.Round(duration*100) / 100
-	fileStat, err := os.Stat(filePath)
+	fileStat, err := statInput(f.remote, filePath)
 	if err != nil {
 		statErr := fmt.Errorf("error statting file <%s>: %w", filePath, err)
 		logger.Errorf("%v", statErr)
@@ -93,6 +93,11 @@ func (f VideoFilter) Fps(fps int) VideoFilter {
 	return f.Append(fmt.Sprintf("fps=%v", fps))
 }
 
+// FrameInterval returns a VideoFilter outputting one frame every given number of seconds.
+func (f VideoFilter) FrameInterval(seconds float64) VideoFilter {
+	return f.Append(fmt.Sprintf("fps=1/%v", seconds))
+}
+
 // Select returns a VideoFilter to select the given frame.
 func (f VideoFilter) Select(frame int) VideoFilter {
 	return f.Append(fmt.Sprintf("select=eq(n\\,%d)", frame))
@@ -2,7 +2,6 @@ package ffmpeg
 
 import (
 	"bytes"
-	"os"
 )
 
 // detect file format from magic file number
@@ -40,8 +39,8 @@ func containsMatroskaSignature(buf, subType []byte) bool {
 // Returns the zero-value on errors or no-match. Implements mkv or
 // webm only, as ffprobe can't distinguish between them and not all
 // browsers support mkv
-func magicContainer(filePath string) (Container, error) {
-	file, err := os.Open(filePath)
+func magicContainer(r RemoteInput, filePath string) (Container, error) {
+	file, err := openInput(r, filePath)
 	if err != nil {
 		return "", err
 	}
@@ -42,7 +42,8 @@ func (a Args) Duration(seconds float64) Args {
 	return append(a, "-t", fmt.Sprint(seconds))
 }
 
-// Input adds the input (-i) and returns the result.
+// Input adds the input (-i) and returns the result. Paths of remote files are
+// replaced with the URL to read them from.
 func (a Args) Input(i string) Args {
 	return append(a, "-i", i)
 }
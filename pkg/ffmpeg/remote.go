@@ -0,0 +1,72 @@
+package ffmpeg
+
+import (
+	"io"
+	"io/fs"
+	"os"
+)
+
+// RemoteInput provides access to files that are not on the local filesystem,
+// such as files in remote library paths or within archives.
+type RemoteInput interface {
+	// IsRemote returns true if the file at path is not on the local filesystem.
+	IsRemote(path string) bool
+	// URL returns the URL that ffmpeg and ffprobe read the file from.
+	URL(path string) string
+	Stat(path string) (fs.FileInfo, error)
+	Open(path string) (io.ReadCloser, error)
+}
+
+// remoteInputFor returns r if it provides the file at path, or nil if the
+// file is read from the local filesystem.
+func remoteInputFor(r RemoteInput, path string) RemoteInput {
+	if r != nil && r.IsRemote(path) {
+		return r
+	}
+
+	return nil
+}
+
+// inputPath returns the input to pass to ffmpeg or ffprobe for path.
+func inputPath(r RemoteInput, path string) string {
+	if r := remoteInputFor(r, path); r != nil {
+		return r.URL(path)
+	}
+
+	return path
+}
+
+func statInput(r RemoteInput, path string) (fs.FileInfo, error) {
+	if r := remoteInputFor(r, path); r != nil {
+		return r.Stat(path)
+	}
+
+	return os.Stat(path)
+}
+
+func openInput(r RemoteInput, path string) (io.ReadCloser, error) {
+	if r := remoteInputFor(r, path); r != nil {
+		return r.Open(path)
+	}
+
+	return os.Open(path)
+}
+
+// resolveInputs returns args with the input paths replaced with the inputs
+// to pass to ffmpeg.
+func resolveInputs(r RemoteInput, args []string) []string {
+	if r == nil {
+		return args
+	}
+
+	ret := make([]string, len(args))
+	copy(ret, args)
+	for i := 0; i < len(ret)-1; i++ {
+		if ret[i] == "-i" {
+			ret[i+1] = inputPath(r, ret[i+1])
+			i++
+		}
+	}
+
+	return ret
+}
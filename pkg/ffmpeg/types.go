@@ -25,10 +25,18 @@ type FFProbeJSON struct {
 			MinorVersion     string        `json:"minor_version"`
 			Title            string        `json:"title"`
 			Comment          string        `json:"comment"`
+			Description      string        `json:"description"`
+			Synopsis         string        `json:"synopsis"`
+			Artist           string        `json:"artist"`
+			AlbumArtist      string        `json:"album_artist"`
+			Date             string        `json:"date"`
+			Genre            string        `json:"genre"`
+			Publisher        string        `json:"publisher"`
 		} `json:"tags"`
 	} `json:"format"`
-	Streams []FFProbeStream `json:"streams"`
-	Error   struct {
+	Streams  []FFProbeStream  `json:"streams"`
+	Chapters []FFProbeChapter `json:"chapters"`
+	Error    struct {
 		Code   int    `json:"code"`
 		String string `json:"string"`
 	} `json:"error"`
@@ -85,6 +93,7 @@ type FFProbeStream struct {
 		HandlerName  string        `json:"handler_name"`
 		Language     string        `json:"language"`
 		Rotate       string        `json:"rotate"`
+		Title        string        `json:"title"`
 	} `json:"tags"`
 	TimeBase      string `json:"time_base"`
 	Width         int    `json:"width,omitempty"`
@@ -95,3 +104,13 @@ type FFProbeStream struct {
 	SampleFmt     string `json:"sample_fmt,omitempty"`
 	SampleRate    string `json:"sample_rate,omitempty"`
 }
+
+// FFProbeChapter is a JSON representation of a chapter of a file.
+type FFProbeChapter struct {
+	ID        int64  `json:"id"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	Tags      struct {
+		Title string `json:"title"`
+	} `json:"tags"`
+}
@@ -0,0 +1,311 @@
+package file
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+var errZipFSOpenZip = errors.New("cannot open archive inside archive")
+
+type archiveFormat int
+
+const (
+	archiveFormatZip archiveFormat = iota
+	archiveFormat7z
+	archiveFormatRar
+	archiveFormatTar
+	archiveFormatTarGzip
+)
+
+// archiveMagicLength is the number of bytes needed to detect the archive
+// format, which is the size of a tar header block.
+const archiveMagicLength = 512
+
+var (
+	magic7z   = []byte{'7', 'z', 0xBC, 0xAF, 0x27, 0x1C}
+	magicRar  = []byte{'R', 'a', 'r', '!', 0x1A, 0x07}
+	magicGzip = []byte{0x1F, 0x8B}
+)
+
+// detectArchiveFormat returns the format of the archive from its first bytes.
+// The format is detected from the content rather than the extension, since
+// comic book archives are often named with the wrong extension. Tar archives
+// are detected by the header checksum, so that V7 archives without the ustar
+// magic are also detected. Gzip streams are detected by readArchiveFormat.
+// Defaults to zip if the format is not recognised.
+func detectArchiveFormat(header []byte) archiveFormat {
+	switch {
+	case bytes.HasPrefix(header, magic7z):
+		return archiveFormat7z
+	case bytes.HasPrefix(header, magicRar):
+		return archiveFormatRar
+	case isTarHeader(header):
+		return archiveFormatTar
+	}
+
+	return archiveFormatZip
+}
+
+// isTarHeader returns true if the block is a tar header with a valid
+// checksum. The checksum is the sum of the header bytes, with the checksum
+// field counted as spaces. Some old archivers sum signed bytes.
+func isTarHeader(block []byte) bool {
+	const (
+		chksumStart = 148
+		chksumEnd   = 156
+	)
+
+	if len(block) < archiveMagicLength {
+		return false
+	}
+
+	field := strings.Trim(string(block[chksumStart:chksumEnd]), " \x00")
+	want, err := strconv.ParseInt(field, 8, 64)
+	if err != nil {
+		return false
+	}
+
+	var unsigned, signed int64
+	for i, c := range block[:archiveMagicLength] {
+		if i >= chksumStart && i < chksumEnd {
+			c = ' '
+		}
+		unsigned += int64(c)
+		signed += int64(int8(c))
+	}
+
+	return want == unsigned || want == signed
+}
+
+// readArchiveFormat returns the format of the archive file. Gzip streams are
+// tar.gz archives if the decompressed stream starts with a tar header.
+// Other gzip streams are not supported, and default to zip.
+func readArchiveFormat(f models.FS, path string) (archiveFormat, error) {
+	r, err := f.Open(path)
+	if err != nil {
+		return archiveFormatZip, err
+	}
+	defer r.Close()
+
+	header, err := readArchiveHeader(r)
+	if err != nil {
+		return archiveFormatZip, err
+	}
+
+	if !bytes.HasPrefix(header, magicGzip) {
+		return detectArchiveFormat(header), nil
+	}
+
+	gr, err := gzip.NewReader(io.MultiReader(bytes.NewReader(header), r))
+	if err != nil {
+		return archiveFormatZip, nil
+	}
+	defer gr.Close()
+
+	tarHeader, err := readArchiveHeader(gr)
+	if err != nil || !isTarHeader(tarHeader) {
+		return archiveFormatZip, nil
+	}
+
+	return archiveFormatTarGzip, nil
+}
+
+func readArchiveHeader(r io.Reader) ([]byte, error) {
+	header := make([]byte, archiveMagicLength)
+	n, err := io.ReadFull(r, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return header[:n], nil
+}
+
+func openArchiveFS(f models.FS, path string, info fs.FileInfo) (*archiveFS, error) {
+	format, err := readArchiveFormat(f, path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case archiveFormat7z:
+		return new7zFS(f, path, info)
+	case archiveFormatRar:
+		return newRarFS(f, path, info)
+	case archiveFormatTar:
+		return newTarFS(f, path, info, false)
+	case archiveFormatTarGzip:
+		return newTarFS(f, path, info, true)
+	}
+
+	return newZipFS(f, path, info)
+}
+
+// GetArchivePath returns the path of the archive that contains path, or an
+// empty string if path is not within an archive. The archive is the closest
+// parent of path that is a file.
+func GetArchivePath(f models.FS, path string) (string, error) {
+	if _, err := f.Lstat(path); err == nil {
+		return "", nil
+	} else if !errors.Is(err, fs.ErrNotExist) && !errors.Is(err, syscall.ENOTDIR) {
+		return "", err
+	}
+
+	for dir := filepath.Dir(path); dir != path; path, dir = dir, filepath.Dir(dir) {
+		info, err := f.Stat(dir)
+		if err == nil {
+			if info.IsDir() {
+				return "", nil
+			}
+			return dir, nil
+		}
+
+		if !errors.Is(err, fs.ErrNotExist) && !errors.Is(err, syscall.ENOTDIR) {
+			return "", err
+		}
+	}
+
+	return "", nil
+}
+
+// archiveFS is a file system backed by an archive file.
+type archiveFS struct {
+	fs.FS
+	archiveCloser io.Closer
+	archiveInfo   fs.FileInfo
+	archivePath   string
+}
+
+func (f *archiveFS) rel(name string) (string, error) {
+	if f.archivePath == name {
+		return ".", nil
+	}
+
+	relName, err := filepath.Rel(f.archivePath, name)
+	if err != nil {
+		return "", fmt.Errorf("internal error getting relative path: %w", err)
+	}
+
+	// convert relName to use slash, since archives do so regardless
+	// of os
+	relName = filepath.ToSlash(relName)
+
+	return relName, nil
+}
+
+func (f *archiveFS) Stat(name string) (fs.FileInfo, error) {
+	relName, err := f.rel(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// uses the index of the archive if supported, rather than opening the entry
+	return fs.Stat(f.FS, relName)
+}
+
+func (f *archiveFS) Lstat(name string) (fs.FileInfo, error) {
+	return f.Stat(name)
+}
+
+func (f *archiveFS) OpenZip(name string) (models.ZipFS, error) {
+	return nil, errZipFSOpenZip
+}
+
+func (f *archiveFS) IsPathCaseSensitive(path string) (bool, error) {
+	return true, nil
+}
+
+// seekableArchiveFile is an archive entry that can be seeked, because it is
+// stored without compression.
+type seekableArchiveFile interface {
+	fs.ReadDirFile
+	io.Seeker
+}
+
+type zipReadDirFile struct {
+	fs.File
+}
+
+func (f *zipReadDirFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	asReadDirFile, _ := f.File.(fs.ReadDirFile)
+	if asReadDirFile == nil {
+		return nil, fmt.Errorf("internal error: not a ReadDirFile")
+	}
+
+	return asReadDirFile.ReadDir(n)
+}
+
+func (f *archiveFS) Open(name string) (fs.ReadDirFile, error) {
+	relName, err := f.rel(name)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := f.FS.Open(relName)
+	if err != nil {
+		return nil, err
+	}
+
+	// keep entries that are read directly from the archive file seekable
+	if sf, ok := r.(seekableArchiveFile); ok {
+		return sf, nil
+	}
+
+	return &zipReadDirFile{
+		File: r,
+	}, nil
+}
+
+func (f *archiveFS) Close() error {
+	return f.archiveCloser.Close()
+}
+
+// openOnly returns a ReadCloser where calling Close will close the zip fs as well.
+func (f *archiveFS) OpenOnly(name string) (io.ReadCloser, error) {
+	r, err := f.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped := &wrappedReadCloser{
+		ReadCloser: r,
+		outer:      f,
+	}
+
+	if rs, ok := r.(io.ReadSeeker); ok {
+		return &wrappedReadSeekCloser{
+			wrappedReadCloser: wrapped,
+			seeker:            rs,
+		}, nil
+	}
+
+	return wrapped, nil
+}
+
+type wrappedReadCloser struct {
+	io.ReadCloser
+	outer io.Closer
+}
+
+func (f *wrappedReadCloser) Close() error {
+	_ = f.ReadCloser.Close()
+	return f.outer.Close()
+}
+
+type wrappedReadSeekCloser struct {
+	*wrappedReadCloser
+	seeker io.Seeker
+}
+
+func (f *wrappedReadSeekCloser) Seek(offset int64, whence int) (int64, error) {
+	return f.seeker.Seek(offset, whence)
+}
@@ -0,0 +1,35 @@
+package file
+
+import (
+	"io"
+	"io/fs"
+
+	"github.com/bodgit/sevenzip"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func new7zFS(f models.FS, path string, info fs.FileInfo) (*archiveFS, error) {
+	reader, err := f.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	asReaderAt, _ := reader.(io.ReaderAt)
+	if asReaderAt == nil {
+		reader.Close()
+		return nil, errNotReaderAt
+	}
+
+	szReader, err := sevenzip.NewReader(asReaderAt, info.Size())
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+
+	return &archiveFS{
+		FS:            szReader,
+		archiveCloser: reader,
+		archiveInfo:   info,
+		archivePath:   path,
+	}, nil
+}
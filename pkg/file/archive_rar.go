@@ -0,0 +1,57 @@
+package file
+
+import (
+	"io"
+	"io/fs"
+
+	"github.com/nwaples/rardecode"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type rarArchiveReader struct {
+	*rardecode.Reader
+}
+
+func (r rarArchiveReader) next() (*streamArchiveEntry, error) {
+	h, err := r.Reader.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	return &streamArchiveEntry{
+		name:    h.Name,
+		size:    h.UnPackedSize,
+		modTime: h.ModificationTime,
+		isDir:   h.IsDir,
+		offset:  -1,
+	}, nil
+}
+
+// newRarFS returns a read-only file system backed by a RAR archive.
+// Encrypted and multi-volume archives are not supported.
+func newRarFS(f models.FS, path string, info fs.FileInfo) (*archiveFS, error) {
+	sfs, err := newStreamFS(path, info, func() (streamArchiveReader, io.Closer, error) {
+		file, err := f.Open(path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		r, err := rardecode.NewReader(file, "")
+		if err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+
+		return rarArchiveReader{r}, file, nil
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &archiveFS{
+		FS:            sfs,
+		archiveCloser: sfs,
+		archiveInfo:   info,
+		archivePath:   path,
+	}, nil
+}
@@ -0,0 +1,416 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// streamArchiveEntry is the header of an entry in an archive that can only
+// be read sequentially.
+type streamArchiveEntry struct {
+	name    string
+	size    int64
+	modTime time.Time
+	isDir   bool
+	// offset is the offset of the content in the archive file, or -1 if the
+	// content cannot be read directly, for example because it is compressed.
+	offset int64
+	// index is the position of the entry in the archive
+	index int
+}
+
+// streamArchiveReader iterates over the entries of a sequential archive.
+// Read reads the content of the current entry.
+type streamArchiveReader interface {
+	io.Reader
+	// next returns the next entry, or io.EOF if there are no more entries.
+	next() (*streamArchiveEntry, error)
+}
+
+// streamArchiveOpener opens an archive from the start. The closer closes
+// the underlying file.
+type streamArchiveOpener func() (streamArchiveReader, io.Closer, error)
+
+// streamArchiveIndex is the index of the entries of a sequential archive.
+// It is not modified once built, so it is shared between file systems of
+// the same archive.
+type streamArchiveIndex struct {
+	entries  map[string]*streamArchiveEntry
+	children map[string][]string
+}
+
+// streamIndexCacheSize is the number of archive indexes that are cached.
+const streamIndexCacheSize = 64
+
+type streamIndexKey struct {
+	path    string
+	modTime int64
+	size    int64
+}
+
+// streamIndexCache caches the indexes of sequential archives by path and
+// modification time, so that opening an archive again does not read it
+// in full.
+var streamIndexCache, _ = lru.New[streamIndexKey, *streamArchiveIndex](streamIndexCacheSize)
+
+// streamPosition is an open archive reader, positioned after the entry at
+// index.
+type streamPosition struct {
+	r      streamArchiveReader
+	closer io.Closer
+	index  int
+}
+
+// streamFS is a file system backed by a sequential archive, such as a tar
+// or RAR archive. The index of the entries is built when the archive is
+// first opened, and cached. Entries with a known offset are read directly
+// from the archive file. Other entries are read sequentially, continuing
+// from the last entry read if it was before the opened entry, and otherwise
+// from the start of the archive.
+type streamFS struct {
+	*streamArchiveIndex
+	open streamArchiveOpener
+	// openFile opens the archive file, to read entries at their offsets.
+	// It is nil if entries cannot be read at offsets.
+	openFile func() (fs.File, error)
+
+	mutex  sync.Mutex
+	resume *streamPosition
+	closed bool
+}
+
+func newStreamFS(path string, info fs.FileInfo, open streamArchiveOpener, openFile func() (fs.File, error)) (*streamFS, error) {
+	key := streamIndexKey{
+		path:    path,
+		modTime: info.ModTime().UnixNano(),
+		size:    info.Size(),
+	}
+
+	index, found := streamIndexCache.Get(key)
+	if !found {
+		var err error
+		index, err = readStreamArchiveIndex(open)
+		if err != nil {
+			return nil, err
+		}
+
+		streamIndexCache.Add(key, index)
+	}
+
+	return &streamFS{
+		streamArchiveIndex: index,
+		open:               open,
+		openFile:           openFile,
+	}, nil
+}
+
+func readStreamArchiveIndex(open streamArchiveOpener) (*streamArchiveIndex, error) {
+	r, closer, err := open()
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	ret := &streamArchiveIndex{
+		entries: map[string]*streamArchiveEntry{
+			".": {name: ".", isDir: true, offset: -1, index: -1},
+		},
+		children: make(map[string][]string),
+	}
+
+	for i := 0; ; i++ {
+		e, err := r.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name, valid := cleanArchiveName(e.name)
+		if !valid {
+			continue
+		}
+
+		e.name = name
+		e.index = i
+		ret.add(e)
+	}
+
+	for _, c := range ret.children {
+		sort.Strings(c)
+	}
+
+	return ret, nil
+}
+
+// cleanArchiveName returns the name as a valid fs.FS path. Returns false
+// if the name is outside of the archive root.
+func cleanArchiveName(name string) (string, bool) {
+	name = path.Clean(strings.TrimPrefix(name, "/"))
+	if name == "." || !fs.ValidPath(name) {
+		return "", false
+	}
+
+	return name, true
+}
+
+func (f *streamArchiveIndex) add(e *streamArchiveEntry) {
+	if existing := f.entries[e.name]; existing != nil {
+		// directories may be added implicitly before their own entry
+		if existing.isDir && e.isDir {
+			existing.modTime = e.modTime
+		}
+		return
+	}
+
+	f.entries[e.name] = e
+
+	parent := path.Dir(e.name)
+	f.children[parent] = append(f.children[parent], e.name)
+
+	if _, found := f.entries[parent]; !found {
+		f.add(&streamArchiveEntry{name: parent, isDir: true, offset: -1, index: -1})
+	}
+}
+
+func (f *streamFS) entry(op string, name string) (*streamArchiveEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	e := f.entries[name]
+	if e == nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+
+	return e, nil
+}
+
+// Stat returns the file info of the entry from the index.
+func (f *streamFS) Stat(name string) (fs.FileInfo, error) {
+	e, err := f.entry("stat", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return streamFileInfo{e}, nil
+}
+
+func (f *streamFS) Open(name string) (fs.File, error) {
+	e, err := f.entry("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.isDir {
+		var entries []fs.DirEntry
+		for _, c := range f.children[name] {
+			entries = append(entries, fs.FileInfoToDirEntry(streamFileInfo{f.entries[c]}))
+		}
+
+		return &streamDir{
+			info:    streamFileInfo{e},
+			entries: entries,
+		}, nil
+	}
+
+	if e.offset >= 0 && f.openFile != nil {
+		file, err := f.openFile()
+		if err != nil {
+			return nil, err
+		}
+
+		if ra, ok := file.(io.ReaderAt); ok {
+			return &streamSectionFile{
+				SectionReader: io.NewSectionReader(ra, e.offset, e.size),
+				closer:        file,
+				info:          streamFileInfo{e},
+			}, nil
+		}
+
+		file.Close()
+	}
+
+	pos, err := f.seek(e)
+	if err != nil {
+		return nil, err
+	}
+
+	return &streamFile{
+		Reader: io.LimitReader(pos.r, e.size),
+		fs:     f,
+		pos:    pos,
+		info:   streamFileInfo{e},
+	}, nil
+}
+
+// seek returns a reader positioned at the content of the entry.
+func (f *streamFS) seek(e *streamArchiveEntry) (*streamPosition, error) {
+	f.mutex.Lock()
+	pos := f.resume
+	f.resume = nil
+	f.mutex.Unlock()
+
+	if pos != nil && pos.index >= e.index {
+		pos.closer.Close()
+		pos = nil
+	}
+
+	if pos == nil {
+		r, closer, err := f.open()
+		if err != nil {
+			return nil, err
+		}
+
+		pos = &streamPosition{r: r, closer: closer, index: -1}
+	}
+
+	for pos.index < e.index {
+		if _, err := pos.r.next(); err != nil {
+			pos.closer.Close()
+			if errors.Is(err, io.EOF) {
+				return nil, &fs.PathError{Op: "open", Path: e.name, Err: fs.ErrNotExist}
+			}
+			return nil, fmt.Errorf("reading archive: %w", err)
+		}
+
+		pos.index++
+	}
+
+	return pos, nil
+}
+
+// release keeps the reader to continue reading from, replacing the
+// previous one.
+func (f *streamFS) release(pos *streamPosition) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.closed {
+		pos.closer.Close()
+		return
+	}
+
+	if f.resume != nil {
+		f.resume.closer.Close()
+	}
+
+	f.resume = pos
+}
+
+// Close closes the reader kept to continue reading from.
+func (f *streamFS) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.closed = true
+	if f.resume == nil {
+		return nil
+	}
+
+	err := f.resume.closer.Close()
+	f.resume = nil
+	return err
+}
+
+type streamFileInfo struct {
+	e *streamArchiveEntry
+}
+
+func (i streamFileInfo) Name() string       { return path.Base(i.e.name) }
+func (i streamFileInfo) Size() int64        { return i.e.size }
+func (i streamFileInfo) ModTime() time.Time { return i.e.modTime }
+func (i streamFileInfo) IsDir() bool        { return i.e.isDir }
+func (i streamFileInfo) Sys() interface{}   { return nil }
+func (i streamFileInfo) Mode() fs.FileMode {
+	if i.e.isDir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
+
+type streamFile struct {
+	io.Reader
+	fs   *streamFS
+	pos  *streamPosition
+	info fs.FileInfo
+}
+
+func (f *streamFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *streamFile) Close() error {
+	if f.pos != nil {
+		f.fs.release(f.pos)
+		f.pos = nil
+	}
+	return nil
+}
+
+// streamSectionFile is an entry read directly from the archive file at its
+// offset, so it can be seeked.
+type streamSectionFile struct {
+	*io.SectionReader
+	closer io.Closer
+	info   fs.FileInfo
+}
+
+func (f *streamSectionFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *streamSectionFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	return nil, &fs.PathError{Op: "readdir", Path: f.info.Name(), Err: errors.New("not a directory")}
+}
+
+func (f *streamSectionFile) Close() error {
+	return f.closer.Close()
+}
+
+type streamDir struct {
+	info    fs.FileInfo
+	entries []fs.DirEntry
+}
+
+func (d *streamDir) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *streamDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.Name(), Err: errors.New("is a directory")}
+}
+
+func (d *streamDir) Close() error {
+	return nil
+}
+
+func (d *streamDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if n <= 0 {
+		ret := d.entries
+		d.entries = nil
+		return ret, nil
+	}
+
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+
+	if n > len(d.entries) {
+		n = len(d.entries)
+	}
+
+	ret := d.entries[:n]
+	d.entries = d.entries[n:]
+	return ret, nil
+}
@@ -0,0 +1,108 @@
+package file
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/fs"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type tarArchiveReader struct {
+	*tar.Reader
+	// seeker is the uncompressed archive file, used to get the offsets of
+	// the entries. It is nil if the archive is compressed.
+	seeker io.Seeker
+}
+
+func (r tarArchiveReader) next() (*streamArchiveEntry, error) {
+	for {
+		h, err := r.Reader.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		// links and special files are not supported
+		switch h.Typeflag {
+		case tar.TypeReg, tar.TypeDir:
+		default:
+			continue
+		}
+
+		offset := int64(-1)
+		if r.seeker != nil {
+			// the header has been read, so the file is at the content
+			offset, err = r.seeker.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return &streamArchiveEntry{
+			name:    h.Name,
+			size:    h.Size,
+			modTime: h.ModTime,
+			isDir:   h.Typeflag == tar.TypeDir,
+			offset:  offset,
+		}, nil
+	}
+}
+
+type multiCloser []io.Closer
+
+func (c multiCloser) Close() error {
+	var ret error
+	for _, cc := range c {
+		if err := cc.Close(); err != nil && ret == nil {
+			ret = err
+		}
+	}
+
+	return ret
+}
+
+// newTarFS returns a read-only file system backed by a tar archive. The
+// entries of uncompressed archives are read at their offsets. Entries of
+// gzipped archives are read sequentially.
+func newTarFS(f models.FS, path string, info fs.FileInfo, gzipped bool) (*archiveFS, error) {
+	openFile := func() (fs.File, error) {
+		return f.Open(path)
+	}
+
+	open := func() (streamArchiveReader, io.Closer, error) {
+		file, err := f.Open(path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if gzipped {
+			gr, err := gzip.NewReader(file)
+			if err != nil {
+				file.Close()
+				return nil, nil, err
+			}
+
+			return tarArchiveReader{Reader: tar.NewReader(gr)}, multiCloser{gr, file}, nil
+		}
+
+		seeker, _ := file.(io.Seeker)
+		return tarArchiveReader{Reader: tar.NewReader(file), seeker: seeker}, file, nil
+	}
+
+	if gzipped {
+		openFile = nil
+	}
+
+	sfs, err := newStreamFS(path, info, open, openFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &archiveFS{
+		FS:            sfs,
+		archiveCloser: sfs,
+		archiveInfo:   info,
+		archivePath:   path,
+	}, nil
+}
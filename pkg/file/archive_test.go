@@ -0,0 +1,346 @@
+package file
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testArchiveFiles = map[string]string{
+	"a.jpg":         "image a",
+	"sub/b.jpg":     "image b",
+	"sub/sub/c.png": "image c",
+}
+
+func writeTestZip(t *testing.T, w io.Writer) {
+	zw := zip.NewWriter(w)
+	for name, content := range testArchiveFiles {
+		fw, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = fw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+}
+
+func writeTestTar(t *testing.T, w io.Writer) {
+	tw := tar.NewWriter(w)
+
+	// explicit directory entry, and a link which should be ignored
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "sub/", Typeflag: tar.TypeDir, Mode: 0755}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "link.jpg", Typeflag: tar.TypeSymlink, Linkname: "a.jpg"}))
+
+	for name, content := range testArchiveFiles {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     "./" + name,
+			Typeflag: tar.TypeReg,
+			Mode:     0644,
+			Size:     int64(len(content)),
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+}
+
+func createTestArchive(t *testing.T, name string, write func(w io.Writer)) string {
+	p := filepath.Join(t.TempDir(), name)
+	f, err := os.Create(p)
+	require.NoError(t, err)
+	write(f)
+	require.NoError(t, f.Close())
+	return p
+}
+
+func TestOpenZipFS(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(w io.Writer)
+	}{
+		{"test.cbz", func(w io.Writer) { writeTestZip(t, w) }},
+		{"test.tar", func(w io.Writer) { writeTestTar(t, w) }},
+		{"test.tar.gz", func(w io.Writer) {
+			gw := gzip.NewWriter(w)
+			writeTestTar(t, gw)
+			require.NoError(t, gw.Close())
+		}},
+		// the format is detected from the content rather than the extension
+		{"misnamed.cbr", func(w io.Writer) { writeTestZip(t, w) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archivePath := createTestArchive(t, tt.name, tt.write)
+
+			zfs, err := OpenZipFS(&OsFS{}, archivePath)
+			require.NoError(t, err)
+			defer zfs.Close()
+
+			var files []string
+			err = symWalk(zfs, archivePath, func(path string, d fs.DirEntry, err error) error {
+				require.NoError(t, err)
+				if !d.IsDir() {
+					rel, _ := filepath.Rel(archivePath, path)
+					files = append(files, filepath.ToSlash(rel))
+				}
+				return nil
+			})
+			require.NoError(t, err)
+
+			var want []string
+			for name := range testArchiveFiles {
+				want = append(want, name)
+			}
+			sort.Strings(want)
+			sort.Strings(files)
+			assert.Equal(t, want, files)
+
+			for name, content := range testArchiveFiles {
+				p := filepath.Join(archivePath, filepath.FromSlash(name))
+
+				info, err := zfs.Stat(p)
+				require.NoError(t, err)
+				assert.Equal(t, int64(len(content)), info.Size())
+
+				r, err := zfs.Open(p)
+				require.NoError(t, err)
+				data, err := io.ReadAll(r)
+				r.Close()
+				require.NoError(t, err)
+				assert.Equal(t, content, string(data))
+			}
+
+			info, err := zfs.Stat(filepath.Join(archivePath, "sub"))
+			require.NoError(t, err)
+			assert.True(t, info.IsDir())
+
+			_, err = zfs.Open(filepath.Join(archivePath, "missing.jpg"))
+			assert.ErrorIs(t, err, fs.ErrNotExist)
+		})
+	}
+}
+
+// testTarHeader returns the first header block of a tar archive. If v7 is
+// true, the ustar fields are cleared as in V7 archives.
+func testTarHeader(t *testing.T, v7 bool) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "a.jpg", Typeflag: tar.TypeReg, Mode: 0644, Format: tar.FormatUSTAR}))
+	require.NoError(t, tw.Close())
+
+	header := buf.Bytes()[:archiveMagicLength]
+	if v7 {
+		for i := 257; i < archiveMagicLength; i++ {
+			header[i] = 0
+		}
+
+		var sum int64
+		for i, c := range header {
+			if i >= 148 && i < 156 {
+				c = ' '
+			}
+			sum += int64(c)
+		}
+		copy(header[148:156], fmt.Sprintf("%06o\x00 ", sum))
+	}
+
+	return header
+}
+
+func TestDetectArchiveFormat(t *testing.T) {
+	badChecksum := testTarHeader(t, false)
+	badChecksum[0]++
+
+	tests := []struct {
+		name   string
+		header []byte
+		want   archiveFormat
+	}{
+		{"zip", []byte("PK\x03\x04rest"), archiveFormatZip},
+		{"7z", []byte("7z\xBC\xAF\x27\x1Crest"), archiveFormat7z},
+		{"rar", []byte("Rar!\x1A\x07\x00rest"), archiveFormatRar},
+		{"rar5", []byte("Rar!\x1A\x07\x01\x00rest"), archiveFormatRar},
+		{"ustar", testTarHeader(t, false), archiveFormatTar},
+		{"v7 tar", testTarHeader(t, true), archiveFormatTar},
+		{"bad tar checksum", badChecksum, archiveFormatZip},
+		{"empty tar block", make([]byte, archiveMagicLength), archiveFormatZip},
+		{"unknown", []byte("unknown"), archiveFormatZip},
+		{"empty", nil, archiveFormatZip},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, detectArchiveFormat(tt.header))
+		})
+	}
+}
+
+func TestReadArchiveFormat(t *testing.T) {
+	gzipped := func(write func(w io.Writer)) func(w io.Writer) {
+		return func(w io.Writer) {
+			gw := gzip.NewWriter(w)
+			write(gw)
+			require.NoError(t, gw.Close())
+		}
+	}
+
+	tests := []struct {
+		name  string
+		write func(w io.Writer)
+		want  archiveFormat
+	}{
+		{"tar.gz", gzipped(func(w io.Writer) { writeTestTar(t, w) }), archiveFormatTarGzip},
+		{"gzipped text", gzipped(func(w io.Writer) { _, _ = w.Write([]byte("not a tar archive")) }), archiveFormatZip},
+		{"tar", func(w io.Writer) { writeTestTar(t, w) }, archiveFormatTar},
+		{"zip", func(w io.Writer) { writeTestZip(t, w) }, archiveFormatZip},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archivePath := createTestArchive(t, "archive", tt.write)
+
+			got, err := readArchiveFormat(&OsFS{}, archivePath)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestStreamFS(t *testing.T) {
+	tarPath := createTestArchive(t, "test.tar", func(w io.Writer) { writeTestTar(t, w) })
+	tarGzPath := createTestArchive(t, "test.tar.gz", func(w io.Writer) {
+		gw := gzip.NewWriter(w)
+		writeTestTar(t, gw)
+		require.NoError(t, gw.Close())
+	})
+
+	readAll := func(t *testing.T, zfs models.ZipFS, archivePath string, name string) (string, bool) {
+		r, err := zfs.Open(filepath.Join(archivePath, name))
+		require.NoError(t, err)
+		defer r.Close()
+
+		_, seekable := r.(io.Seeker)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		return string(data), seekable
+	}
+
+	t.Run("uncompressed entries are seekable", func(t *testing.T) {
+		zfs, err := OpenZipFS(&OsFS{}, tarPath)
+		require.NoError(t, err)
+		defer zfs.Close()
+
+		data, seekable := readAll(t, zfs, tarPath, "sub/b.jpg")
+		assert.Equal(t, testArchiveFiles["sub/b.jpg"], data)
+		assert.True(t, seekable)
+	})
+
+	t.Run("compressed entries are read in any order", func(t *testing.T) {
+		zfs, err := OpenZipFS(&OsFS{}, tarGzPath)
+		require.NoError(t, err)
+		defer zfs.Close()
+
+		names := []string{"a.jpg", "sub/b.jpg", "sub/sub/c.png", "a.jpg", "sub/sub/c.png", "sub/b.jpg"}
+		for _, name := range names {
+			data, seekable := readAll(t, zfs, tarGzPath, name)
+			assert.Equal(t, testArchiveFiles[name], data, name)
+			assert.False(t, seekable)
+		}
+	})
+
+	t.Run("index is cached", func(t *testing.T) {
+		zfs, err := OpenZipFS(&OsFS{}, tarGzPath)
+		require.NoError(t, err)
+		zfs.Close()
+
+		info, err := os.Stat(tarGzPath)
+		require.NoError(t, err)
+
+		_, found := streamIndexCache.Get(streamIndexKey{
+			path:    tarGzPath,
+			modTime: info.ModTime().UnixNano(),
+			size:    info.Size(),
+		})
+		assert.True(t, found)
+	})
+}
+
+func TestGetArchivePath(t *testing.T) {
+	archivePath := createTestArchive(t, "test.zip", func(w io.Writer) { writeTestZip(t, w) })
+	dir := filepath.Dir(archivePath)
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"archive member", filepath.Join(archivePath, "a.jpg"), archivePath},
+		{"nested archive member", filepath.Join(archivePath, "sub", "sub", "c.png"), archivePath},
+		{"archive", archivePath, ""},
+		{"folder", dir, ""},
+		{"missing file", filepath.Join(dir, "missing", "a.jpg"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetArchivePath(&OsFS{}, tt.path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestZipStoredFile(t *testing.T) {
+	const content = "0123456789"
+
+	archivePath := createTestArchive(t, "test.zip", func(w io.Writer) {
+		zw := zip.NewWriter(w)
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: "stored.mp4", Method: zip.Store})
+		require.NoError(t, err)
+		_, err = fw.Write([]byte(content))
+		require.NoError(t, err)
+		fw, err = zw.Create("deflated.mp4")
+		require.NoError(t, err)
+		_, err = fw.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+	})
+
+	zfs, err := OpenZipFS(&OsFS{}, archivePath)
+	require.NoError(t, err)
+
+	r, err := zfs.OpenOnly(filepath.Join(archivePath, "stored.mp4"))
+	require.NoError(t, err)
+	defer r.Close()
+
+	rs, ok := r.(io.ReadSeeker)
+	require.True(t, ok, "stored entry should be seekable")
+
+	_, err = rs.Seek(4, io.SeekStart)
+	require.NoError(t, err)
+	data, err := io.ReadAll(rs)
+	require.NoError(t, err)
+	assert.Equal(t, content[4:], string(data))
+
+	df, err := zfs.Open(filepath.Join(archivePath, "deflated.mp4"))
+	require.NoError(t, err)
+	defer df.Close()
+
+	_, ok = df.(io.Seeker)
+	assert.False(t, ok, "compressed entry should not be seekable")
+	data, err = io.ReadAll(df)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+}
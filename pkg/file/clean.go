@@ -30,6 +30,7 @@ type cleanJob struct {
 
 // ScanOptions provides options for scanning files.
 type CleanOptions struct {
+	// Paths are the folders, or individual files, to clean.
 	Paths []string
 
 	// Do a dry run. Don't delete any files
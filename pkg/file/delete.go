@@ -65,8 +65,12 @@ func newRenamerRemoverImpl() renamerRemoverImpl {
 // filesystem using the Complete method.
 type Deleter struct {
 	RenamerRemover RenamerRemover
-	files          []string
-	dirs           []string
+	// ReadOnlyPaths are the paths in which files and directories may not
+	// be deleted.
+	ReadOnlyPaths []string
+
+	files []string
+	dirs  []string
 }
 
 func NewDeleter() *Deleter {
@@ -90,9 +94,13 @@ func (d *Deleter) RegisterHooks(ctx context.Context) {
 // a `.delete` suffix. An error is returned if a file could not be renamed.
 // Note that if an error is returned, then some files may be left renamed.
 // Abort should be called to restore marked files if this function returns an
-// error.
+// error. An error is returned if a file is in a read-only path.
 func (d *Deleter) Files(paths []string) error {
 	for _, p := range paths {
+		if err := checkWritable(d.ReadOnlyPaths, p); err != nil {
+			return fmt.Errorf("cannot delete file: %w", err)
+		}
+
 		// fail silently if the file does not exist
 		if _, err := d.RenamerRemover.Stat(p); err != nil {
 			if errors.Is(err, fs.ErrNotExist) {
@@ -116,9 +124,13 @@ func (d *Deleter) Files(paths []string) error {
 // a `.delete` suffix. An error is returned if a directory could not be renamed.
 // Note that if an error is returned, then some directories may be left renamed.
 // Abort should be called to restore marked files/directories if this function returns an
-// error.
+// error. An error is returned if a directory is in a read-only path.
 func (d *Deleter) Dirs(paths []string) error {
 	for _, p := range paths {
+		if err := checkWritable(d.ReadOnlyPaths, p); err != nil {
+			return fmt.Errorf("cannot delete directory: %w", err)
+		}
+
 		// fail silently if the file does not exist
 		if _, err := d.RenamerRemover.Stat(p); err != nil {
 			if errors.Is(err, fs.ErrNotExist) {
@@ -1,6 +1,7 @@
 package file
 
 import (
+	"fmt"
 	"io"
 	"io/fs"
 	"os"
@@ -59,12 +60,64 @@ func (f *OsFS) Open(name string) (fs.ReadDirFile, error) {
 }
 
 func (f *OsFS) OpenZip(name string) (models.ZipFS, error) {
+	return OpenZipFS(f, name)
+}
+
+// OpenZipFS opens the archive file name in f as a file system. Zip, 7z, RAR
+// and tar archives are supported. The file opened by f must implement
+// io.ReaderAt for zip and 7z archives.
+func OpenZipFS(f models.FS, name string) (models.ZipFS, error) {
 	info, err := f.Lstat(name)
 	if err != nil {
 		return nil, err
 	}
 
-	return newZipFS(f, name, info)
+	return openArchiveFS(f, name, info)
+}
+
+// OpenFileFS returns the file system containing file. If file is in an
+// archive, the archive is opened using f, and the returned closer closes it.
+// Returns an error if the archive of the file is unknown.
+func OpenFileFS(f models.FS, file *models.BaseFile) (models.FS, io.Closer, error) {
+	if file.ZipFileID == nil {
+		return f, nopCloser{}, nil
+	}
+
+	if file.ZipFile == nil {
+		return nil, nil, fmt.Errorf("archive of %s is not loaded", file.Path)
+	}
+
+	parent, parentCloser, err := OpenFileFS(f, file.ZipFile.Base())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	zfs, err := parent.OpenZip(file.ZipFile.Base().Path)
+	if err != nil {
+		parentCloser.Close()
+		return nil, nil, err
+	}
+
+	return zfs, &fsCloser{inner: zfs, outer: parentCloser}, nil
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+type fsCloser struct {
+	inner io.Closer
+	outer io.Closer
+}
+
+func (c *fsCloser) Close() error {
+	err := c.inner.Close()
+	if outerErr := c.outer.Close(); err == nil {
+		err = outerErr
+	}
+	return err
 }
 
 func (f *OsFS) IsPathCaseSensitive(path string) (bool, error) {
@@ -10,13 +10,18 @@ import (
 	_ "image/png"
 
 	"github.com/stashapp/stash/pkg/ffmpeg"
-	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/file/video"
+	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
 	_ "golang.org/x/image/webp"
 )
 
+// stillImageExtensions are the extensions of images that cannot be clips,
+// and can be decoded without ffprobe. This should match the codecs that are
+// not treated as clips in Decorate.
+var stillImageExtensions = []string{"jpg", "jpeg", "png", "webp"}
+
 // Decorator adds image specific fields to a File.
 type Decorator struct {
 	FFProbe ffmpeg.FFProbe
@@ -44,10 +49,9 @@ func (d *Decorator) Decorate(ctx context.Context, fs models.FS, f models.File) (
 		}, nil
 	}
 
-	// ignore clips in non-OsFS filesystems as ffprobe cannot read them
-	// TODO - copy to temp file if not an OsFS
-	if _, isOs := fs.(*file.OsFS); !isOs {
-		logger.Debugf("assuming ImageFile for non-OsFS file %q", base.Path)
+	// only probe archived entries that could be clips, since they are read
+	// by ffprobe through a proxy
+	if _, isZip := fs.(models.ZipFS); isZip && fsutil.MatchExtension(base.Path, stillImageExtensions) {
 		return decorateFallback()
 	}
 
@@ -0,0 +1,77 @@
+package metadata
+
+import (
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil"
+)
+
+// The following functions return the partial update of a field with a value
+// read from file metadata. Empty values never replace existing values.
+
+// UpdateString returns the update of a string field, or an unset value if
+// the field should not be updated.
+func UpdateString(precedence models.FileMetadataPrecedence, existing string, v string) models.OptionalString {
+	if v == "" || v == existing || (existing != "" && precedence != models.FileMetadataPrecedenceFile) {
+		return models.OptionalString{}
+	}
+
+	return models.NewOptionalString(v)
+}
+
+// UpdateDate returns the update of a date field. Dates that cannot be parsed
+// are ignored.
+func UpdateDate(precedence models.FileMetadataPrecedence, existing *models.Date, v string) models.OptionalDate {
+	if v == "" || (existing != nil && precedence != models.FileMetadataPrecedenceFile) {
+		return models.OptionalDate{}
+	}
+
+	d, err := models.ParseDate(v)
+	if err != nil {
+		logger.Debugf("Ignoring invalid date %q in file metadata: %v", v, err)
+		return models.OptionalDate{}
+	}
+
+	if existing != nil && existing.String() == d.String() {
+		return models.OptionalDate{}
+	}
+
+	return models.NewOptionalDate(d)
+}
+
+// UpdateID returns the update of an ID field.
+func UpdateID(precedence models.FileMetadataPrecedence, existing *int, v *int) models.OptionalInt {
+	if v == nil || (existing != nil && (*existing == *v || precedence != models.FileMetadataPrecedenceFile)) {
+		return models.OptionalInt{}
+	}
+
+	return models.NewOptionalInt(*v)
+}
+
+// AddIDs returns the update adding the ids that are not in existing, or nil
+// if there are none. Related IDs are always added, regardless of precedence.
+func AddIDs(existing []int, ids []int) *models.UpdateIDs {
+	toAdd := sliceutil.Exclude(sliceutil.Unique(ids), existing)
+	if len(toAdd) == 0 {
+		return nil
+	}
+
+	return &models.UpdateIDs{
+		IDs:  toAdd,
+		Mode: models.RelationshipUpdateModeAdd,
+	}
+}
+
+// AddStrings returns the update adding the values that are not in existing,
+// or nil if there are none.
+func AddStrings(existing []string, values []string) *models.UpdateStrings {
+	toAdd := sliceutil.Exclude(sliceutil.Unique(values), existing)
+	if len(toAdd) == 0 {
+		return nil
+	}
+
+	return &models.UpdateStrings{
+		Values: toAdd,
+		Mode:   models.RelationshipUpdateModeAdd,
+	}
+}
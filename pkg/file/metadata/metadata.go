@@ -0,0 +1,83 @@
+// Package metadata reads scene and gallery metadata embedded in files and
+// from sidecar files stored next to them.
+package metadata
+
+import (
+	"strings"
+
+	"github.com/stashapp/stash/pkg/sliceutil"
+)
+
+// Chapter is a named position in a video file.
+type Chapter struct {
+	Title   string  `json:"title"`
+	Seconds float64 `json:"seconds"`
+}
+
+// Metadata is the metadata read from a file or its sidecar files.
+// Related objects are referenced by name.
+type Metadata struct {
+	Title    string `json:"title,omitempty"`
+	Code     string `json:"code,omitempty"`
+	Details  string `json:"details,omitempty"`
+	Director string `json:"director,omitempty"`
+	// Date in yyyy-mm-dd format, or any format accepted by models.ParseDate
+	Date       string    `json:"date,omitempty"`
+	URLs       []string  `json:"urls,omitempty"`
+	Studio     string    `json:"studio,omitempty"`
+	Performers []string  `json:"performers,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+	Chapters   []Chapter `json:"chapters,omitempty"`
+}
+
+// IsEmpty returns true if no metadata was read.
+func (m *Metadata) IsEmpty() bool {
+	return m == nil || (m.Title == "" && m.Code == "" && m.Details == "" && m.Director == "" &&
+		m.Date == "" && len(m.URLs) == 0 && m.Studio == "" && len(m.Performers) == 0 &&
+		len(m.Tags) == 0 && len(m.Chapters) == 0)
+}
+
+func mergeString(dest *string, v string) {
+	if *dest == "" {
+		*dest = v
+	}
+}
+
+// Merge sets the empty fields of m from o, and adds the URLs, performers
+// and tags of o. Chapters are only taken from o if m has none.
+func (m *Metadata) Merge(o *Metadata) {
+	if o == nil {
+		return
+	}
+
+	mergeString(&m.Title, o.Title)
+	mergeString(&m.Code, o.Code)
+	mergeString(&m.Details, o.Details)
+	mergeString(&m.Director, o.Director)
+	mergeString(&m.Date, o.Date)
+	mergeString(&m.Studio, o.Studio)
+
+	m.URLs = sliceutil.AppendUniques(m.URLs, o.URLs)
+	m.Performers = sliceutil.AppendUniques(m.Performers, o.Performers)
+	m.Tags = sliceutil.AppendUniques(m.Tags, o.Tags)
+
+	if len(m.Chapters) == 0 {
+		m.Chapters = o.Chapters
+	}
+}
+
+// splitList splits a list of names separated by any of the separators,
+// removing empty and duplicate entries.
+func splitList(s string, separators string) []string {
+	var ret []string
+	for _, v := range strings.FieldsFunc(s, func(r rune) bool {
+		return strings.ContainsRune(separators, r)
+	}) {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			ret = sliceutil.AppendUnique(ret, v)
+		}
+	}
+
+	return ret
+}
@@ -0,0 +1,167 @@
+package metadata
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func TestReadNFO(t *testing.T) {
+	const nfo = `<?xml version="1.0" encoding="UTF-8" standalone="yes" ?>
+<movie>
+  <title>Scene Title</title>
+  <outline>Short</outline>
+  <plot>Long plot</plot>
+  <premiered>2023-01-02</premiered>
+  <year>2023</year>
+  <studio>Studio A</studio>
+  <studio>Studio B</studio>
+  <director>Director</director>
+  <actor><name>Jane Doe</name><role>Self</role></actor>
+  <actor><name> </name></actor>
+  <actor><name>John Doe</name></actor>
+  <genre>Genre A / Genre B</genre>
+  <tag>Tag A</tag>
+  <id>ABC-123</id>
+  <trailer>plugin://plugin.video.youtube/?video_id=1</trailer>
+</movie>`
+
+	got, err := ReadNFO(strings.NewReader(nfo))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, &Metadata{
+		Title:      "Scene Title",
+		Code:       "ABC-123",
+		Details:    "Long plot",
+		Director:   "Director",
+		Date:       "2023-01-02",
+		Studio:     "Studio A",
+		Performers: []string{"Jane Doe", "John Doe"},
+		Tags:       []string{"Genre A", "Genre B", "Tag A"},
+	}, got)
+
+	_, err = ReadNFO(strings.NewReader("<movie><title>"))
+	assert.Error(t, err)
+}
+
+func TestReadJSON(t *testing.T) {
+	const j = `{"title": "Title", "urls": ["https://example.com"], "performers": ["Jane Doe"], "chapters": [{"title": "Intro", "seconds": 1.5}]}`
+
+	got, err := ReadJSON(strings.NewReader(j))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, &Metadata{
+		Title:      "Title",
+		URLs:       []string{"https://example.com"},
+		Performers: []string{"Jane Doe"},
+		Chapters:   []Chapter{{Title: "Intro", Seconds: 1.5}},
+	}, got)
+}
+
+func TestFromProbe(t *testing.T) {
+	v := &ffmpeg.VideoFile{}
+	tags := &v.JSON.Format.Tags
+	tags.Artist = "Jane Doe; John Doe;Jane Doe"
+	tags.AlbumArtist = "Studio"
+	tags.Genre = "Tag A, Tag B"
+	tags.Comment = "Comment"
+	tags.Synopsis = "Synopsis"
+	tags.Date = "2023"
+
+	v.VideoStream = &ffmpeg.FFProbeStream{}
+	v.VideoStream.Tags.Title = "Stream title"
+
+	var chapter, untitled ffmpeg.FFProbeChapter
+	chapter.StartTime = "12.500000"
+	chapter.Tags.Title = "Chapter"
+	untitled.StartTime = "20.000000"
+	v.JSON.Chapters = []ffmpeg.FFProbeChapter{chapter, untitled}
+
+	assert.Equal(t, &Metadata{
+		Title:      "Stream title",
+		Details:    "Synopsis",
+		Date:       "2023",
+		Studio:     "Studio",
+		Performers: []string{"Jane Doe", "John Doe"},
+		Tags:       []string{"Tag A", "Tag B"},
+		Chapters:   []Chapter{{Title: "Chapter", Seconds: 12.5}},
+	}, FromProbe(v))
+}
+
+func TestMetadata_Merge(t *testing.T) {
+	m := &Metadata{
+		Title:      "Sidecar",
+		Performers: []string{"Jane Doe"},
+	}
+
+	m.Merge(&Metadata{
+		Title:      "Embedded",
+		Details:    "Details",
+		Performers: []string{"Jane Doe", "John Doe"},
+		Chapters:   []Chapter{{Title: "Chapter"}},
+	})
+
+	assert.Equal(t, &Metadata{
+		Title:      "Sidecar",
+		Details:    "Details",
+		Performers: []string{"Jane Doe", "John Doe"},
+		Chapters:   []Chapter{{Title: "Chapter"}},
+	}, m)
+}
+
+func TestUpdateString(t *testing.T) {
+	existing := models.FileMetadataPrecedenceExisting
+	file := models.FileMetadataPrecedenceFile
+
+	assert.Equal(t, models.NewOptionalString("v"), UpdateString(existing, "", "v"))
+	assert.Equal(t, models.OptionalString{}, UpdateString(existing, "old", "v"))
+	assert.Equal(t, models.NewOptionalString("v"), UpdateString(file, "old", "v"))
+	assert.Equal(t, models.OptionalString{}, UpdateString(file, "old", ""))
+	assert.Equal(t, models.OptionalString{}, UpdateString(file, "v", "v"))
+}
+
+func TestAddIDs(t *testing.T) {
+	assert.Nil(t, AddIDs([]int{1, 2}, []int{2, 1}))
+	assert.Equal(t, &models.UpdateIDs{
+		IDs:  []int{3},
+		Mode: models.RelationshipUpdateModeAdd,
+	}, AddIDs([]int{1}, []int{1, 3, 3}))
+}
+
+func TestSidecarsModifiedAfter(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "scene.mp4")
+	fs := &file.OsFS{}
+
+	scanned := time.Now().Add(-time.Hour)
+
+	// no sidecar files
+	assert.False(t, SidecarsModifiedAfter(fs, path, scanned))
+
+	nfo := filepath.Join(dir, "scene.nfo")
+	if err := os.WriteFile(nfo, []byte("<movie/>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chtimes(nfo, scanned, scanned.Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, SidecarsModifiedAfter(fs, path, scanned))
+
+	if err := os.Chtimes(nfo, scanned, scanned.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, SidecarsModifiedAfter(fs, path, scanned))
+}
@@ -0,0 +1,58 @@
+package metadata
+
+import (
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+)
+
+// separators of multiple values in container tags
+const tagListSeparators = ";,/"
+
+// FromProbe returns the metadata embedded in the container and streams of
+// a video file.
+func FromProbe(v *ffmpeg.VideoFile) *Metadata {
+	tags := v.JSON.Format.Tags
+
+	ret := &Metadata{
+		Title:      tags.Title,
+		Date:       tags.Date,
+		Studio:     tags.Publisher,
+		Performers: splitList(tags.Artist, tagListSeparators),
+		Tags:       splitList(tags.Genre, tagListSeparators),
+	}
+
+	if ret.Title == "" && v.VideoStream != nil {
+		ret.Title = v.VideoStream.Tags.Title
+	}
+
+	if ret.Studio == "" {
+		ret.Studio = tags.AlbumArtist
+	}
+
+	// prefer the longer description fields to the comment
+	for _, d := range []string{tags.Description, tags.Synopsis, tags.Comment} {
+		if d != "" {
+			ret.Details = d
+			break
+		}
+	}
+
+	for _, c := range v.JSON.Chapters {
+		if c.Tags.Title == "" {
+			continue
+		}
+
+		seconds, err := strconv.ParseFloat(c.StartTime, 64)
+		if err != nil {
+			continue
+		}
+
+		ret.Chapters = append(ret.Chapters, Chapter{
+			Title:   c.Tags.Title,
+			Seconds: seconds,
+		})
+	}
+
+	return ret
+}
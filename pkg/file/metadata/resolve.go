@@ -0,0 +1,182 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/performer"
+	"github.com/stashapp/stash/pkg/studio"
+	"github.com/stashapp/stash/pkg/tag"
+)
+
+type PerformerFinderCreator interface {
+	models.PerformerQueryer
+	FindByNames(ctx context.Context, names []string, nocase bool) ([]*models.Performer, error)
+	Create(ctx context.Context, newPerformer *models.Performer) error
+}
+
+type StudioFinderCreator interface {
+	models.StudioQueryer
+	Create(ctx context.Context, newStudio *models.Studio) error
+}
+
+type TagFinderCreator interface {
+	models.TagQueryer
+	Create(ctx context.Context, newTag *models.Tag) error
+}
+
+// Resolver finds the performers, studios and tags referenced by name in
+// metadata, matching names and aliases case-insensitively.
+type Resolver struct {
+	PerformerFinderCreator PerformerFinderCreator
+	StudioFinderCreator    StudioFinderCreator
+	TagFinderCreator       TagFinderCreator
+
+	// Create performers, studios and tags that are not found.
+	CreateMissing bool
+}
+
+func (r *Resolver) performerID(ctx context.Context, name string) (*int, error) {
+	qb := r.PerformerFinderCreator
+	performers, err := qb.FindByNames(ctx, []string{name}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(performers) == 0 {
+		performers, err = performer.ByAlias(ctx, qb, name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(performers) == 1 {
+		return &performers[0].ID, nil
+	}
+
+	if len(performers) > 1 || !r.CreateMissing {
+		// ambiguous or missing
+		return nil, nil
+	}
+
+	newPerformer := models.NewPerformer()
+	newPerformer.Name = name
+	if err := qb.Create(ctx, &newPerformer); err != nil {
+		return nil, fmt.Errorf("creating performer %q: %w", name, err)
+	}
+
+	logger.Infof("Created performer %q from file metadata", name)
+	return &newPerformer.ID, nil
+}
+
+// PerformerIDs returns the IDs of the performers with the given names.
+// Names that are not found, or match more than one performer, are ignored.
+func (r *Resolver) PerformerIDs(ctx context.Context, names []string) ([]int, error) {
+	var ret []int
+	for _, name := range names {
+		id, err := r.performerID(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if id != nil {
+			ret = append(ret, *id)
+		}
+	}
+
+	return ret, nil
+}
+
+// StudioID returns the ID of the studio with the given name, or nil if it
+// is not found.
+func (r *Resolver) StudioID(ctx context.Context, name string) (*int, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	qb := r.StudioFinderCreator
+	s, err := studio.ByName(ctx, qb, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		s, err = studio.ByAlias(ctx, qb, name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if s != nil {
+		return &s.ID, nil
+	}
+
+	if !r.CreateMissing {
+		return nil, nil
+	}
+
+	newStudio := models.NewStudio()
+	newStudio.Name = name
+	if err := qb.Create(ctx, &newStudio); err != nil {
+		return nil, fmt.Errorf("creating studio %q: %w", name, err)
+	}
+
+	logger.Infof("Created studio %q from file metadata", name)
+	return &newStudio.ID, nil
+}
+
+// TagID returns the ID of the tag with the given name, or nil if it is not
+// found.
+func (r *Resolver) TagID(ctx context.Context, name string) (*int, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	qb := r.TagFinderCreator
+	t, err := tag.ByName(ctx, qb, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if t == nil {
+		t, err = tag.ByAlias(ctx, qb, name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if t != nil {
+		return &t.ID, nil
+	}
+
+	if !r.CreateMissing {
+		return nil, nil
+	}
+
+	newTag := models.NewTag()
+	newTag.Name = name
+	if err := qb.Create(ctx, &newTag); err != nil {
+		return nil, fmt.Errorf("creating tag %q: %w", name, err)
+	}
+
+	logger.Infof("Created tag %q from file metadata", name)
+	return &newTag.ID, nil
+}
+
+// TagIDs returns the IDs of the tags with the given names. Names that are
+// not found are ignored.
+func (r *Resolver) TagIDs(ctx context.Context, names []string) ([]int, error) {
+	var ret []int
+	for _, name := range names {
+		id, err := r.TagID(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if id != nil {
+			ret = append(ret, *id)
+		}
+	}
+
+	return ret, nil
+}
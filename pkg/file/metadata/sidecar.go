@@ -0,0 +1,162 @@
+package metadata
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	nfoExt  = ".nfo"
+	jsonExt = ".json"
+)
+
+// nfoActor is an actor element of a Kodi nfo file.
+type nfoActor struct {
+	Name string `xml:"name"`
+}
+
+// nfoFile is a Kodi-style movie, episode or music video nfo file.
+// The root element name is not checked.
+type nfoFile struct {
+	Title     string     `xml:"title"`
+	Plot      string     `xml:"plot"`
+	Outline   string     `xml:"outline"`
+	Premiered string     `xml:"premiered"`
+	Aired     string     `xml:"aired"`
+	Year      string     `xml:"year"`
+	Studio    []string   `xml:"studio"`
+	Director  []string   `xml:"director"`
+	Actors    []nfoActor `xml:"actor"`
+	Genres    []string   `xml:"genre"`
+	Tags      []string   `xml:"tag"`
+	ID        string     `xml:"id"`
+	Trailer   string     `xml:"trailer"`
+}
+
+func firstNonEmpty(v ...string) string {
+	for _, s := range v {
+		if s = strings.TrimSpace(s); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// ReadNFO reads a Kodi-style nfo file.
+func ReadNFO(r io.Reader) (*Metadata, error) {
+	var nfo nfoFile
+	if err := xml.NewDecoder(r).Decode(&nfo); err != nil {
+		return nil, err
+	}
+
+	ret := &Metadata{
+		Title:    strings.TrimSpace(nfo.Title),
+		Code:     strings.TrimSpace(nfo.ID),
+		Details:  firstNonEmpty(nfo.Plot, nfo.Outline),
+		Director: firstNonEmpty(nfo.Director...),
+		Date:     firstNonEmpty(nfo.Premiered, nfo.Aired, nfo.Year),
+		Studio:   firstNonEmpty(nfo.Studio...),
+	}
+
+	for _, a := range nfo.Actors {
+		if name := strings.TrimSpace(a.Name); name != "" {
+			ret.Performers = append(ret.Performers, name)
+		}
+	}
+
+	for _, t := range append(nfo.Genres, nfo.Tags...) {
+		ret.Tags = append(ret.Tags, splitList(t, "/")...)
+	}
+
+	// only use web links as urls
+	if trailer := strings.TrimSpace(nfo.Trailer); strings.HasPrefix(trailer, "http://") || strings.HasPrefix(trailer, "https://") {
+		ret.URLs = []string{trailer}
+	}
+
+	return ret, nil
+}
+
+// ReadJSON reads a json sidecar file. The file uses the field names of
+// Metadata.
+func ReadJSON(r io.Reader) (*Metadata, error) {
+	var ret Metadata
+	if err := json.NewDecoder(r).Decode(&ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func readSidecar(f models.FS, path string, read func(io.Reader) (*Metadata, error)) (*Metadata, error) {
+	file, err := f.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	ret, err := read(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", path, err)
+	}
+
+	return ret, nil
+}
+
+// SidecarPaths returns the paths of the sidecar files of the file at path,
+// in order of precedence.
+func SidecarPaths(path string) []string {
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	return []string{base + jsonExt, base + nfoExt}
+}
+
+// SidecarsModifiedAfter returns true if any of the sidecar files of the
+// file at path was modified after t.
+func SidecarsModifiedAfter(f models.FS, path string, t time.Time) bool {
+	for _, p := range SidecarPaths(path) {
+		info, err := f.Lstat(p)
+		if err == nil && info.ModTime().After(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ReadSidecars reads the json and nfo sidecar files of the file at path, if
+// present. Values from the json file take precedence. Returns nil if there
+// are no sidecar files.
+func ReadSidecars(f models.FS, path string) (*Metadata, error) {
+	var ret *Metadata
+
+	for _, p := range SidecarPaths(path) {
+		read := ReadNFO
+		if filepath.Ext(p) == jsonExt {
+			read = ReadJSON
+		}
+
+		m, err := readSidecar(f, p, read)
+		if err != nil {
+			return nil, err
+		}
+
+		if ret == nil {
+			ret = m
+		} else {
+			ret.Merge(m)
+		}
+	}
+
+	return ret, nil
+}
@@ -42,6 +42,9 @@ type Mover struct {
 	Renamer DirMakerStatRenamer
 	Files   models.FileFinderUpdater
 	Folders models.FolderReaderWriter
+	// ReadOnlyPaths are the paths in which files may not be moved or
+	// folders created.
+	ReadOnlyPaths []string
 
 	moved          map[string]string
 	foldersCreated []string
@@ -81,8 +84,17 @@ func (m *Mover) Move(ctx context.Context, f models.File, folder *models.Folder,
 		return nil
 	}
 
-	// ensure that the new path doesn't already exist
 	newPath := filepath.Join(folder.Path, basename)
+
+	// don't allow moving files out of or into read-only paths
+	if err := checkWritable(m.ReadOnlyPaths, oldPath); err != nil {
+		return fmt.Errorf("cannot move file: %w", err)
+	}
+	if err := checkWritable(m.ReadOnlyPaths, newPath); err != nil {
+		return fmt.Errorf("cannot move file: %w", err)
+	}
+
+	// ensure that the new path doesn't already exist
 	if _, err := m.Renamer.Stat(newPath); !errors.Is(err, fs.ErrNotExist) {
 		return fmt.Errorf("file %s already exists", newPath)
 	}
@@ -108,6 +120,10 @@ func (m *Mover) CreateFolderHierarchy(path string) error {
 	info, err := m.Renamer.Stat(path)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
+			if err := checkWritable(m.ReadOnlyPaths, path); err != nil {
+				return fmt.Errorf("cannot create folder: %w", err)
+			}
+
 			// create the parent folder
 			parentPath := filepath.Dir(path)
 			if err := m.CreateFolderHierarchy(parentPath); err != nil {
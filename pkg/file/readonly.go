@@ -0,0 +1,20 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+)
+
+// ErrReadOnly is returned when deleting or moving a file in a read-only path.
+var ErrReadOnly = errors.New("path is read-only")
+
+// checkWritable returns ErrReadOnly if path is within one of readOnlyPaths.
+func checkWritable(readOnlyPaths []string, path string) error {
+	if fsutil.IsPathInDirs(readOnlyPaths, path) {
+		return fmt.Errorf("%q: %w", path, ErrReadOnly)
+	}
+
+	return nil
+}
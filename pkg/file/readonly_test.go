@@ -0,0 +1,57 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func TestReadOnlyPaths(t *testing.T) {
+	dir := t.TempDir()
+	readOnly := filepath.Join(dir, "readonly")
+	writable := filepath.Join(dir, "writable")
+	require.NoError(t, os.Mkdir(readOnly, 0755))
+	require.NoError(t, os.Mkdir(writable, 0755))
+
+	readOnlyPaths := []string{readOnly}
+
+	assert.ErrorIs(t, checkWritable(readOnlyPaths, readOnly), ErrReadOnly)
+	assert.ErrorIs(t, checkWritable(readOnlyPaths, filepath.Join(readOnly, "sub", "file.mp4")), ErrReadOnly)
+	assert.NoError(t, checkWritable(readOnlyPaths, writable))
+	assert.NoError(t, checkWritable(readOnlyPaths, readOnly+" 2"))
+
+	readOnlyFile := filepath.Join(readOnly, "file.mp4")
+	writableFile := filepath.Join(writable, "file.mp4")
+	require.NoError(t, os.WriteFile(readOnlyFile, nil, 0644))
+	require.NoError(t, os.WriteFile(writableFile, nil, 0644))
+
+	d := NewDeleter()
+	d.ReadOnlyPaths = readOnlyPaths
+	assert.ErrorIs(t, d.Files([]string{readOnlyFile}), ErrReadOnly)
+	assert.ErrorIs(t, d.Dirs([]string{readOnly}), ErrReadOnly)
+	assert.FileExists(t, readOnlyFile)
+
+	assert.NoError(t, d.Files([]string{writableFile}))
+	d.Commit()
+	assert.NoFileExists(t, writableFile)
+
+	m := NewMover(nil, nil)
+	m.ReadOnlyPaths = readOnlyPaths
+	assert.ErrorIs(t, m.CreateFolderHierarchy(filepath.Join(readOnly, "new")), ErrReadOnly)
+	assert.NoError(t, m.CreateFolderHierarchy(filepath.Join(writable, "new")))
+
+	f := &models.BaseFile{
+		Path:           readOnlyFile,
+		Basename:       "file.mp4",
+		ParentFolderID: 1,
+	}
+	err := m.Move(context.Background(), f, &models.Folder{ID: 2, Path: writable}, "")
+	assert.ErrorIs(t, err, ErrReadOnly)
+	assert.FileExists(t, readOnlyFile)
+}
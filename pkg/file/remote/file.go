@@ -0,0 +1,174 @@
+package remote
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"time"
+)
+
+var errIsDir = errors.New("is a directory")
+
+// remoteFile is a file or directory on remote storage. Reads are performed using
+// ranged requests, so that seeking does not require reading the skipped
+// content.
+type remoteFile struct {
+	backend Backend
+	name    string
+	info    fs.FileInfo
+
+	offset int64
+	reader io.ReadCloser
+
+	entries []fs.DirEntry
+	listed  bool
+}
+
+func (f *remoteFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *remoteFile) Read(p []byte) (int, error) {
+	if f.info.IsDir() {
+		return 0, &fs.PathError{Op: "read", Path: f.name, Err: errIsDir}
+	}
+
+	size := f.info.Size()
+	if f.offset >= size {
+		return 0, io.EOF
+	}
+
+	if f.reader == nil {
+		r, err := f.backend.OpenRange(f.name, f.offset, size-f.offset)
+		if err != nil {
+			return 0, err
+		}
+		f.reader = r
+	}
+
+	n, err := f.reader.Read(p)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *remoteFile) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = f.offset + offset
+	case io.SeekEnd:
+		abs = f.info.Size() + offset
+	default:
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: fs.ErrInvalid}
+	}
+
+	if abs < 0 {
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: fs.ErrInvalid}
+	}
+
+	// the next read starts a new request at the new offset
+	if abs != f.offset && f.reader != nil {
+		f.reader.Close()
+		f.reader = nil
+	}
+
+	f.offset = abs
+	return abs, nil
+}
+
+// ReadAt reads using a separate request, so that it can be used concurrently
+// with Read.
+func (f *remoteFile) ReadAt(p []byte, off int64) (int, error) {
+	size := f.info.Size()
+	if off >= size {
+		return 0, io.EOF
+	}
+
+	length := int64(len(p))
+	if off+length > size {
+		length = size - off
+	}
+
+	r, err := f.backend.OpenRange(f.name, off, length)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	n, err := io.ReadFull(r, p[:length])
+	if err == nil && length < int64(len(p)) {
+		err = io.EOF
+	}
+
+	return n, err
+}
+
+func (f *remoteFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !f.info.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: f.name, Err: errors.New("not a directory")}
+	}
+
+	if !f.listed {
+		infos, err := f.backend.ReadDir(f.name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, info := range infos {
+			f.entries = append(f.entries, fs.FileInfoToDirEntry(info))
+		}
+		f.listed = true
+	}
+
+	if n <= 0 {
+		ret := f.entries
+		f.entries = nil
+		return ret, nil
+	}
+
+	if len(f.entries) == 0 {
+		return nil, io.EOF
+	}
+
+	if n > len(f.entries) {
+		n = len(f.entries)
+	}
+
+	ret := f.entries[:n]
+	f.entries = f.entries[n:]
+	return ret, nil
+}
+
+func (f *remoteFile) Close() error {
+	if f.reader != nil {
+		err := f.reader.Close()
+		f.reader = nil
+		return err
+	}
+
+	return nil
+}
+
+// fileInfo is the info of a file on remote storage that does not report
+// the info in the form of fs.FileInfo.
+type fileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	isDir   bool
+}
+
+func (i *fileInfo) Name() string { return path.Base(i.name) }
+func (i *fileInfo) Size() int64  { return i.size }
+func (i *fileInfo) Mode() fs.FileMode {
+	if i.isDir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
+func (i *fileInfo) ModTime() time.Time { return i.modTime }
+func (i *fileInfo) IsDir() bool        { return i.isDir }
+func (i *fileInfo) Sys() interface{}   { return nil }
@@ -0,0 +1,307 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// Mount maps a local path to remote storage. Files within Path are read
+// from the remote storage instead of the local file system.
+type Mount struct {
+	Path   string
+	Config Config
+}
+
+type mount struct {
+	Mount
+
+	mutex   sync.Mutex
+	backend Backend
+	root    string
+}
+
+func (m *mount) connect() (Backend, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.backend == nil {
+		b, root, err := Connect(m.Config)
+		if err != nil {
+			return nil, fmt.Errorf("connecting to %s: %w", m.Config.URL, err)
+		}
+
+		m.backend = b
+		m.root = root
+	}
+
+	return m.backend, nil
+}
+
+func (m *mount) close() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.backend != nil {
+		if err := m.backend.Close(); err != nil {
+			logger.Warnf("error closing connection to %s: %v", m.Config.URL, err)
+		}
+		m.backend = nil
+	}
+}
+
+// remoteName returns the name on the remote storage of the local path p,
+// which must be within the mount path.
+func (m *mount) remoteName(p string) string {
+	rel, _ := filepath.Rel(m.Path, p)
+	return path.Join("/", m.root, filepath.ToSlash(rel))
+}
+
+// FS is a file system that reads paths within its mounts from remote
+// storage, and all other paths from the local file system. Connections to
+// remote storage are made when first used.
+type FS struct {
+	Local models.FS
+
+	mutex  sync.RWMutex
+	mounts []*mount
+}
+
+func NewFS(local models.FS) *FS {
+	return &FS{
+		Local: local,
+	}
+}
+
+// SetMounts replaces the mounts of the file system, closing the connections
+// of the existing mounts.
+func (f *FS) SetMounts(mounts []Mount) {
+	var newMounts []*mount
+	for _, m := range mounts {
+		newMounts = append(newMounts, &mount{Mount: m})
+	}
+
+	// match the most specific mount first
+	sort.Slice(newMounts, func(i, j int) bool {
+		return len(newMounts[i].Path) > len(newMounts[j].Path)
+	})
+
+	f.mutex.Lock()
+	old := f.mounts
+	f.mounts = newMounts
+	f.mutex.Unlock()
+
+	for _, m := range old {
+		m.close()
+	}
+}
+
+// Close closes all connections to remote storage.
+func (f *FS) Close() {
+	f.SetMounts(nil)
+}
+
+func (f *FS) findMount(p string) *mount {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	for _, m := range f.mounts {
+		if p == m.Path || strings.HasPrefix(p, strings.TrimSuffix(m.Path, string(filepath.Separator))+string(filepath.Separator)) {
+			return m
+		}
+	}
+
+	return nil
+}
+
+// IsRemote returns true if the path is on remote storage.
+func (f *FS) IsRemote(p string) bool {
+	return f.findMount(p) != nil
+}
+
+func (f *FS) resolve(p string) (Backend, string, error) {
+	m := f.findMount(p)
+	if m == nil {
+		return nil, "", nil
+	}
+
+	b, err := m.connect()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return b, m.remoteName(p), nil
+}
+
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	b, remoteName, err := f.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return f.Local.Stat(name)
+	}
+
+	return b.Stat(remoteName)
+}
+
+// Lstat is the same as Stat for remote paths, since symlinks are resolved
+// by the remote storage.
+func (f *FS) Lstat(name string) (fs.FileInfo, error) {
+	b, remoteName, err := f.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return f.Local.Lstat(name)
+	}
+
+	return b.Stat(remoteName)
+}
+
+func (f *FS) Open(name string) (fs.ReadDirFile, error) {
+	b, remoteName, err := f.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return f.Local.Open(name)
+	}
+
+	return openFile(b, remoteName)
+}
+
+func openFile(b Backend, name string) (*remoteFile, error) {
+	info, err := b.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remoteFile{
+		backend: b,
+		name:    name,
+		info:    info,
+	}, nil
+}
+
+func (f *FS) OpenZip(name string) (models.ZipFS, error) {
+	if !f.IsRemote(name) {
+		return f.Local.OpenZip(name)
+	}
+
+	return file.OpenZipFS(f, name)
+}
+
+// IsPathCaseSensitive returns true for remote paths, since all of the
+// supported remote storage is case sensitive.
+func (f *FS) IsPathCaseSensitive(p string) (bool, error) {
+	if f.IsRemote(p) {
+		return true, nil
+	}
+
+	return f.Local.IsPathCaseSensitive(p)
+}
+
+// IsArchived returns true if p is a file within an archive.
+func (f *FS) IsArchived(p string) bool {
+	archivePath, err := file.GetArchivePath(f, p)
+	return err == nil && archivePath != ""
+}
+
+// OpenFile opens the file at p for reading. p may be on remote storage or
+// within an archive. The returned reader implements io.ReadSeeker if the
+// file can be seeked.
+func (f *FS) OpenFile(p string) (io.ReadCloser, fs.FileInfo, error) {
+	archivePath, err := file.GetArchivePath(f, p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if archivePath == "" {
+		rf, err := f.Open(p)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		info, err := rf.Stat()
+		if err != nil {
+			rf.Close()
+			return nil, nil, err
+		}
+
+		return rf, info, nil
+	}
+
+	zfs, err := f.OpenZip(archivePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, err := zfs.Stat(p)
+	if err != nil {
+		zfs.Close()
+		return nil, nil, err
+	}
+
+	// closes the archive as well
+	r, err := zfs.OpenOnly(p)
+	if err != nil {
+		zfs.Close()
+		return nil, nil, err
+	}
+
+	return r, info, nil
+}
+
+// ServeFile serves the file at p, which may be on remote storage or within
+// an archive. Range requests are supported if the file can be seeked.
+func (f *FS) ServeFile(w http.ResponseWriter, r *http.Request, p string) {
+	rf, info, err := f.OpenFile(p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			http.NotFound(w, r)
+			return
+		}
+
+		logger.Errorf("error serving %s: %v", p, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	defer rf.Close()
+
+	if info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	if rs, ok := rf.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, info.Name(), info.ModTime(), rs)
+		return
+	}
+
+	// compressed archive entries can only be read from the start
+	if ctype := mime.TypeByExtension(filepath.Ext(info.Name())); ctype != "" {
+		w.Header().Set("Content-Type", ctype)
+	}
+	w.Header().Set("Content-Length", strconv.FormatInt(info.Size(), 10))
+	w.WriteHeader(http.StatusOK)
+
+	if r.Method != http.MethodHead {
+		if _, err := io.Copy(w, rf); err != nil {
+			logger.Debugf("error serving %s: %v", p, err)
+		}
+	}
+}
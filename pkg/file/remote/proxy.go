@@ -0,0 +1,146 @@
+package remote
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// Proxy serves files on remote storage and within archives over http on the
+// loopback interface, so that they can be read by external tools such as
+// ffmpeg. Only archives within the library paths are served. Requests must
+// include a random token, which is generated when the proxy is started.
+type Proxy struct {
+	FS *FS
+
+	libraryPaths []string
+	mutex        sync.RWMutex
+
+	server   *http.Server
+	listener net.Listener
+	token    string
+}
+
+func NewProxy(f *FS) *Proxy {
+	return &Proxy{
+		FS: f,
+	}
+}
+
+// Start starts serving on a random port of the loopback interface.
+func (p *Proxy) Start() error {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return fmt.Errorf("generating proxy token: %w", err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("starting remote storage proxy: %w", err)
+	}
+
+	p.token = hex.EncodeToString(token)
+	p.listener = l
+	p.server = &http.Server{
+		Handler: p,
+	}
+
+	go func() {
+		if err := p.server.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Errorf("remote storage proxy error: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// Stop stops the proxy.
+func (p *Proxy) Stop() error {
+	if p.server == nil {
+		return nil
+	}
+
+	err := p.server.Shutdown(context.Background())
+	p.server = nil
+	return err
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if p.token == "" || token != p.token {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	// only serve remote files and archived files in the library, so that the
+	// proxy cannot be used to read arbitrary local files
+	path := r.URL.Query().Get("path")
+	if !p.IsRemote(path) {
+		http.NotFound(w, r)
+		return
+	}
+
+	p.FS.ServeFile(w, r, path)
+}
+
+// SetLibraryPaths sets the library paths. Only archives within these paths
+// are served.
+func (p *Proxy) SetLibraryPaths(paths []string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.libraryPaths = paths
+}
+
+func (p *Proxy) inLibrary(path string) bool {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return fsutil.IsPathInDirs(p.libraryPaths, path)
+}
+
+// IsRemote returns true if the path is on remote storage, or within an
+// archive in a library path.
+func (p *Proxy) IsRemote(path string) bool {
+	return p.FS.IsRemote(path) || (p.inLibrary(path) && p.FS.IsArchived(path))
+}
+
+// URL returns the proxy URL of the remote path. The basename is included
+// in the URL path, so that tools can detect the file type from the
+// extension.
+func (p *Proxy) URL(path string) string {
+	return fmt.Sprintf("http://%s/%s/%s?path=%s", p.listener.Addr().String(), p.token, url.PathEscape(filepath.Base(path)), url.QueryEscape(path))
+}
+
+func (p *Proxy) Stat(path string) (fs.FileInfo, error) {
+	r, info, err := p.FS.OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r.Close()
+
+	return info, nil
+}
+
+func (p *Proxy) Open(path string) (io.ReadCloser, error) {
+	r, _, err := p.FS.OpenFile(path)
+	return r, err
+}
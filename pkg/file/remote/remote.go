@@ -0,0 +1,115 @@
+// Package remote provides access to library paths on remote storage, such as
+// SFTP servers, WebDAV servers and S3 buckets.
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const (
+	SchemeSFTP    = "sftp"
+	SchemeWebDAV  = "webdav"
+	SchemeWebDAVS = "webdavs"
+	SchemeS3      = "s3"
+)
+
+var ErrUnsupportedScheme = errors.New("unsupported remote storage scheme")
+
+// Config is the configuration of the remote storage of a library path.
+type Config struct {
+	// URL of the root of the library. Supported schemes are sftp, webdav,
+	// webdavs (WebDAV over https) and s3. S3 URLs have the form
+	// s3://endpoint/bucket/prefix.
+	URL string `json:"url"`
+	// Username is the access key for S3.
+	Username string `json:"username"`
+	// Password is the secret key for S3.
+	Password string `json:"password"`
+	// KeyFile is the path to the private key used to authenticate with SFTP servers.
+	KeyFile string `json:"keyFile"`
+	// HostKey is the public key of the SFTP server, in authorized_keys format.
+	// The user's known_hosts file is used if not set.
+	HostKey string `json:"hostKey"`
+	// DisableTLS connects to S3 endpoints using http.
+	DisableTLS bool `json:"disableTLS"`
+}
+
+// HasPassword returns true if the password is set.
+func (c Config) HasPassword() bool {
+	return c.Password != ""
+}
+
+// Validate returns an error if the URL of the configuration is invalid.
+func (c Config) Validate() error {
+	_, err := c.parseURL()
+	return err
+}
+
+func (c Config) parseURL() (*url.URL, error) {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote storage URL %q: %w", c.URL, err)
+	}
+
+	switch u.Scheme {
+	case SchemeSFTP, SchemeWebDAV, SchemeWebDAVS, SchemeS3:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedScheme, u.Scheme)
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid remote storage URL %q: host is required", c.URL)
+	}
+
+	return u, nil
+}
+
+// Backend is a connection to remote storage. Names are slash-separated
+// paths on the remote storage.
+type Backend interface {
+	Stat(name string) (fs.FileInfo, error)
+	ReadDir(name string) ([]fs.FileInfo, error)
+	// OpenRange returns a reader of length bytes of the file, starting at offset.
+	OpenRange(name string, offset, length int64) (io.ReadCloser, error)
+	Close() error
+}
+
+// Connect connects to the remote storage of the configuration. It returns
+// the backend and the path of the library root on the remote storage.
+func Connect(c Config) (Backend, string, error) {
+	u, err := c.parseURL()
+	if err != nil {
+		return nil, "", err
+	}
+
+	root := path.Clean("/" + u.Path)
+
+	switch u.Scheme {
+	case SchemeSFTP:
+		b, err := newSFTPBackend(c, u)
+		return b, root, err
+	case SchemeWebDAV, SchemeWebDAVS:
+		return newWebDAVBackend(c, u), root, nil
+	case SchemeS3:
+		// the first path element is the bucket
+		bucket, prefix, _ := strings.Cut(strings.TrimPrefix(root, "/"), "/")
+		if bucket == "" {
+			return nil, "", fmt.Errorf("invalid remote storage URL %q: bucket is required", c.URL)
+		}
+
+		b, err := newS3Backend(c, u.Host, bucket)
+		return b, prefix, err
+	}
+
+	return nil, "", fmt.Errorf("%w: %q", ErrUnsupportedScheme, u.Scheme)
+}
+
+func notExist(op string, name string) error {
+	return &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+}
@@ -0,0 +1,347 @@
+package remote
+
+import (
+	"archive/zip"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/webdav"
+
+	"github.com/stashapp/stash/pkg/file"
+)
+
+const testContent = "0123456789"
+
+// makeTestTree creates a folder containing a file and a sub-folder, and
+// returns its path.
+func makeTestTree(t *testing.T) string {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.mp4"), []byte(testContent), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "empty.jpg"), nil, 0644))
+
+	return dir
+}
+
+// newTestSFTPBackend returns a backend connected to an in-process sftp
+// server that serves the local file system.
+func newTestSFTPBackend(t *testing.T) *sftpBackend {
+	b := &sftpBackend{
+		connect: func() (*sftp.Client, io.Closer, error) {
+			serverReader, clientWriter := io.Pipe()
+			clientReader, serverWriter := io.Pipe()
+
+			server, err := sftp.NewServer(struct {
+				io.Reader
+				io.WriteCloser
+			}{serverReader, serverWriter})
+			if err != nil {
+				return nil, nil, err
+			}
+			go func() {
+				_ = server.Serve()
+			}()
+
+			client, err := sftp.NewClientPipe(clientReader, clientWriter)
+			if err != nil {
+				server.Close()
+				return nil, nil, err
+			}
+
+			return client, server, nil
+		},
+	}
+	t.Cleanup(func() {
+		b.Close()
+	})
+
+	return b
+}
+
+func newTestWebDAVBackend(t *testing.T, dir string) *webDAVBackend {
+	server := httptest.NewServer(&webdav.Handler{
+		FileSystem: webdav.Dir(dir),
+		LockSystem: webdav.NewMemLS(),
+	})
+	t.Cleanup(server.Close)
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	u.Scheme = SchemeWebDAV
+
+	return newWebDAVBackend(Config{}, u)
+}
+
+func testBackend(t *testing.T, b Backend, root string) {
+	name := func(p string) string {
+		return filepath.ToSlash(filepath.Join(root, p))
+	}
+
+	info, err := b.Stat(name("file.mp4"))
+	require.NoError(t, err)
+	assert.False(t, info.IsDir())
+	assert.Equal(t, int64(len(testContent)), info.Size())
+	assert.Equal(t, "file.mp4", info.Name())
+
+	info, err = b.Stat(name("sub"))
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+
+	_, err = b.Stat(name("missing"))
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	infos, err := b.ReadDir(root)
+	require.NoError(t, err)
+
+	var names []string
+	for _, i := range infos {
+		names = append(names, i.Name())
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"file.mp4", "sub"}, names)
+
+	r, err := b.OpenRange(name("file.mp4"), 2, 5)
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	r.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "23456", string(data))
+}
+
+func TestSFTPBackend(t *testing.T) {
+	dir := makeTestTree(t)
+	testBackend(t, newTestSFTPBackend(t), dir)
+}
+
+func TestSFTPBackendReconnect(t *testing.T) {
+	dir := makeTestTree(t)
+	b := newTestSFTPBackend(t)
+
+	_, err := b.Stat(filepath.ToSlash(dir))
+	require.NoError(t, err)
+
+	// drop the connection without the backend knowing
+	b.conn.Close()
+
+	_, err = b.Stat(filepath.ToSlash(dir))
+	assert.NoError(t, err)
+}
+
+func TestWebDAVBackend(t *testing.T) {
+	dir := makeTestTree(t)
+	testBackend(t, newTestWebDAVBackend(t, dir), "/")
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"sftp://host/path", false},
+		{"webdav://host:8080/path", false},
+		{"webdavs://host/path", false},
+		{"s3://host/bucket/prefix", false},
+		{"ftp://host/path", true},
+		{"sftp:///path", true},
+		{"/local/path", true},
+	}
+
+	for _, tt := range tests {
+		err := Config{URL: tt.url}.Validate()
+		assert.Equal(t, tt.wantErr, err != nil, tt.url)
+	}
+}
+
+// newTestFS returns an FS with mountPath mounted to dir through the sftp
+// test server.
+func newTestFS(t *testing.T, mountPath string, dir string) *FS {
+	f := NewFS(&file.OsFS{})
+	f.mounts = []*mount{
+		{
+			Mount:   Mount{Path: mountPath},
+			backend: newTestSFTPBackend(t),
+			root:    filepath.ToSlash(dir),
+		},
+	}
+
+	return f
+}
+
+func TestFS(t *testing.T) {
+	dir := makeTestTree(t)
+	mountPath := filepath.Join(t.TempDir(), "remote")
+	f := newTestFS(t, mountPath, dir)
+
+	assert.True(t, f.IsRemote(mountPath))
+	assert.True(t, f.IsRemote(filepath.Join(mountPath, "file.mp4")))
+	assert.False(t, f.IsRemote(mountPath+" 2"))
+	assert.False(t, f.IsRemote(dir))
+
+	// local paths are read from the local file system
+	info, err := f.Stat(filepath.Join(dir, "file.mp4"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(testContent)), info.Size())
+
+	d, err := f.Open(mountPath)
+	require.NoError(t, err)
+	entries, err := d.ReadDir(-1)
+	d.Close()
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	rf, err := f.Open(filepath.Join(mountPath, "file.mp4"))
+	require.NoError(t, err)
+	defer rf.Close()
+
+	buf := make([]byte, 3)
+	_, err = io.ReadFull(rf, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "012", string(buf))
+
+	_, err = rf.(io.Seeker).Seek(-2, io.SeekEnd)
+	require.NoError(t, err)
+	rest, err := io.ReadAll(rf)
+	require.NoError(t, err)
+	assert.Equal(t, "89", string(rest))
+
+	n, err := rf.(io.ReaderAt).ReadAt(buf, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, "456", string(buf))
+
+	// reading past the end returns what is available
+	n, err = rf.(io.ReaderAt).ReadAt(buf, 8)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, "89", string(buf[:n]))
+
+	// empty files are read without requests
+	ef, err := f.Open(filepath.Join(mountPath, "sub", "empty.jpg"))
+	require.NoError(t, err)
+	data, err := io.ReadAll(ef)
+	ef.Close()
+	require.NoError(t, err)
+	assert.Empty(t, data)
+}
+
+func TestProxy(t *testing.T) {
+	dir := makeTestTree(t)
+	mountPath := filepath.Join(t.TempDir(), "remote")
+	f := newTestFS(t, mountPath, dir)
+
+	p := NewProxy(f)
+	require.NoError(t, p.Start())
+	defer p.Stop()
+
+	get := func(u string, rangeHeader string) (int, string) {
+		req, err := http.NewRequest(http.MethodGet, u, nil)
+		require.NoError(t, err)
+		if rangeHeader != "" {
+			req.Header.Set("Range", rangeHeader)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	remotePath := filepath.Join(mountPath, "file.mp4")
+	status, body := get(p.URL(remotePath), "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, testContent, body)
+
+	status, body = get(p.URL(remotePath), "bytes=3-5")
+	assert.Equal(t, http.StatusPartialContent, status)
+	assert.Equal(t, "345", body)
+
+	// local files are not served
+	localPath := filepath.Join(dir, "file.mp4")
+	status, _ = get(p.URL(localPath), "")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	// requests without the token are rejected
+	u, err := url.Parse(p.URL(remotePath))
+	require.NoError(t, err)
+	u.Path = "/wrong/file.mp4"
+	status, _ = get(u.String(), "")
+	assert.Equal(t, http.StatusForbidden, status)
+}
+
+func TestProxyArchive(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "videos.zip")
+
+	out, err := os.Create(archivePath)
+	require.NoError(t, err)
+	zw := zip.NewWriter(out)
+	for _, h := range []*zip.FileHeader{
+		{Name: "stored.mp4", Method: zip.Store},
+		{Name: "deflated.mp4", Method: zip.Deflate},
+	} {
+		w, err := zw.CreateHeader(h)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(testContent))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, out.Close())
+
+	f := NewFS(&file.OsFS{})
+	p := NewProxy(f)
+	p.SetLibraryPaths([]string{dir})
+	require.NoError(t, p.Start())
+	defer p.Stop()
+
+	get := func(path string, rangeHeader string) (int, string) {
+		req, err := http.NewRequest(http.MethodGet, p.URL(path), nil)
+		require.NoError(t, err)
+		req.Header.Set("Range", rangeHeader)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	stored := filepath.Join(archivePath, "stored.mp4")
+	assert.True(t, p.IsRemote(stored))
+
+	status, body := get(stored, "bytes=3-5")
+	assert.Equal(t, http.StatusPartialContent, status)
+	assert.Equal(t, "345", body)
+
+	// compressed entries are served in full
+	status, body = get(filepath.Join(archivePath, "deflated.mp4"), "bytes=3-5")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, testContent, body)
+
+	info, err := p.Stat(stored)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(testContent)), info.Size())
+
+	// the archive itself is a local file
+	assert.False(t, p.IsRemote(archivePath))
+
+	// archives outside of the library paths are not served
+	p.SetLibraryPaths([]string{filepath.Join(dir, "library")})
+	assert.False(t, p.IsRemote(stored))
+	status, _ = get(stored, "")
+	assert.Equal(t, http.StatusNotFound, status)
+}
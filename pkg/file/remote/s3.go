@@ -0,0 +1,145 @@
+package remote
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// s3Backend reads objects from an S3 bucket. Folders are the common
+// prefixes of the object keys.
+type s3Backend struct {
+	client *minio.Client
+	bucket string
+}
+
+func newS3Backend(c Config, endpoint string, bucket string) (*s3Backend, error) {
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(c.Username, c.Password, ""),
+		Secure: !c.DisableTLS,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3Backend{
+		client: client,
+		bucket: bucket,
+	}, nil
+}
+
+func s3Key(name string) string {
+	return strings.Trim(name, "/")
+}
+
+func isS3NotFound(err error) bool {
+	resp := minio.ToErrorResponse(err)
+	return resp.StatusCode == http.StatusNotFound || resp.Code == "NoSuchKey"
+}
+
+func (b *s3Backend) Stat(name string) (fs.FileInfo, error) {
+	ctx := context.Background()
+	key := s3Key(name)
+
+	if key == "" {
+		return &fileInfo{name: name, isDir: true}, nil
+	}
+
+	obj, err := b.client.StatObject(ctx, b.bucket, key, minio.StatObjectOptions{})
+	if err == nil {
+		return &fileInfo{
+			name:    name,
+			size:    obj.Size,
+			modTime: obj.LastModified,
+		}, nil
+	}
+
+	if !isS3NotFound(err) {
+		return nil, err
+	}
+
+	// a folder exists if there are objects with its prefix
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for obj := range b.client.ListObjects(ctx, b.bucket, minio.ListObjectsOptions{
+		Prefix:  key + "/",
+		MaxKeys: 1,
+	}) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+
+		return &fileInfo{name: name, isDir: true}, nil
+	}
+
+	return nil, notExist("stat", name)
+}
+
+func (b *s3Backend) ReadDir(name string) ([]fs.FileInfo, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	prefix := s3Key(name)
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	var ret []fs.FileInfo
+	for obj := range b.client.ListObjects(ctx, b.bucket, minio.ListObjectsOptions{
+		Prefix: prefix,
+	}) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+
+		// skip folder marker objects
+		if obj.Key == prefix {
+			continue
+		}
+
+		childName := "/" + strings.TrimSuffix(obj.Key, "/")
+		if strings.HasSuffix(obj.Key, "/") {
+			ret = append(ret, &fileInfo{name: childName, isDir: true})
+			continue
+		}
+
+		ret = append(ret, &fileInfo{
+			name:    childName,
+			size:    obj.Size,
+			modTime: obj.LastModified,
+		})
+	}
+
+	return ret, nil
+}
+
+func (b *s3Backend) OpenRange(name string, offset, length int64) (io.ReadCloser, error) {
+	if length <= 0 {
+		return io.NopCloser(eofReader{}), nil
+	}
+
+	opts := minio.GetObjectOptions{}
+	if err := opts.SetRange(offset, offset+length-1); err != nil {
+		return nil, err
+	}
+
+	obj, err := b.client.GetObject(context.Background(), b.bucket, s3Key(name), opts)
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, notExist("open", name)
+		}
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+func (b *s3Backend) Close() error {
+	return nil
+}
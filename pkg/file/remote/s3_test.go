@@ -0,0 +1,195 @@
+package remote
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testBucket = "library"
+
+type fakeS3Object struct {
+	data    []byte
+	modTime time.Time
+}
+
+// fakeS3 is a minimal S3 server that implements the requests used by the
+// S3 backend: bucket location, ListObjectsV2, HeadObject and ranged
+// GetObject. Authentication is not checked.
+type fakeS3 struct {
+	objects map[string]fakeS3Object
+}
+
+// newFakeS3 returns a fake S3 server with an object for each file in dir.
+func newFakeS3(t *testing.T, dir string) *fakeS3 {
+	ret := &fakeS3{objects: make(map[string]fakeS3Object)}
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		ret.objects[filepath.ToSlash(rel)] = fakeS3Object{
+			data:    data,
+			modTime: info.ModTime(),
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	return ret
+}
+
+type fakeS3ListContents struct {
+	Key          string
+	LastModified string
+	ETag         string
+	Size         int64
+}
+
+type fakeS3CommonPrefix struct {
+	Prefix string
+}
+
+type fakeS3ListResult struct {
+	XMLName        xml.Name `xml:"ListBucketResult"`
+	Name           string
+	Prefix         string
+	Delimiter      string
+	MaxKeys        int
+	KeyCount       int
+	IsTruncated    bool
+	Contents       []fakeS3ListContents
+	CommonPrefixes []fakeS3CommonPrefix
+}
+
+type fakeS3Error struct {
+	XMLName xml.Name `xml:"Error"`
+	Code    string
+	Message string
+}
+
+func (s *fakeS3) writeXML(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_ = xml.NewEncoder(w).Encode(v)
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != testBucket {
+		s.writeXML(w, http.StatusNotFound, fakeS3Error{Code: "NoSuchBucket"})
+		return
+	}
+
+	query := r.URL.Query()
+	switch {
+	case key == "" && query.Has("location"):
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+	case key == "":
+		s.list(w, query)
+	default:
+		s.serveObject(w, r, key)
+	}
+}
+
+func (s *fakeS3) list(w http.ResponseWriter, query url.Values) {
+	prefix := query.Get("prefix")
+	delimiter := query.Get("delimiter")
+
+	ret := fakeS3ListResult{
+		Name:      testBucket,
+		Prefix:    prefix,
+		Delimiter: delimiter,
+		MaxKeys:   1000,
+	}
+
+	var keys []string
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	seenPrefixes := make(map[string]bool)
+	for _, key := range keys {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		rest := strings.TrimPrefix(key, prefix)
+		if i := strings.Index(rest, delimiter); delimiter != "" && i >= 0 {
+			common := prefix + rest[:i+len(delimiter)]
+			if !seenPrefixes[common] {
+				seenPrefixes[common] = true
+				ret.CommonPrefixes = append(ret.CommonPrefixes, fakeS3CommonPrefix{Prefix: common})
+			}
+			continue
+		}
+
+		obj := s.objects[key]
+		ret.Contents = append(ret.Contents, fakeS3ListContents{
+			Key:          key,
+			LastModified: obj.modTime.UTC().Format(time.RFC3339),
+			ETag:         `"etag"`,
+			Size:         int64(len(obj.data)),
+		})
+	}
+
+	ret.KeyCount = len(ret.Contents) + len(ret.CommonPrefixes)
+	s.writeXML(w, http.StatusOK, ret)
+}
+
+func (s *fakeS3) serveObject(w http.ResponseWriter, r *http.Request, key string) {
+	obj, found := s.objects[key]
+	if !found {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		s.writeXML(w, http.StatusNotFound, fakeS3Error{Code: "NoSuchKey", Message: key})
+		return
+	}
+
+	w.Header().Set("ETag", `"etag"`)
+	http.ServeContent(w, r, key, obj.modTime, bytes.NewReader(obj.data))
+}
+
+func TestS3Backend(t *testing.T) {
+	dir := makeTestTree(t)
+
+	server := httptest.NewServer(newFakeS3(t, dir))
+	t.Cleanup(server.Close)
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	b, err := newS3Backend(Config{
+		Username:   "access",
+		Password:   "secret",
+		DisableTLS: true,
+	}, u.Host, testBucket)
+	require.NoError(t, err)
+
+	testBackend(t, b, "/")
+}
@@ -0,0 +1,261 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	sftpDefaultPort = "22"
+	sftpDialTimeout = 30 * time.Second
+)
+
+// sftpConnectFunc returns a new sftp client, and the closer of its underlying
+// connection.
+type sftpConnectFunc func() (*sftp.Client, io.Closer, error)
+
+type sftpBackend struct {
+	connect sftpConnectFunc
+
+	mutex  sync.Mutex
+	client *sftp.Client
+	conn   io.Closer
+}
+
+func newSFTPBackend(c Config, u *url.URL) (*sftpBackend, error) {
+	sshConfig, err := sshClientConfig(c, u)
+	if err != nil {
+		return nil, err
+	}
+
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), sftpDefaultPort)
+	}
+
+	b := &sftpBackend{
+		connect: func() (*sftp.Client, io.Closer, error) {
+			conn, err := ssh.Dial("tcp", addr, sshConfig)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			client, err := sftp.NewClient(conn)
+			if err != nil {
+				conn.Close()
+				return nil, nil, err
+			}
+
+			return client, conn, nil
+		},
+	}
+
+	// connect immediately so that configuration errors are reported early
+	if _, err := b.getClient(); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func sshClientConfig(c Config, u *url.URL) (*ssh.ClientConfig, error) {
+	username := c.Username
+	if username == "" {
+		username = u.User.Username()
+	}
+
+	password := c.Password
+	if p, set := u.User.Password(); password == "" && set {
+		password = p
+	}
+
+	var auth []ssh.AuthMethod
+	if c.KeyFile != "" {
+		key, err := os.ReadFile(c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading key file: %w", err)
+		}
+
+		var signer ssh.Signer
+		if password != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase(key, []byte(password))
+		} else {
+			signer, err = ssh.ParsePrivateKey(key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing key file: %w", err)
+		}
+
+		auth = append(auth, ssh.PublicKeys(signer))
+	} else if password != "" {
+		auth = append(auth, ssh.Password(password))
+	}
+
+	hostKeyCallback, err := sshHostKeyCallback(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ssh.ClientConfig{
+		User:            username,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         sftpDialTimeout,
+	}, nil
+}
+
+func sshHostKeyCallback(c Config) (ssh.HostKeyCallback, error) {
+	if c.HostKey != "" {
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(c.HostKey))
+		if err != nil {
+			return nil, fmt.Errorf("parsing host key: %w", err)
+		}
+
+		return ssh.FixedHostKey(key), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("host key is required: %w", err)
+	}
+
+	// never accept unknown hosts
+	cb, err := knownhosts.New(filepath.Join(home, ".ssh", "known_hosts"))
+	if err != nil {
+		return nil, fmt.Errorf("host key is required if the host is not in known_hosts: %w", err)
+	}
+
+	return cb, nil
+}
+
+func (b *sftpBackend) getClient() (*sftp.Client, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.client == nil {
+		client, conn, err := b.connect()
+		if err != nil {
+			return nil, err
+		}
+
+		b.client = client
+		b.conn = conn
+	}
+
+	return b.client, nil
+}
+
+// reset closes the client if it is the current client, so that the next
+// request reconnects.
+func (b *sftpBackend) reset(client *sftp.Client) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.client == client {
+		b.closeClient()
+	}
+}
+
+func (b *sftpBackend) closeClient() error {
+	if b.client == nil {
+		return nil
+	}
+
+	// closing the connection first ensures that closing the client does
+	// not wait on a broken connection
+	err := b.conn.Close()
+	b.client.Close()
+	b.client = nil
+	b.conn = nil
+	return err
+}
+
+// do calls fn with the client, reconnecting and retrying once if the
+// connection was lost.
+func (b *sftpBackend) do(fn func(client *sftp.Client) error) error {
+	client, err := b.getClient()
+	if err != nil {
+		return err
+	}
+
+	err = fn(client)
+	if !errors.Is(err, sftp.ErrSSHFxConnectionLost) {
+		return err
+	}
+
+	b.reset(client)
+	client, err = b.getClient()
+	if err != nil {
+		return err
+	}
+
+	return fn(client)
+}
+
+func (b *sftpBackend) Stat(name string) (fs.FileInfo, error) {
+	var ret fs.FileInfo
+	err := b.do(func(client *sftp.Client) error {
+		var err error
+		ret, err = client.Stat(name)
+		return err
+	})
+
+	return ret, err
+}
+
+func (b *sftpBackend) ReadDir(name string) ([]fs.FileInfo, error) {
+	var ret []fs.FileInfo
+	err := b.do(func(client *sftp.Client) error {
+		var err error
+		ret, err = client.ReadDir(name)
+		return err
+	})
+
+	return ret, err
+}
+
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func (b *sftpBackend) OpenRange(name string, offset, length int64) (io.ReadCloser, error) {
+	var f *sftp.File
+	err := b.do(func(client *sftp.Client) error {
+		var err error
+		f, err = client.Open(name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return limitedReadCloser{
+		Reader: io.LimitReader(f, length),
+		Closer: f,
+	}, nil
+}
+
+func (b *sftpBackend) Close() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.closeClient()
+}
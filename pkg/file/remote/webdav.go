@@ -0,0 +1,83 @@
+package remote
+
+import (
+	"io"
+	"io/fs"
+	"net/url"
+
+	"github.com/studio-b12/gowebdav"
+)
+
+type webDAVBackend struct {
+	client *gowebdav.Client
+}
+
+func newWebDAVBackend(c Config, u *url.URL) *webDAVBackend {
+	scheme := "http"
+	if u.Scheme == SchemeWebDAVS {
+		scheme = "https"
+	}
+
+	username := c.Username
+	if username == "" {
+		username = u.User.Username()
+	}
+
+	password := c.Password
+	if p, set := u.User.Password(); password == "" && set {
+		password = p
+	}
+
+	// names include the root path, so the client is rooted at the host
+	root := url.URL{
+		Scheme: scheme,
+		Host:   u.Host,
+	}
+
+	return &webDAVBackend{
+		client: gowebdav.NewClient(root.String(), username, password),
+	}
+}
+
+func (b *webDAVBackend) Stat(name string) (fs.FileInfo, error) {
+	ret, err := b.client.Stat(name)
+	if gowebdav.IsErrNotFound(err) {
+		return nil, notExist("stat", name)
+	}
+
+	return ret, err
+}
+
+func (b *webDAVBackend) ReadDir(name string) ([]fs.FileInfo, error) {
+	ret, err := b.client.ReadDir(name)
+	if gowebdav.IsErrNotFound(err) {
+		return nil, notExist("readdir", name)
+	}
+
+	return ret, err
+}
+
+func (b *webDAVBackend) OpenRange(name string, offset, length int64) (io.ReadCloser, error) {
+	// a length of zero reads nothing if the server does not support
+	// ranged requests
+	if length <= 0 {
+		return io.NopCloser(eofReader{}), nil
+	}
+
+	ret, err := b.client.ReadStreamRange(name, offset, length)
+	if gowebdav.IsErrNotFound(err) {
+		return nil, notExist("open", name)
+	}
+
+	return ret, err
+}
+
+func (b *webDAVBackend) Close() error {
+	return nil
+}
+
+type eofReader struct{}
+
+func (eofReader) Read([]byte) (int, error) {
+	return 0, io.EOF
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/remeh/sizedwaitgroup"
+	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/txn"
@@ -116,11 +117,14 @@ type scanJob struct {
 	zipPathToID    sync.Map
 	count          int
 
+	folderState incrementalScanState
+
 	txnRetryer txn.Retryer
 }
 
 // ScanOptions provides options for scanning files.
 type ScanOptions struct {
+	// Paths are scanned in order.
 	Paths []string
 
 	// ZipFileExtensions is a list of file extensions that are considered zip files.
@@ -133,11 +137,28 @@ type ScanOptions struct {
 	// HandlerRequiredFilters are used to determine if an unchanged file needs to be handled
 	HandlerRequiredFilters []Filter
 
+	// ChangedFilters are used to determine if a file with an unchanged
+	// modification time should be rescanned as an updated file, for
+	// example because a related file has changed. Existing fingerprints
+	// are kept for these files.
+	ChangedFilters []Filter
+
 	ParallelTasks int
+
+	// FullRescan checks every file, rather than skipping the files of folders
+	// whose modification time and number of entries are unchanged since the
+	// last scan.
+	FullRescan bool
+}
+
+// ScanResult contains the results of a scan.
+type ScanResult struct {
+	// SkippedFolders is the number of unchanged folders whose files were not read.
+	SkippedFolders int `json:"skippedFolders"`
 }
 
 // Scan starts the scanning process.
-func (s *Scanner) Scan(ctx context.Context, handlers []Handler, options ScanOptions, progressReporter ProgressReporter) {
+func (s *Scanner) Scan(ctx context.Context, handlers []Handler, options ScanOptions, progressReporter ProgressReporter) ScanResult {
 	job := &scanJob{
 		Scanner:         s,
 		handlers:        handlers,
@@ -150,12 +171,24 @@ func (s *Scanner) Scan(ctx context.Context, handlers []Handler, options ScanOpti
 	}
 
 	job.execute(ctx)
+
+	return ScanResult{
+		SkippedFolders: job.folderState.skipped,
+	}
 }
 
 type scanFile struct {
 	*models.BaseFile
 	fs   models.FS
 	info fs.FileInfo
+
+	// childCount is the number of entries in a folder, or nil if unknown
+	childCount *int
+
+	// inUnchangedFolder is true if the file is in an unchanged folder. The
+	// file is read from the data store and set in existing.
+	inUnchangedFolder bool
+	existing          models.File
 }
 
 func (s *scanJob) withTxn(ctx context.Context, fn func(ctx context.Context) error) error {
@@ -175,23 +208,27 @@ func (s *scanJob) execute(ctx context.Context) {
 	var wg sync.WaitGroup
 	wg.Add(1)
 
+	var queueErr error
 	go func() {
 		defer wg.Done()
-		if err := s.queueFiles(ctx, paths); err != nil {
-			if errors.Is(err, context.Canceled) {
+		if queueErr = s.queueFiles(ctx, paths); queueErr != nil {
+			if errors.Is(queueErr, context.Canceled) {
 				return
 			}
 
-			logger.Errorf("error queuing files for scan: %v", err)
+			logger.Errorf("error queuing files for scan: %v", queueErr)
 			return
 		}
 
 		logger.Infof("Finished adding files to queue. %d files queued", s.count)
+		if s.folderState.skipped > 0 {
+			logger.Infof("Skipped reading files of %d unchanged folders", s.folderState.skipped)
+		}
 	}()
 
-	defer wg.Wait()
-
 	if err := s.processQueue(ctx); err != nil {
+		wg.Wait()
+
 		if errors.Is(err, context.Canceled) {
 			return
 		}
@@ -199,6 +236,21 @@ func (s *scanJob) execute(ctx context.Context) {
 		logger.Errorf("error scanning files: %v", err)
 		return
 	}
+
+	wg.Wait()
+
+	// only mark folders as scanned if all of their files were scanned
+	if queueErr != nil || ctx.Err() != nil {
+		return
+	}
+
+	if err := s.setFolderChildCounts(ctx); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+
+		logger.Errorf("error setting folder child counts: %v", err)
+	}
 }
 
 func (s *scanJob) queueFiles(ctx context.Context, paths []string) error {
@@ -234,6 +286,28 @@ func (s *scanJob) queueFileFunc(ctx context.Context, f models.FS, zipFile *scanF
 			return err
 		}
 
+		// queue the files of unchanged folders using their stored info, rather
+		// than reading it
+		if zipFile == nil && !d.IsDir() {
+			existing, unchanged := s.folderState.takeUnchangedFile(path)
+			if unchanged && existing != nil {
+				if !s.acceptEntry(ctx, path, storedFileInfo{existing.Base()}) {
+					return nil
+				}
+
+				s.fileQueue <- scanFile{
+					BaseFile:          existing.Base(),
+					fs:                f,
+					existing:          existing,
+					inUnchangedFolder: true,
+				}
+
+				s.count++
+
+				return nil
+			}
+		}
+
 		info, err := d.Info()
 		if err != nil {
 			return fmt.Errorf("reading info for %q: %w", path, err)
@@ -275,6 +349,10 @@ func (s *scanJob) queueFileFunc(ctx context.Context, f models.FS, zipFile *scanF
 		}
 
 		if info.IsDir() {
+			if zipFile == nil {
+				ff.childCount = dirEntryCount(d)
+			}
+
 			// handle folders immediately
 			if err := s.handleFolder(ctx, ff); err != nil {
 				if !errors.Is(err, context.Canceled) {
@@ -295,6 +373,7 @@ func (s *scanJob) queueFileFunc(ctx context.Context, f models.FS, zipFile *scanF
 					if !errors.Is(err, context.Canceled) {
 						logger.Errorf("error processing %q: %v", path, err)
 					}
+					s.folderState.setFailed(ff)
 					// don't return an error, just skip the file
 				}
 			})
@@ -420,15 +499,22 @@ func (s *scanJob) incrementProgress(f scanFile) {
 func (s *scanJob) processQueueItem(ctx context.Context, f scanFile) {
 	s.ProgressReports.ExecuteTask("Scanning "+f.Path, func() {
 		var err error
-		if f.info.IsDir() {
+		switch {
+		case f.inUnchangedFolder:
+			err = s.handleUnchangedFolderFile(ctx, f)
+		case f.info.IsDir():
 			err = s.handleFolder(ctx, f)
-		} else {
+		default:
 			err = s.handleFile(ctx, f)
 		}
 
 		if err != nil && !errors.Is(err, context.Canceled) {
 			logger.Errorf("error processing %q: %v", f.Path, err)
 		}
+
+		if err != nil {
+			s.folderState.setFailed(f)
+		}
 	})
 }
 
@@ -494,11 +580,22 @@ func (s *scanJob) handleFolder(ctx context.Context, file scanFile) error {
 			return fmt.Errorf("checking for existing folder %q: %w", path, err)
 		}
 
+		unchanged := false
+		var files []models.File
+
 		// if folder not exists, create it
 		if f == nil {
 			f, err = s.onNewFolder(ctx, file)
 		} else {
-			f, err = s.onExistingFolder(ctx, file, f)
+			unchanged = !s.options.FullRescan && isFolderUnchanged(file, f)
+			if unchanged {
+				files, err = s.Repository.File.FindByParentFolderID(ctx, f.ID)
+				if err != nil {
+					return fmt.Errorf("finding files of folder %q: %w", path, err)
+				}
+			} else {
+				f, err = s.onExistingFolder(ctx, file, f)
+			}
 		}
 
 		if err != nil {
@@ -507,6 +604,11 @@ func (s *scanJob) handleFolder(ctx context.Context, file scanFile) error {
 
 		if f != nil {
 			s.folderPathToID.Store(f.Path, f.ID)
+
+			folder := f
+			txn.AddPostCommitHook(ctx, func(ctx context.Context) {
+				s.folderState.setScanned(folder, file, unchanged, files)
+			})
 		}
 
 		return nil
@@ -579,6 +681,7 @@ func (s *scanJob) handleFolderRename(ctx context.Context, file scanFile) (*model
 	// if the folder was moved, update the existing folder
 	logger.Infof("%s moved to %s. Updating path...", renamedFrom.Path, file.Path)
 	renamedFrom.Path = file.Path
+	renamedFrom.ChildCount = nil
 
 	// update the parent folder ID
 	// find the parent folder
@@ -624,6 +727,12 @@ func (s *scanJob) onExistingFolder(ctx context.Context, f scanFile, existing *mo
 		}
 	}
 
+	// the child count is set again once the files of the folder are scanned
+	if existing.ChildCount != nil {
+		existing.ChildCount = nil
+		update = true
+	}
+
 	if update {
 		var err error
 		if err = s.Repository.Folder.Update(ctx, existing); err != nil {
@@ -680,15 +789,44 @@ func (s *scanJob) handleFile(ctx context.Context, f scanFile) error {
 	return nil
 }
 
-func (s *scanJob) isZipFile(path string) bool {
-	fExt := filepath.Ext(path)
-	for _, ext := range s.options.ZipFileExtensions {
-		if strings.EqualFold(fExt, "."+ext) {
-			return true
+// handleUnchangedFolderFile runs the handlers for a file in an unchanged
+// folder if required, without reading or fingerprinting the file.
+func (s *scanJob) handleUnchangedFolderFile(ctx context.Context, f scanFile) error {
+	defer s.incrementProgress(f)
+
+	existing := f.existing
+	handlerRequired := false
+	if err := s.withDB(ctx, func(ctx context.Context) error {
+		handlerRequired = s.isHandlerRequired(ctx, existing)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if !handlerRequired {
+		return nil
+	}
+
+	if err := s.withTxn(ctx, func(ctx context.Context) error {
+		return s.fireHandlers(ctx, existing, nil)
+	}); err != nil {
+		return err
+	}
+
+	// rescan the contents of zip files, as for other unchanged files
+	if s.isZipFile(f.Basename) {
+		zipCtx := utils.ValueOnlyContext{Context: ctx}
+		if err := s.scanZipFile(zipCtx, f); err != nil {
+			logger.Errorf("Error scanning zip file %q: %v", f.Path, err)
 		}
 	}
 
-	return false
+	return nil
+}
+
+func (s *scanJob) isZipFile(path string) bool {
+	// match the full suffix so that extensions such as tar.gz are matched
+	return fsutil.MatchExtension(path, s.options.ZipFileExtensions)
 }
 
 func (s *scanJob) onNewFile(ctx context.Context, f scanFile) (models.File, error) {
@@ -922,6 +1060,17 @@ func (s *scanJob) handleRename(ctx context.Context, f models.File, fp []models.F
 	return f, nil
 }
 
+// isChanged returns true if any of the ChangedFilters accepts the file.
+func (s *scanJob) isChanged(ctx context.Context, f models.File) bool {
+	for _, filter := range s.options.ChangedFilters {
+		if filter.Accept(ctx, f) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (s *scanJob) isHandlerRequired(ctx context.Context, f models.File) bool {
 	accept := len(s.options.HandlerRequiredFilters) == 0
 	for _, filter := range s.options.HandlerRequiredFilters {
@@ -1009,7 +1158,8 @@ func (s *scanJob) onExistingFile(ctx context.Context, f scanFile, existing model
 	path := base.Path
 
 	fileModTime := f.ModTime
-	updated := !fileModTime.Equal(base.ModTime)
+	modTimeChanged := !fileModTime.Equal(base.ModTime)
+	updated := modTimeChanged || s.isChanged(ctx, existing)
 
 	if !updated {
 		return s.onUnchangedFile(ctx, f, existing)
@@ -1023,7 +1173,8 @@ func (s *scanJob) onExistingFile(ctx context.Context, f scanFile, existing model
 	base.UpdatedAt = time.Now()
 
 	// calculate and update fingerprints for the file
-	const useExisting = false
+	// the file contents are unchanged if only a related file has changed
+	useExisting := !modTimeChanged
 	fp, err := s.calculateFingerprints(f.fs, base, path, useExisting)
 	if err != nil {
 		return nil, err
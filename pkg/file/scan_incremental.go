@@ -0,0 +1,146 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// A folder is considered unchanged if its modification time and number of
+// entries are the same as when its files were last scanned. The files of
+// unchanged folders are loaded from the data store in a single query, rather
+// than being read and fingerprinted. They are still passed through the scan
+// filters, using the stored file information, and to the handlers if
+// required. Files that are not in the data store, such as files that were not
+// accepted by the filters of a previous scan, are scanned as usual. Files
+// modified in place do not change the folder, so are only detected by a full
+// rescan.
+
+type folderChildCount struct {
+	path  string
+	count int
+}
+
+// incrementalScanState tracks the folders visited during a scan.
+type incrementalScanState struct {
+	// unchanged is a map of the paths of unchanged folders to their files
+	// in the data store, keyed by basename. Files are removed once visited
+	// by the walk.
+	unchanged sync.Map
+	// childCounts is a map of folder ID to the child count to set once the
+	// scan has finished
+	childCounts sync.Map
+	// failed is the set of paths of folders containing files that could not
+	// be scanned
+	failed sync.Map
+
+	// skipped is only modified while walking the directory tree
+	skipped int
+}
+
+// takeUnchangedFile returns the stored file at path, if its folder is
+// unchanged. Returns false if the folder is not unchanged.
+func (s *incrementalScanState) takeUnchangedFile(path string) (models.File, bool) {
+	v, found := s.unchanged.Load(filepath.Dir(path))
+	if !found {
+		return nil, false
+	}
+
+	files := v.(map[string]models.File)
+	basename := filepath.Base(path)
+	ret := files[basename]
+	delete(files, basename)
+
+	return ret, true
+}
+
+// setScanned records the scanned folder. files are the files of the folder
+// in the data store, and are only required if the folder is unchanged.
+func (s *incrementalScanState) setScanned(f *models.Folder, file scanFile, unchanged bool, files []models.File) {
+	if unchanged {
+		byBasename := make(map[string]models.File, len(files))
+		for _, ff := range files {
+			byBasename[ff.Base().Basename] = ff
+		}
+
+		s.unchanged.Store(f.Path, byBasename)
+		s.skipped++
+		return
+	}
+
+	if file.childCount != nil {
+		s.childCounts.Store(f.ID, folderChildCount{
+			path:  f.Path,
+			count: *file.childCount,
+		})
+	}
+}
+
+func (s *incrementalScanState) setFailed(f scanFile) {
+	path := f.Path
+	if f.ZipFile != nil {
+		path = f.ZipFile.Base().Path
+	}
+
+	s.failed.Store(filepath.Dir(path), struct{}{})
+}
+
+// storedFileInfo is the file information of a file in the data store.
+type storedFileInfo struct {
+	f *models.BaseFile
+}
+
+func (i storedFileInfo) Name() string       { return i.f.Basename }
+func (i storedFileInfo) Size() int64        { return i.f.Size }
+func (i storedFileInfo) Mode() fs.FileMode  { return 0 }
+func (i storedFileInfo) ModTime() time.Time { return i.f.ModTime }
+func (i storedFileInfo) IsDir() bool        { return false }
+func (i storedFileInfo) Sys() any           { return nil }
+
+func isFolderUnchanged(f scanFile, existing *models.Folder) bool {
+	return f.childCount != nil && existing.ChildCount != nil &&
+		*f.childCount == *existing.ChildCount &&
+		f.ModTime.Equal(existing.ModTime) &&
+		f.ZipFileID == nil && existing.ZipFileID == nil
+}
+
+// setFolderChildCounts sets the child counts of the scanned folders, so that
+// they are skipped by the next scan if unchanged.
+func (s *scanJob) setFolderChildCounts(ctx context.Context) error {
+	var ret error
+	s.folderState.childCounts.Range(func(key, value interface{}) bool {
+		id := key.(models.FolderID)
+		cc := value.(folderChildCount)
+
+		if _, failed := s.folderState.failed.Load(cc.path); failed {
+			return true
+		}
+
+		if err := s.withTxn(ctx, func(ctx context.Context) error {
+			f, err := s.Repository.Folder.Find(ctx, id)
+			if err != nil {
+				return err
+			}
+
+			if f == nil {
+				return nil
+			}
+
+			count := cc.count
+			f.ChildCount = &count
+			return s.Repository.Folder.Update(ctx, f)
+		}); err != nil {
+			ret = fmt.Errorf("setting child count of folder %q: %w", cc.path, err)
+			return false
+		}
+
+		return true
+	})
+
+	return ret
+}
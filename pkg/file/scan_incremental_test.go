@@ -0,0 +1,215 @@
+package file
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsFolderUnchanged(t *testing.T) {
+	var (
+		modTime    = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+		laterTime  = modTime.Add(time.Second)
+		childCount = 2
+		moreCount  = 3
+		zipFileID  = models.FileID(1)
+	)
+
+	makeFile := func(modTime time.Time, childCount *int) scanFile {
+		return scanFile{
+			BaseFile: &models.BaseFile{
+				DirEntry: models.DirEntry{
+					ModTime: modTime,
+				},
+			},
+			childCount: childCount,
+		}
+	}
+
+	makeFolder := func(modTime time.Time, childCount *int) *models.Folder {
+		return &models.Folder{
+			DirEntry: models.DirEntry{
+				ModTime: modTime,
+			},
+			ChildCount: childCount,
+		}
+	}
+
+	inZip := makeFolder(modTime, &childCount)
+	inZip.ZipFileID = &zipFileID
+
+	tests := []struct {
+		name     string
+		f        scanFile
+		existing *models.Folder
+		want     bool
+	}{
+		{"unchanged", makeFile(modTime, &childCount), makeFolder(modTime, &childCount), true},
+		{"mod time changed", makeFile(laterTime, &childCount), makeFolder(modTime, &childCount), false},
+		{"child count changed", makeFile(modTime, &moreCount), makeFolder(modTime, &childCount), false},
+		{"child count unknown", makeFile(modTime, nil), makeFolder(modTime, &childCount), false},
+		{"not scanned", makeFile(modTime, &childCount), makeFolder(modTime, nil), false},
+		{"in zip file", makeFile(modTime, &childCount), inZip, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isFolderUnchanged(tt.f, tt.existing))
+		})
+	}
+}
+
+func TestSymWalkDirEntryCount(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{
+		filepath.Join(root, "a.mp4"),
+		filepath.Join(sub, "b.mp4"),
+		filepath.Join(sub, "c.mp4"),
+	} {
+		if err := os.WriteFile(p, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	counts := make(map[string]*int)
+	err := symWalk(&OsFS{}, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		counts[path] = dirEntryCount(d)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	intPtr := func(v int) *int { return &v }
+	assert.Equal(t, map[string]*int{
+		root:                         intPtr(2),
+		sub:                          intPtr(2),
+		filepath.Join(root, "a.mp4"): nil,
+		filepath.Join(sub, "b.mp4"):  nil,
+		filepath.Join(sub, "c.mp4"):  nil,
+	}, counts)
+}
+
+// openCountingFS is an OsFS that records the opened paths.
+type openCountingFS struct {
+	OsFS
+	opened []string
+}
+
+func (f *openCountingFS) Open(name string) (fs.ReadDirFile, error) {
+	f.opened = append(f.opened, name)
+	return f.OsFS.Open(name)
+}
+
+func TestSymWalkSkippedDirNotRead(t *testing.T) {
+	root := t.TempDir()
+	excluded := filepath.Join(root, "excluded")
+	if err := os.Mkdir(excluded, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(excluded, "a.mp4"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &openCountingFS{}
+	var visited []string
+	err := symWalk(f, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, path)
+		if path == excluded {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{root, excluded}, visited)
+	assert.Equal(t, []string{root}, f.opened)
+}
+
+type testPathFilter func(path string, info fs.FileInfo) bool
+
+func (f testPathFilter) Accept(ctx context.Context, path string, info fs.FileInfo) bool {
+	return f(path, info)
+}
+
+func TestQueueUnchangedFolderFiles(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"stored.mp4", "excluded.mp4", "new.mp4"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var (
+		modTime    = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+		minModTime = modTime.Add(-time.Hour)
+	)
+
+	stored := func(name string) models.File {
+		return &models.BaseFile{
+			DirEntry: models.DirEntry{ModTime: modTime},
+			Path:     filepath.Join(root, name),
+			Basename: name,
+			Size:     4,
+		}
+	}
+
+	s := &scanJob{
+		Scanner: &Scanner{},
+		options: ScanOptions{
+			ScanFilters: []PathFilter{testPathFilter(func(path string, info fs.FileInfo) bool {
+				return filepath.Base(path) != "excluded.mp4" && !info.ModTime().Before(minModTime)
+			})},
+		},
+		fileQueue: make(chan scanFile, 10),
+	}
+
+	s.folderState.setScanned(&models.Folder{Path: root}, scanFile{}, true, []models.File{
+		stored("stored.mp4"),
+		stored("excluded.mp4"),
+	})
+
+	f := &OsFS{}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fn := s.queueFileFunc(context.Background(), f, nil)
+	for _, d := range entries {
+		assert.NoError(t, fn(filepath.Join(root, d.Name()), d, nil))
+	}
+	close(s.fileQueue)
+
+	queued := make(map[string]scanFile)
+	for ff := range s.fileQueue {
+		queued[ff.Basename] = ff
+	}
+
+	// files in the data store are not read, and excluded files are not queued
+	if assert.Contains(t, queued, "stored.mp4") {
+		assert.True(t, queued["stored.mp4"].inUnchangedFolder)
+		assert.Nil(t, queued["stored.mp4"].info)
+	}
+	assert.NotContains(t, queued, "excluded.mp4")
+
+	// files not in the data store are scanned as usual
+	if assert.Contains(t, queued, "new.mp4") {
+		assert.False(t, queued["new.mp4"].inUnchangedFolder)
+		assert.NotNil(t, queued["new.mp4"].info)
+	}
+}
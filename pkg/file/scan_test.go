@@ -0,0 +1,126 @@
+package file
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type testFingerprintCalculator struct{}
+
+func (testFingerprintCalculator) CalculateFingerprints(f *models.BaseFile, o Opener, useExisting bool) ([]models.Fingerprint, error) {
+	return []models.Fingerprint{{Type: models.FingerprintTypeMD5, Fingerprint: f.Path}}, nil
+}
+
+type testProgressReporter struct{}
+
+func (testProgressReporter) AddTotal(total int)                        {}
+func (testProgressReporter) Increment()                                {}
+func (testProgressReporter) Definite()                                 {}
+func (testProgressReporter) ExecuteTask(description string, fn func()) { fn() }
+
+// mockScanStore sets up db to store the folders and files created by a scan.
+// It returns a function that returns the paths of the created files.
+func mockScanStore(db *mocks.Database) func() []string {
+	var (
+		mutex   sync.Mutex
+		folders = make(map[string]*models.Folder)
+		files   = make(map[string]models.File)
+	)
+
+	db.Folder.On("FindByPath", mock.Anything, mock.Anything).Return(func(ctx context.Context, path string) *models.Folder {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return folders[path]
+	}, nil)
+	db.Folder.On("Find", mock.Anything, mock.Anything).Return(func(ctx context.Context, id models.FolderID) *models.Folder {
+		mutex.Lock()
+		defer mutex.Unlock()
+		for _, f := range folders {
+			if f.ID == id {
+				return f
+			}
+		}
+		return nil
+	}, nil)
+	db.Folder.On("Create", mock.Anything, mock.Anything).Return(func(ctx context.Context, f *models.Folder) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		f.ID = models.FolderID(len(folders) + 1)
+		folders[f.Path] = f
+		return nil
+	})
+	db.Folder.On("Update", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	db.File.On("FindByPath", mock.Anything, mock.Anything).Return(func(ctx context.Context, path string) models.File {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return files[path]
+	}, nil)
+	db.File.On("FindByFingerprint", mock.Anything, mock.Anything).Return(nil, nil)
+	db.File.On("FindByFileInfo", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	db.File.On("Create", mock.Anything, mock.Anything).Return(func(ctx context.Context, f models.File) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		f.Base().ID = models.FileID(len(files) + 1)
+		files[f.Base().Path] = f
+		return nil
+	})
+
+	return func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		var ret []string
+		for p := range files {
+			ret = append(ret, p)
+		}
+		sort.Strings(ret)
+		return ret
+	}
+}
+
+func TestScanTarGzArchive(t *testing.T) {
+	archivePath := createTestArchive(t, "gallery.tar.gz", func(w io.Writer) {
+		gw := gzip.NewWriter(w)
+		writeTestTar(t, gw)
+		require.NoError(t, gw.Close())
+	})
+	root := filepath.Dir(archivePath)
+
+	db := mocks.NewDatabase()
+	createdFiles := mockScanStore(db)
+
+	scanner := &Scanner{
+		FS:                    &OsFS{},
+		Repository:            NewRepository(db.Repository()),
+		FingerprintCalculator: testFingerprintCalculator{},
+	}
+
+	scanner.Scan(context.Background(), nil, ScanOptions{
+		Paths:             []string{root},
+		ZipFileExtensions: []string{"zip", "tar.gz"},
+		ParallelTasks:     1,
+	}, testProgressReporter{})
+
+	inArchive := func(p string) string {
+		return filepath.Join(archivePath, filepath.FromSlash(p))
+	}
+
+	assert.Equal(t, []string{
+		archivePath,
+		inArchive("a.jpg"),
+		inArchive("sub/b.jpg"),
+		inArchive("sub/sub/c.png"),
+	}, createdFiles())
+}
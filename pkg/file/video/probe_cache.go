@@ -0,0 +1,50 @@
+package video
+
+import (
+	"sync"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+)
+
+// ProbeCache keeps the ffprobe results of the files decorated during a scan,
+// so that the scan handlers can use them without running ffprobe again.
+// Results should be removed once they have been used.
+type ProbeCache struct {
+	probes map[string]*ffmpeg.VideoFile
+	mutex  sync.Mutex
+}
+
+func NewProbeCache() *ProbeCache {
+	return &ProbeCache{
+		probes: make(map[string]*ffmpeg.VideoFile),
+	}
+}
+
+func (c *ProbeCache) add(path string, probe *ffmpeg.VideoFile) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.probes[path] = probe
+}
+
+// Get returns the ffprobe result of the file at path. Returns nil if the
+// file was not probed during the scan.
+func (c *ProbeCache) Get(path string) *ffmpeg.VideoFile {
+	if c == nil {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.probes[path]
+}
+
+// Remove removes the ffprobe result of the file at path.
+func (c *ProbeCache) Remove(path string) {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.probes, path)
+}
@@ -6,33 +6,38 @@ import (
 	"fmt"
 
 	"github.com/stashapp/stash/pkg/ffmpeg"
-	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/models"
 )
 
 // Decorator adds video specific fields to a File.
 type Decorator struct {
 	FFProbe ffmpeg.FFProbe
+
+	// Probes is optional. If set, the ffprobe result of each decorated file
+	// is added to it.
+	Probes *ProbeCache
 }
 
 func (d *Decorator) Decorate(ctx context.Context, fs models.FS, f models.File) (models.File, error) {
-	if d.FFProbe == "" {
+	if d.FFProbe.Path() == "" {
 		return f, errors.New("ffprobe not configured")
 	}
 
 	base := f.Base()
-	// TODO - copy to temp file if not an OsFS
-	if _, isOs := fs.(*file.OsFS); !isOs {
-		return f, fmt.Errorf("video.constructFile: only OsFS is supported")
-	}
 
+	// files in archives and on remote storage are read by ffprobe through
+	// its remote input
 	probe := d.FFProbe
 	videoFile, err := probe.NewVideoFile(base.Path)
 	if err != nil {
 		return f, fmt.Errorf("running ffprobe on %q: %w", base.Path, err)
 	}
 
-	container, err := ffmpeg.MatchContainer(videoFile.Container, base.Path)
+	if d.Probes != nil {
+		d.Probes.add(base.Path, videoFile)
+	}
+
+	container, err := probe.MatchContainer(videoFile.Container, base.Path)
 	if err != nil {
 		return f, fmt.Errorf("matching container for %q: %w", base.Path, err)
 	}
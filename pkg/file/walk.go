@@ -108,7 +108,57 @@ func fsWalk(f models.FS, root string, fn fs.WalkDirFunc) error {
 	return err
 }
 
+// countedDirEntry is a directory entry that reads the entries of the
+// directory once, when first required. This allows walkDirFn to get the
+// number of entries of accepted directories, without reading the directories
+// it skips.
+type countedDirEntry struct {
+	fs.DirEntry
+	read func() ([]fs.DirEntry, error)
+
+	entries []fs.DirEntry
+	err     error
+	done    bool
+}
+
+func (d *countedDirEntry) readDir() ([]fs.DirEntry, error) {
+	if !d.done {
+		d.entries, d.err = d.read()
+		d.done = true
+	}
+
+	return d.entries, d.err
+}
+
+// dirEntryCount returns the number of entries in the directory, reading it
+// if required. Returns nil if the directory could not be read.
+func dirEntryCount(d fs.DirEntry) *int {
+	cd, ok := d.(*countedDirEntry)
+	if !ok {
+		return nil
+	}
+
+	entries, err := cd.readDir()
+	if err != nil {
+		return nil
+	}
+
+	ret := len(entries)
+	return &ret
+}
+
 func walkDir(f models.FS, path string, d fs.DirEntry, walkDirFn fs.WalkDirFunc) error {
+	var cd *countedDirEntry
+	if d.IsDir() {
+		cd = &countedDirEntry{
+			DirEntry: d,
+			read: func() ([]fs.DirEntry, error) {
+				return readDir(f, path)
+			},
+		}
+		d = cd
+	}
+
 	if err := walkDirFn(path, d, nil); err != nil || !d.IsDir() {
 		if errors.Is(err, fs.SkipDir) && d.IsDir() {
 			// Successfully skipped directory.
@@ -117,7 +167,7 @@ func walkDir(f models.FS, path string, d fs.DirEntry, walkDirFn fs.WalkDirFunc)
 		return err
 	}
 
-	dirs, err := readDir(f, path)
+	dirs, err := cd.readDir()
 	if err != nil {
 		// Second call, to report ReadDir error.
 		err = walkDirFn(path, d, err)
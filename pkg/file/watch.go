@@ -0,0 +1,361 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+const (
+	DefaultWatchDebounce     = 5 * time.Second
+	DefaultWatchMaxDelay     = time.Minute
+	DefaultWatchPollInterval = time.Minute
+)
+
+// WatchChanges contains the paths that changed since the last notification.
+// Paths within another changed path are omitted.
+type WatchChanges struct {
+	// Updated contains the paths of files and folders that were created or modified.
+	Updated []string
+	// Removed contains the paths of files and folders that no longer exist.
+	Removed []string
+}
+
+// Watcher watches a set of paths for changes, using filesystem notifications
+// where possible and polling otherwise. Changes are debounced, so that OnChange
+// is called once the paths have been quiet for the debounce period, or once
+// the first pending change is MaxDelay old if changes keep being made.
+type Watcher struct {
+	Paths []string
+
+	// Poll forces polling for all paths. Paths on network filesystems are
+	// always polled, since they do not report changes made by other hosts.
+	Poll         bool
+	PollInterval time.Duration
+	Debounce     time.Duration
+	MaxDelay     time.Duration
+
+	OnChange func(ctx context.Context, changes WatchChanges)
+}
+
+type watchEvent struct {
+	path string
+}
+
+// Watch watches the paths until the context is cancelled.
+func (w *Watcher) Watch(ctx context.Context) error {
+	notify, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating filesystem watcher: %w", err)
+	}
+	defer notify.Close()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events := make(chan watchEvent)
+	send := func(path string) {
+		select {
+		case events <- watchEvent{path: path}:
+		case <-ctx.Done():
+		}
+	}
+
+	for _, p := range w.Paths {
+		poll := w.Poll || isNetworkFS(p)
+		if !poll {
+			if err := watchRecursive(notify, p); err != nil {
+				logger.Warnf("Could not watch %q, falling back to polling: %v", p, err)
+				poll = true
+			}
+		}
+
+		if poll {
+			logger.Infof("Polling %q for changes", p)
+			wg.Add(1)
+			go func(p string) {
+				defer wg.Done()
+				w.poll(ctx, p, send)
+			}(p)
+		} else {
+			logger.Infof("Watching %q for changes", p)
+		}
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		readNotifications(ctx, notify, send)
+	}()
+
+	debounce := w.Debounce
+	if debounce <= 0 {
+		debounce = DefaultWatchDebounce
+	}
+
+	maxDelay := w.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultWatchMaxDelay
+	}
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	pending := make(map[string]struct{})
+	// time of the first pending change
+	var first time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e := <-events:
+			if len(pending) == 0 {
+				first = time.Now()
+			}
+			pending[e.path] = struct{}{}
+			timer.Reset(watchDelay(debounce, maxDelay, time.Since(first)))
+		case <-timer.C:
+			if len(pending) == 0 {
+				continue
+			}
+
+			changes := compactWatchChanges(pending, pathExists)
+			pending = make(map[string]struct{})
+
+			if w.OnChange != nil {
+				w.OnChange(ctx, changes)
+			}
+		}
+	}
+}
+
+// watchDelay returns the time to wait for further changes, given the time
+// since the first pending change. The wait is shortened so that changes are
+// never held for longer than maxDelay.
+func watchDelay(debounce time.Duration, maxDelay time.Duration, elapsed time.Duration) time.Duration {
+	remaining := maxDelay - elapsed
+	if remaining < 0 {
+		return 0
+	}
+	if remaining < debounce {
+		return remaining
+	}
+	return debounce
+}
+
+func readNotifications(ctx context.Context, notify *fsnotify.Watcher, send func(path string)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-notify.Events:
+			if !ok {
+				return
+			}
+
+			// permission changes are not relevant
+			if e.Op == fsnotify.Chmod {
+				continue
+			}
+
+			// new folders need to be watched as well
+			if e.Has(fsnotify.Create) {
+				if info, err := os.Stat(e.Name); err == nil && info.IsDir() {
+					if err := watchRecursive(notify, e.Name); err != nil {
+						logger.Warnf("Could not watch %q: %v", e.Name, err)
+					}
+				}
+			}
+
+			send(e.Name)
+		case err, ok := <-notify.Errors:
+			if !ok {
+				return
+			}
+
+			logger.Errorf("Filesystem watcher error: %v", err)
+		}
+	}
+}
+
+// watchRecursive adds a watch for path and all of the folders within it.
+func watchRecursive(notify *fsnotify.Watcher, path string) error {
+	return filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// the root must be watchable, but ignore errors for sub-folders
+			if p == path {
+				return err
+			}
+
+			logger.Warnf("Could not watch %q: %v", p, err)
+			return nil
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if err := notify.Add(p); err != nil {
+			if p == path {
+				return err
+			}
+
+			logger.Warnf("Could not watch %q: %v", p, err)
+		}
+
+		return nil
+	})
+}
+
+func (w *Watcher) poll(ctx context.Context, path string, send func(path string)) {
+	interval := w.PollInterval
+	if interval <= 0 {
+		interval = DefaultWatchPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := takePollSnapshot(path)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := takePollSnapshot(path)
+			for _, p := range current.changedSince(last) {
+				send(p)
+			}
+			last = current
+		}
+	}
+}
+
+type pollEntry struct {
+	modTime time.Time
+	size    int64
+	isDir   bool
+}
+
+type pollSnapshot map[string]pollEntry
+
+func takePollSnapshot(path string) pollSnapshot {
+	ret := make(pollSnapshot)
+
+	_ = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// don't let errors prevent polling
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		ret[p] = pollEntry{
+			modTime: info.ModTime(),
+			size:    info.Size(),
+			isDir:   d.IsDir(),
+		}
+
+		return nil
+	})
+
+	return ret
+}
+
+// changedSince returns the paths that were added, modified or removed since
+// the previous snapshot. Folders are only returned when added or removed,
+// since their modification time changes whenever their contents change.
+func (s pollSnapshot) changedSince(previous pollSnapshot) []string {
+	var ret []string
+
+	for p, e := range s {
+		old, found := previous[p]
+		switch {
+		case !found:
+			ret = append(ret, p)
+		case e.isDir != old.isDir:
+			ret = append(ret, p)
+		case !e.isDir && (!e.modTime.Equal(old.modTime) || e.size != old.size):
+			ret = append(ret, p)
+		}
+	}
+
+	for p := range previous {
+		if _, found := s[p]; !found {
+			ret = append(ret, p)
+		}
+	}
+
+	sort.Strings(ret)
+	return ret
+}
+
+func pathExists(path string) bool {
+	_, err := os.Lstat(path)
+	return !errors.Is(err, fs.ErrNotExist)
+}
+
+// compactWatchChanges splits paths into updated and removed paths, based on
+// whether they currently exist, and drops paths that are within another path
+// of the same kind.
+func compactWatchChanges(paths map[string]struct{}, exists func(path string) bool) WatchChanges {
+	var updated, removed []string
+	for p := range paths {
+		if exists(p) {
+			updated = append(updated, p)
+		} else {
+			removed = append(removed, p)
+		}
+	}
+
+	return WatchChanges{
+		Updated: withoutDescendants(updated),
+		Removed: withoutDescendants(removed),
+	}
+}
+
+func withoutDescendants(paths []string) []string {
+	sort.Strings(paths)
+
+	var ret []string
+	for _, p := range paths {
+		if !isWithinAny(p, ret) {
+			ret = append(ret, p)
+		}
+	}
+
+	return ret
+}
+
+func isWithinAny(path string, parents []string) bool {
+	for _, parent := range parents {
+		if isWithin(path, parent) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isWithin(path string, parent string) bool {
+	return strings.HasPrefix(path, strings.TrimSuffix(parent, string(filepath.Separator))+string(filepath.Separator))
+}
@@ -0,0 +1,32 @@
+//go:build linux
+// +build linux
+
+package file
+
+import "golang.org/x/sys/unix"
+
+// filesystems that do not report changes made by other hosts
+var networkFSTypes = []uint32{
+	unix.NFS_SUPER_MAGIC,
+	unix.SMB_SUPER_MAGIC,
+	unix.SMB2_SUPER_MAGIC,
+	unix.CIFS_SUPER_MAGIC,
+	unix.FUSE_SUPER_MAGIC,
+}
+
+// isNetworkFS returns true if path is on a network filesystem.
+func isNetworkFS(path string) bool {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return false
+	}
+
+	fsType := uint32(st.Type)
+	for _, t := range networkFSTypes {
+		if fsType == t {
+			return true
+		}
+	}
+
+	return false
+}
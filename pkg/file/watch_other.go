@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package file
+
+// isNetworkFS returns true if path is on a network filesystem. Network
+// filesystems are only detected on linux.
+func isNetworkFS(path string) bool {
+	return false
+}
@@ -0,0 +1,95 @@
+package file
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompactWatchChanges(t *testing.T) {
+	var (
+		root       = filepath.Join("stash")
+		folder     = filepath.Join(root, "folder")
+		folderFile = filepath.Join(folder, "file.mp4")
+		similar    = filepath.Join(root, "folder 2")
+		removed    = filepath.Join(root, "removed")
+		removedSub = filepath.Join(removed, "sub")
+		file       = filepath.Join(root, "file.mp4")
+	)
+
+	existing := map[string]bool{
+		folder:     true,
+		folderFile: true,
+		similar:    true,
+		file:       true,
+	}
+
+	paths := map[string]struct{}{
+		folder:     {},
+		folderFile: {},
+		similar:    {},
+		removed:    {},
+		removedSub: {},
+		file:       {},
+	}
+
+	got := compactWatchChanges(paths, func(path string) bool {
+		return existing[path]
+	})
+
+	assert.Equal(t, WatchChanges{
+		Updated: []string{file, folder, similar},
+		Removed: []string{removed},
+	}, got)
+}
+
+func TestPollSnapshotChangedSince(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Minute)
+
+	previous := pollSnapshot{
+		"folder":           {modTime: now, isDir: true},
+		"folder/same.mp4":  {modTime: now, size: 10},
+		"folder/mod.mp4":   {modTime: now, size: 10},
+		"folder/size.mp4":  {modTime: now, size: 10},
+		"folder/gone.mp4":  {modTime: now, size: 10},
+		"folder/type":      {modTime: now, size: 10},
+		"folder/gone":      {modTime: now, isDir: true},
+		"folder/gone/file": {modTime: now, size: 10},
+	}
+
+	current := pollSnapshot{
+		"folder":          {modTime: later, isDir: true},
+		"folder/same.mp4": {modTime: now, size: 10},
+		"folder/mod.mp4":  {modTime: later, size: 10},
+		"folder/size.mp4": {modTime: now, size: 20},
+		"folder/type":     {modTime: now, isDir: true},
+		"folder/new":      {modTime: later, isDir: true},
+		"folder/new.mp4":  {modTime: later, size: 10},
+	}
+
+	assert.Equal(t, []string{
+		"folder/gone",
+		"folder/gone.mp4",
+		"folder/gone/file",
+		"folder/mod.mp4",
+		"folder/new",
+		"folder/new.mp4",
+		"folder/size.mp4",
+		"folder/type",
+	}, current.changedSince(previous))
+}
+
+func TestWatchDelay(t *testing.T) {
+	const (
+		debounce = 5 * time.Second
+		maxDelay = time.Minute
+	)
+
+	assert.Equal(t, debounce, watchDelay(debounce, maxDelay, 0))
+	assert.Equal(t, debounce, watchDelay(debounce, maxDelay, 50*time.Second))
+	assert.Equal(t, 2*time.Second, watchDelay(debounce, maxDelay, 58*time.Second))
+	assert.Equal(t, time.Duration(0), watchDelay(debounce, maxDelay, 2*time.Minute))
+}
@@ -4,10 +4,8 @@ import (
 	"archive/zip"
 	"bytes"
 	"errors"
-	"fmt"
 	"io"
 	"io/fs"
-	"path/filepath"
 
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
@@ -17,20 +15,9 @@ import (
 	"golang.org/x/text/transform"
 )
 
-var (
-	errNotReaderAt  = errors.New("not a ReaderAt")
-	errZipFSOpenZip = errors.New("cannot open zip file inside zip file")
-)
-
-// ZipFS is a file system backed by a zip file.
-type zipFS struct {
-	*zip.Reader
-	zipFileCloser io.Closer
-	zipInfo       fs.FileInfo
-	zipPath       string
-}
+var errNotReaderAt = errors.New("not a ReaderAt")
 
-func newZipFS(fs models.FS, path string, info fs.FileInfo) (*zipFS, error) {
+func newZipFS(fs models.FS, path string, info fs.FileInfo) (*archiveFS, error) {
 	reader, err := fs.Open(path)
 	if err != nil {
 		return nil, err
@@ -86,105 +73,69 @@ func newZipFS(fs models.FS, path string, info fs.FileInfo) (*zipFS, error) {
 		}
 	}
 
-	return &zipFS{
-		Reader:        zipReader,
-		zipFileCloser: reader,
-		zipInfo:       info,
-		zipPath:       path,
+	return &archiveFS{
+		FS:            newZipStoredFS(zipReader, asReaderAt),
+		archiveCloser: reader,
+		archiveInfo:   info,
+		archivePath:   path,
 	}, nil
 }
 
-func (f *zipFS) rel(name string) (string, error) {
-	if f.zipPath == name {
-		return ".", nil
-	}
-
-	relName, err := filepath.Rel(f.zipPath, name)
-	if err != nil {
-		return "", fmt.Errorf("internal error getting relative path: %w", err)
-	}
-
-	// convert relName to use slash, since zip files do so regardless
-	// of os
-	relName = filepath.ToSlash(relName)
-
-	return relName, nil
+// zipStoredFS opens entries that are stored without compression as section
+// readers of the zip file, so that they can be seeked and read at offsets.
+// This allows videos in zip files to be streamed using range requests.
+type zipStoredFS struct {
+	*zip.Reader
+	readerAt io.ReaderAt
+	stored   map[string]*zip.File
 }
 
-func (f *zipFS) Stat(name string) (fs.FileInfo, error) {
-	reader, err := f.Open(name)
-	if err != nil {
-		return nil, err
+func newZipStoredFS(r *zip.Reader, readerAt io.ReaderAt) *zipStoredFS {
+	ret := &zipStoredFS{
+		Reader:   r,
+		readerAt: readerAt,
+		stored:   make(map[string]*zip.File),
 	}
-	defer reader.Close()
-
-	return reader.Stat()
-}
 
-func (f *zipFS) Lstat(name string) (fs.FileInfo, error) {
-	return f.Stat(name)
-}
-
-func (f *zipFS) OpenZip(name string) (models.ZipFS, error) {
-	return nil, errZipFSOpenZip
-}
-
-func (f *zipFS) IsPathCaseSensitive(path string) (bool, error) {
-	return true, nil
-}
-
-type zipReadDirFile struct {
-	fs.File
-}
-
-func (f *zipReadDirFile) ReadDir(n int) ([]fs.DirEntry, error) {
-	asReadDirFile, _ := f.File.(fs.ReadDirFile)
-	if asReadDirFile == nil {
-		return nil, fmt.Errorf("internal error: not a ReadDirFile")
+	for _, f := range r.File {
+		if f.Method == zip.Store && !f.FileInfo().IsDir() && f.CompressedSize64 == f.UncompressedSize64 {
+			ret.stored[f.Name] = f
+		}
 	}
 
-	return asReadDirFile.ReadDir(n)
+	return ret
 }
 
-func (f *zipFS) Open(name string) (fs.ReadDirFile, error) {
-	relName, err := f.rel(name)
-	if err != nil {
-		return nil, err
+func (f *zipStoredFS) Open(name string) (fs.File, error) {
+	zf := f.stored[name]
+	if zf == nil {
+		return f.Reader.Open(name)
 	}
 
-	r, err := f.Reader.Open(relName)
+	offset, err := zf.DataOffset()
 	if err != nil {
 		return nil, err
 	}
 
-	return &zipReadDirFile{
-		File: r,
+	return &zipStoredFile{
+		SectionReader: io.NewSectionReader(f.readerAt, offset, int64(zf.UncompressedSize64)),
+		info:          zf.FileInfo(),
 	}, nil
 }
 
-func (f *zipFS) Close() error {
-	return f.zipFileCloser.Close()
+type zipStoredFile struct {
+	*io.SectionReader
+	info fs.FileInfo
 }
 
-// openOnly returns a ReadCloser where calling Close will close the zip fs as well.
-func (f *zipFS) OpenOnly(name string) (io.ReadCloser, error) {
-	r, err := f.Open(name)
-	if err != nil {
-		return nil, err
-	}
-
-	return &wrappedReadCloser{
-		ReadCloser: r,
-		outer:      f,
-	}, nil
+func (f *zipStoredFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
 }
 
-type wrappedReadCloser struct {
-	io.ReadCloser
-	outer io.Closer
+func (f *zipStoredFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	return nil, &fs.PathError{Op: "readdir", Path: f.info.Name(), Err: errors.New("not a directory")}
 }
 
-func (f *wrappedReadCloser) Close() error {
-	_ = f.ReadCloser.Close()
-	return f.outer.Close()
+func (f *zipStoredFile) Close() error {
+	return nil
 }
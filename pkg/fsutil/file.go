@@ -74,11 +74,12 @@ func SafeMove(src, dst string) error {
 }
 
 // MatchExtension returns true if the extension of the provided path
-// matches any of the provided extensions.
+// matches any of the provided extensions. Extensions may contain multiple
+// parts, such as tar.gz.
 func MatchExtension(path string, extensions []string) bool {
-	ext := filepath.Ext(path)
+	base := strings.ToLower(filepath.Base(path))
 	for _, e := range extensions {
-		if strings.EqualFold(ext, "."+e) {
+		if strings.HasSuffix(base, "."+strings.ToLower(e)) {
 			return true
 		}
 	}
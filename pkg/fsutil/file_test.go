@@ -24,3 +24,29 @@ func TestSanitiseBasename(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchExtension(t *testing.T) {
+	extensions := []string{"zip", "CBZ", "tar.gz"}
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"match", "/path/file.zip", true},
+		{"case insensitive", "/path/file.ZIP", true},
+		{"case insensitive extension", "/path/file.cbz", true},
+		{"multi-part", "/path/file.tar.gz", true},
+		{"partial multi-part", "/path/file.gz", false},
+		{"no extension", "/path/zip", false},
+		{"suffix without dot", "/path/filezip", false},
+		{"extension of folder", "/path.zip/file", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchExtension(tt.path, extensions); got != tt.want {
+				t.Errorf("MatchExtension() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
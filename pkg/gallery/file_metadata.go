@@ -0,0 +1,116 @@
+package gallery
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/file/metadata"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type FileMetadataUpdater interface {
+	models.URLLoader
+	models.PerformerIDLoader
+	models.TagIDLoader
+	UpdatePartial(ctx context.Context, id int, updatedGallery models.GalleryPartial) (*models.Gallery, error)
+}
+
+// FileMetadataApplier sets the fields of galleries from the json and nfo
+// sidecar files next to their zip files.
+type FileMetadataApplier struct {
+	Updater    FileMetadataUpdater
+	Resolver   *metadata.Resolver
+	Precedence models.FileMetadataPrecedence
+
+	// FS is the file system the files were scanned with. The sidecar files
+	// are read from the file system containing the file.
+	FS models.FS
+}
+
+func (a *FileMetadataApplier) readSidecars(f *models.BaseFile) (*metadata.Metadata, error) {
+	fs := a.FS
+	if fs == nil {
+		fs = &file.OsFS{}
+	}
+
+	fileFS, closer, err := file.OpenFileFS(fs, f)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	return metadata.ReadSidecars(fileFS, f.Path)
+}
+
+// Apply reads the sidecar files of the file and applies them to the gallery.
+func (a *FileMetadataApplier) Apply(ctx context.Context, g *models.Gallery, f models.File) error {
+	m, err := a.readSidecars(f.Base())
+	if err != nil {
+		return err
+	}
+
+	if m.IsEmpty() {
+		return nil
+	}
+
+	if err := a.updateGallery(ctx, g, m); err != nil {
+		return fmt.Errorf("updating gallery from file metadata: %w", err)
+	}
+
+	return nil
+}
+
+func (a *FileMetadataApplier) updateGallery(ctx context.Context, g *models.Gallery, m *metadata.Metadata) error {
+	r := a.Updater
+	p := a.Precedence
+
+	if err := g.LoadURLs(ctx, r); err != nil {
+		return err
+	}
+	if err := g.LoadPerformerIDs(ctx, r); err != nil {
+		return err
+	}
+	if err := g.LoadTagIDs(ctx, r); err != nil {
+		return err
+	}
+
+	studioID, err := a.Resolver.StudioID(ctx, m.Studio)
+	if err != nil {
+		return err
+	}
+	performerIDs, err := a.Resolver.PerformerIDs(ctx, m.Performers)
+	if err != nil {
+		return err
+	}
+	tagIDs, err := a.Resolver.TagIDs(ctx, m.Tags)
+	if err != nil {
+		return err
+	}
+
+	// the director is used as the photographer of galleries
+	partial := models.NewGalleryPartial()
+	partial.Title = metadata.UpdateString(p, g.Title, m.Title)
+	partial.Code = metadata.UpdateString(p, g.Code, m.Code)
+	partial.Details = metadata.UpdateString(p, g.Details, m.Details)
+	partial.Photographer = metadata.UpdateString(p, g.Photographer, m.Director)
+	partial.Date = metadata.UpdateDate(p, g.Date, m.Date)
+	partial.StudioID = metadata.UpdateID(p, g.StudioID, studioID)
+	partial.URLs = metadata.AddStrings(g.URLs.List(), m.URLs)
+	partial.PerformerIDs = metadata.AddIDs(g.PerformerIDs.List(), performerIDs)
+	partial.TagIDs = metadata.AddIDs(g.TagIDs.List(), tagIDs)
+
+	if !partial.Title.Set && !partial.Code.Set && !partial.Details.Set && !partial.Photographer.Set &&
+		!partial.Date.Set && !partial.StudioID.Set && partial.URLs == nil &&
+		partial.PerformerIDs == nil && partial.TagIDs == nil {
+		return nil
+	}
+
+	if _, err := r.UpdatePartial(ctx, g.ID, partial); err != nil {
+		return err
+	}
+
+	logger.Infof("Updated gallery %s from file metadata", g.DisplayName())
+	return nil
+}
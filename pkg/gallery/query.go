@@ -2,8 +2,12 @@ package gallery
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
 	"strconv"
+	"strings"
 
+	"github.com/stashapp/stash/pkg/job"
 	"github.com/stashapp/stash/pkg/models"
 )
 
@@ -41,3 +45,70 @@ func CountByTagID(ctx context.Context, r models.GalleryQueryer, id int, depth *i
 
 	return r.QueryCount(ctx, filter, nil)
 }
+
+func BatchProcess(ctx context.Context, reader models.GalleryQueryer, galleryFilter *models.GalleryFilterType, findFilter *models.FindFilterType, fn func(gallery *models.Gallery) error) error {
+	const batchSize = 1000
+
+	if findFilter == nil {
+		findFilter = &models.FindFilterType{}
+	}
+
+	page := 1
+	perPage := batchSize
+	findFilter.Page = &page
+	findFilter.PerPage = &perPage
+
+	for more := true; more; {
+		if job.IsCancelled(ctx) {
+			return nil
+		}
+
+		galleries, _, err := reader.Query(ctx, galleryFilter, findFilter)
+		if err != nil {
+			return fmt.Errorf("error querying for galleries: %w", err)
+		}
+
+		for _, gallery := range galleries {
+			if err := fn(gallery); err != nil {
+				return err
+			}
+		}
+
+		if len(galleries) != batchSize {
+			more = false
+		} else {
+			*findFilter.Page++
+		}
+	}
+
+	return nil
+}
+
+// FilterFromPaths creates a GalleryFilterType that filters using the provided
+// paths.
+func FilterFromPaths(paths []string) *models.GalleryFilterType {
+	ret := &models.GalleryFilterType{}
+	or := ret
+	sep := string(filepath.Separator)
+
+	for _, p := range paths {
+		if !strings.HasSuffix(p, sep) {
+			p += sep
+		}
+
+		if ret.Path == nil {
+			or = ret
+		} else {
+			newOr := &models.GalleryFilterType{}
+			or.Or = newOr
+			or = newOr
+		}
+
+		or.Path = &models.StringCriterionInput{
+			Modifier: models.CriterionModifierEquals,
+			Value:    p + "%",
+		}
+	}
+
+	return ret
+}
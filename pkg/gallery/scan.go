@@ -32,11 +32,23 @@ type ScanImageFinderUpdater interface {
 	UpdatePartial(ctx context.Context, id int, partial models.ImagePartial) (*models.Image, error)
 }
 
+type ScanMetadataApplier interface {
+	Apply(ctx context.Context, g *models.Gallery, f models.File) error
+}
+
 type ScanHandler struct {
 	CreatorUpdater     ScanCreatorUpdater
 	SceneFinderUpdater ScanSceneFinderUpdater
 	ImageFinderUpdater ScanImageFinderUpdater
 	PluginCache        *plugin.Cache
+
+	// MetadataApplier is optional. If set, it is applied to new galleries and
+	// to the galleries of changed files.
+	MetadataApplier ScanMetadataApplier
+
+	// ScanDefaults is optional. If set, it provides the tags and studio of
+	// new galleries.
+	ScanDefaults models.ScanDefaultsGetter
 }
 
 func (h *ScanHandler) Handle(ctx context.Context, f models.File, oldFile models.File) error {
@@ -56,6 +68,7 @@ func (h *ScanHandler) Handle(ctx context.Context, f models.File, oldFile models.
 		}
 	}
 
+	applyMetadata := oldFile != nil
 	if len(existing) > 0 {
 		updateExisting := oldFile != nil
 		if err := h.associateExisting(ctx, existing, f, updateExisting); err != nil {
@@ -76,6 +89,9 @@ func (h *ScanHandler) Handle(ctx context.Context, f models.File, oldFile models.
 
 		// create a new gallery
 		newGallery := models.NewGallery()
+		if h.ScanDefaults != nil {
+			h.ScanDefaults.GetScanDefaults(f.Base().Path).Apply(&newGallery.TagIDs, &newGallery.StudioID)
+		}
 
 		logger.Infof("%s doesn't exist. Creating new gallery...", f.Base().Path)
 
@@ -102,6 +118,15 @@ func (h *ScanHandler) Handle(ctx context.Context, f models.File, oldFile models.
 		}
 
 		existing = []*models.Gallery{&newGallery}
+		applyMetadata = true
+	}
+
+	if applyMetadata && h.MetadataApplier != nil {
+		for _, g := range existing {
+			if err := h.MetadataApplier.Apply(ctx, g, f); err != nil {
+				return fmt.Errorf("applying file metadata: %w", err)
+			}
+		}
 	}
 
 	if err := h.associateScene(ctx, existing, f); err != nil {
@@ -0,0 +1,37 @@
+package imagephash
+
+import (
+	"fmt"
+	"image"
+
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/corona10/goimagehash"
+	"github.com/stashapp/stash/pkg/models"
+	_ "golang.org/x/image/webp"
+)
+
+// Generate returns the perceptual hash of the provided image file, read using fs.
+// Only the first frame of animated images is hashed.
+func Generate(fs models.FS, f *models.ImageFile) (*uint64, error) {
+	reader, err := f.Open(fs)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", f.Path, err)
+	}
+	defer reader.Close()
+
+	img, _, err := image.Decode(reader)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", f.Path, err)
+	}
+
+	hash, err := goimagehash.PerceptionHash(img)
+	if err != nil {
+		return nil, fmt.Errorf("computing phash of %s: %w", f.Path, err)
+	}
+
+	hashValue := hash.GetHash()
+	return &hashValue, nil
+}
@@ -0,0 +1,84 @@
+package imagephash
+
+import (
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math/bits"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func testImage() image.Image {
+	// blocks of varying brightness, so that the hash is not sensitive to
+	// small changes from re-encoding
+	r := rand.New(rand.NewSource(1234))
+	img := image.NewRGBA(image.Rect(0, 0, 256, 256))
+	for bx := 0; bx < 8; bx++ {
+		for by := 0; by < 8; by++ {
+			c := color.Gray{Y: uint8(r.Intn(256))}
+			for x := bx * 32; x < (bx+1)*32; x++ {
+				for y := by * 32; y < (by+1)*32; y++ {
+					img.Set(x, y, c)
+				}
+			}
+		}
+	}
+	return img
+}
+
+func writeTestFile(t *testing.T, name string, encode func(f *os.File) error) *models.ImageFile {
+	path := filepath.Join(t.TempDir(), name)
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if err := encode(f); err != nil {
+		t.Fatal(err)
+	}
+
+	return &models.ImageFile{
+		BaseFile: &models.BaseFile{Path: path},
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	fs := &file.OsFS{}
+	img := testImage()
+
+	pngFile := writeTestFile(t, "test.png", func(f *os.File) error {
+		return png.Encode(f, img)
+	})
+	jpegFile := writeTestFile(t, "test.jpg", func(f *os.File) error {
+		return jpeg.Encode(f, img, &jpeg.Options{Quality: 90})
+	})
+	invalidFile := writeTestFile(t, "invalid.png", func(f *os.File) error {
+		_, err := f.WriteString("not an image")
+		return err
+	})
+
+	pngHash, err := Generate(fs, pngFile)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	jpegHash, err := Generate(fs, jpegFile)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// re-encoding should not significantly change the phash
+	assert.LessOrEqual(t, bits.OnesCount64(*pngHash^*jpegHash), 4)
+
+	_, err = Generate(fs, invalidFile)
+	assert.NotNil(t, err)
+}
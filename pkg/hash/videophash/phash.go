@@ -3,10 +3,14 @@ package videophash
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"image"
 	"image/color"
+	"io"
 	"math"
+	"os/exec"
+	"strings"
 
 	"github.com/corona10/goimagehash"
 	"github.com/disintegration/imaging"
@@ -15,6 +19,7 @@ import (
 	"github.com/stashapp/stash/pkg/ffmpeg/transcoder"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
 )
 
 const (
@@ -102,3 +107,122 @@ func generateSprite(encoder *ffmpeg.FFMpeg, videoFile *models.VideoFile) (image.
 
 	return combineImages(images), nil
 }
+
+// GenerateSegments returns the phashes of single frames of the video file, taken every
+// interval seconds. The first frame is taken at half of the interval. The frames are
+// extracted in a single ffmpeg pass.
+func GenerateSegments(encoder *ffmpeg.FFMpeg, videoFile *models.VideoFile, interval float64) (*utils.PhashSegments, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid segment interval %v", interval)
+	}
+
+	logger.Infof("[generator] generating phash segments for %s", videoFile.Path)
+
+	frameCount := 0
+	for t := interval / 2; t < videoFile.Duration; t += interval {
+		frameCount++
+	}
+
+	if frameCount == 0 {
+		return nil, fmt.Errorf("video %s is too short for phash segments", videoFile.Path)
+	}
+
+	// the phash is calculated from a square image, so the frames are scaled to a
+	// square instead of maintaining the aspect ratio. This keeps the frame size
+	// known in advance.
+	var vf ffmpeg.VideoFilter
+	vf = vf.FrameInterval(interval)
+	vf = vf.ScaleDimensions(screenshotSize, screenshotSize)
+
+	var args ffmpeg.Args
+	args = args.LogLevel(ffmpeg.LogLevelError)
+	args = args.Seek(interval / 2)
+	args = args.Input(videoFile.Path)
+	args = args.VideoFilter(vf)
+	args = append(args, "-pix_fmt", "rgba")
+	args = args.Format(ffmpeg.FormatRawVideo)
+	args = args.Output("-")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cmd := encoder.Command(ctx, args)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	hashes, err := readCommandFrameHashes(cmd, cancel, frameCount)
+	if err != nil {
+		return nil, fmt.Errorf("error running ffmpeg command <%s>: %w: %s", strings.Join(args, " "), err, stderr.String())
+	}
+
+	if len(hashes) == 0 {
+		return nil, fmt.Errorf("no frames extracted from %s", videoFile.Path)
+	}
+
+	return &utils.PhashSegments{
+		Interval: interval,
+		Hashes:   hashes,
+	}, nil
+}
+
+// readCommandFrameHashes runs cmd and returns the phashes of up to count frames read
+// from its output. cancel must cancel the context of cmd. The command is stopped if
+// reading stops before the end of its output, so that waiting for it does not block
+// on the unread output.
+func readCommandFrameHashes(cmd *exec.Cmd, cancel context.CancelFunc, count int) ([]uint64, error) {
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting command: %w", err)
+	}
+
+	hashes, readErr := readFrameHashes(stdout, count)
+
+	// the fps filter may output more frames than needed, and a read error
+	// leaves the rest of the output unread, so stop the command unless all of
+	// its output was read
+	stopped := readErr != nil || len(hashes) == count
+	if stopped {
+		cancel()
+		stdout.Close()
+	}
+
+	if err := cmd.Wait(); err != nil && !stopped {
+		return nil, err
+	}
+
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	return hashes, nil
+}
+
+// readFrameHashes reads up to count raw RGBA frames of screenshotSize x screenshotSize
+// from r and returns their phashes.
+func readFrameHashes(r io.Reader, count int) ([]uint64, error) {
+	var ret []uint64
+	rect := image.Rect(0, 0, screenshotSize, screenshotSize)
+
+	for len(ret) < count {
+		img := image.NewRGBA(rect)
+		if _, err := io.ReadFull(r, img.Pix); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("reading frame %d: %w", len(ret), err)
+		}
+
+		hash, err := goimagehash.PerceptionHash(img)
+		if err != nil {
+			return nil, fmt.Errorf("computing phash of frame %d: %w", len(ret), err)
+		}
+
+		ret = append(ret, hash.GetHash())
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,65 @@
+package videophash
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const frameSize = screenshotSize * screenshotSize * 4
+
+func TestReadCommandFrameHashes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires head and cat")
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		count   int
+		want    int
+		wantErr bool
+	}{
+		// the output ends part way through the second frame
+		{"truncated", []string{"head", "-c", strconv.Itoa(frameSize + frameSize/2), "/dev/zero"}, 3, 0, true},
+		{"fewer frames", []string{"head", "-c", strconv.Itoa(frameSize * 2), "/dev/zero"}, 3, 2, false},
+		// the output does not end, so the command must be stopped
+		{"more frames", []string{"cat", "/dev/zero"}, 3, 3, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			type result struct {
+				hashes []uint64
+				err    error
+			}
+			done := make(chan result, 1)
+			go func() {
+				cmd := exec.CommandContext(ctx, tt.args[0], tt.args[1:]...)
+				hashes, err := readCommandFrameHashes(cmd, cancel, tt.count)
+				done <- result{hashes, err}
+			}()
+
+			select {
+			case r := <-done:
+				if tt.wantErr {
+					assert.Error(t, r.err)
+					return
+				}
+
+				assert.NoError(t, r.err)
+				assert.Len(t, r.hashes, tt.want)
+			case <-time.After(10 * time.Second):
+				t.Fatal("timed out waiting for the command")
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil"
+)
+
+// Merge moves the files of the source images to the destination image, adds the galleries,
+// tags and performers of the source images to the destination, then destroys the source images.
+func (s *Service) Merge(ctx context.Context, sourceIDs []int, destinationID int) error {
+	// ensure source ids are unique
+	sourceIDs = sliceutil.AppendUniques(nil, sourceIDs)
+
+	// ensure destination is not in source list
+	if sliceutil.Contains(sourceIDs, destinationID) {
+		return errors.New("destination image cannot be in source list")
+	}
+
+	dest, err := s.Repository.Find(ctx, destinationID)
+	if err != nil {
+		return fmt.Errorf("finding destination image ID %d: %w", destinationID, err)
+	}
+	if dest == nil {
+		return fmt.Errorf("destination image ID %d not found", destinationID)
+	}
+
+	sources, err := s.Repository.FindMany(ctx, sourceIDs)
+	if err != nil {
+		return fmt.Errorf("finding source images: %w", err)
+	}
+
+	var (
+		galleryIDs   []int
+		tagIDs       []int
+		performerIDs []int
+	)
+
+	for _, src := range sources {
+		if err := src.LoadFiles(ctx, s.Repository); err != nil {
+			return fmt.Errorf("loading files of image %d: %w", src.ID, err)
+		}
+		if err := src.LoadGalleryIDs(ctx, s.Repository); err != nil {
+			return fmt.Errorf("loading galleries of image %d: %w", src.ID, err)
+		}
+		if err := src.LoadTagIDs(ctx, s.Repository); err != nil {
+			return fmt.Errorf("loading tags of image %d: %w", src.ID, err)
+		}
+		if err := src.LoadPerformerIDs(ctx, s.Repository); err != nil {
+			return fmt.Errorf("loading performers of image %d: %w", src.ID, err)
+		}
+
+		// move files to destination image
+		for _, f := range src.Files.List() {
+			if err := s.Repository.AddFileID(ctx, destinationID, f.Base().ID); err != nil {
+				return fmt.Errorf("moving file %s to destination image: %w", f.Base().Path, err)
+			}
+		}
+
+		galleryIDs = sliceutil.AppendUniques(galleryIDs, src.GalleryIDs.List())
+		tagIDs = sliceutil.AppendUniques(tagIDs, src.TagIDs.List())
+		performerIDs = sliceutil.AppendUniques(performerIDs, src.PerformerIDs.List())
+	}
+
+	imagePartial := models.NewImagePartial()
+	imagePartial.GalleryIDs = &models.UpdateIDs{
+		IDs:  galleryIDs,
+		Mode: models.RelationshipUpdateModeAdd,
+	}
+	imagePartial.TagIDs = &models.UpdateIDs{
+		IDs:  tagIDs,
+		Mode: models.RelationshipUpdateModeAdd,
+	}
+	imagePartial.PerformerIDs = &models.UpdateIDs{
+		IDs:  performerIDs,
+		Mode: models.RelationshipUpdateModeAdd,
+	}
+
+	if _, err := s.Repository.UpdatePartial(ctx, destinationID, imagePartial); err != nil {
+		return fmt.Errorf("updating image: %w", err)
+	}
+
+	// delete old images
+	for _, srcID := range sourceIDs {
+		if err := s.Repository.Destroy(ctx, srcID); err != nil {
+			return fmt.Errorf("deleting image %d: %w", srcID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package image
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestServiceMerge(t *testing.T) {
+	const (
+		destID    = 1
+		srcID     = 2
+		srcFile   = models.FileID(20)
+		tagID     = 3
+		galleryID = 4
+	)
+
+	newSource := func() *models.Image {
+		return &models.Image{
+			ID: srcID,
+			Files: models.NewRelatedFiles([]models.File{
+				&models.BaseFile{ID: srcFile},
+			}),
+			GalleryIDs:   models.NewRelatedIDs([]int{galleryID}),
+			TagIDs:       models.NewRelatedIDs([]int{tagID}),
+			PerformerIDs: models.NewRelatedIDs([]int{}),
+		}
+	}
+
+	t.Run("destination in source list", func(t *testing.T) {
+		db := mocks.NewDatabase()
+		s := &Service{Repository: db.Image}
+
+		err := s.Merge(testCtx, []int{srcID, destID}, destID)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		db := mocks.NewDatabase()
+		s := &Service{Repository: db.Image}
+
+		db.Image.On("Find", testCtx, destID).Return(&models.Image{ID: destID}, nil).Once()
+		db.Image.On("FindMany", testCtx, []int{srcID}).Return([]*models.Image{newSource()}, nil).Once()
+		db.Image.On("AddFileID", testCtx, destID, srcFile).Return(nil).Once()
+		db.Image.On("UpdatePartial", testCtx, destID, mock.MatchedBy(func(p models.ImagePartial) bool {
+			return p.TagIDs.Mode == models.RelationshipUpdateModeAdd &&
+				assert.ObjectsAreEqual([]int{tagID}, p.TagIDs.IDs) &&
+				assert.ObjectsAreEqual([]int{galleryID}, p.GalleryIDs.IDs)
+		})).Return(&models.Image{ID: destID}, nil).Once()
+		db.Image.On("Destroy", testCtx, srcID).Return(nil).Once()
+
+		err := s.Merge(testCtx, []int{srcID}, destID)
+		assert.Nil(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("add file error", func(t *testing.T) {
+		db := mocks.NewDatabase()
+		s := &Service{Repository: db.Image}
+
+		db.Image.On("Find", testCtx, destID).Return(&models.Image{ID: destID}, nil).Once()
+		db.Image.On("FindMany", testCtx, []int{srcID}).Return([]*models.Image{newSource()}, nil).Once()
+		db.Image.On("AddFileID", testCtx, destID, srcFile).Return(errors.New("AddFileID error")).Once()
+
+		err := s.Merge(testCtx, []int{srcID}, destID)
+		assert.NotNil(t, err)
+		db.AssertExpectations(t)
+	})
+}
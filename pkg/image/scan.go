@@ -39,7 +39,9 @@ type GalleryFinderCreator interface {
 }
 
 type ScanConfig interface {
-	GetCreateGalleriesFromFolders() bool
+	// GetCreateGalleriesFromFolders returns true if a gallery should be
+	// created for the folder at path.
+	GetCreateGalleriesFromFolders(path string) bool
 }
 
 type ScanGenerator interface {
@@ -54,6 +56,10 @@ type ScanHandler struct {
 
 	ScanConfig ScanConfig
 
+	// ScanDefaults is optional. If set, it provides the tags and studio of
+	// new images and galleries.
+	ScanDefaults models.ScanDefaultsGetter
+
 	PluginCache *plugin.Cache
 
 	Paths *paths.Paths
@@ -110,6 +116,7 @@ func (h *ScanHandler) Handle(ctx context.Context, f models.File, oldFile models.
 		// create a new image
 		newImage := models.NewImage()
 		newImage.GalleryIDs = models.NewRelatedIDs([]int{})
+		h.applyScanDefaults(f.Base().Path, &newImage.TagIDs, &newImage.StudioID)
 
 		logger.Infof("%s doesn't exist. Creating new image...", f.Base().Path)
 
@@ -166,6 +173,12 @@ func (h *ScanHandler) Handle(ctx context.Context, f models.File, oldFile models.
 	return nil
 }
 
+func (h *ScanHandler) applyScanDefaults(path string, tagIDs *models.RelatedIDs, studioID **int) {
+	if h.ScanDefaults != nil {
+		h.ScanDefaults.GetScanDefaults(path).Apply(tagIDs, studioID)
+	}
+}
+
 func (h *ScanHandler) associateExisting(ctx context.Context, existing []*models.Image, f *models.BaseFile, updateExisting bool) error {
 	for _, i := range existing {
 		if err := i.LoadFiles(ctx, h.CreatorUpdater); err != nil {
@@ -250,6 +263,7 @@ func (h *ScanHandler) getOrCreateFolderBasedGallery(ctx context.Context, f model
 	// create a new folder-based gallery
 	newGallery := models.NewGallery()
 	newGallery.FolderID = &folderID
+	h.applyScanDefaults(f.Base().Path, &newGallery.TagIDs, &newGallery.StudioID)
 
 	logger.Infof("Creating folder-based gallery for %s", filepath.Dir(f.Base().Path))
 
@@ -304,6 +318,7 @@ func (h *ScanHandler) getOrCreateZipBasedGallery(ctx context.Context, zipFile mo
 
 	// create a new zip-based gallery
 	newGallery := models.NewGallery()
+	h.applyScanDefaults(zipFile.Base().Path, &newGallery.TagIDs, &newGallery.StudioID)
 
 	logger.Infof("%s doesn't exist. Creating new gallery...", zipFile.Base().Path)
 
@@ -338,7 +353,7 @@ func (h *ScanHandler) getOrCreateGallery(ctx context.Context, f models.File) (*m
 		return nil, fmt.Errorf("Could not test Path %s: %w", folderPath, err)
 	}
 
-	if forceGallery || (h.ScanConfig.GetCreateGalleriesFromFolders() && !exemptGallery) {
+	if forceGallery || (h.ScanConfig.GetCreateGalleriesFromFolders(folderPath) && !exemptGallery) {
 		return h.getOrCreateFolderBasedGallery(ctx, f)
 	}
 
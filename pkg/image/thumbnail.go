@@ -33,7 +33,9 @@ type ThumbnailEncoder struct {
 	FFMpeg             *ffmpeg.FFMpeg
 	FFProbe            ffmpeg.FFProbe
 	ClipPreviewOptions ClipPreviewOptions
-	vips               *vipsEncoder
+	// FS is used to read image files. Defaults to the OS file system.
+	FS   models.FS
+	vips *vipsEncoder
 }
 
 type ClipPreviewOptions struct {
@@ -54,6 +56,7 @@ func NewThumbnailEncoder(ffmpegEncoder *ffmpeg.FFMpeg, ffProbe ffmpeg.FFProbe, c
 		FFMpeg:             ffmpegEncoder,
 		FFProbe:            ffProbe,
 		ClipPreviewOptions: clipPreviewOptions,
+		FS:                 &file.OsFS{},
 	}
 
 	vipsPath := GetVipsPath()
@@ -70,7 +73,7 @@ func NewThumbnailEncoder(ffmpegEncoder *ffmpeg.FFMpeg, ffProbe ffmpeg.FFProbe, c
 // It returns nil and an error if an error occurs reading, decoding or encoding
 // the image, or if the image is not suitable for thumbnails.
 func (e *ThumbnailEncoder) GetThumbnail(f models.File, maxSize int) ([]byte, error) {
-	reader, err := f.Open(&file.OsFS{})
+	reader, err := f.Open(e.FS)
 	if err != nil {
 		return nil, err
 	}
@@ -56,6 +56,8 @@ type Job struct {
 	StartTime *time.Time
 	EndTime   *time.Time
 	AddTime   time.Time
+	// Report is an optional job-specific report of the results of the job.
+	Report interface{}
 
 	outerCtx   context.Context
 	exec       JobExec
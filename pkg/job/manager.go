@@ -409,3 +409,10 @@ func (u *updater) updateProgress(progress float64, details []string) {
 		u.notifyUpdate()
 	}
 }
+
+func (u *updater) updateReport(report interface{}) {
+	u.m.mutex.Lock()
+	defer u.m.mutex.Unlock()
+
+	u.job.Report = report
+}
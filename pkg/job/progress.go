@@ -163,6 +163,12 @@ func (p *Progress) removeTask(t *task) {
 	}
 }
 
+// SetReport sets the report of the job. The report is retained after the job
+// has finished.
+func (p *Progress) SetReport(report interface{}) {
+	p.updater.updateReport(report)
+}
+
 // ExecuteTask executes a task as part of a job. The description is used to
 // populate the Details slice in the parent Job.
 func (p *Progress) ExecuteTask(description string, fn func()) {
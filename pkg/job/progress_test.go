@@ -148,3 +148,15 @@ func TestExecuteTask(t *testing.T) {
 	assert.Len(j.Details, 0)
 	m.mutex.Unlock()
 }
+
+func TestProgressSetReport(t *testing.T) {
+	m := NewManager()
+	j := &Job{}
+
+	p := createProgress(m, j)
+
+	report := []string{"report"}
+	p.SetReport(report)
+
+	assert.Equal(t, report, j.Report)
+}
@@ -87,10 +87,24 @@ func allASCII(s string) bool {
 // nameMatchesPath returns the index in the path for the right-most match.
 // Returns -1 if not found.
 func nameMatchesPath(name, path string) int {
+	loc := nameMatchLoc(name, path)
+	if loc == nil {
+		return -1
+	}
+	return loc[0]
+}
+
+// nameMatchLoc returns the location in the lower-cased path of the right-most
+// match. Returns nil if not found.
+func nameMatchLoc(name, path string) []int {
 	// #2363 - optimisation: only use unicode character regexp if path contains
 	// unicode characters
 	re := nameToRegexp(name, !allASCII(path))
-	return regexpMatchesPath(re, path)
+	found := re.FindAllStringIndex(strings.ToLower(path), -1)
+	if found == nil {
+		return nil
+	}
+	return found[len(found)-1]
 }
 
 // nameToRegexp compiles a regexp pattern to match paths from the given name.
@@ -117,15 +131,6 @@ func nameToRegexp(name string, useUnicode bool) *regexp.Regexp {
 	return re
 }
 
-func regexpMatchesPath(r *regexp.Regexp, path string) int {
-	path = strings.ToLower(path)
-	found := r.FindAllStringIndex(path, -1)
-	if found == nil {
-		return -1
-	}
-	return found[len(found)-1][0]
-}
-
 func getPerformers(ctx context.Context, words []string, performerReader models.PerformerAutoTagQueryer, cache *Cache) ([]*models.Performer, error) {
 	performers, err := performerReader.QueryForAutoTag(ctx, words)
 	if err != nil {
@@ -140,7 +145,9 @@ func getPerformers(ctx context.Context, words []string, performerReader models.P
 	return append(performers, swPerformers...), nil
 }
 
-func PathToPerformers(ctx context.Context, path string, reader models.PerformerAutoTagQueryer, cache *Cache, trimExt bool) ([]*models.Performer, error) {
+// PathToPerformers returns the performers that match the given path, or the
+// given title where the auto-tag rules of the performer match titles.
+func PathToPerformers(ctx context.Context, path string, title string, reader models.PerformerAutoTagQueryer, cache *Cache, trimExt bool) ([]*models.Performer, error) {
 	words := getPathWords(path, trimExt)
 
 	performers, err := getPerformers(ctx, words, reader, cache)
@@ -151,7 +158,7 @@ func PathToPerformers(ctx context.Context, path string, reader models.PerformerA
 	var ret []*models.Performer
 	for _, p := range performers {
 		matches := false
-		if nameMatchesPath(p.Name, path) != -1 {
+		if namesMatch([]string{p.Name}, p.AutoTagRules, path, title) != -1 {
 			matches = true
 		}
 
@@ -192,10 +199,11 @@ func getStudios(ctx context.Context, words []string, reader models.StudioAutoTag
 	return append(studios, swStudios...), nil
 }
 
-// PathToStudio returns the Studio that matches the given path.
+// PathToStudio returns the Studio that matches the given path, or the given
+// title where the auto-tag rules of the studio match titles.
 // Where multiple matching studios are found, the one that matches the latest
 // position in the path is returned.
-func PathToStudio(ctx context.Context, path string, reader models.StudioAutoTagQueryer, cache *Cache, trimExt bool) (*models.Studio, error) {
+func PathToStudio(ctx context.Context, path string, title string, reader models.StudioAutoTagQueryer, cache *Cache, trimExt bool) (*models.Studio, error) {
 	words := getPathWords(path, trimExt)
 	candidates, err := getStudios(ctx, words, reader, cache)
 
@@ -206,23 +214,15 @@ func PathToStudio(ctx context.Context, path string, reader models.StudioAutoTagQ
 	var ret *models.Studio
 	index := -1
 	for _, c := range candidates {
-		matchIndex := nameMatchesPath(c.Name, path)
-		if matchIndex != -1 && matchIndex > index {
-			ret = c
-			index = matchIndex
-		}
-
 		aliases, err := reader.GetAliases(ctx, c.ID)
 		if err != nil {
 			return nil, err
 		}
 
-		for _, alias := range aliases {
-			matchIndex = nameMatchesPath(alias, path)
-			if matchIndex != -1 && matchIndex > index {
-				ret = c
-				index = matchIndex
-			}
+		matchIndex := namesMatch(append([]string{c.Name}, aliases...), c.AutoTagRules, path, title)
+		if matchIndex != -1 && matchIndex > index {
+			ret = c
+			index = matchIndex
 		}
 	}
 
@@ -243,7 +243,9 @@ func getTags(ctx context.Context, words []string, reader models.TagAutoTagQuerye
 	return append(tags, swTags...), nil
 }
 
-func PathToTags(ctx context.Context, path string, reader models.TagAutoTagQueryer, cache *Cache, trimExt bool) ([]*models.Tag, error) {
+// PathToTags returns the tags that match the given path, or the given title
+// where the auto-tag rules of the tag match titles.
+func PathToTags(ctx context.Context, path string, title string, reader models.TagAutoTagQueryer, cache *Cache, trimExt bool) ([]*models.Tag, error) {
 	words := getPathWords(path, trimExt)
 	tags, err := getTags(ctx, words, reader, cache)
 
@@ -254,7 +256,7 @@ func PathToTags(ctx context.Context, path string, reader models.TagAutoTagQuerye
 	var ret []*models.Tag
 	for _, t := range tags {
 		matches := false
-		if nameMatchesPath(t.Name, path) != -1 {
+		if namesMatch([]string{t.Name}, t.AutoTagRules, path, title) != -1 {
 			matches = true
 		}
 
@@ -263,11 +265,8 @@ func PathToTags(ctx context.Context, path string, reader models.TagAutoTagQuerye
 			if err != nil {
 				return nil, err
 			}
-			for _, alias := range aliases {
-				if nameMatchesPath(alias, path) != -1 {
-					matches = true
-					break
-				}
+			if namesMatch(aliases, t.AutoTagRules, path, title) != -1 {
+				matches = true
 			}
 		}
 
@@ -279,16 +278,24 @@ func PathToTags(ctx context.Context, path string, reader models.TagAutoTagQuerye
 	return ret, nil
 }
 
-func PathToScenesFn(ctx context.Context, name string, paths []string, sceneReader models.SceneQueryer, fn func(ctx context.Context, scene *models.Scene) error) error {
-	regex := getPathQueryRegex(name)
+// PathToScenesFn calls fn for each unorganized scene in the paths that
+// matches the name, honouring the auto-tag rules.
+func PathToScenesFn(ctx context.Context, name string, rules *models.AutoTagRules, paths []string, sceneReader models.SceneQueryer, fn func(ctx context.Context, scene *models.Scene) error) error {
+	criterion, byTitle := rulesQueryCriterion(name, rules)
+	if criterion == nil {
+		return nil
+	}
+
+	regex := criterion.Value
 	organized := false
 	filter := models.SceneFilterType{
-		Path: &models.StringCriterionInput{
-			Value:    "(?i)" + regex,
-			Modifier: models.CriterionModifierMatchesRegex,
-		},
 		Organized: &organized,
 	}
+	if byTitle {
+		filter.Title = criterion
+	} else {
+		filter.Path = criterion
+	}
 
 	filter.And = scene.PathsFilter(paths)
 
@@ -316,12 +323,8 @@ func PathToScenesFn(ctx context.Context, name string, paths []string, sceneReade
 			return fmt.Errorf("error querying scenes with regex '%s': %s", regex, err.Error())
 		}
 
-		// paths may have unicode characters
-		const useUnicode = true
-
-		r := nameToRegexp(name, useUnicode)
 		for _, p := range scenes {
-			if regexpMatchesPath(r, p.Path) != -1 {
+			if namesMatch([]string{name}, rules, p.Path, p.Title) != -1 {
 				if err := fn(ctx, p); err != nil {
 					return fmt.Errorf("processing scene %s: %w", p.GetTitle(), err)
 				}
@@ -338,16 +341,24 @@ func PathToScenesFn(ctx context.Context, name string, paths []string, sceneReade
 	return nil
 }
 
-func PathToImagesFn(ctx context.Context, name string, paths []string, imageReader models.ImageQueryer, fn func(ctx context.Context, scene *models.Image) error) error {
-	regex := getPathQueryRegex(name)
+// PathToImagesFn calls fn for each unorganized image in the paths that
+// matches the name, honouring the auto-tag rules.
+func PathToImagesFn(ctx context.Context, name string, rules *models.AutoTagRules, paths []string, imageReader models.ImageQueryer, fn func(ctx context.Context, scene *models.Image) error) error {
+	criterion, byTitle := rulesQueryCriterion(name, rules)
+	if criterion == nil {
+		return nil
+	}
+
+	regex := criterion.Value
 	organized := false
 	filter := models.ImageFilterType{
-		Path: &models.StringCriterionInput{
-			Value:    "(?i)" + regex,
-			Modifier: models.CriterionModifierMatchesRegex,
-		},
 		Organized: &organized,
 	}
+	if byTitle {
+		filter.Title = criterion
+	} else {
+		filter.Path = criterion
+	}
 
 	filter.And = image.PathsFilter(paths)
 
@@ -375,12 +386,8 @@ func PathToImagesFn(ctx context.Context, name string, paths []string, imageReade
 			return fmt.Errorf("error querying images with regex '%s': %s", regex, err.Error())
 		}
 
-		// paths may have unicode characters
-		const useUnicode = true
-
-		r := nameToRegexp(name, useUnicode)
 		for _, p := range images {
-			if regexpMatchesPath(r, p.Path) != -1 {
+			if namesMatch([]string{name}, rules, p.Path, p.Title) != -1 {
 				if err := fn(ctx, p); err != nil {
 					return fmt.Errorf("processing image %s: %w", p.GetTitle(), err)
 				}
@@ -397,16 +404,24 @@ func PathToImagesFn(ctx context.Context, name string, paths []string, imageReade
 	return nil
 }
 
-func PathToGalleriesFn(ctx context.Context, name string, paths []string, galleryReader models.GalleryQueryer, fn func(ctx context.Context, scene *models.Gallery) error) error {
-	regex := getPathQueryRegex(name)
+// PathToGalleriesFn calls fn for each unorganized gallery in the paths that
+// matches the name, honouring the auto-tag rules.
+func PathToGalleriesFn(ctx context.Context, name string, rules *models.AutoTagRules, paths []string, galleryReader models.GalleryQueryer, fn func(ctx context.Context, scene *models.Gallery) error) error {
+	criterion, byTitle := rulesQueryCriterion(name, rules)
+	if criterion == nil {
+		return nil
+	}
+
+	regex := criterion.Value
 	organized := false
 	filter := models.GalleryFilterType{
-		Path: &models.StringCriterionInput{
-			Value:    "(?i)" + regex,
-			Modifier: models.CriterionModifierMatchesRegex,
-		},
 		Organized: &organized,
 	}
+	if byTitle {
+		filter.Title = criterion
+	} else {
+		filter.Path = criterion
+	}
 
 	filter.And = gallery.PathsFilter(paths)
 
@@ -434,13 +449,8 @@ func PathToGalleriesFn(ctx context.Context, name string, paths []string, gallery
 			return fmt.Errorf("error querying galleries with regex '%s': %s", regex, err.Error())
 		}
 
-		// paths may have unicode characters
-		const useUnicode = true
-
-		r := nameToRegexp(name, useUnicode)
 		for _, p := range galleries {
-			path := p.Path
-			if path != "" && regexpMatchesPath(r, path) != -1 {
+			if namesMatch([]string{name}, rules, p.Path, p.Title) != -1 {
 				if err := fn(ctx, p); err != nil {
 					return fmt.Errorf("processing gallery %s: %w", p.GetTitle(), err)
 				}
@@ -0,0 +1,169 @@
+package match
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// ruleRegexps caches the compiled expressions of auto-tag rules, which are
+// matched against every candidate path.
+var ruleRegexps sync.Map
+
+func ruleRegexp(pattern string) *regexp.Regexp {
+	if v, ok := ruleRegexps.Load(pattern); ok {
+		return v.(*regexp.Regexp)
+	}
+
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		// rules are validated when saved, so this should not happen
+		logger.Warnf("Ignoring invalid auto-tag expression %q: %v", pattern, err)
+		re = nil
+	}
+
+	ruleRegexps.Store(pattern, re)
+	return re
+}
+
+// ruleTarget returns the value that the rules are matched against.
+func ruleTarget(rules *models.AutoTagRules, path, title string) string {
+	switch rules.GetTarget() {
+	case models.AutoTagMatchTargetFilename:
+		if path == "" {
+			return ""
+		}
+		return filepath.Base(path)
+	case models.AutoTagMatchTargetTitle:
+		return title
+	default:
+		return path
+	}
+}
+
+// nameAllowed returns false if the name is shorter than the minimum word
+// length of the rules, ignoring separators.
+func nameAllowed(rules *models.AutoTagRules, name string) bool {
+	if rules == nil || rules.MinWordLength <= 0 {
+		return true
+	}
+
+	return utf8.RuneCountInString(separatorRE.ReplaceAllString(name, "")) >= rules.MinWordLength
+}
+
+// excluded returns true if any exclude expression of the rules matches the
+// value.
+func excluded(rules *models.AutoTagRules, v string) bool {
+	if rules == nil {
+		return false
+	}
+
+	for _, p := range rules.Exclude {
+		if re := ruleRegexp(p); re != nil && re.MatchString(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// namesMatch returns the index of the right-most match of any of the names
+// or include expressions of the rules in the path or title, as selected by
+// the rules. Returns -1 if none match, or if an exclude expression matches.
+func namesMatch(names []string, rules *models.AutoTagRules, path, title string) int {
+	_, loc := namesMatchLoc(names, rules, path, title)
+	if loc == nil {
+		return -1
+	}
+	return loc[0]
+}
+
+// MatchedText returns the text of the path or title that the names or the
+// include expressions of the rules matched, as used by namesMatch. Returns
+// an empty string if none match.
+func MatchedText(names []string, rules *models.AutoTagRules, path, title string) string {
+	v, loc := namesMatchLoc(names, rules, path, title)
+	if loc == nil || loc[1] > len(v) {
+		return ""
+	}
+
+	// name matches include the surrounding separators
+	return strings.TrimFunc(v[loc[0]:loc[1]], func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// namesMatchLoc returns the value matched against and the location of the
+// right-most match in it. Name matches are located in the lower-cased value.
+func namesMatchLoc(names []string, rules *models.AutoTagRules, path, title string) (string, []int) {
+	v := ruleTarget(rules, path, title)
+	if v == "" || excluded(rules, v) {
+		return v, nil
+	}
+
+	var ret []int
+	for _, name := range names {
+		if !nameAllowed(rules, name) {
+			continue
+		}
+
+		if loc := nameMatchLoc(name, v); loc != nil && (ret == nil || loc[0] > ret[0]) {
+			ret = loc
+		}
+	}
+
+	if rules != nil {
+		for _, p := range rules.Include {
+			re := ruleRegexp(p)
+			if re == nil {
+				continue
+			}
+
+			if found := re.FindAllStringIndex(v, -1); found != nil {
+				if loc := found[len(found)-1]; ret == nil || loc[0] > ret[0] {
+					ret = loc
+				}
+			}
+		}
+	}
+
+	return v, ret
+}
+
+// rulesQueryCriterion returns the criterion used to query the objects that
+// may match the name with the rules, and whether it applies to the title
+// instead of the path. Returns nil if nothing can match.
+func rulesQueryCriterion(name string, rules *models.AutoTagRules) (*models.StringCriterionInput, bool) {
+	var patterns []string
+	if nameAllowed(rules, name) {
+		patterns = append(patterns, getPathQueryRegex(name))
+	}
+
+	if rules != nil {
+		for _, p := range rules.Include {
+			if ruleRegexp(p) != nil {
+				patterns = append(patterns, p)
+			}
+		}
+	}
+
+	if len(patterns) == 0 {
+		return nil, false
+	}
+
+	regex := patterns[0]
+	if len(patterns) > 1 {
+		regex = "(?:" + strings.Join(patterns, "|") + ")"
+	}
+
+	return &models.StringCriterionInput{
+		Value:    "(?i)" + regex,
+		Modifier: models.CriterionModifierMatchesRegex,
+	}, rules.GetTarget() == models.AutoTagMatchTargetTitle
+}
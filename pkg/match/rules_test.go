@@ -0,0 +1,163 @@
+package match
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func Test_namesMatch(t *testing.T) {
+	const (
+		path  = "/studio/ABC-JD scene.mp4"
+		title = "Scene with Jane Doe"
+	)
+
+	tests := []struct {
+		name  string
+		names []string
+		rules *models.AutoTagRules
+		want  int
+	}{
+		{
+			"no rules",
+			[]string{"jd"},
+			nil,
+			11,
+		},
+		{
+			"no match",
+			[]string{"jane doe"},
+			nil,
+			-1,
+		},
+		{
+			"min word length",
+			[]string{"jd"},
+			&models.AutoTagRules{MinWordLength: 3},
+			-1,
+		},
+		{
+			"min word length ignores separators",
+			[]string{"j.d"},
+			&models.AutoTagRules{MinWordLength: 3},
+			-1,
+		},
+		{
+			"include",
+			[]string{"jd"},
+			&models.AutoTagRules{Include: []string{`abc-jd\b`}, MinWordLength: 3},
+			8,
+		},
+		{
+			"exclude",
+			[]string{"jd"},
+			&models.AutoTagRules{Exclude: []string{`/studio/`}},
+			-1,
+		},
+		{
+			"exclude overrides include",
+			[]string{"jd"},
+			&models.AutoTagRules{Include: []string{`abc-jd`}, Exclude: []string{`scene`}},
+			-1,
+		},
+		{
+			"filename",
+			[]string{"studio"},
+			&models.AutoTagRules{Target: models.AutoTagMatchTargetFilename},
+			-1,
+		},
+		{
+			"title",
+			[]string{"jane doe"},
+			&models.AutoTagRules{Target: models.AutoTagMatchTargetTitle},
+			10,
+		},
+		{
+			"invalid expression ignored",
+			[]string{"jd"},
+			&models.AutoTagRules{Include: []string{`(`}, Exclude: []string{`(`}},
+			11,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, namesMatch(tt.names, tt.rules, path, title))
+		})
+	}
+}
+
+func Test_rulesQueryCriterion(t *testing.T) {
+	tests := []struct {
+		name        string
+		rules       *models.AutoTagRules
+		want        *models.StringCriterionInput
+		wantByTitle bool
+	}{
+		{
+			"no rules",
+			nil,
+			&models.StringCriterionInput{
+				Value:    "(?i)" + getPathQueryRegex("jd"),
+				Modifier: models.CriterionModifierMatchesRegex,
+			},
+			false,
+		},
+		{
+			"include",
+			&models.AutoTagRules{Include: []string{`abc-jd`}},
+			&models.StringCriterionInput{
+				Value:    "(?i)(?:" + getPathQueryRegex("jd") + "|abc-jd)",
+				Modifier: models.CriterionModifierMatchesRegex,
+			},
+			false,
+		},
+		{
+			"include only",
+			&models.AutoTagRules{Include: []string{`abc-jd`}, MinWordLength: 3, Target: models.AutoTagMatchTargetTitle},
+			&models.StringCriterionInput{
+				Value:    "(?i)abc-jd",
+				Modifier: models.CriterionModifierMatchesRegex,
+			},
+			true,
+		},
+		{
+			"nothing to match",
+			&models.AutoTagRules{MinWordLength: 3},
+			nil,
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotByTitle := rulesQueryCriterion("jd", tt.rules)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantByTitle, gotByTitle)
+		})
+	}
+}
+
+func TestMatchedText(t *testing.T) {
+	const (
+		path  = "/studio/ABC-JD scene.mp4"
+		title = "Scene with Jane Doe"
+	)
+
+	tests := []struct {
+		name  string
+		names []string
+		rules *models.AutoTagRules
+		want  string
+	}{
+		{"name", []string{"jd"}, nil, "JD"},
+		{"include", []string{"jane"}, &models.AutoTagRules{Include: []string{`abc-jd\b`}}, "ABC-JD"},
+		{"title", []string{"jane doe"}, &models.AutoTagRules{Target: models.AutoTagMatchTargetTitle}, "Jane Doe"},
+		{"no match", []string{"jane doe"}, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MatchedText(tt.names, tt.rules, path, title))
+		})
+	}
+}
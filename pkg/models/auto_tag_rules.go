@@ -0,0 +1,107 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+type AutoTagMatchTarget string
+
+const (
+	// Match against the full path of the file.
+	AutoTagMatchTargetPath AutoTagMatchTarget = "PATH"
+	// Match against the base name of the file only.
+	AutoTagMatchTargetFilename AutoTagMatchTarget = "FILENAME"
+	// Match against the title of the scene, image or gallery.
+	AutoTagMatchTargetTitle AutoTagMatchTarget = "TITLE"
+)
+
+var AllAutoTagMatchTarget = []AutoTagMatchTarget{
+	AutoTagMatchTargetPath,
+	AutoTagMatchTargetFilename,
+	AutoTagMatchTargetTitle,
+}
+
+func (e AutoTagMatchTarget) IsValid() bool {
+	switch e {
+	case AutoTagMatchTargetPath, AutoTagMatchTargetFilename, AutoTagMatchTargetTitle:
+		return true
+	}
+	return false
+}
+
+func (e AutoTagMatchTarget) String() string {
+	return string(e)
+}
+
+func (e *AutoTagMatchTarget) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AutoTagMatchTarget(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AutoTagMatchTarget", str)
+	}
+	return nil
+}
+
+func (e AutoTagMatchTarget) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// AutoTagRules customises how a performer, studio or tag is matched when
+// auto-tagging.
+type AutoTagRules struct {
+	// Case-insensitive regular expressions. The object matches if any of
+	// them matches, in addition to matching by name.
+	Include []string `json:"include,omitempty"`
+	// Case-insensitive regular expressions. The object never matches if any
+	// of them matches, even by name.
+	Exclude []string `json:"exclude,omitempty"`
+	// The value matched against. Defaults to the full path.
+	Target AutoTagMatchTarget `json:"target,omitempty"`
+	// Names and aliases shorter than this, ignoring separators, are not
+	// matched. Include expressions are not affected.
+	MinWordLength int `json:"min_word_length,omitempty"`
+}
+
+// IsEmpty returns true if the rules do not change the default matching.
+func (r *AutoTagRules) IsEmpty() bool {
+	return r == nil || (len(r.Include) == 0 && len(r.Exclude) == 0 && (r.Target == "" || r.Target == AutoTagMatchTargetPath) && r.MinWordLength <= 0)
+}
+
+// GetTarget returns the value matched against.
+func (r *AutoTagRules) GetTarget() AutoTagMatchTarget {
+	if r == nil || r.Target == "" {
+		return AutoTagMatchTargetPath
+	}
+	return r.Target
+}
+
+// Validate returns an error if the target is invalid or any of the
+// expressions cannot be compiled.
+func (r *AutoTagRules) Validate() error {
+	if r == nil {
+		return nil
+	}
+
+	if r.Target != "" && !r.Target.IsValid() {
+		return fmt.Errorf("invalid auto-tag target %q", r.Target)
+	}
+
+	if r.MinWordLength < 0 {
+		return fmt.Errorf("invalid minimum word length %d", r.MinWordLength)
+	}
+
+	for _, p := range append(append([]string{}, r.Include...), r.Exclude...) {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("invalid auto-tag expression %q: %w", p, err)
+		}
+	}
+
+	return nil
+}
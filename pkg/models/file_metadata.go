@@ -0,0 +1,52 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// FileMetadataPrecedence determines whether metadata read from files and
+// their sidecar files replaces existing values.
+type FileMetadataPrecedence string
+
+const (
+	// Existing values are kept. Only empty fields are set.
+	FileMetadataPrecedenceExisting FileMetadataPrecedence = "EXISTING"
+	// Values read from the file replace existing values.
+	FileMetadataPrecedenceFile FileMetadataPrecedence = "FILE"
+)
+
+var AllFileMetadataPrecedence = []FileMetadataPrecedence{
+	FileMetadataPrecedenceExisting,
+	FileMetadataPrecedenceFile,
+}
+
+func (e FileMetadataPrecedence) IsValid() bool {
+	switch e {
+	case FileMetadataPrecedenceExisting, FileMetadataPrecedenceFile:
+		return true
+	}
+	return false
+}
+
+func (e FileMetadataPrecedence) String() string {
+	return string(e)
+}
+
+func (e *FileMetadataPrecedence) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FileMetadataPrecedence(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FileMetadataPrecedence", str)
+	}
+	return nil
+}
+
+func (e FileMetadataPrecedence) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
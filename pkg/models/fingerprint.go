@@ -9,6 +9,9 @@ var (
 	FingerprintTypeOshash = "oshash"
 	FingerprintTypeMD5    = "md5"
 	FingerprintTypePhash  = "phash"
+	// FingerprintTypePhashSegments is a sequence of phashes of video frames taken
+	// at a fixed interval. See utils.PhashSegments for the format.
+	FingerprintTypePhashSegments = "phash_segments"
 )
 
 // Fingerprint represents a fingerprint of a file.
@@ -222,6 +222,29 @@ func (_m *FileReaderWriter) FindByFingerprint(ctx context.Context, fp models.Fin
 	return r0, r1
 }
 
+// FindByParentFolderID provides a mock function with given fields: ctx, parentFolderID
+func (_m *FileReaderWriter) FindByParentFolderID(ctx context.Context, parentFolderID models.FolderID) ([]models.File, error) {
+	ret := _m.Called(ctx, parentFolderID)
+
+	var r0 []models.File
+	if rf, ok := ret.Get(0).(func(context.Context, models.FolderID) []models.File); ok {
+		r0 = rf(ctx, parentFolderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.File)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, models.FolderID) error); ok {
+		r1 = rf(ctx, parentFolderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByPath provides a mock function with given fields: ctx, path
 func (_m *FileReaderWriter) FindByPath(ctx context.Context, path string) (models.File, error) {
 	ret := _m.Called(ctx, path)
@@ -268,6 +291,36 @@ func (_m *FileReaderWriter) FindByZipFileID(ctx context.Context, zipFileID model
 	return r0, r1
 }
 
+// FindDuplicates provides a mock function with given fields: ctx, fingerprintType, findFilter
+func (_m *FileReaderWriter) FindDuplicates(ctx context.Context, fingerprintType string, findFilter *models.FindFilterType) ([][]models.File, int, error) {
+	ret := _m.Called(ctx, fingerprintType, findFilter)
+
+	var r0 [][]models.File
+	if rf, ok := ret.Get(0).(func(context.Context, string, *models.FindFilterType) [][]models.File); ok {
+		r0 = rf(ctx, fingerprintType, findFilter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([][]models.File)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, string, *models.FindFilterType) int); ok {
+		r1 = rf(ctx, fingerprintType, findFilter)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, *models.FindFilterType) error); ok {
+		r2 = rf(ctx, fingerprintType, findFilter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetCaptions provides a mock function with given fields: ctx, fileID
 func (_m *FileReaderWriter) GetCaptions(ctx context.Context, fileID models.FileID) ([]*models.VideoCaption, error) {
 	ret := _m.Called(ctx, fileID)
@@ -234,6 +234,29 @@ func (_m *GalleryReaderWriter) FindByFileID(ctx context.Context, fileID models.F
 	return r0, r1
 }
 
+// FindByFileIDs provides a mock function with given fields: ctx, fileIDs
+func (_m *GalleryReaderWriter) FindByFileIDs(ctx context.Context, fileIDs []models.FileID) ([]*models.Gallery, error) {
+	ret := _m.Called(ctx, fileIDs)
+
+	var r0 []*models.Gallery
+	if rf, ok := ret.Get(0).(func(context.Context, []models.FileID) []*models.Gallery); ok {
+		r0 = rf(ctx, fileIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Gallery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []models.FileID) error); ok {
+		r1 = rf(ctx, fileIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByFingerprints provides a mock function with given fields: ctx, fp
 func (_m *GalleryReaderWriter) FindByFingerprints(ctx context.Context, fp []models.Fingerprint) ([]*models.Gallery, error) {
 	ret := _m.Called(ctx, fp)
@@ -386,6 +386,29 @@ func (_m *SceneReaderWriter) FindByFileID(ctx context.Context, fileID models.Fil
 	return r0, r1
 }
 
+// FindByFileIDs provides a mock function with given fields: ctx, fileIDs
+func (_m *SceneReaderWriter) FindByFileIDs(ctx context.Context, fileIDs []models.FileID) ([]*models.Scene, error) {
+	ret := _m.Called(ctx, fileIDs)
+
+	var r0 []*models.Scene
+	if rf, ok := ret.Get(0).(func(context.Context, []models.FileID) []*models.Scene); ok {
+		r0 = rf(ctx, fileIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Scene)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []models.FileID) error); ok {
+		r1 = rf(ctx, fileIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByFingerprints provides a mock function with given fields: ctx, fp
 func (_m *SceneReaderWriter) FindByFingerprints(ctx context.Context, fp []models.Fingerprint) ([]*models.Scene, error) {
 	ret := _m.Called(ctx, fp)
@@ -593,6 +616,29 @@ func (_m *SceneReaderWriter) FindMany(ctx context.Context, ids []int) ([]*models
 	return r0, r1
 }
 
+// FindSegmentOverlaps provides a mock function with given fields: ctx, distance, minOverlap
+func (_m *SceneReaderWriter) FindSegmentOverlaps(ctx context.Context, distance int, minOverlap float64) ([]*models.SceneOverlap, error) {
+	ret := _m.Called(ctx, distance, minOverlap)
+
+	var r0 []*models.SceneOverlap
+	if rf, ok := ret.Get(0).(func(context.Context, int, float64) []*models.SceneOverlap); ok {
+		r0 = rf(ctx, distance, minOverlap)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.SceneOverlap)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int, float64) error); ok {
+		r1 = rf(ctx, distance, minOverlap)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCover provides a mock function with given fields: ctx, sceneID
 func (_m *SceneReaderWriter) GetCover(ctx context.Context, sceneID int) ([]byte, error) {
 	ret := _m.Called(ctx, sceneID)
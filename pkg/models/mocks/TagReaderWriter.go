@@ -404,6 +404,29 @@ func (_m *TagReaderWriter) FindBySceneMarkerID(ctx context.Context, sceneMarkerI
 	return r0, r1
 }
 
+// FindByStashID provides a mock function with given fields: ctx, stashID
+func (_m *TagReaderWriter) FindByStashID(ctx context.Context, stashID models.StashID) ([]*models.Tag, error) {
+	ret := _m.Called(ctx, stashID)
+
+	var r0 []*models.Tag
+	if rf, ok := ret.Get(0).(func(context.Context, models.StashID) []*models.Tag); ok {
+		r0 = rf(ctx, stashID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Tag)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, models.StashID) error); ok {
+		r1 = rf(ctx, stashID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindMany provides a mock function with given fields: ctx, ids
 func (_m *TagReaderWriter) FindMany(ctx context.Context, ids []int) ([]*models.Tag, error) {
 	ret := _m.Called(ctx, ids)
@@ -473,6 +496,29 @@ func (_m *TagReaderWriter) GetImage(ctx context.Context, tagID int) ([]byte, err
 	return r0, r1
 }
 
+// GetStashIDs provides a mock function with given fields: ctx, relatedID
+func (_m *TagReaderWriter) GetStashIDs(ctx context.Context, relatedID int) ([]models.StashID, error) {
+	ret := _m.Called(ctx, relatedID)
+
+	var r0 []models.StashID
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.StashID); ok {
+		r0 = rf(ctx, relatedID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.StashID)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, relatedID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // HasImage provides a mock function with given fields: ctx, tagID
 func (_m *TagReaderWriter) HasImage(ctx context.Context, tagID int) (bool, error) {
 	ret := _m.Called(ctx, tagID)
@@ -653,3 +699,17 @@ func (_m *TagReaderWriter) UpdatePartial(ctx context.Context, id int, updateTag
 
 	return r0, r1
 }
+
+// UpdateStashIDs provides a mock function with given fields: ctx, tagID, stashIDs
+func (_m *TagReaderWriter) UpdateStashIDs(ctx context.Context, tagID int, stashIDs []models.StashID) error {
+	ret := _m.Called(ctx, tagID, stashIDs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []models.StashID) error); ok {
+		r0 = rf(ctx, tagID, stashIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
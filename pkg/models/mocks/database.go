@@ -9,18 +9,20 @@ import (
 )
 
 type Database struct {
-	File           *FileReaderWriter
-	Folder         *FolderReaderWriter
-	Gallery        *GalleryReaderWriter
-	GalleryChapter *GalleryChapterReaderWriter
-	Image          *ImageReaderWriter
-	Movie          *MovieReaderWriter
-	Performer      *PerformerReaderWriter
-	Scene          *SceneReaderWriter
-	SceneMarker    *SceneMarkerReaderWriter
-	Studio         *StudioReaderWriter
-	Tag            *TagReaderWriter
-	SavedFilter    *SavedFilterReaderWriter
+	File                  *FileReaderWriter
+	Folder                *FolderReaderWriter
+	Gallery               *GalleryReaderWriter
+	GalleryChapter        *GalleryChapterReaderWriter
+	Image                 *ImageReaderWriter
+	Movie                 *MovieReaderWriter
+	Performer             *PerformerReaderWriter
+	Scene                 *SceneReaderWriter
+	SceneMarker           *SceneMarkerReaderWriter
+	Studio                *StudioReaderWriter
+	Tag                   *TagReaderWriter
+	SavedFilter           *SavedFilterReaderWriter
+	PendingSceneChange    *PendingSceneChangeReaderWriter
+	FingerprintSubmission *FingerprintSubmissionReaderWriter
 }
 
 func (*Database) Begin(ctx context.Context, exclusive bool) (context.Context, error) {
@@ -58,18 +60,20 @@ func (*Database) Reset() error {
 
 func NewDatabase() *Database {
 	return &Database{
-		File:           &FileReaderWriter{},
-		Folder:         &FolderReaderWriter{},
-		Gallery:        &GalleryReaderWriter{},
-		GalleryChapter: &GalleryChapterReaderWriter{},
-		Image:          &ImageReaderWriter{},
-		Movie:          &MovieReaderWriter{},
-		Performer:      &PerformerReaderWriter{},
-		Scene:          &SceneReaderWriter{},
-		SceneMarker:    &SceneMarkerReaderWriter{},
-		Studio:         &StudioReaderWriter{},
-		Tag:            &TagReaderWriter{},
-		SavedFilter:    &SavedFilterReaderWriter{},
+		File:                  &FileReaderWriter{},
+		Folder:                &FolderReaderWriter{},
+		Gallery:               &GalleryReaderWriter{},
+		GalleryChapter:        &GalleryChapterReaderWriter{},
+		Image:                 &ImageReaderWriter{},
+		Movie:                 &MovieReaderWriter{},
+		Performer:             &PerformerReaderWriter{},
+		Scene:                 &SceneReaderWriter{},
+		SceneMarker:           &SceneMarkerReaderWriter{},
+		Studio:                &StudioReaderWriter{},
+		Tag:                   &TagReaderWriter{},
+		SavedFilter:           &SavedFilterReaderWriter{},
+		PendingSceneChange:    &PendingSceneChangeReaderWriter{},
+		FingerprintSubmission: &FingerprintSubmissionReaderWriter{},
 	}
 }
 
@@ -86,22 +90,26 @@ func (db *Database) AssertExpectations(t mock.TestingT) {
 	db.Studio.AssertExpectations(t)
 	db.Tag.AssertExpectations(t)
 	db.SavedFilter.AssertExpectations(t)
+	db.PendingSceneChange.AssertExpectations(t)
+	db.FingerprintSubmission.AssertExpectations(t)
 }
 
 func (db *Database) Repository() models.Repository {
 	return models.Repository{
-		TxnManager:     db,
-		File:           db.File,
-		Folder:         db.Folder,
-		Gallery:        db.Gallery,
-		GalleryChapter: db.GalleryChapter,
-		Image:          db.Image,
-		Movie:          db.Movie,
-		Performer:      db.Performer,
-		Scene:          db.Scene,
-		SceneMarker:    db.SceneMarker,
-		Studio:         db.Studio,
-		Tag:            db.Tag,
-		SavedFilter:    db.SavedFilter,
+		TxnManager:            db,
+		File:                  db.File,
+		Folder:                db.Folder,
+		Gallery:               db.Gallery,
+		GalleryChapter:        db.GalleryChapter,
+		Image:                 db.Image,
+		Movie:                 db.Movie,
+		Performer:             db.Performer,
+		Scene:                 db.Scene,
+		SceneMarker:           db.SceneMarker,
+		Studio:                db.Studio,
+		Tag:                   db.Tag,
+		SavedFilter:           db.SavedFilter,
+		PendingSceneChange:    db.PendingSceneChange,
+		FingerprintSubmission: db.FingerprintSubmission,
 	}
 }
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type FingerprintSubmissionStatus string
+
+const (
+	// The fingerprints are waiting to be submitted.
+	FingerprintSubmissionStatusPending FingerprintSubmissionStatus = "PENDING"
+	// The fingerprints were submitted.
+	FingerprintSubmissionStatusSubmitted FingerprintSubmissionStatus = "SUBMITTED"
+	// The fingerprints could not be submitted after the maximum number of
+	// attempts.
+	FingerprintSubmissionStatusFailed FingerprintSubmissionStatus = "FAILED"
+)
+
+var AllFingerprintSubmissionStatus = []FingerprintSubmissionStatus{
+	FingerprintSubmissionStatusPending,
+	FingerprintSubmissionStatusSubmitted,
+	FingerprintSubmissionStatusFailed,
+}
+
+func (e FingerprintSubmissionStatus) IsValid() bool {
+	switch e {
+	case FingerprintSubmissionStatusPending, FingerprintSubmissionStatusSubmitted, FingerprintSubmissionStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e FingerprintSubmissionStatus) String() string {
+	return string(e)
+}
+
+func (e *FingerprintSubmissionStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FingerprintSubmissionStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FingerprintSubmissionStatus", str)
+	}
+	return nil
+}
+
+func (e FingerprintSubmissionStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// FingerprintSubmission is the submission state of the fingerprints of a
+// scene to the stash-box scene it is linked to. A pending submission is
+// created whenever a scene is linked to a stash-box scene.
+type FingerprintSubmission struct {
+	SceneID  int                         `json:"scene_id"`
+	Endpoint string                      `json:"endpoint"`
+	StashID  string                      `json:"stash_id"`
+	Status   FingerprintSubmissionStatus `json:"status"`
+	// Number of failed attempts since the submission was queued.
+	Attempts      int        `json:"attempts"`
+	Error         string     `json:"error"`
+	LastAttemptAt *time.Time `json:"last_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type FingerprintSubmissionCriterionInput struct {
+	// If present, only submissions to this stash-box endpoint are considered
+	Endpoint *string                       `json:"endpoint"`
+	Status   []FingerprintSubmissionStatus `json:"status"`
+	Modifier CriterionModifier             `json:"modifier"`
+}
@@ -42,6 +42,10 @@ type Folder struct {
 	Path           string    `json:"path"`
 	ParentFolderID *FolderID `json:"parent_folder_id"`
 
+	// ChildCount is the number of entries in the folder when its files were
+	// last scanned. It is nil if the folder has changed since.
+	ChildCount *int `json:"child_count"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -33,7 +33,7 @@ func (u *UpdateMovieIDs) SceneMovieInputs() []SceneMovieInput {
 		return nil
 	}
 
-	ret := make([]SceneMovieInput, len(u.Movies))
+	ret := make([]SceneMovieInput, 0, len(u.Movies))
 	for _, id := range u.Movies {
 		ret = append(ret, id.SceneMovieInput())
 	}
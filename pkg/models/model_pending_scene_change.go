@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// PendingSceneChange is a set of changes to a scene that are awaiting review.
+type PendingSceneChange struct {
+	ID      int `json:"id"`
+	SceneID int `json:"scene_id"`
+	// Source is the name of the source that proposed the changes.
+	Source string `json:"source"`
+	// Data is the JSON-encoded proposal. It is interpreted by the creator of
+	// the pending change.
+	Data      string    `json:"data"`
+	CreatedAt time.Time `json:"created_at"`
+}
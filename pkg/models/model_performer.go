@@ -29,12 +29,13 @@ type Performer struct {
 	CreatedAt      time.Time       `json:"created_at"`
 	UpdatedAt      time.Time       `json:"updated_at"`
 	// Rating expressed in 1-100 scale
-	Rating        *int   `json:"rating"`
-	Details       string `json:"details"`
-	DeathDate     *Date  `json:"death_date"`
-	HairColor     string `json:"hair_color"`
-	Weight        *int   `json:"weight"`
-	IgnoreAutoTag bool   `json:"ignore_auto_tag"`
+	Rating        *int          `json:"rating"`
+	Details       string        `json:"details"`
+	DeathDate     *Date         `json:"death_date"`
+	HairColor     string        `json:"hair_color"`
+	Weight        *int          `json:"weight"`
+	IgnoreAutoTag bool          `json:"ignore_auto_tag"`
+	AutoTagRules  *AutoTagRules `json:"auto_tag_rules"`
 
 	Aliases  RelatedStrings  `json:"aliases"`
 	TagIDs   RelatedIDs      `json:"tag_ids"`
@@ -80,6 +81,8 @@ type PerformerPartial struct {
 	HairColor     OptionalString
 	Weight        OptionalInt
 	IgnoreAutoTag OptionalBool
+	// Replaces the auto-tag rules if not nil. Empty rules remove the rules.
+	AutoTagRules *AutoTagRules
 
 	Aliases  *UpdateStrings
 	TagIDs   *UpdateIDs
@@ -16,6 +16,8 @@ type ScrapedStudio struct {
 	Parent       *ScrapedStudio `json:"parent"`
 	Image        *string        `json:"image"`
 	Images       []string       `json:"images"`
+	Details      *string        `json:"details"`
+	Aliases      *string        `json:"aliases"`
 	RemoteSiteID *string        `json:"remote_site_id"`
 }
 
@@ -371,6 +373,8 @@ type ScrapedMovie struct {
 	URL      *string        `json:"url"`
 	Synopsis *string        `json:"synopsis"`
 	Studio   *ScrapedStudio `json:"studio"`
+	// index of the scene in the movie, when scraped with a scene
+	SceneIndex *string `json:"scene_index"`
 	// This should be a base64 encoded data URL
 	FrontImage *string `json:"front_image"`
 	// This should be a base64 encoded data URL
@@ -378,3 +382,12 @@ type ScrapedMovie struct {
 }
 
 func (ScrapedMovie) IsScrapedContent() {}
+
+// ScrapedSceneMarker is a scene marker scraped with a scene.
+type ScrapedSceneMarker struct {
+	Title *string `json:"title"`
+	// start time of the marker in seconds
+	Seconds *string `json:"seconds"`
+	// name of the primary tag of the marker
+	PrimaryTag *string `json:"primary_tag"`
+}
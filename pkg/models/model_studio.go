@@ -13,9 +13,10 @@ type Studio struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	// Rating expressed in 1-100 scale
-	Rating        *int   `json:"rating"`
-	Details       string `json:"details"`
-	IgnoreAutoTag bool   `json:"ignore_auto_tag"`
+	Rating        *int          `json:"rating"`
+	Details       string        `json:"details"`
+	IgnoreAutoTag bool          `json:"ignore_auto_tag"`
+	AutoTagRules  *AutoTagRules `json:"auto_tag_rules"`
 
 	Aliases  RelatedStrings  `json:"aliases"`
 	StashIDs RelatedStashIDs `json:"stash_ids"`
@@ -41,6 +42,8 @@ type StudioPartial struct {
 	CreatedAt     OptionalTime
 	UpdatedAt     OptionalTime
 	IgnoreAutoTag OptionalBool
+	// Replaces the auto-tag rules if not nil. Empty rules remove the rules.
+	AutoTagRules *AutoTagRules
 
 	Aliases  *UpdateStrings
 	StashIDs *UpdateStashIDs
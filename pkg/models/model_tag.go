@@ -5,12 +5,13 @@ import (
 )
 
 type Tag struct {
-	ID            int       `json:"id"`
-	Name          string    `json:"name"`
-	Description   string    `json:"description"`
-	IgnoreAutoTag bool      `json:"ignore_auto_tag"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID            int           `json:"id"`
+	Name          string        `json:"name"`
+	Description   string        `json:"description"`
+	IgnoreAutoTag bool          `json:"ignore_auto_tag"`
+	AutoTagRules  *AutoTagRules `json:"auto_tag_rules"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
 }
 
 func NewTag() Tag {
@@ -25,8 +26,10 @@ type TagPartial struct {
 	Name          OptionalString
 	Description   OptionalString
 	IgnoreAutoTag OptionalBool
-	CreatedAt     OptionalTime
-	UpdatedAt     OptionalTime
+	// Replaces the auto-tag rules if not nil. Empty rules remove the rules.
+	AutoTagRules *AutoTagRules
+	CreatedAt    OptionalTime
+	UpdatedAt    OptionalTime
 }
 
 func NewTagPartial() TagPartial {
@@ -21,6 +21,8 @@ type generatedPaths struct {
 	Downloads          string
 	Tmp                string
 	InteractiveHeatmap string
+	// report of the last auto-tag dry run
+	AutoTagReport string
 }
 
 func newGeneratedPaths(path string) *generatedPaths {
@@ -33,6 +35,7 @@ func newGeneratedPaths(path string) *generatedPaths {
 	gp.Downloads = filepath.Join(path, "download_stage")
 	gp.Tmp = filepath.Join(path, "tmp")
 	gp.InteractiveHeatmap = filepath.Join(path, "interactive_heatmaps")
+	gp.AutoTagReport = filepath.Join(path, "autotag_report.json")
 	return &gp
 }
 
@@ -217,14 +217,15 @@ type PerformerCreateInput struct {
 	Favorite       *bool           `json:"favorite"`
 	TagIds         []string        `json:"tag_ids"`
 	// This should be a URL or a base64 encoded data URL
-	Image         *string   `json:"image"`
-	StashIds      []StashID `json:"stash_ids"`
-	Rating100     *int      `json:"rating100"`
-	Details       *string   `json:"details"`
-	DeathDate     *string   `json:"death_date"`
-	HairColor     *string   `json:"hair_color"`
-	Weight        *int      `json:"weight"`
-	IgnoreAutoTag *bool     `json:"ignore_auto_tag"`
+	Image         *string       `json:"image"`
+	StashIds      []StashID     `json:"stash_ids"`
+	Rating100     *int          `json:"rating100"`
+	Details       *string       `json:"details"`
+	DeathDate     *string       `json:"death_date"`
+	HairColor     *string       `json:"hair_color"`
+	Weight        *int          `json:"weight"`
+	IgnoreAutoTag *bool         `json:"ignore_auto_tag"`
+	AutoTagRules  *AutoTagRules `json:"auto_tag_rules"`
 }
 
 type PerformerUpdateInput struct {
@@ -253,12 +254,13 @@ type PerformerUpdateInput struct {
 	Favorite       *bool           `json:"favorite"`
 	TagIds         []string        `json:"tag_ids"`
 	// This should be a URL or a base64 encoded data URL
-	Image         *string   `json:"image"`
-	StashIds      []StashID `json:"stash_ids"`
-	Rating100     *int      `json:"rating100"`
-	Details       *string   `json:"details"`
-	DeathDate     *string   `json:"death_date"`
-	HairColor     *string   `json:"hair_color"`
-	Weight        *int      `json:"weight"`
-	IgnoreAutoTag *bool     `json:"ignore_auto_tag"`
+	Image         *string       `json:"image"`
+	StashIds      []StashID     `json:"stash_ids"`
+	Rating100     *int          `json:"rating100"`
+	Details       *string       `json:"details"`
+	DeathDate     *string       `json:"death_date"`
+	HairColor     *string       `json:"hair_color"`
+	Weight        *int          `json:"weight"`
+	IgnoreAutoTag *bool         `json:"ignore_auto_tag"`
+	AutoTagRules  *AutoTagRules `json:"auto_tag_rules"`
 }
@@ -14,18 +14,20 @@ type TxnManager interface {
 type Repository struct {
 	TxnManager TxnManager
 
-	File           FileReaderWriter
-	Folder         FolderReaderWriter
-	Gallery        GalleryReaderWriter
-	GalleryChapter GalleryChapterReaderWriter
-	Image          ImageReaderWriter
-	Movie          MovieReaderWriter
-	Performer      PerformerReaderWriter
-	Scene          SceneReaderWriter
-	SceneMarker    SceneMarkerReaderWriter
-	Studio         StudioReaderWriter
-	Tag            TagReaderWriter
-	SavedFilter    SavedFilterReaderWriter
+	File                  FileReaderWriter
+	Folder                FolderReaderWriter
+	Gallery               GalleryReaderWriter
+	GalleryChapter        GalleryChapterReaderWriter
+	Image                 ImageReaderWriter
+	Movie                 MovieReaderWriter
+	Performer             PerformerReaderWriter
+	Scene                 SceneReaderWriter
+	SceneMarker           SceneMarkerReaderWriter
+	Studio                StudioReaderWriter
+	Tag                   TagReaderWriter
+	SavedFilter           SavedFilterReaderWriter
+	PendingSceneChange    PendingSceneChangeReaderWriter
+	FingerprintSubmission FingerprintSubmissionReaderWriter
 }
 
 func (r *Repository) WithTxn(ctx context.Context, fn txn.TxnFunc) error {
@@ -18,6 +18,7 @@ type FileFinder interface {
 	FindByPath(ctx context.Context, path string) (File, error)
 	FindByFingerprint(ctx context.Context, fp Fingerprint) ([]File, error)
 	FindByZipFileID(ctx context.Context, zipFileID FileID) ([]File, error)
+	FindByParentFolderID(ctx context.Context, parentFolderID FolderID) ([]File, error)
 	FindByFileInfo(ctx context.Context, info fs.FileInfo, size int64) ([]File, error)
 }
 
@@ -68,6 +69,7 @@ type FileReader interface {
 	FileQueryer
 	FileCounter
 
+	FindDuplicates(ctx context.Context, fingerprintType string, findFilter *FindFilterType) ([][]File, int, error)
 	GetCaptions(ctx context.Context, fileID FileID) ([]*VideoCaption, error)
 	IsPrimary(ctx context.Context, fileID FileID) (bool, error)
 }
@@ -0,0 +1,35 @@
+package models
+
+import "context"
+
+// FingerprintSubmissionFinder provides methods to find fingerprint submissions.
+type FingerprintSubmissionFinder interface {
+	FindBySceneID(ctx context.Context, sceneID int) ([]*FingerprintSubmission, error)
+	// FindPending returns the pending submissions to the endpoint, oldest
+	// first.
+	FindPending(ctx context.Context, endpoint string) ([]*FingerprintSubmission, error)
+}
+
+// FingerprintSubmissionUpdater provides methods to update fingerprint submissions.
+type FingerprintSubmissionUpdater interface {
+	Update(ctx context.Context, updatedObject *FingerprintSubmission) error
+	// Requeue sets the submissions of the scenes to the endpoint to pending,
+	// and resets their attempts. Submissions are created for linked scenes
+	// that have none.
+	Requeue(ctx context.Context, sceneIDs []int, endpoint string) error
+	// QueueUnsubmitted creates pending submissions for all scenes linked
+	// to the endpoint that have none. It returns the number of submissions
+	// created.
+	QueueUnsubmitted(ctx context.Context, endpoint string) (int, error)
+	// SyncScene creates pending submissions for the stash ids of the scene
+	// that have none, and removes the submissions of stash ids that the
+	// scene no longer has. It must be called whenever the stash ids of a
+	// scene are changed.
+	SyncScene(ctx context.Context, sceneID int) error
+}
+
+// FingerprintSubmissionReaderWriter provides all fingerprint submission methods.
+type FingerprintSubmissionReaderWriter interface {
+	FingerprintSubmissionFinder
+	FingerprintSubmissionUpdater
+}
@@ -17,6 +17,7 @@ type GalleryFinder interface {
 	FindByChecksums(ctx context.Context, checksums []string) ([]*Gallery, error)
 	FindByPath(ctx context.Context, path string) ([]*Gallery, error)
 	FindByFileID(ctx context.Context, fileID FileID) ([]*Gallery, error)
+	FindByFileIDs(ctx context.Context, fileIDs []FileID) ([]*Gallery, error)
 	FindByFolderID(ctx context.Context, folderID FolderID) ([]*Gallery, error)
 	FindBySceneID(ctx context.Context, sceneID int) ([]*Gallery, error)
 	FindByImageID(ctx context.Context, imageID int) ([]*Gallery, error)
@@ -0,0 +1,50 @@
+package models
+
+import "context"
+
+// PendingSceneChangeGetter provides methods to get pending scene changes by ID.
+type PendingSceneChangeGetter interface {
+	Find(ctx context.Context, id int) (*PendingSceneChange, error)
+	FindMany(ctx context.Context, ids []int) ([]*PendingSceneChange, error)
+}
+
+// PendingSceneChangeFinder provides methods to find pending scene changes.
+type PendingSceneChangeFinder interface {
+	PendingSceneChangeGetter
+	FindBySceneID(ctx context.Context, sceneID int) (*PendingSceneChange, error)
+}
+
+// PendingSceneChangeQueryer provides methods to query pending scene changes.
+type PendingSceneChangeQueryer interface {
+	Query(ctx context.Context, findFilter *FindFilterType) ([]*PendingSceneChange, int, error)
+}
+
+// PendingSceneChangeCreator provides methods to create pending scene changes.
+type PendingSceneChangeCreator interface {
+	// Create creates a pending change, replacing any existing pending change
+	// for the same scene.
+	Create(ctx context.Context, newObject *PendingSceneChange) error
+}
+
+// PendingSceneChangeDestroyer provides methods to destroy pending scene changes.
+type PendingSceneChangeDestroyer interface {
+	Destroy(ctx context.Context, ids []int) error
+}
+
+// PendingSceneChangeReader provides all methods to read pending scene changes.
+type PendingSceneChangeReader interface {
+	PendingSceneChangeFinder
+	PendingSceneChangeQueryer
+}
+
+// PendingSceneChangeWriter provides all methods to modify pending scene changes.
+type PendingSceneChangeWriter interface {
+	PendingSceneChangeCreator
+	PendingSceneChangeDestroyer
+}
+
+// PendingSceneChangeReaderWriter provides all pending scene change methods.
+type PendingSceneChangeReaderWriter interface {
+	PendingSceneChangeReader
+	PendingSceneChangeWriter
+}
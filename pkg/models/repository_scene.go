@@ -17,11 +17,13 @@ type SceneFinder interface {
 	FindByOSHash(ctx context.Context, oshash string) ([]*Scene, error)
 	FindByPath(ctx context.Context, path string) ([]*Scene, error)
 	FindByFileID(ctx context.Context, fileID FileID) ([]*Scene, error)
+	FindByFileIDs(ctx context.Context, fileIDs []FileID) ([]*Scene, error)
 	FindByPrimaryFileID(ctx context.Context, fileID FileID) ([]*Scene, error)
 	FindByPerformerID(ctx context.Context, performerID int) ([]*Scene, error)
 	FindByGalleryID(ctx context.Context, performerID int) ([]*Scene, error)
 	FindByMovieID(ctx context.Context, movieID int) ([]*Scene, error)
 	FindDuplicates(ctx context.Context, distance int, durationDiff float64) ([][]*Scene, error)
+	FindSegmentOverlaps(ctx context.Context, distance int, minOverlap float64) ([]*SceneOverlap, error)
 }
 
 // SceneQueryer provides methods to query scenes.
@@ -23,6 +23,7 @@ type TagFinder interface {
 	FindBySceneMarkerID(ctx context.Context, sceneMarkerID int) ([]*Tag, error)
 	FindByName(ctx context.Context, name string, nocase bool) (*Tag, error)
 	FindByNames(ctx context.Context, names []string, nocase bool) ([]*Tag, error)
+	FindByStashID(ctx context.Context, stashID StashID) ([]*Tag, error)
 }
 
 // TagQueryer provides methods to query tags.
@@ -56,6 +57,7 @@ type TagUpdater interface {
 	Update(ctx context.Context, updatedTag *Tag) error
 	UpdatePartial(ctx context.Context, id int, updateTag TagPartial) (*Tag, error)
 	UpdateAliases(ctx context.Context, tagID int, aliases []string) error
+	UpdateStashIDs(ctx context.Context, tagID int, stashIDs []StashID) error
 	UpdateImage(ctx context.Context, tagID int, image []byte) error
 	UpdateParentTags(ctx context.Context, tagID int, parentIDs []int) error
 	UpdateChildTags(ctx context.Context, tagID int, parentIDs []int) error
@@ -84,6 +86,7 @@ type TagReader interface {
 	TagCounter
 
 	AliasLoader
+	StashIDLoader
 
 	All(ctx context.Context) ([]*Tag, error)
 	GetImage(ctx context.Context, tagID int) ([]byte, error)
@@ -0,0 +1,25 @@
+package models
+
+// ScanDefaults are the values set on scenes, images and galleries that are
+// created by the scan.
+type ScanDefaults struct {
+	TagIDs   []int
+	StudioID *int
+}
+
+// ScanDefaultsGetter returns the defaults of objects created from the file
+// or folder at path.
+type ScanDefaultsGetter interface {
+	GetScanDefaults(path string) ScanDefaults
+}
+
+// Apply sets the defaults on the tag ids and studio id of a new object.
+func (d ScanDefaults) Apply(tagIDs *RelatedIDs, studioID **int) {
+	if len(d.TagIDs) > 0 {
+		*tagIDs = NewRelatedIDs(d.TagIDs)
+	}
+	if d.StudioID != nil {
+		id := *d.StudioID
+		*studioID = &id
+	}
+}
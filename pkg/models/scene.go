@@ -75,6 +75,8 @@ type SceneFilterType struct {
 	StashID *StringCriterionInput `json:"stash_id"`
 	// Filter by StashID Endpoint
 	StashIDEndpoint *StashIDCriterionInput `json:"stash_id_endpoint"`
+	// Filter by fingerprint submission status
+	FingerprintSubmission *FingerprintSubmissionCriterionInput `json:"fingerprint_submission"`
 	// Filter by url
 	URL *StringCriterionInput `json:"url"`
 	// Filter by interactive
@@ -196,3 +198,20 @@ func (r *SceneQueryResult) Resolve(ctx context.Context) ([]*Scene, error) {
 	}
 	return r.scenes, r.resolveErr
 }
+
+// SceneOverlap is a part of a scene that is also found in another scene.
+type SceneOverlap struct {
+	SceneID      int `json:"scene_id"`
+	OtherSceneID int `json:"other_scene_id"`
+	// Start of the overlap in the scene, in seconds
+	SceneStart float64 `json:"scene_start"`
+	// Start of the overlap in the other scene, in seconds
+	OtherStart float64 `json:"other_start"`
+	// Duration of the overlap in seconds
+	Duration float64 `json:"duration"`
+}
+
+// Offset returns the position in the other scene at which the scene starts, in seconds.
+func (o SceneOverlap) Offset() float64 {
+	return o.OtherStart - o.SceneStart
+}
@@ -41,12 +41,13 @@ type StudioCreateInput struct {
 	URL      *string `json:"url"`
 	ParentID *string `json:"parent_id"`
 	// This should be a URL or a base64 encoded data URL
-	Image         *string   `json:"image"`
-	StashIds      []StashID `json:"stash_ids"`
-	Rating100     *int      `json:"rating100"`
-	Details       *string   `json:"details"`
-	Aliases       []string  `json:"aliases"`
-	IgnoreAutoTag *bool     `json:"ignore_auto_tag"`
+	Image         *string       `json:"image"`
+	StashIds      []StashID     `json:"stash_ids"`
+	Rating100     *int          `json:"rating100"`
+	Details       *string       `json:"details"`
+	Aliases       []string      `json:"aliases"`
+	IgnoreAutoTag *bool         `json:"ignore_auto_tag"`
+	AutoTagRules  *AutoTagRules `json:"auto_tag_rules"`
 }
 
 type StudioUpdateInput struct {
@@ -55,10 +56,11 @@ type StudioUpdateInput struct {
 	URL      *string `json:"url"`
 	ParentID *string `json:"parent_id"`
 	// This should be a URL or a base64 encoded data URL
-	Image         *string   `json:"image"`
-	StashIds      []StashID `json:"stash_ids"`
-	Rating100     *int      `json:"rating100"`
-	Details       *string   `json:"details"`
-	Aliases       []string  `json:"aliases"`
-	IgnoreAutoTag *bool     `json:"ignore_auto_tag"`
+	Image         *string       `json:"image"`
+	StashIds      []StashID     `json:"stash_ids"`
+	Rating100     *int          `json:"rating100"`
+	Details       *string       `json:"details"`
+	Aliases       []string      `json:"aliases"`
+	IgnoreAutoTag *bool         `json:"ignore_auto_tag"`
+	AutoTagRules  *AutoTagRules `json:"auto_tag_rules"`
 }
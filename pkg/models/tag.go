@@ -10,6 +10,8 @@ type TagFilterType struct {
 	Aliases *StringCriterionInput `json:"aliases"`
 	// Filter by tag description
 	Description *StringCriterionInput `json:"description"`
+	// Filter by StashID Endpoint
+	StashIDEndpoint *StashIDCriterionInput `json:"stash_id_endpoint"`
 	// Filter to only include tags missing this property
 	IsMissing *string `json:"is_missing"`
 	// Filter by number of scenes with this tag
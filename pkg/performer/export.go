@@ -38,6 +38,7 @@ func ToJSON(ctx context.Context, reader ImageAliasStashIDGetter, performer *mode
 		Details:        performer.Details,
 		HairColor:      performer.HairColor,
 		IgnoreAutoTag:  performer.IgnoreAutoTag,
+		AutoTagRules:   performer.AutoTagRules,
 		CreatedAt:      json.JSONTime{Time: performer.CreatedAt},
 		UpdatedAt:      json.JSONTime{Time: performer.UpdatedAt},
 	}
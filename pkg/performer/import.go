@@ -204,6 +204,7 @@ func performerJSONToPerformer(performerJSON jsonschema.Performer) models.Perform
 		HairColor:      performerJSON.HairColor,
 		Favorite:       performerJSON.Favorite,
 		IgnoreAutoTag:  performerJSON.IgnoreAutoTag,
+		AutoTagRules:   performerJSON.AutoTagRules,
 		CreatedAt:      performerJSON.CreatedAt.GetTime(),
 		UpdatedAt:      performerJSON.UpdatedAt.GetTime(),
 
@@ -301,6 +301,15 @@ func (c Cache) ExecuteSceneUpdatePostHooks(ctx context.Context, input models.Sce
 	c.ExecutePostHooks(ctx, id, SceneUpdatePost, input, inputFields)
 }
 
+func (c Cache) ExecuteGalleryUpdatePostHooks(ctx context.Context, input models.GalleryUpdateInput, inputFields []string) {
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		logger.Errorf("error converting id in GalleryUpdatePostHooks: %v", err)
+		return
+	}
+	c.ExecutePostHooks(ctx, id, GalleryUpdatePost, input, inputFields)
+}
+
 func (c Cache) executePostHooks(ctx context.Context, hookType HookTriggerEnum, hookContext common.HookContext) error {
 	visitedPlugins := session.GetVisitedPlugins(ctx)
 
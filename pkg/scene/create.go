@@ -27,6 +27,12 @@ func (s *Service) Create(ctx context.Context, input *models.Scene, fileIDs []mod
 		return nil, fmt.Errorf("creating new scene: %w", err)
 	}
 
+	if len(newScene.StashIDs.List()) > 0 {
+		if err := s.FingerprintSubmission.SyncScene(ctx, newScene.ID); err != nil {
+			return nil, fmt.Errorf("queueing fingerprint submissions of new scene: %w", err)
+		}
+	}
+
 	for _, f := range fileIDs {
 		if err := s.AssignFile(ctx, newScene.ID, f); err != nil {
 			return nil, fmt.Errorf("assigning file %d to new scene: %w", f, err)
@@ -0,0 +1,195 @@
+package scene
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/file/metadata"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type FileMetadataUpdater interface {
+	models.URLLoader
+	models.PerformerIDLoader
+	models.TagIDLoader
+	UpdatePartial(ctx context.Context, id int, updatedScene models.ScenePartial) (*models.Scene, error)
+}
+
+type FileMetadataMarkerFinderCreator interface {
+	FindBySceneID(ctx context.Context, sceneID int) ([]*models.SceneMarker, error)
+	Create(ctx context.Context, newSceneMarker *models.SceneMarker) error
+}
+
+// FileMetadataApplier sets the fields of scenes from the metadata embedded in
+// their video files and from the json and nfo sidecar files next to them.
+// Sidecar files take precedence over embedded metadata.
+type FileMetadataApplier struct {
+	Updater             FileMetadataUpdater
+	MarkerFinderCreator FileMetadataMarkerFinderCreator
+	Resolver            *metadata.Resolver
+	Precedence          models.FileMetadataPrecedence
+
+	// FS is the file system the files were scanned with. The sidecar files
+	// are read from the file system containing the file.
+	FS models.FS
+}
+
+func (a *FileMetadataApplier) readSidecars(f *models.BaseFile) (*metadata.Metadata, error) {
+	fs := a.FS
+	if fs == nil {
+		fs = &file.OsFS{}
+	}
+
+	fileFS, closer, err := file.OpenFileFS(fs, f)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	return metadata.ReadSidecars(fileFS, f.Path)
+}
+
+func (a *FileMetadataApplier) read(f *models.VideoFile, probe *ffmpeg.VideoFile) (*metadata.Metadata, error) {
+	ret, err := a.readSidecars(f.BaseFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if probe != nil {
+		if ret == nil {
+			ret = metadata.FromProbe(probe)
+		} else {
+			ret.Merge(metadata.FromProbe(probe))
+		}
+	}
+
+	return ret, nil
+}
+
+// Apply reads the metadata of the file and applies it to the scene. probe
+// is the ffprobe result of the file from the scan. Embedded metadata is not
+// read if it is nil.
+func (a *FileMetadataApplier) Apply(ctx context.Context, s *models.Scene, f *models.VideoFile, probe *ffmpeg.VideoFile) error {
+	m, err := a.read(f, probe)
+	if err != nil {
+		return err
+	}
+
+	if m.IsEmpty() {
+		return nil
+	}
+
+	if err := a.updateScene(ctx, s, m); err != nil {
+		return fmt.Errorf("updating scene from file metadata: %w", err)
+	}
+
+	if err := a.createMarkers(ctx, s, m.Chapters); err != nil {
+		return fmt.Errorf("creating scene markers from chapters: %w", err)
+	}
+
+	return nil
+}
+
+func (a *FileMetadataApplier) updateScene(ctx context.Context, s *models.Scene, m *metadata.Metadata) error {
+	r := a.Updater
+	p := a.Precedence
+
+	if err := s.LoadURLs(ctx, r); err != nil {
+		return err
+	}
+	if err := s.LoadPerformerIDs(ctx, r); err != nil {
+		return err
+	}
+	if err := s.LoadTagIDs(ctx, r); err != nil {
+		return err
+	}
+
+	studioID, err := a.Resolver.StudioID(ctx, m.Studio)
+	if err != nil {
+		return err
+	}
+	performerIDs, err := a.Resolver.PerformerIDs(ctx, m.Performers)
+	if err != nil {
+		return err
+	}
+	tagIDs, err := a.Resolver.TagIDs(ctx, m.Tags)
+	if err != nil {
+		return err
+	}
+
+	partial := models.NewScenePartial()
+	partial.Title = metadata.UpdateString(p, s.Title, m.Title)
+	partial.Code = metadata.UpdateString(p, s.Code, m.Code)
+	partial.Details = metadata.UpdateString(p, s.Details, m.Details)
+	partial.Director = metadata.UpdateString(p, s.Director, m.Director)
+	partial.Date = metadata.UpdateDate(p, s.Date, m.Date)
+	partial.StudioID = metadata.UpdateID(p, s.StudioID, studioID)
+	partial.URLs = metadata.AddStrings(s.URLs.List(), m.URLs)
+	partial.PerformerIDs = metadata.AddIDs(s.PerformerIDs.List(), performerIDs)
+	partial.TagIDs = metadata.AddIDs(s.TagIDs.List(), tagIDs)
+
+	if !partial.Title.Set && !partial.Code.Set && !partial.Details.Set && !partial.Director.Set &&
+		!partial.Date.Set && !partial.StudioID.Set && partial.URLs == nil &&
+		partial.PerformerIDs == nil && partial.TagIDs == nil {
+		return nil
+	}
+
+	if _, err := r.UpdatePartial(ctx, s.ID, partial); err != nil {
+		return err
+	}
+
+	logger.Infof("Updated scene %s from file metadata", s.DisplayName())
+	return nil
+}
+
+// createMarkers creates a marker for each chapter, using the tag named as
+// the chapter as the primary tag. Chapters without a matching tag, and
+// chapters at the same position as an existing marker, are skipped.
+func (a *FileMetadataApplier) createMarkers(ctx context.Context, s *models.Scene, chapters []metadata.Chapter) error {
+	if len(chapters) == 0 {
+		return nil
+	}
+
+	existing, err := a.MarkerFinderCreator.FindBySceneID(ctx, s.ID)
+	if err != nil {
+		return err
+	}
+
+	seconds := make(map[float64]bool)
+	for _, m := range existing {
+		seconds[m.Seconds] = true
+	}
+
+	for _, c := range chapters {
+		if seconds[c.Seconds] {
+			continue
+		}
+
+		tagID, err := a.Resolver.TagID(ctx, c.Title)
+		if err != nil {
+			return err
+		}
+
+		if tagID == nil {
+			logger.Debugf("Skipping chapter %q of scene %s: no tag named %q", c.Title, s.DisplayName(), c.Title)
+			continue
+		}
+
+		marker := models.NewSceneMarker()
+		marker.Title = c.Title
+		marker.Seconds = c.Seconds
+		marker.PrimaryTagID = *tagID
+		marker.SceneID = s.ID
+
+		if err := a.MarkerFinderCreator.Create(ctx, &marker); err != nil {
+			return err
+		}
+
+		seconds[c.Seconds] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package scene
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/file/metadata"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+)
+
+func tagNameFilter(name string) interface{} {
+	return mock.MatchedBy(func(f *models.TagFilterType) bool {
+		return f.Name != nil && f.Name.Value == name
+	})
+}
+
+func TestFileMetadataApplier_updateScene(t *testing.T) {
+	const (
+		sceneID     = 1
+		performerID = 2
+	)
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		precedence models.FileMetadataPrecedence
+		want       models.ScenePartial
+	}{
+		{
+			"existing",
+			models.FileMetadataPrecedenceExisting,
+			models.ScenePartial{
+				Details:      models.NewOptionalString("details"),
+				PerformerIDs: &models.UpdateIDs{IDs: []int{performerID}, Mode: models.RelationshipUpdateModeAdd},
+			},
+		},
+		{
+			"file",
+			models.FileMetadataPrecedenceFile,
+			models.ScenePartial{
+				Title:        models.NewOptionalString("new title"),
+				Details:      models.NewOptionalString("details"),
+				PerformerIDs: &models.UpdateIDs{IDs: []int{performerID}, Mode: models.RelationshipUpdateModeAdd},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := mocks.NewDatabase()
+			db.Scene.On("GetURLs", ctx, sceneID).Return(nil, nil)
+			db.Scene.On("GetPerformerIDs", ctx, sceneID).Return(nil, nil)
+			db.Scene.On("GetTagIDs", ctx, sceneID).Return(nil, nil)
+			db.Performer.On("FindByNames", ctx, []string{"Jane Doe"}, true).Return([]*models.Performer{{ID: performerID}}, nil)
+			db.Scene.On("UpdatePartial", ctx, sceneID, mock.MatchedBy(func(p models.ScenePartial) bool {
+				return p.Title == tt.want.Title && p.Details == tt.want.Details &&
+					assert.Equal(t, tt.want.PerformerIDs, p.PerformerIDs)
+			})).Return(nil, nil).Once()
+
+			a := &FileMetadataApplier{
+				Updater:    db.Scene,
+				Resolver:   &metadata.Resolver{PerformerFinderCreator: db.Performer},
+				Precedence: tt.precedence,
+			}
+
+			s := &models.Scene{ID: sceneID, Title: "old title"}
+			err := a.updateScene(ctx, s, &metadata.Metadata{
+				Title:      "new title",
+				Details:    "details",
+				Performers: []string{"Jane Doe"},
+			})
+
+			assert.NoError(t, err)
+			db.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileMetadataApplier_createMarkers(t *testing.T) {
+	const (
+		sceneID = 1
+		tagID   = 2
+	)
+
+	ctx := context.Background()
+	db := mocks.NewDatabase()
+
+	db.SceneMarker.On("FindBySceneID", ctx, sceneID).Return([]*models.SceneMarker{{Seconds: 5}}, nil)
+	db.Tag.On("Query", ctx, tagNameFilter("Intro"), mock.Anything).Return([]*models.Tag{{ID: tagID}}, 1, nil)
+	db.Tag.On("Query", ctx, tagNameFilter("Unknown"), mock.Anything).Return(nil, 0, nil)
+	db.Tag.On("Query", ctx, mock.Anything, mock.Anything).Return(nil, 0, nil)
+	db.SceneMarker.On("Create", ctx, mock.MatchedBy(func(m *models.SceneMarker) bool {
+		return m.Title == "Intro" && m.Seconds == 10 && m.PrimaryTagID == tagID && m.SceneID == sceneID
+	})).Return(nil).Once()
+
+	a := &FileMetadataApplier{
+		MarkerFinderCreator: db.SceneMarker,
+		Resolver:            &metadata.Resolver{TagFinderCreator: db.Tag},
+	}
+
+	err := a.createMarkers(ctx, &models.Scene{ID: sceneID}, []metadata.Chapter{
+		{Title: "Existing", Seconds: 5},
+		{Title: "Intro", Seconds: 10},
+		{Title: "Unknown", Seconds: 20},
+	})
+
+	assert.NoError(t, err)
+	db.AssertExpectations(t)
+}
+
+func TestFileMetadataApplier_readFromZip(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "scenes.zip")
+
+	out, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := zip.NewWriter(out)
+	for name, content := range map[string]string{
+		"scene.mp4": "",
+		"scene.nfo": "<movie><title>zipped title</title></movie>",
+	} {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := out.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	zipFileID := models.FileID(1)
+	f := &models.VideoFile{
+		BaseFile: &models.BaseFile{
+			DirEntry: models.DirEntry{
+				ZipFileID: &zipFileID,
+				ZipFile:   &models.BaseFile{ID: zipFileID, Path: zipPath},
+			},
+			Path: filepath.Join(zipPath, "scene.mp4"),
+		},
+	}
+
+	a := &FileMetadataApplier{
+		FS: &file.OsFS{},
+	}
+
+	m, err := a.read(f, nil)
+	if assert.NoError(t, err) && assert.NotNil(t, m) {
+		assert.Equal(t, "zipped title", m.Title)
+	}
+}
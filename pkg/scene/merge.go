@@ -70,6 +70,12 @@ func (s *Service) Merge(ctx context.Context, sourceIDs []int, destinationID int,
 		return fmt.Errorf("updating scene: %w", err)
 	}
 
+	if scenePartial.StashIDs != nil {
+		if err := s.FingerprintSubmission.SyncScene(ctx, destinationID); err != nil {
+			return fmt.Errorf("syncing fingerprint submissions: %w", err)
+		}
+	}
+
 	// delete old scenes
 	for _, srcID := range sourceIDs {
 		if err := s.Repository.Destroy(ctx, srcID); err != nil {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/stashapp/stash/pkg/ffmpeg"
 	"github.com/stashapp/stash/pkg/file/video"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
@@ -31,6 +32,10 @@ type ScanGenerator interface {
 	Generate(ctx context.Context, s *models.Scene, f *models.VideoFile) error
 }
 
+type ScanMetadataApplier interface {
+	Apply(ctx context.Context, s *models.Scene, f *models.VideoFile, probe *ffmpeg.VideoFile) error
+}
+
 type ScanHandler struct {
 	CreatorUpdater ScanCreatorUpdater
 
@@ -38,6 +43,17 @@ type ScanHandler struct {
 	CaptionUpdater video.CaptionUpdater
 	PluginCache    *plugin.Cache
 
+	// MetadataApplier is optional. If set, it is applied to new scenes and to
+	// the scenes of changed files.
+	MetadataApplier ScanMetadataApplier
+	// Probes is optional. It provides the ffprobe results of the scanned
+	// files to MetadataApplier.
+	Probes *video.ProbeCache
+
+	// ScanDefaults is optional. If set, it provides the tags and studio of
+	// new scenes.
+	ScanDefaults models.ScanDefaultsGetter
+
 	FileNamingAlgorithm models.HashAlgorithm
 	Paths               *paths.Paths
 }
@@ -72,6 +88,13 @@ func (h *ScanHandler) Handle(ctx context.Context, f models.File, oldFile models.
 		return ErrNotVideoFile
 	}
 
+	// the probe result is kept until the transaction is committed, in case
+	// the transaction is retried
+	probe := h.Probes.Get(videoFile.Path)
+	txn.AddPostCommitHook(ctx, func(ctx context.Context) {
+		h.Probes.Remove(videoFile.Path)
+	})
+
 	if oldFile != nil {
 		if err := video.CleanCaptions(ctx, videoFile, nil, h.CaptionUpdater); err != nil {
 			return fmt.Errorf("cleaning captions: %w", err)
@@ -92,6 +115,7 @@ func (h *ScanHandler) Handle(ctx context.Context, f models.File, oldFile models.
 		}
 	}
 
+	applyMetadata := oldFile != nil
 	if len(existing) > 0 {
 		updateExisting := oldFile != nil
 		if err := h.associateExisting(ctx, existing, videoFile, updateExisting); err != nil {
@@ -100,6 +124,9 @@ func (h *ScanHandler) Handle(ctx context.Context, f models.File, oldFile models.
 	} else {
 		// create a new scene
 		newScene := models.NewScene()
+		if h.ScanDefaults != nil {
+			h.ScanDefaults.GetScanDefaults(f.Base().Path).Apply(&newScene.TagIDs, &newScene.StudioID)
+		}
 
 		logger.Infof("%s doesn't exist. Creating new scene...", f.Base().Path)
 
@@ -110,6 +137,15 @@ func (h *ScanHandler) Handle(ctx context.Context, f models.File, oldFile models.
 		h.PluginCache.RegisterPostHooks(ctx, newScene.ID, plugin.SceneCreatePost, nil, nil)
 
 		existing = []*models.Scene{&newScene}
+		applyMetadata = true
+	}
+
+	if applyMetadata && h.MetadataApplier != nil {
+		for _, s := range existing {
+			if err := h.MetadataApplier.Apply(ctx, s, videoFile, probe); err != nil {
+				return fmt.Errorf("applying file metadata: %w", err)
+			}
+		}
 	}
 
 	if oldFile != nil {
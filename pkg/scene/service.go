@@ -14,7 +14,9 @@ type Service struct {
 	File             models.FileReaderWriter
 	Repository       models.SceneReaderWriter
 	MarkerRepository models.SceneMarkerReaderWriter
-	PluginCache      *plugin.Cache
+	// FingerprintSubmission is kept in sync with the stash ids of scenes
+	FingerprintSubmission models.FingerprintSubmissionUpdater
+	PluginCache           *plugin.Cache
 
 	Paths  *paths.Paths
 	Config Config
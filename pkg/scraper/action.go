@@ -31,9 +31,12 @@ type scraperActionImpl interface {
 
 	scrapeSceneByScene(ctx context.Context, scene *models.Scene) (*ScrapedScene, error)
 	scrapeGalleryByGallery(ctx context.Context, gallery *models.Gallery) (*ScrapedGallery, error)
+	scrapeScenesByFingerprints(ctx context.Context, scenes []sceneFingerprints) ([][]*ScrapedScene, error)
 }
 
 func (c config) getScraper(scraper scraperTypeConfig, client *http.Client, globalConfig GlobalConfig) scraperActionImpl {
+	c.settings = globalConfig.GetScraperConfiguration(c.ID)
+
 	switch scraper.Action {
 	case scraperActionScript:
 		return newScriptScraper(scraper, c, globalConfig)
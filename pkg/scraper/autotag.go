@@ -26,8 +26,8 @@ type autotagScraper struct {
 	globalConfig GlobalConfig
 }
 
-func autotagMatchPerformers(ctx context.Context, path string, performerReader models.PerformerAutoTagQueryer, trimExt bool) ([]*models.ScrapedPerformer, error) {
-	p, err := match.PathToPerformers(ctx, path, performerReader, nil, trimExt)
+func autotagMatchPerformers(ctx context.Context, path string, title string, performerReader models.PerformerAutoTagQueryer, trimExt bool) ([]*models.ScrapedPerformer, error) {
+	p, err := match.PathToPerformers(ctx, path, title, performerReader, nil, trimExt)
 	if err != nil {
 		return nil, fmt.Errorf("error matching performers: %w", err)
 	}
@@ -51,8 +51,8 @@ func autotagMatchPerformers(ctx context.Context, path string, performerReader mo
 	return ret, nil
 }
 
-func autotagMatchStudio(ctx context.Context, path string, studioReader models.StudioAutoTagQueryer, trimExt bool) (*models.ScrapedStudio, error) {
-	studio, err := match.PathToStudio(ctx, path, studioReader, nil, trimExt)
+func autotagMatchStudio(ctx context.Context, path string, title string, studioReader models.StudioAutoTagQueryer, trimExt bool) (*models.ScrapedStudio, error) {
+	studio, err := match.PathToStudio(ctx, path, title, studioReader, nil, trimExt)
 	if err != nil {
 		return nil, fmt.Errorf("error matching studios: %w", err)
 	}
@@ -68,8 +68,8 @@ func autotagMatchStudio(ctx context.Context, path string, studioReader models.St
 	return nil, nil
 }
 
-func autotagMatchTags(ctx context.Context, path string, tagReader models.TagAutoTagQueryer, trimExt bool) ([]*models.ScrapedTag, error) {
-	t, err := match.PathToTags(ctx, path, tagReader, nil, trimExt)
+func autotagMatchTags(ctx context.Context, path string, title string, tagReader models.TagAutoTagQueryer, trimExt bool) ([]*models.ScrapedTag, error) {
+	t, err := match.PathToTags(ctx, path, title, tagReader, nil, trimExt)
 	if err != nil {
 		return nil, fmt.Errorf("error matching tags: %w", err)
 	}
@@ -100,16 +100,16 @@ func (s autotagScraper) viaScene(ctx context.Context, _client *http.Client, scen
 			return nil
 		}
 
-		performers, err := autotagMatchPerformers(ctx, path, s.performerReader, trimExt)
+		performers, err := autotagMatchPerformers(ctx, path, scene.Title, s.performerReader, trimExt)
 		if err != nil {
 			return fmt.Errorf("autotag scraper viaScene: %w", err)
 		}
-		studio, err := autotagMatchStudio(ctx, path, s.studioReader, trimExt)
+		studio, err := autotagMatchStudio(ctx, path, scene.Title, s.studioReader, trimExt)
 		if err != nil {
 			return fmt.Errorf("autotag scraper viaScene: %w", err)
 		}
 
-		tags, err := autotagMatchTags(ctx, path, s.tagReader, trimExt)
+		tags, err := autotagMatchTags(ctx, path, scene.Title, s.tagReader, trimExt)
 		if err != nil {
 			return fmt.Errorf("autotag scraper viaScene: %w", err)
 		}
@@ -145,16 +145,16 @@ func (s autotagScraper) viaGallery(ctx context.Context, _client *http.Client, ga
 	// populate performers, studio and tags based on scene path
 	if err := txn.WithReadTxn(ctx, s.txnManager, func(ctx context.Context) error {
 		path := gallery.Path
-		performers, err := autotagMatchPerformers(ctx, path, s.performerReader, trimExt)
+		performers, err := autotagMatchPerformers(ctx, path, gallery.Title, s.performerReader, trimExt)
 		if err != nil {
 			return fmt.Errorf("autotag scraper viaGallery: %w", err)
 		}
-		studio, err := autotagMatchStudio(ctx, path, s.studioReader, trimExt)
+		studio, err := autotagMatchStudio(ctx, path, gallery.Title, s.studioReader, trimExt)
 		if err != nil {
 			return fmt.Errorf("autotag scraper viaGallery: %w", err)
 		}
 
-		tags, err := autotagMatchTags(ctx, path, s.tagReader, trimExt)
+		tags, err := autotagMatchTags(ctx, path, gallery.Title, s.tagReader, trimExt)
 		if err != nil {
 			return fmt.Errorf("autotag scraper viaGallery: %w", err)
 		}
@@ -15,6 +15,7 @@ import (
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/match"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil"
 	"github.com/stashapp/stash/pkg/txn"
 )
 
@@ -40,6 +41,8 @@ type GlobalConfig interface {
 	GetScraperCertCheck() bool
 	GetPythonPath() string
 	GetProxy() string
+	GetCachePath() string
+	GetScraperConfiguration(scraperID string) map[string]interface{}
 }
 
 func isCDPPathHTTP(c GlobalConfig) bool {
@@ -53,6 +56,7 @@ func isCDPPathWS(c GlobalConfig) bool {
 type SceneFinder interface {
 	models.SceneGetter
 	models.URLLoader
+	models.VideoFileLoader
 }
 
 type PerformerFinder interface {
@@ -106,7 +110,8 @@ func (r *Repository) WithReadTxn(ctx context.Context, fn txn.TxnFunc) error {
 // Cache stores the database of scrapers
 type Cache struct {
 	client       *http.Client
-	scrapers     map[string]scraper // Scraper ID -> Scraper
+	scrapers     map[string]scraper         // Scraper ID -> Scraper
+	sessions     map[string]*scraperSession // Scraper ID -> login session
 	globalConfig GlobalConfig
 
 	repository Repository
@@ -146,6 +151,7 @@ func NewCache(globalConfig GlobalConfig, repo Repository) *Cache {
 
 	return &Cache{
 		client:       client,
+		sessions:     make(map[string]*scraperSession),
 		globalConfig: globalConfig,
 		repository:   repo,
 	}
@@ -171,6 +177,9 @@ func (c *Cache) ReloadScrapers() {
 			if err != nil {
 				logger.Errorf("Error loading scraper %s: %v", fp, err)
 			} else {
+				if conf.Login != nil {
+					conf.session = c.getSession(conf.ID)
+				}
 				scraper := newGroupScraper(*conf, c.globalConfig)
 				scrapers[scraper.spec().ID] = scraper
 			}
@@ -185,6 +194,18 @@ func (c *Cache) ReloadScrapers() {
 	c.scrapers = scrapers
 }
 
+// getSession returns the login session for the scraper with the given id.
+// Sessions are kept across scraper reloads.
+func (c *Cache) getSession(scraperID string) *scraperSession {
+	s, ok := c.sessions[scraperID]
+	if !ok {
+		s = newScraperSession(sessionPath(c.globalConfig, scraperID))
+		c.sessions[scraperID] = s
+	}
+
+	return s
+}
+
 // ListScrapers lists scrapers matching one of the given types.
 // Returns a list of scrapers, sorted by their name.
 func (c Cache) ListScrapers(tys []ScrapeContentType) []*Scraper {
@@ -305,6 +326,35 @@ func (c Cache) ScrapeURL(ctx context.Context, url string, ty ScrapeContentType)
 	return nil, nil
 }
 
+// ScrapeURLWithScraper scrapes the given url using the scraper with the
+// provided id. Returns nil if the scraper is not capable of scraping the url.
+func (c Cache) ScrapeURLWithScraper(ctx context.Context, scraperID string, url string, ty ScrapeContentType) (ScrapedContent, error) {
+	s := c.findScraper(scraperID)
+	if s == nil {
+		return nil, fmt.Errorf("%w: id %s", ErrNotFound, scraperID)
+	}
+
+	if !s.supportsURL(url, ty) {
+		return nil, nil
+	}
+
+	ul, ok := s.(urlScraper)
+	if !ok {
+		return nil, fmt.Errorf("%w: cannot use scraper %s as an url scraper", ErrNotSupported, scraperID)
+	}
+
+	ret, err := ul.viaURL(ctx, c.client, url, ty)
+	if err != nil {
+		return nil, fmt.Errorf("error while url scraping with scraper %s: %w", scraperID, err)
+	}
+
+	if ret == nil {
+		return ret, nil
+	}
+
+	return c.postScrape(ctx, ret)
+}
+
 func (c Cache) ScrapeID(ctx context.Context, scraperID string, id int, ty ScrapeContentType) (ScrapedContent, error) {
 	s := c.findScraper(scraperID)
 	if s == nil {
@@ -364,6 +414,85 @@ func (c Cache) ScrapeID(ctx context.Context, scraperID string, id int, ty Scrape
 	return c.postScrape(ctx, ret)
 }
 
+// SupportsSceneFingerprints returns true if the scraper with the given id
+// supports scraping scenes by their file fingerprints.
+func (c Cache) SupportsSceneFingerprints(scraperID string) bool {
+	s := c.findScraper(scraperID)
+	if s == nil {
+		return false
+	}
+
+	_, ok := s.(fingerprintScraper)
+	return ok && supportsSceneScrape(s, ScrapeTypeFingerprint)
+}
+
+// SupportsSceneFragments returns true if the scraper with the given id
+// supports scraping scenes by fragment.
+func (c Cache) SupportsSceneFragments(scraperID string) bool {
+	s := c.findScraper(scraperID)
+	if s == nil {
+		return false
+	}
+
+	return supportsSceneScrape(s, ScrapeTypeFragment)
+}
+
+func supportsSceneScrape(s scraper, ty ScrapeType) bool {
+	return s.spec().Scene != nil && sliceutil.Contains(s.spec().Scene.SupportedScrapes, ty)
+}
+
+// ScrapeSceneFingerprints scrapes the scenes with the given ids using the
+// fingerprints of their primary files. Returns the matching scenes for each
+// scene id, in the same order as the input.
+func (c Cache) ScrapeSceneFingerprints(ctx context.Context, scraperID string, sceneIDs []int) ([][]*ScrapedScene, error) {
+	s := c.findScraper(scraperID)
+	if s == nil {
+		return nil, fmt.Errorf("%w: id %s", ErrNotFound, scraperID)
+	}
+
+	fs, ok := s.(fingerprintScraper)
+	if !ok {
+		return nil, fmt.Errorf("%w: cannot use scraper %s as a fingerprint scraper", ErrNotSupported, scraperID)
+	}
+
+	var scenes []*models.Scene
+	for _, id := range sceneIDs {
+		scene, err := c.getScene(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("scraper %s: unable to load scene id %v: %w", scraperID, id, err)
+		}
+
+		scenes = append(scenes, scene)
+	}
+
+	results, err := fs.viaFingerprints(ctx, c.client, scenes)
+	if err != nil {
+		return nil, fmt.Errorf("scraper %s: %w", scraperID, err)
+	}
+
+	ret := make([][]*ScrapedScene, len(results))
+	for i, sceneResults := range results {
+		for _, r := range sceneResults {
+			if r == nil {
+				continue
+			}
+
+			content, err := c.postScrape(ctx, r)
+			if err != nil {
+				return nil, fmt.Errorf("error while post-scraping with scraper %s: %w", scraperID, err)
+			}
+
+			ss, ok := content.(ScrapedScene)
+			if !ok {
+				return nil, fmt.Errorf("scraper %s: could not convert content to scene", scraperID)
+			}
+			ret[i] = append(ret[i], &ss)
+		}
+	}
+
+	return ret, nil
+}
+
 func (c Cache) getScene(ctx context.Context, sceneID int) (*models.Scene, error) {
 	var ret *models.Scene
 	r := c.repository
@@ -380,6 +509,10 @@ func (c Cache) getScene(ctx context.Context, sceneID int) (*models.Scene, error)
 			return fmt.Errorf("scene with id %d not found", sceneID)
 		}
 
+		if err := ret.LoadFiles(ctx, qb); err != nil {
+			return err
+		}
+
 		return ret.LoadURLs(ctx, qb)
 	}); err != nil {
 		return nil, err
@@ -15,6 +15,12 @@ type config struct {
 	ID   string
 	path string
 
+	// login session, set if Login is not nil
+	session *scraperSession
+
+	// configured values of the scraper settings
+	settings map[string]interface{}
+
 	// The name of the scraper. This is displayed in the UI.
 	Name string `yaml:"name"`
 
@@ -39,6 +45,9 @@ type config struct {
 	// Configuration for querying scenes by query fragment
 	SceneByQueryFragment *scraperTypeConfig `yaml:"sceneByQueryFragment"`
 
+	// Configuration for querying scenes by their file fingerprints
+	SceneByFingerprint *scraperTypeConfig `yaml:"sceneByFingerprint"`
+
 	// Configuration for querying a scene by a URL
 	SceneByURL []*scrapeByURLConfig `yaml:"sceneByURL"`
 
@@ -48,6 +57,12 @@ type config struct {
 	// Configuration for querying a movie by a URL
 	MovieByURL []*scrapeByURLConfig `yaml:"movieByURL"`
 
+	// Configuration for querying studios by name
+	StudioByName *scraperTypeConfig `yaml:"studioByName"`
+
+	// Configuration for querying a studio by a URL
+	StudioByURL []*scrapeByURLConfig `yaml:"studioByURL"`
+
 	// Scraper debugging options
 	DebugOptions *scraperDebugOptions `yaml:"debug"`
 
@@ -62,6 +77,12 @@ type config struct {
 
 	// Scraping driver options
 	DriverOptions *scraperDriverOptions `yaml:"driver"`
+
+	// Login configuration
+	Login *loginConfig `yaml:"login"`
+
+	// User-configurable settings
+	Settings map[string]settingConfig `yaml:"settings"`
 }
 
 func (c config) validate() error {
@@ -69,6 +90,10 @@ func (c config) validate() error {
 		return errors.New("name must not be empty")
 	}
 
+	if err := c.validatePagination(); err != nil {
+		return err
+	}
+
 	if c.PerformerByName != nil {
 		if err := c.PerformerByName.validate(); err != nil {
 			return err
@@ -87,6 +112,24 @@ func (c config) validate() error {
 		}
 	}
 
+	if c.SceneByName != nil {
+		if err := c.SceneByName.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.SceneByFingerprint != nil {
+		if err := c.SceneByFingerprint.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.StudioByName != nil {
+		if err := c.StudioByName.validate(); err != nil {
+			return err
+		}
+	}
+
 	for _, s := range c.PerformerByURL {
 		if err := s.validate(); err != nil {
 			return err
@@ -105,6 +148,60 @@ func (c config) validate() error {
 		}
 	}
 
+	for _, s := range c.StudioByURL {
+		if err := s.validate(); err != nil {
+			return err
+		}
+	}
+
+	for k, s := range c.Settings {
+		if s.Type != "" && !s.Type.IsValid() {
+			return fmt.Errorf("setting %s: %s is not a valid setting type", k, s.Type)
+		}
+	}
+
+	if c.Login != nil {
+		if err := c.Login.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validatePagination returns an error if pagination is configured for a
+// scraper type that does not search by name.
+func (c config) validatePagination() error {
+	unpaginated := map[string]*scraperTypeConfig{
+		"performerByFragment":  c.PerformerByFragment,
+		"sceneByFragment":      c.SceneByFragment,
+		"galleryByFragment":    c.GalleryByFragment,
+		"sceneByQueryFragment": c.SceneByQueryFragment,
+		"sceneByFingerprint":   c.SceneByFingerprint,
+	}
+
+	for name, t := range unpaginated {
+		if t != nil && t.Pagination != nil {
+			return fmt.Errorf("%s: pagination is only supported for name scrapers", name)
+		}
+	}
+
+	byURL := map[string][]*scrapeByURLConfig{
+		"performerByURL": c.PerformerByURL,
+		"sceneByURL":     c.SceneByURL,
+		"galleryByURL":   c.GalleryByURL,
+		"movieByURL":     c.MovieByURL,
+		"studioByURL":    c.StudioByURL,
+	}
+
+	for name, scrapers := range byURL {
+		for _, s := range scrapers {
+			if s.Pagination != nil {
+				return fmt.Errorf("%s: pagination is only supported for name scrapers", name)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -120,6 +217,22 @@ type scraperTypeConfig struct {
 	// for xpath name scraper only
 	QueryURL             string               `yaml:"queryURL"`
 	QueryURLReplacements queryURLReplacements `yaml:"queryURLReplace"`
+
+	// for xpath and json name scrapers only
+	Pagination *paginationConfig `yaml:"pagination"`
+
+	// for fingerprint scrapers only. Maximum number of scenes sent to a
+	// script, or to a json scraper using the {scenes} placeholder, in a
+	// single request.
+	BatchSize int `yaml:"batchSize"`
+}
+
+func (c scraperTypeConfig) batchSize() int {
+	if c.BatchSize > 0 {
+		return c.BatchSize
+	}
+
+	return defaultFingerprintBatchSize
 }
 
 func (c scraperTypeConfig) validate() error {
@@ -131,6 +244,20 @@ func (c scraperTypeConfig) validate() error {
 		return errors.New("script is mandatory for script scraper action")
 	}
 
+	if c.Pagination != nil {
+		if c.Action != scraperActionXPath && c.Action != scraperActionJson {
+			return fmt.Errorf("pagination is not supported for %s scrapers", c.Action)
+		}
+
+		if err := c.Pagination.validate(c.QueryURL); err != nil {
+			return err
+		}
+	}
+
+	if c.BatchSize < 0 {
+		return errors.New("batchSize must not be negative")
+	}
+
 	return nil
 }
 
@@ -234,8 +361,9 @@ func loadConfigFromYAMLFile(path string) (*config, error) {
 
 func (c config) spec() Scraper {
 	ret := Scraper{
-		ID:   c.ID,
-		Name: c.Name,
+		ID:       c.ID,
+		Name:     c.Name,
+		Settings: c.getSettings(),
 	}
 
 	performer := ScraperSpec{}
@@ -263,6 +391,9 @@ func (c config) spec() Scraper {
 	if c.SceneByName != nil && c.SceneByQueryFragment != nil {
 		scene.SupportedScrapes = append(scene.SupportedScrapes, ScrapeTypeName)
 	}
+	if c.SceneByFingerprint != nil {
+		scene.SupportedScrapes = append(scene.SupportedScrapes, ScrapeTypeFingerprint)
+	}
 	if len(c.SceneByURL) > 0 {
 		scene.SupportedScrapes = append(scene.SupportedScrapes, ScrapeTypeURL)
 		for _, v := range c.SceneByURL {
@@ -301,6 +432,21 @@ func (c config) spec() Scraper {
 		ret.Movie = &movie
 	}
 
+	studio := ScraperSpec{}
+	if c.StudioByName != nil {
+		studio.SupportedScrapes = append(studio.SupportedScrapes, ScrapeTypeName)
+	}
+	if len(c.StudioByURL) > 0 {
+		studio.SupportedScrapes = append(studio.SupportedScrapes, ScrapeTypeURL)
+		for _, v := range c.StudioByURL {
+			studio.Urls = append(studio.Urls, v.URL...)
+		}
+	}
+
+	if len(studio.SupportedScrapes) > 0 {
+		ret.Studio = &studio
+	}
+
 	return ret
 }
 
@@ -309,11 +455,13 @@ func (c config) supports(ty ScrapeContentType) bool {
 	case ScrapeContentTypePerformer:
 		return c.PerformerByName != nil || c.PerformerByFragment != nil || len(c.PerformerByURL) > 0
 	case ScrapeContentTypeScene:
-		return (c.SceneByName != nil && c.SceneByQueryFragment != nil) || c.SceneByFragment != nil || len(c.SceneByURL) > 0
+		return (c.SceneByName != nil && c.SceneByQueryFragment != nil) || c.SceneByFragment != nil || c.SceneByFingerprint != nil || len(c.SceneByURL) > 0
 	case ScrapeContentTypeGallery:
 		return c.GalleryByFragment != nil || len(c.GalleryByURL) > 0
 	case ScrapeContentTypeMovie:
 		return len(c.MovieByURL) > 0
+	case ScrapeContentTypeStudio:
+		return c.StudioByName != nil || len(c.StudioByURL) > 0
 	}
 
 	panic("Unhandled ScrapeContentType")
@@ -345,6 +493,12 @@ func (c config) matchesURL(url string, ty ScrapeContentType) bool {
 				return true
 			}
 		}
+	case ScrapeContentTypeStudio:
+		for _, scraper := range c.StudioByURL {
+			if scraper.matchesURL(url) {
+				return true
+			}
+		}
 	}
 
 	return false
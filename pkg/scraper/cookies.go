@@ -40,13 +40,13 @@ func (c config) jar() (*cookiejar.Jar, error) {
 
 		var httpCookies []*http.Cookie
 		for _, cookie := range ckURL.Cookies {
-			c := &http.Cookie{
+			httpCookie := &http.Cookie{
 				Name:   cookie.Name,
-				Value:  getCookieValue(cookie),
+				Value:  c.expandSettings(getCookieValue(cookie)),
 				Path:   cookie.Path,
 				Domain: cookie.Domain,
 			}
-			httpCookies = append(httpCookies, c)
+			httpCookies = append(httpCookies, httpCookie)
 		}
 
 		jar.SetCookies(url, httpCookies)
@@ -107,7 +107,8 @@ func printJarCookies(jar *cookiejar.Jar, urls []*url.URL) {
 }
 
 // set all cookies listed in the scraper config
-func setCDPCookies(driverOptions scraperDriverOptions) chromedp.Tasks {
+func setCDPCookies(scraperConfig config) chromedp.Tasks {
+	driverOptions := scraperConfig.DriverOptions
 	return chromedp.Tasks{
 		chromedp.ActionFunc(func(ctx context.Context) error {
 			// create cookie expiration
@@ -115,7 +116,7 @@ func setCDPCookies(driverOptions scraperDriverOptions) chromedp.Tasks {
 
 			for _, ckURL := range driverOptions.Cookies {
 				for _, cookie := range ckURL.Cookies {
-					err := network.SetCookie(cookie.Name, getCookieValue(cookie)).
+					err := network.SetCookie(cookie.Name, scraperConfig.expandSettings(getCookieValue(cookie))).
 						WithExpires(&expr).
 						WithDomain(cookie.Domain).
 						WithPath(cookie.Path).
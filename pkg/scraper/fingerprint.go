@@ -0,0 +1,104 @@
+package scraper
+
+import (
+	"errors"
+	"path/filepath"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// defaultFingerprintBatchSize is the default number of scenes sent to a
+// sceneByFingerprint scraper in a single request.
+const defaultFingerprintBatchSize = 40
+
+// fingerprintScenesPlaceholder is replaced with the JSON-encoded fingerprints
+// of all scenes of a batch in the query URL of sceneByFingerprint json scrapers.
+const fingerprintScenesPlaceholder = "{scenes}"
+
+// sceneFingerprints contains the fingerprints of a scene, used as input to
+// sceneByFingerprint scrapers.
+type sceneFingerprints struct {
+	ID       string  `json:"id"`
+	Filename string  `json:"filename,omitempty"`
+	Checksum string  `json:"checksum,omitempty"`
+	OSHash   string  `json:"oshash,omitempty"`
+	PHash    string  `json:"phash,omitempty"`
+	Duration float64 `json:"duration,omitempty"`
+}
+
+// sceneFingerprintsFromScene returns the fingerprints of the primary file of
+// the scene. The scene files must be loaded.
+func sceneFingerprintsFromScene(scene *models.Scene) sceneFingerprints {
+	ret := sceneFingerprints{
+		ID: strconv.Itoa(scene.ID),
+	}
+
+	f := scene.Files.Primary()
+	if f == nil {
+		return ret
+	}
+
+	ret.Filename = filepath.Base(f.Path)
+	ret.Checksum = f.Fingerprints.GetString(models.FingerprintTypeMD5)
+	ret.OSHash = f.Fingerprints.GetString(models.FingerprintTypeOshash)
+	ret.Duration = f.Duration
+
+	phash := f.Fingerprints.GetInt64(models.FingerprintTypePhash)
+	if phash != 0 {
+		ret.PHash = utils.PhashToString(phash)
+	}
+
+	return ret
+}
+
+func (fp sceneFingerprints) hasFingerprints() bool {
+	return fp.Checksum != "" || fp.OSHash != "" || fp.PHash != ""
+}
+
+func queryURLParametersFromFingerprints(fp sceneFingerprints) queryURLParameters {
+	ret := make(queryURLParameters)
+
+	setField := func(field string, value string) {
+		if value != "" {
+			ret[field] = value
+		}
+	}
+
+	setField("filename", fp.Filename)
+	setField("checksum", fp.Checksum)
+	setField("oshash", fp.OSHash)
+	setField("phash", fp.PHash)
+	if fp.Duration > 0 {
+		ret["duration"] = strconv.Itoa(int(fp.Duration))
+	}
+
+	return ret
+}
+
+// scriptFingerprintsInput is the input sent to sceneByFingerprint script
+// scrapers.
+type scriptFingerprintsInput struct {
+	Scenes []sceneFingerprints `json:"scenes"`
+}
+
+// fingerprintErrors handles the errors of scraping count scenes or batches of
+// scenes by fingerprint. An error is returned if all of them failed. Otherwise
+// the errors are logged, and the failed scenes are returned without results.
+func fingerprintErrors(scraperID string, count int, errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	if len(errs) == count {
+		return errors.Join(errs...)
+	}
+
+	for _, err := range errs {
+		logger.Warnf("[scraper] %s: %v", scraperID, err)
+	}
+
+	return nil
+}
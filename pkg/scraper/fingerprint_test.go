@@ -0,0 +1,234 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func makeFingerprintScene(id int, oshash string) *models.Scene {
+	var files []*models.VideoFile
+	if oshash != "" {
+		f := &models.VideoFile{
+			BaseFile: &models.BaseFile{
+				Path: fmt.Sprintf("/scenes/%d.mp4", id),
+				Fingerprints: models.Fingerprints{
+					{Type: models.FingerprintTypeOshash, Fingerprint: oshash},
+					{Type: models.FingerprintTypePhash, Fingerprint: int64(0x1234)},
+				},
+			},
+			Duration: 90.5,
+		}
+		files = append(files, f)
+	}
+
+	return &models.Scene{
+		ID:    id,
+		Files: models.NewRelatedVideoFiles(files),
+	}
+}
+
+func TestSceneFingerprintsFromScene(t *testing.T) {
+	fp := sceneFingerprintsFromScene(makeFingerprintScene(1, "abc"))
+
+	assert.Equal(t, sceneFingerprints{
+		ID:       "1",
+		Filename: "1.mp4",
+		OSHash:   "abc",
+		PHash:    "1234",
+		Duration: 90.5,
+	}, fp)
+
+	assert.False(t, sceneFingerprintsFromScene(makeFingerprintScene(2, "")).hasFingerprints())
+}
+
+func TestViaFingerprints(t *testing.T) {
+	var requested []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		oshash := r.URL.Query().Get("oshash")
+		requested = append(requested, oshash+"/"+r.URL.Query().Get("duration"))
+		if oshash == "nomatch" {
+			fmt.Fprint(w, `{"results": []}`)
+			return
+		}
+		fmt.Fprintf(w, `{"results": [{"title": "%s-1"}, {"title": "%s-2"}]}`, oshash, oshash)
+	}))
+	defer ts.Close()
+
+	yamlStr := `name: Test
+sceneByFingerprint:
+  action: scrapeJson
+  queryURL: ` + ts.URL + `/match?oshash={oshash}&duration={duration}
+  scraper: sceneSearch
+jsonScrapers:
+  sceneSearch:
+    scene:
+      Title: results.#.title
+`
+
+	c := &config{}
+	if err := yaml.Unmarshal([]byte(yamlStr), &c); err != nil {
+		t.Fatalf("Error loading yaml: %v", err)
+	}
+
+	if err := c.validate(); err != nil {
+		t.Fatalf("Error validating config: %v", err)
+	}
+
+	assert.True(t, c.supports(ScrapeContentTypeScene))
+	assert.Contains(t, c.spec().Scene.SupportedScrapes, ScrapeTypeFingerprint)
+
+	s := newGroupScraper(*c, mockGlobalConfig{})
+	fs, ok := s.(fingerprintScraper)
+	if !ok {
+		t.Fatal("couldn't convert scraper into fingerprint scraper")
+	}
+
+	scenes := []*models.Scene{
+		makeFingerprintScene(1, "aaa"),
+		makeFingerprintScene(2, ""),
+		makeFingerprintScene(3, "nomatch"),
+		makeFingerprintScene(4, "bbb"),
+	}
+
+	results, err := fs.viaFingerprints(context.Background(), &http.Client{}, scenes)
+	if err != nil {
+		t.Fatalf("Error scraping fingerprints: %v", err)
+	}
+
+	// scene without fingerprints should not be queried
+	assert.Equal(t, []string{"aaa/90", "nomatch/90", "bbb/90"}, requested)
+
+	titles := make([][]string, len(results))
+	for i, r := range results {
+		for _, s := range r {
+			titles[i] = append(titles[i], *s.Title)
+		}
+	}
+
+	assert.Equal(t, [][]string{
+		{"aaa-1", "aaa-2"},
+		nil,
+		nil,
+		{"bbb-1", "bbb-2"},
+	}, titles)
+}
+
+func TestViaFingerprintsBatch(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		var scenes []sceneFingerprints
+		if err := json.Unmarshal([]byte(r.URL.Query().Get("scenes")), &scenes); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var results []string
+		for _, s := range scenes {
+			results = append(results, fmt.Sprintf(`{"results": [{"title": "%s"}]}`, s.OSHash))
+		}
+		fmt.Fprintf(w, "[%s]", strings.Join(results, ","))
+	}))
+	defer ts.Close()
+
+	yamlStr := `name: Test
+sceneByFingerprint:
+  action: scrapeJson
+  queryURL: ` + ts.URL + `/match?scenes={scenes}
+  scraper: sceneSearch
+  batchSize: 2
+jsonScrapers:
+  sceneSearch:
+    scene:
+      Title: results.#.title
+`
+
+	c := &config{}
+	if err := yaml.Unmarshal([]byte(yamlStr), &c); err != nil {
+		t.Fatalf("Error loading yaml: %v", err)
+	}
+
+	fs := newGroupScraper(*c, mockGlobalConfig{}).(fingerprintScraper)
+
+	scenes := []*models.Scene{
+		makeFingerprintScene(1, "aaa"),
+		makeFingerprintScene(2, "bbb"),
+		makeFingerprintScene(3, "ccc"),
+	}
+
+	results, err := fs.viaFingerprints(context.Background(), &http.Client{}, scenes)
+	if err != nil {
+		t.Fatalf("Error scraping fingerprints: %v", err)
+	}
+
+	assert.Equal(t, 2, requests)
+
+	if assert.Len(t, results, 3) {
+		for i, oshash := range []string{"aaa", "bbb", "ccc"} {
+			if assert.Len(t, results[i], 1) {
+				assert.Equal(t, oshash, *results[i][0].Title)
+			}
+		}
+	}
+}
+
+func TestViaFingerprintsErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		oshash := r.URL.Query().Get("oshash")
+		if oshash == "fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, `{"results": [{"title": "%s"}]}`, oshash)
+	}))
+	defer ts.Close()
+
+	yamlStr := `name: Test
+sceneByFingerprint:
+  action: scrapeJson
+  queryURL: ` + ts.URL + `/match?oshash={oshash}
+  scraper: sceneSearch
+jsonScrapers:
+  sceneSearch:
+    scene:
+      Title: results.#.title
+`
+
+	c := &config{}
+	if err := yaml.Unmarshal([]byte(yamlStr), &c); err != nil {
+		t.Fatalf("Error loading yaml: %v", err)
+	}
+
+	fs := newGroupScraper(*c, mockGlobalConfig{}).(fingerprintScraper)
+
+	// a failed scene does not prevent the others from being scraped
+	results, err := fs.viaFingerprints(context.Background(), &http.Client{}, []*models.Scene{
+		makeFingerprintScene(1, "fail"),
+		makeFingerprintScene(2, "aaa"),
+	})
+	if err != nil {
+		t.Fatalf("Error scraping fingerprints: %v", err)
+	}
+
+	if assert.Len(t, results, 2) {
+		assert.Nil(t, results[0])
+		assert.Len(t, results[1], 1)
+	}
+
+	// an error is returned if all scenes failed
+	_, err = fs.viaFingerprints(context.Background(), &http.Client{}, []*models.Scene{
+		makeFingerprintScene(1, "fail"),
+	})
+	assert.NotNil(t, err)
+}
@@ -75,6 +75,59 @@ func (g group) viaGallery(ctx context.Context, client *http.Client, gallery *mod
 	return s.scrapeGalleryByGallery(ctx, gallery)
 }
 
+func (g group) viaFingerprints(ctx context.Context, client *http.Client, scenes []*models.Scene) ([][]*ScrapedScene, error) {
+	stc := g.config.SceneByFingerprint
+	if stc == nil {
+		return nil, ErrNotSupported
+	}
+
+	// only query scenes with fingerprints
+	var fingerprints []sceneFingerprints
+	var indexes []int
+	for i, scene := range scenes {
+		fp := sceneFingerprintsFromScene(scene)
+		if fp.hasFingerprints() {
+			fingerprints = append(fingerprints, fp)
+			indexes = append(indexes, i)
+		}
+	}
+
+	s := g.config.getScraper(*stc, client, g.globalConf)
+	batchSize := stc.batchSize()
+
+	// results are returned in the same order as the input
+	// a failed batch does not prevent the other batches from being scraped
+	ret := make([][]*ScrapedScene, len(scenes))
+	var errs []error
+	batches := 0
+	for i := 0; i < len(fingerprints); i += batchSize {
+		end := i + batchSize
+		if end > len(fingerprints) {
+			end = len(fingerprints)
+		}
+		batches++
+
+		results, err := s.scrapeScenesByFingerprints(ctx, fingerprints[i:end])
+		if err == nil && len(results) != end-i {
+			err = fmt.Errorf("expected %d fingerprint results, got %d", end-i, len(results))
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("scenes %s-%s: %w", fingerprints[i].ID, fingerprints[end-1].ID, err))
+			continue
+		}
+
+		for j, r := range results {
+			ret[indexes[i+j]] = r
+		}
+	}
+
+	if err := fingerprintErrors(g.config.ID, batches, errs); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
 func loadUrlCandidates(c config, ty ScrapeContentType) []*scrapeByURLConfig {
 	switch ty {
 	case ScrapeContentTypePerformer:
@@ -85,6 +138,8 @@ func loadUrlCandidates(c config, ty ScrapeContentType) []*scrapeByURLConfig {
 		return c.MovieByURL
 	case ScrapeContentTypeGallery:
 		return c.GalleryByURL
+	case ScrapeContentTypeStudio:
+		return c.StudioByURL
 	}
 
 	panic("loadUrlCandidates: unreachable")
@@ -125,6 +180,13 @@ func (g group) viaName(ctx context.Context, client *http.Client, name string, ty
 
 		s := g.config.getScraper(*g.config.SceneByName, client, g.globalConf)
 		return s.scrapeByName(ctx, name, ty)
+	case ScrapeContentTypeStudio:
+		if g.config.StudioByName == nil {
+			break
+		}
+
+		s := g.config.getScraper(*g.config.StudioByName, client, g.globalConf)
+		return s.scrapeByName(ctx, name, ty)
 	}
 
 	return nil, fmt.Errorf("%w: cannot load %v by name", ErrNotSupported, ty)
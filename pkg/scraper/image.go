@@ -11,7 +11,26 @@ import (
 	"github.com/stashapp/stash/pkg/utils"
 )
 
+type imageURLsKey struct{}
+
+// WithImageURLs returns a context in which scraped image URLs are not
+// downloaded. The URLs are returned instead, and the images must be fetched
+// by the caller when they are used.
+func WithImageURLs(ctx context.Context) context.Context {
+	return context.WithValue(ctx, imageURLsKey{}, true)
+}
+
+// KeepImageURLs returns true if scraped image URLs should not be downloaded.
+func KeepImageURLs(ctx context.Context) bool {
+	v, _ := ctx.Value(imageURLsKey{}).(bool)
+	return v
+}
+
 func setPerformerImage(ctx context.Context, client *http.Client, p *models.ScrapedPerformer, globalConfig GlobalConfig) error {
+	if KeepImageURLs(ctx) {
+		return nil
+	}
+
 	if p.Image == nil || !strings.HasPrefix(*p.Image, "http") {
 		// nothing to do
 		return nil
@@ -29,7 +48,33 @@ func setPerformerImage(ctx context.Context, client *http.Client, p *models.Scrap
 	return nil
 }
 
+func setStudioImage(ctx context.Context, client *http.Client, s *models.ScrapedStudio, globalConfig GlobalConfig) error {
+	if KeepImageURLs(ctx) {
+		return nil
+	}
+
+	// don't try to get the image if it doesn't appear to be a URL
+	if s.Image == nil || !strings.HasPrefix(*s.Image, "http") {
+		// nothing to do
+		return nil
+	}
+
+	img, err := getImage(ctx, *s.Image, client, globalConfig)
+	if err != nil {
+		return err
+	}
+
+	s.Image = img
+	s.Images = []string{*img}
+
+	return nil
+}
+
 func setSceneImage(ctx context.Context, client *http.Client, s *ScrapedScene, globalConfig GlobalConfig) error {
+	if KeepImageURLs(ctx) {
+		return nil
+	}
+
 	// don't try to get the image if it doesn't appear to be a URL
 	if s.Image == nil || !strings.HasPrefix(*s.Image, "http") {
 		// nothing to do
@@ -47,6 +92,10 @@ func setSceneImage(ctx context.Context, client *http.Client, s *ScrapedScene, gl
 }
 
 func setMovieFrontImage(ctx context.Context, client *http.Client, m *models.ScrapedMovie, globalConfig GlobalConfig) error {
+	if KeepImageURLs(ctx) {
+		return nil
+	}
+
 	// don't try to get the image if it doesn't appear to be a URL
 	if m.FrontImage == nil || !strings.HasPrefix(*m.FrontImage, "http") {
 		// nothing to do
@@ -64,6 +113,10 @@ func setMovieFrontImage(ctx context.Context, client *http.Client, m *models.Scra
 }
 
 func setMovieBackImage(ctx context.Context, client *http.Client, m *models.ScrapedMovie, globalConfig GlobalConfig) error {
+	if KeepImageURLs(ctx) {
+		return nil
+	}
+
 	// don't try to get the image if it doesn't appear to be a URL
 	if m.BackImage == nil || !strings.HasPrefix(*m.BackImage, "http") {
 		// nothing to do
@@ -2,6 +2,7 @@ package scraper
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -50,7 +51,118 @@ func (s *jsonScraper) scrapeURL(ctx context.Context, url string) (string, *mappe
 	return doc, scraper, nil
 }
 
+func (s *jsonScraper) scrapeScenesByFingerprints(ctx context.Context, scenes []sceneFingerprints) ([][]*ScrapedScene, error) {
+	scraper := s.getJsonScraper()
+
+	if scraper == nil {
+		return nil, errors.New("json scraper with name " + s.scraper.Scraper + " not found in config")
+	}
+
+	if strings.Contains(s.scraper.QueryURL, fingerprintScenesPlaceholder) {
+		return s.scrapeSceneBatchByFingerprints(ctx, scraper, scenes)
+	}
+
+	ret := make([][]*ScrapedScene, len(scenes))
+	var errs []error
+	for i, fp := range scenes {
+		// construct the URL
+		queryURL := queryURLParametersFromFingerprints(fp)
+		if s.scraper.QueryURLReplacements != nil {
+			queryURL.applyReplacements(s.scraper.QueryURLReplacements)
+		}
+		u := queryURL.constructURL(s.config.expandURLSettings(s.scraper.QueryURL))
+
+		var err error
+		ret[i], err = s.scrapeScenesFromURL(ctx, scraper, u)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("scene %s: %w", fp.ID, err))
+		}
+	}
+
+	if err := fingerprintErrors(s.config.ID, len(scenes), errs); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// scrapeSceneBatchByFingerprints sends the fingerprints of all scenes in a
+// single request. The response must be an array with the results of each
+// scene, in the same order as the scenes.
+func (s *jsonScraper) scrapeSceneBatchByFingerprints(ctx context.Context, scraper *mappedScraper, scenes []sceneFingerprints) ([][]*ScrapedScene, error) {
+	input, err := json.Marshal(scenes)
+	if err != nil {
+		return nil, err
+	}
+
+	u := s.config.expandURLSettings(s.scraper.QueryURL)
+	u = strings.ReplaceAll(u, fingerprintScenesPlaceholder, url.QueryEscape(string(input)))
+
+	doc, err := s.loadURL(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	results := gjson.Parse(doc)
+	if !results.IsArray() {
+		return nil, errors.New("fingerprint batch response is not an array")
+	}
+
+	docs := results.Array()
+	if len(docs) != len(scenes) {
+		return nil, fmt.Errorf("expected %d fingerprint results, got %d", len(scenes), len(docs))
+	}
+
+	ret := make([][]*ScrapedScene, len(scenes))
+	var errs []error
+	for i, d := range docs {
+		q := s.getJsonQuery(d.Raw)
+		q.setType(SearchQuery)
+
+		ret[i], err = scraper.scrapeScenes(ctx, q)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("scene %s: %w", scenes[i].ID, err))
+		}
+	}
+
+	if err := fingerprintErrors(s.config.ID, len(scenes), errs); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (s *jsonScraper) scrapeScenesFromURL(ctx context.Context, scraper *mappedScraper, u string) ([]*ScrapedScene, error) {
+	doc, err := s.loadURL(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	q := s.getJsonQuery(doc)
+	q.setType(SearchQuery)
+
+	return scraper.scrapeScenes(ctx, q)
+}
+
 func (s *jsonScraper) loadURL(ctx context.Context, url string) (string, error) {
+	ret, err := s.loadDocument(ctx, url)
+	if err != nil {
+		return "", err
+	}
+
+	if s.config.loggedOut(s.getJsonQuery(ret)) {
+		logger.Infof("[scraper] %s: session expired", s.config.ID)
+		if err := s.config.session.refresh(ctx, s.client, s.config, s.globalConfig); err != nil {
+			return "", err
+		}
+
+		return s.loadDocument(ctx, url)
+	}
+
+	return ret, nil
+}
+
+func (s *jsonScraper) loadDocument(ctx context.Context, url string) (string, error) {
 	r, err := loadURL(ctx, url, s.client, s.config, s.globalConfig)
 	if err != nil {
 		return "", err
@@ -90,6 +202,8 @@ func (s *jsonScraper) scrapeByURL(ctx context.Context, url string, ty ScrapeCont
 		return scraper.scrapeGallery(ctx, q)
 	case ScrapeContentTypeMovie:
 		return scraper.scrapeMovie(ctx, q)
+	case ScrapeContentTypeStudio:
+		return scraper.scrapeStudio(ctx, q)
 	}
 
 	return nil, ErrNotSupported
@@ -102,50 +216,45 @@ func (s *jsonScraper) scrapeByName(ctx context.Context, name string, ty ScrapeCo
 		return nil, fmt.Errorf("%w: name %v", ErrNotFound, s.scraper.Scraper)
 	}
 
+	if ty != ScrapeContentTypePerformer && ty != ScrapeContentTypeScene && ty != ScrapeContentTypeStudio {
+		return nil, ErrNotSupported
+	}
+
 	const placeholder = "{}"
 
 	// replace the placeholder string with the URL-escaped name
 	escapedName := url.QueryEscape(name)
 
-	url := s.scraper.QueryURL
+	url := s.config.expandURLSettings(s.scraper.QueryURL)
 	url = strings.ReplaceAll(url, placeholder, escapedName)
 
-	doc, err := s.loadURL(ctx, url)
-
-	if err != nil {
-		return nil, err
-	}
-
-	q := s.getJsonQuery(doc)
-	q.setType(SearchQuery)
-
-	var content []ScrapedContent
-	switch ty {
-	case ScrapeContentTypePerformer:
-		performers, err := scraper.scrapePerformers(ctx, q)
+	load := func(ctx context.Context, url string) (mappedQuery, error) {
+		doc, err := s.loadURL(ctx, url)
 		if err != nil {
 			return nil, err
 		}
 
-		for _, p := range performers {
-			content = append(content, p)
-		}
+		return s.getJsonQuery(doc), nil
+	}
 
-		return content, nil
-	case ScrapeContentTypeScene:
-		scenes, err := scraper.scrapeScenes(ctx, q)
+	var content []ScrapedContent
+	scrapePage := func(q mappedQuery) (int, error) {
+		q.setType(SearchQuery)
+
+		results, err := scraper.scrapeSearchResults(ctx, q, ty)
 		if err != nil {
-			return nil, err
+			return 0, err
 		}
 
-		for _, s := range scenes {
-			content = append(content, s)
-		}
+		content = append(content, results...)
+		return len(results), nil
+	}
 
-		return content, nil
+	if err := scrapePages(ctx, url, s.scraper.Pagination, load, scrapePage); err != nil {
+		return nil, err
 	}
 
-	return nil, ErrNotSupported
+	return content, nil
 }
 
 func (s *jsonScraper) scrapeSceneByScene(ctx context.Context, scene *models.Scene) (*ScrapedScene, error) {
@@ -154,7 +263,7 @@ func (s *jsonScraper) scrapeSceneByScene(ctx context.Context, scene *models.Scen
 	if s.scraper.QueryURLReplacements != nil {
 		queryURL.applyReplacements(s.scraper.QueryURLReplacements)
 	}
-	url := queryURL.constructURL(s.scraper.QueryURL)
+	url := queryURL.constructURL(s.config.expandURLSettings(s.scraper.QueryURL))
 
 	scraper := s.getJsonScraper()
 
@@ -189,7 +298,7 @@ func (s *jsonScraper) scrapeByFragment(ctx context.Context, input Input) (Scrape
 	if s.scraper.QueryURLReplacements != nil {
 		queryURL.applyReplacements(s.scraper.QueryURLReplacements)
 	}
-	url := queryURL.constructURL(s.scraper.QueryURL)
+	url := queryURL.constructURL(s.config.expandURLSettings(s.scraper.QueryURL))
 
 	scraper := s.getJsonScraper()
 
@@ -213,7 +322,7 @@ func (s *jsonScraper) scrapeGalleryByGallery(ctx context.Context, gallery *model
 	if s.scraper.QueryURLReplacements != nil {
 		queryURL.applyReplacements(s.scraper.QueryURLReplacements)
 	}
-	url := queryURL.constructURL(s.scraper.QueryURL)
+	url := queryURL.constructURL(s.config.expandURLSettings(s.scraper.QueryURL))
 
 	scraper := s.getJsonScraper()
 
@@ -116,3 +116,68 @@ jsonScrapers:
 		t.Errorf("expected nil scraped performer when not found, got %v", scrapedPerformer)
 	}
 }
+
+func TestJsonStudioScraper(t *testing.T) {
+	const yamlStr = `name: Test
+studioByURL:
+  - action: scrapeJson
+    url:
+      - example.com
+    scraper: studioScraper
+jsonScrapers:
+  studioScraper:
+    studio:
+      Name: data.name
+      URL: data.url
+      Details: data.description
+      Aliases: data.aliases
+      Parent:
+        Name: data.network.name
+`
+
+	const json = `
+{
+	"data": {
+		"name": "Studio",
+		"url": "https://example.com/studio",
+		"description": "Studio description",
+		"aliases": "Alias 1, Alias 2",
+		"network": {
+			"name": "Network"
+		}
+	}
+}
+`
+
+	c := &config{}
+	err := yaml.Unmarshal([]byte(yamlStr), &c)
+
+	if err != nil {
+		t.Fatalf("Error loading yaml: %s", err.Error())
+	}
+
+	if !c.supports(ScrapeContentTypeStudio) || !c.matchesURL("https://example.com/studio", ScrapeContentTypeStudio) {
+		t.Error("expected config to support studio url scraping")
+	}
+
+	studioScraper := c.JsonScrapers["studioScraper"]
+	q := &jsonQuery{
+		doc: json,
+	}
+
+	scrapedStudio, err := studioScraper.scrapeStudio(context.Background(), q)
+	if err != nil {
+		t.Fatalf("Error scraping studio: %s", err.Error())
+	}
+
+	if scrapedStudio.Name != "Studio" {
+		t.Errorf("expected name Studio, got %s", scrapedStudio.Name)
+	}
+	verifyField(t, "https://example.com/studio", scrapedStudio.URL, "URL")
+	verifyField(t, "Studio description", scrapedStudio.Details, "Details")
+	verifyField(t, "Alias 1, Alias 2", scrapedStudio.Aliases, "Aliases")
+
+	if scrapedStudio.Parent == nil || scrapedStudio.Parent.Name != "Network" {
+		t.Errorf("expected parent studio Network, got %v", scrapedStudio.Parent)
+	}
+}
@@ -0,0 +1,539 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"github.com/tidwall/gjson"
+	"golang.org/x/net/html"
+	"golang.org/x/net/publicsuffix"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+const sessionsDir = "scraper_sessions"
+
+type loginAuthHeader struct {
+	// Header to set on subsequent requests.
+	Key string `yaml:"Key"`
+	// GJSON selector of the token in the login response.
+	Selector string `yaml:"selector"`
+	// Prefix prepended to the token. For example "Bearer ".
+	Prefix string `yaml:"prefix"`
+}
+
+type loginAction struct {
+	XPath string `yaml:"xpath"`
+	// Text to type into the node. If empty, the node is clicked.
+	Value string `yaml:"value"`
+	Sleep int    `yaml:"sleep"`
+}
+
+type loginConfig struct {
+	// URL the login form is submitted to. When using CDP, the URL of the
+	// login page to navigate to.
+	URL string `yaml:"url"`
+
+	// URL of the login page. If set, the page is loaded before submitting
+	// the form, so that cookies are set and tokens can be extracted.
+	FormURL string `yaml:"formURL"`
+
+	// Form fields to submit.
+	Fields map[string]string `yaml:"fields"`
+
+	// Submit the fields as a JSON object rather than an url-encoded form.
+	JSON bool `yaml:"json"`
+
+	// Map of form field name to xpath selector. Each selector is run against
+	// the page at FormURL and the result is submitted with the form.
+	Tokens map[string]string `yaml:"tokens"`
+
+	// Header to set on subsequent requests using a value from the login
+	// response.
+	AuthHeader *loginAuthHeader `yaml:"authHeader"`
+
+	// Browser actions to perform to log in when using CDP.
+	Actions []*loginAction `yaml:"actions"`
+
+	// Selector which matches content only present on pages when logged
+	// out. When it matches a scraped page, the scraper logs in again and
+	// reloads the page.
+	LoggedOut string `yaml:"loggedOut"`
+}
+
+func (c loginConfig) validate() error {
+	if c.URL == "" {
+		return errors.New("url is mandatory for login")
+	}
+
+	if len(c.Tokens) > 0 && c.FormURL == "" {
+		return errors.New("formURL is mandatory for login tokens")
+	}
+
+	if c.AuthHeader != nil && (c.AuthHeader.Key == "" || c.AuthHeader.Selector == "") {
+		return errors.New("Key and selector are mandatory for login authHeader")
+	}
+
+	return nil
+}
+
+type sessionCookie struct {
+	URL    string       `json:"url"`
+	Cookie *http.Cookie `json:"cookie"`
+}
+
+// sessionJar is a cookie jar that records the cookies set on it, so that
+// they can be persisted.
+type sessionJar struct {
+	*cookiejar.Jar
+
+	mutex   sync.Mutex
+	cookies map[string]sessionCookie
+}
+
+func newSessionJar() *sessionJar {
+	// cookiejar.New only returns an error on invalid options
+	jar, _ := cookiejar.New(&cookiejar.Options{
+		PublicSuffixList: publicsuffix.List,
+	})
+
+	return &sessionJar{
+		Jar:     jar,
+		cookies: make(map[string]sessionCookie),
+	}
+}
+
+func (j *sessionJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.Jar.SetCookies(u, cookies)
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	for _, c := range cookies {
+		key := strings.Join([]string{u.Host, c.Domain, c.Path, c.Name}, "|")
+		j.cookies[key] = sessionCookie{
+			URL:    u.String(),
+			Cookie: c,
+		}
+	}
+}
+
+func (j *sessionJar) records() []sessionCookie {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	var ret []sessionCookie
+	for _, c := range j.cookies {
+		ret = append(ret, c)
+	}
+
+	return ret
+}
+
+type persistedSession struct {
+	Cookies []sessionCookie   `json:"cookies"`
+	Headers map[string]string `json:"headers"`
+}
+
+// scraperSession holds the logged in session of a scraper with a login
+// configuration. The session is persisted to disk if a path is set.
+type scraperSession struct {
+	mutex    sync.Mutex
+	jar      *sessionJar
+	headers  map[string]string
+	loggedIn bool
+	path     string
+}
+
+// newScraperSession returns a new session, loading the persisted session
+// from path if present. If path is empty, the session is not persisted.
+func newScraperSession(path string) *scraperSession {
+	ret := &scraperSession{
+		jar:     newSessionJar(),
+		headers: make(map[string]string),
+		path:    path,
+	}
+
+	if path == "" {
+		return ret
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Warnf("error reading scraper session %s: %v", path, err)
+		}
+		return ret
+	}
+
+	var persisted persistedSession
+	if err := json.Unmarshal(data, &persisted); err != nil {
+		logger.Warnf("error parsing scraper session %s: %v", path, err)
+		return ret
+	}
+
+	for _, c := range persisted.Cookies {
+		u, err := url.Parse(c.URL)
+		if err != nil || c.Cookie == nil {
+			continue
+		}
+		ret.jar.SetCookies(u, []*http.Cookie{c.Cookie})
+	}
+
+	for k, v := range persisted.Headers {
+		ret.headers[k] = v
+	}
+
+	ret.loggedIn = len(persisted.Cookies) > 0 || len(persisted.Headers) > 0
+
+	return ret
+}
+
+func sessionPath(globalConfig GlobalConfig, scraperID string) string {
+	cachePath := globalConfig.GetCachePath()
+	if cachePath == "" {
+		return ""
+	}
+
+	return filepath.Join(cachePath, sessionsDir, scraperID+".json")
+}
+
+// save persists the session. The session mutex must be held by the caller.
+func (s *scraperSession) save() {
+	if s.path == "" {
+		return
+	}
+
+	persisted := persistedSession{
+		Cookies: s.jar.records(),
+		Headers: s.headers,
+	}
+	data, err := json.Marshal(persisted)
+	if err != nil {
+		logger.Warnf("error encoding scraper session: %v", err)
+		return
+	}
+
+	if err := fsutil.EnsureDir(filepath.Dir(s.path)); err != nil {
+		logger.Warnf("error creating scraper session directory: %v", err)
+		return
+	}
+
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		logger.Warnf("error writing scraper session %s: %v", s.path, err)
+	}
+}
+
+// apply adds the session cookies and headers to the request.
+func (s *scraperSession) apply(req *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, cookie := range s.jar.Cookies(req.URL) {
+		req.AddCookie(cookie)
+	}
+
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+}
+
+// update stores cookies set by a response in the session.
+func (s *scraperSession) update(resp *http.Response) {
+	cookies := resp.Cookies()
+	if len(cookies) == 0 {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.jar.SetCookies(resp.Request.URL, cookies)
+	s.save()
+}
+
+// ensureLoggedIn logs in if the session is not already logged in.
+func (s *scraperSession) ensureLoggedIn(ctx context.Context, client *http.Client, c config, globalConfig GlobalConfig) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.loggedIn {
+		return nil
+	}
+
+	return s.login(ctx, client, c, globalConfig)
+}
+
+// refresh discards the current session and logs in again.
+func (s *scraperSession) refresh(ctx context.Context, client *http.Client, c config, globalConfig GlobalConfig) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.jar = newSessionJar()
+	s.headers = make(map[string]string)
+	s.loggedIn = false
+
+	return s.login(ctx, client, c, globalConfig)
+}
+
+// login performs the login. The session mutex must be held by the caller.
+func (s *scraperSession) login(ctx context.Context, client *http.Client, c config, globalConfig GlobalConfig) error {
+	login := c.Login
+	if login == nil {
+		return nil
+	}
+
+	logger.Infof("[scraper] %s: logging in", c.ID)
+
+	var err error
+	if c.DriverOptions != nil && c.DriverOptions.UseCDP {
+		err = s.cdpLogin(ctx, c, globalConfig)
+	} else {
+		err = s.formLogin(ctx, client, c, globalConfig)
+	}
+
+	if err != nil {
+		return fmt.Errorf("logging in to %s: %w", login.URL, err)
+	}
+
+	s.loggedIn = true
+	s.save()
+
+	return nil
+}
+
+func (s *scraperSession) doLoginRequest(client *http.Client, req *http.Request, globalConfig GlobalConfig) ([]byte, error) {
+	userAgent := globalConfig.GetScraperUserAgent()
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("http error %d:%s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+func (s *scraperSession) formLogin(ctx context.Context, client *http.Client, c config, globalConfig GlobalConfig) error {
+	login := *c.Login
+
+	// use a copy of the client which stores cookies in the session,
+	// including those set during redirects
+	sessionClient := *client
+	sessionClient.Jar = s.jar
+
+	fields := make(map[string]string)
+
+	if login.FormURL != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.expandURLSettings(login.FormURL), nil)
+		if err != nil {
+			return err
+		}
+
+		body, err := s.doLoginRequest(&sessionClient, req, globalConfig)
+		if err != nil {
+			return fmt.Errorf("loading login page: %w", err)
+		}
+
+		doc, err := html.Parse(bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("parsing login page: %w", err)
+		}
+
+		q := &xpathQuery{doc: doc}
+		for name, selector := range login.Tokens {
+			found, err := q.runQuery(selector)
+			if err != nil {
+				return err
+			}
+			if len(found) == 0 {
+				return fmt.Errorf("token %s not found in login page", name)
+			}
+			fields[name] = found[0]
+		}
+	}
+
+	for k, v := range login.Fields {
+		fields[k] = c.expandSettings(v)
+	}
+
+	var body io.Reader
+	contentType := "application/x-www-form-urlencoded"
+	if login.JSON {
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+		contentType = "application/json"
+	} else {
+		values := url.Values{}
+		for k, v := range fields {
+			values.Set(k, v)
+		}
+		body = strings.NewReader(values.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.expandURLSettings(login.URL), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	respBody, err := s.doLoginRequest(&sessionClient, req, globalConfig)
+	if err != nil {
+		return err
+	}
+
+	if login.AuthHeader != nil {
+		token := gjson.GetBytes(respBody, login.AuthHeader.Selector).String()
+		if token == "" {
+			return fmt.Errorf("auth token %s not found in login response", login.AuthHeader.Selector)
+		}
+		s.headers[login.AuthHeader.Key] = login.AuthHeader.Prefix + token
+	}
+
+	return nil
+}
+
+func (s *scraperSession) cdpLogin(ctx context.Context, c config, globalConfig GlobalConfig) error {
+	login := *c.Login
+	driverOptions := *c.DriverOptions
+
+	ctx, cancel, err := newCDPContext(ctx, globalConfig)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	ctx, cancel = context.WithTimeout(ctx, scrapeGetTimeout)
+	defer cancel()
+
+	sleepDuration := scrapeDefaultSleep
+	if driverOptions.Sleep > 0 {
+		sleepDuration = time.Duration(driverOptions.Sleep) * time.Second
+	}
+
+	tasks := chromedp.Tasks{
+		network.Enable(),
+		network.SetExtraHTTPHeaders(network.Headers(cdpHeaders(c))),
+		chromedp.Navigate(c.expandURLSettings(login.URL)),
+		chromedp.Sleep(sleepDuration),
+	}
+
+	for _, action := range login.Actions {
+		if action.Value != "" {
+			tasks = append(tasks, chromedp.SendKeys(action.XPath, c.expandSettings(action.Value)))
+		} else {
+			tasks = append(tasks, chromedp.Click(action.XPath))
+		}
+
+		waitDuration := scrapeDefaultSleep
+		if action.Sleep > 0 {
+			waitDuration = time.Duration(action.Sleep) * time.Second
+		}
+		tasks = append(tasks, chromedp.Sleep(waitDuration))
+	}
+
+	tasks = append(tasks, chromedp.ActionFunc(func(ctx context.Context) error {
+		cookies, err := network.GetCookies().Do(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, chromeCookie := range cookies {
+			u := &url.URL{
+				Scheme: "https",
+				Host:   strings.TrimPrefix(chromeCookie.Domain, "."),
+				Path:   chromeCookie.Path,
+			}
+			if !chromeCookie.Secure {
+				u.Scheme = "http"
+			}
+
+			cookie := &http.Cookie{
+				Name:     chromeCookie.Name,
+				Value:    chromeCookie.Value,
+				Path:     chromeCookie.Path,
+				Secure:   chromeCookie.Secure,
+				HttpOnly: chromeCookie.HTTPOnly,
+			}
+			if strings.HasPrefix(chromeCookie.Domain, ".") {
+				cookie.Domain = chromeCookie.Domain
+			}
+			if !chromeCookie.Session {
+				cookie.Expires = time.Unix(int64(chromeCookie.Expires), 0)
+			}
+
+			s.jar.SetCookies(u, []*http.Cookie{cookie})
+		}
+
+		return nil
+	}))
+
+	return chromedp.Run(ctx, tasks)
+}
+
+// setCDPSessionCookies sets the session cookies for the given URL in the browser.
+func setCDPSessionCookies(session *scraperSession, loadURL string) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if session == nil {
+			return nil
+		}
+
+		u, err := url.Parse(loadURL)
+		if err != nil {
+			return err
+		}
+
+		session.mutex.Lock()
+		cookies := session.jar.Cookies(u)
+		session.mutex.Unlock()
+
+		for _, cookie := range cookies {
+			if err := network.SetCookie(cookie.Name, cookie.Value).WithURL(loadURL).Do(ctx); err != nil {
+				return fmt.Errorf("could not set chrome session cookie %s: %w", cookie.Name, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// loggedOut returns true if the login configuration's logged out selector
+// matches the query.
+func (c config) loggedOut(q mappedQuery) bool {
+	if c.Login == nil || c.Login.LoggedOut == "" || c.session == nil {
+		return false
+	}
+
+	found, err := q.runQuery(c.Login.LoggedOut)
+	if err != nil {
+		logger.Warnf("error running loggedOut selector: %v", err)
+		return false
+	}
+
+	return len(found) > 0
+}
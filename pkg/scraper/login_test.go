@@ -0,0 +1,144 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type loginTestServer struct {
+	*httptest.Server
+	logins  int
+	session string
+}
+
+func newLoginTestServer() *loginTestServer {
+	ret := &loginTestServer{}
+	ret.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			fmt.Fprint(w, `<form><input name="csrf" value="token123"/></form>`)
+		case "/do-login":
+			if r.FormValue("user") != "user" || r.FormValue("csrf") != "token123" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			ret.logins++
+			ret.session = fmt.Sprintf("session%d", ret.logins)
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: ret.session, Path: "/"})
+		default:
+			c, err := r.Cookie("session")
+			if err != nil || c.Value != ret.session {
+				fmt.Fprint(w, `<div class="login-required">Please log in</div>`)
+				return
+			}
+			fmt.Fprint(w, `<div><span class="name">The name</span></div>`)
+		}
+	}))
+
+	return ret
+}
+
+func loginTestConfig(t *testing.T, serverURL string) *config {
+	t.Helper()
+
+	yamlStr := `name: Test
+performerByURL:
+  - action: scrapeXPath
+    url:
+      - ` + serverURL + `
+    scraper: performerScraper
+login:
+  url: ` + serverURL + `/do-login
+  formURL: ` + serverURL + `/login
+  fields:
+    user: user
+  tokens:
+    csrf: //input[@name="csrf"]/@value
+  loggedOut: //div[@class="login-required"]
+xPathScrapers:
+  performerScraper:
+    performer:
+      Name: //span[@class="name"]
+`
+
+	c := &config{}
+	if err := yaml.Unmarshal([]byte(yamlStr), &c); err != nil {
+		t.Fatalf("Error loading yaml: %v", err)
+	}
+
+	if err := c.validate(); err != nil {
+		t.Fatalf("Error validating config: %v", err)
+	}
+
+	return c
+}
+
+func scrapeLoginTestPerformer(t *testing.T, c *config, u string) string {
+	t.Helper()
+
+	s := newGroupScraper(*c, mockGlobalConfig{})
+	content, err := s.(urlScraper).viaURL(context.Background(), &http.Client{}, u, ScrapeContentTypePerformer)
+	if err != nil {
+		t.Fatalf("Error scraping performer: %v", err)
+	}
+
+	performer, ok := content.(*models.ScrapedPerformer)
+	if !ok || performer == nil || performer.Name == nil {
+		t.Fatal("performer not scraped")
+	}
+
+	return *performer.Name
+}
+
+func TestLogin(t *testing.T) {
+	ts := newLoginTestServer()
+	defer ts.Close()
+
+	sessionFile := filepath.Join(t.TempDir(), "session.json")
+
+	c := loginTestConfig(t, ts.URL)
+	c.session = newScraperSession(sessionFile)
+
+	// logs in before the first scrape
+	assert.Equal(t, "The name", scrapeLoginTestPerformer(t, c, ts.URL+"/performer"))
+	assert.Equal(t, 1, ts.logins)
+
+	// reuses the session
+	assert.Equal(t, "The name", scrapeLoginTestPerformer(t, c, ts.URL+"/performer"))
+	assert.Equal(t, 1, ts.logins)
+
+	// restores the persisted session
+	c.session = newScraperSession(sessionFile)
+	assert.Equal(t, "The name", scrapeLoginTestPerformer(t, c, ts.URL+"/performer"))
+	assert.Equal(t, 1, ts.logins)
+
+	// logs in again when the session expires
+	ts.session = "expired"
+	assert.Equal(t, "The name", scrapeLoginTestPerformer(t, c, ts.URL+"/performer"))
+	assert.Equal(t, 2, ts.logins)
+}
+
+func TestLoginConfigValidate(t *testing.T) {
+	assert.Error(t, loginConfig{}.validate())
+	assert.Error(t, loginConfig{
+		URL:    "http://example.com/login",
+		Tokens: map[string]string{"csrf": "//input"},
+	}.validate())
+	assert.Error(t, loginConfig{
+		URL:        "http://example.com/login",
+		AuthHeader: &loginAuthHeader{Key: "Authorization"},
+	}.validate())
+	assert.NoError(t, loginConfig{
+		URL:        "http://example.com/login",
+		AuthHeader: &loginAuthHeader{Key: "Authorization", Selector: "token"},
+	}.validate())
+}
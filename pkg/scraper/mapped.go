@@ -117,6 +117,7 @@ type mappedSceneScraperConfig struct {
 	Performers mappedPerformerScraperConfig `yaml:"Performers"`
 	Studio     mappedConfig                 `yaml:"Studio"`
 	Movies     mappedConfig                 `yaml:"Movies"`
+	Markers    mappedConfig                 `yaml:"Markers"`
 }
 type _mappedSceneScraperConfig mappedSceneScraperConfig
 
@@ -125,6 +126,7 @@ const (
 	mappedScraperConfigScenePerformers = "Performers"
 	mappedScraperConfigSceneStudio     = "Studio"
 	mappedScraperConfigSceneMovies     = "Movies"
+	mappedScraperConfigSceneMarkers    = "Markers"
 )
 
 func (s *mappedSceneScraperConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
@@ -142,11 +144,13 @@ func (s *mappedSceneScraperConfig) UnmarshalYAML(unmarshal func(interface{}) err
 	thisMap[mappedScraperConfigScenePerformers] = parentMap[mappedScraperConfigScenePerformers]
 	thisMap[mappedScraperConfigSceneStudio] = parentMap[mappedScraperConfigSceneStudio]
 	thisMap[mappedScraperConfigSceneMovies] = parentMap[mappedScraperConfigSceneMovies]
+	thisMap[mappedScraperConfigSceneMarkers] = parentMap[mappedScraperConfigSceneMarkers]
 
 	delete(parentMap, mappedScraperConfigSceneTags)
 	delete(parentMap, mappedScraperConfigScenePerformers)
 	delete(parentMap, mappedScraperConfigSceneStudio)
 	delete(parentMap, mappedScraperConfigSceneMovies)
+	delete(parentMap, mappedScraperConfigSceneMarkers)
 
 	// re-unmarshal the sub-fields
 	yml, err := yaml.Marshal(thisMap)
@@ -760,6 +764,58 @@ func (c mappedScraperAttrConfig) postProcess(ctx context.Context, value string,
 	return value
 }
 
+type mappedStudioScraperConfig struct {
+	mappedConfig
+
+	Parent mappedConfig `yaml:"Parent"`
+}
+type _mappedStudioScraperConfig mappedStudioScraperConfig
+
+const (
+	mappedScraperConfigStudioParent = "Parent"
+)
+
+func (s *mappedStudioScraperConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	// HACK - unmarshal to map first, then remove known studio sub-fields, then
+	// remarshal to yaml and pass that down to the base map
+	parentMap := make(map[string]interface{})
+	if err := unmarshal(parentMap); err != nil {
+		return err
+	}
+
+	// move the known sub-fields to a separate map
+	thisMap := make(map[string]interface{})
+
+	thisMap[mappedScraperConfigStudioParent] = parentMap[mappedScraperConfigStudioParent]
+
+	delete(parentMap, mappedScraperConfigStudioParent)
+
+	// re-unmarshal the sub-fields
+	yml, err := yaml.Marshal(thisMap)
+	if err != nil {
+		return err
+	}
+
+	// needs to be a different type to prevent infinite recursion
+	c := _mappedStudioScraperConfig{}
+	if err := yaml.Unmarshal(yml, &c); err != nil {
+		return err
+	}
+
+	*s = mappedStudioScraperConfig(c)
+
+	yml, err = yaml.Marshal(parentMap)
+	if err != nil {
+		return err
+	}
+
+	if err := yaml.Unmarshal(yml, &s.mappedConfig); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 type mappedScrapers map[string]*mappedScraper
 
 type mappedScraper struct {
@@ -768,6 +824,7 @@ type mappedScraper struct {
 	Gallery   *mappedGalleryScraperConfig   `yaml:"gallery"`
 	Performer *mappedPerformerScraperConfig `yaml:"performer"`
 	Movie     *mappedMovieScraperConfig     `yaml:"movie"`
+	Studio    *mappedStudioScraperConfig    `yaml:"studio"`
 }
 
 type mappedResult map[string]string
@@ -863,6 +920,49 @@ func (s mappedScraper) scrapePerformers(ctx context.Context, q mappedQuery) ([]*
 	return ret, nil
 }
 
+// scrapeSearchResults scrapes all search results of the given type from a
+// single results page.
+func (s mappedScraper) scrapeSearchResults(ctx context.Context, q mappedQuery, ty ScrapeContentType) ([]ScrapedContent, error) {
+	var content []ScrapedContent
+	switch ty {
+	case ScrapeContentTypePerformer:
+		performers, err := s.scrapePerformers(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range performers {
+			content = append(content, p)
+		}
+
+		return content, nil
+	case ScrapeContentTypeScene:
+		scenes, err := s.scrapeScenes(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, s := range scenes {
+			content = append(content, s)
+		}
+
+		return content, nil
+	case ScrapeContentTypeStudio:
+		studios, err := s.scrapeStudios(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, s := range studios {
+			content = append(content, s)
+		}
+
+		return content, nil
+	}
+
+	return nil, ErrNotSupported
+}
+
 // processSceneRelationships sets the relationships on the ScrapedScene. It returns true if any relationships were set.
 func (s mappedScraper) processSceneRelationships(ctx context.Context, q mappedQuery, resultIndex int, ret *ScrapedScene) bool {
 	sceneScraperConfig := s.Scene
@@ -871,6 +971,7 @@ func (s mappedScraper) processSceneRelationships(ctx context.Context, q mappedQu
 	sceneTagsMap := sceneScraperConfig.Tags
 	sceneStudioMap := sceneScraperConfig.Studio
 	sceneMoviesMap := sceneScraperConfig.Movies
+	sceneMarkersMap := sceneScraperConfig.Markers
 
 	ret.Performers = s.processPerformers(ctx, scenePerformersMap, q)
 
@@ -897,7 +998,12 @@ func (s mappedScraper) processSceneRelationships(ctx context.Context, q mappedQu
 		ret.Movies = processRelationships[models.ScrapedMovie](ctx, s, sceneMoviesMap, q)
 	}
 
-	return len(ret.Performers) > 0 || len(ret.Tags) > 0 || ret.Studio != nil || len(ret.Movies) > 0
+	if sceneMarkersMap != nil {
+		logger.Debug(`Processing scene markers:`)
+		ret.Markers = processRelationships[models.ScrapedSceneMarker](ctx, s, sceneMarkersMap, q)
+	}
+
+	return len(ret.Performers) > 0 || len(ret.Tags) > 0 || ret.Studio != nil || len(ret.Movies) > 0 || len(ret.Markers) > 0
 }
 
 func (s mappedScraper) processPerformers(ctx context.Context, performersMap mappedPerformerScraperConfig, q mappedQuery) []*models.ScrapedPerformer {
@@ -1095,3 +1201,55 @@ func (s mappedScraper) scrapeMovie(ctx context.Context, q mappedQuery) (*models.
 
 	return &ret, nil
 }
+
+func (s mappedScraper) scrapeStudio(ctx context.Context, q mappedQuery) (*models.ScrapedStudio, error) {
+	var ret models.ScrapedStudio
+
+	studioScraperConfig := s.Studio
+	if studioScraperConfig == nil {
+		return nil, nil
+	}
+
+	studioMap := studioScraperConfig.mappedConfig
+
+	studioParentMap := studioScraperConfig.Parent
+
+	results := studioMap.process(ctx, q, s.Common)
+
+	if studioParentMap != nil {
+		logger.Debug(`Processing studio parent:`)
+		parentResults := studioParentMap.process(ctx, q, s.Common)
+
+		if len(parentResults) > 0 {
+			parent := &models.ScrapedStudio{}
+			parentResults[0].apply(parent)
+			ret.Parent = parent
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	results[0].apply(&ret)
+
+	return &ret, nil
+}
+
+func (s mappedScraper) scrapeStudios(ctx context.Context, q mappedQuery) ([]*models.ScrapedStudio, error) {
+	var ret []*models.ScrapedStudio
+
+	studioScraperConfig := s.Studio
+	if studioScraperConfig == nil {
+		return nil, nil
+	}
+
+	results := studioScraperConfig.process(ctx, q, s.Common)
+	for _, r := range results {
+		var p models.ScrapedStudio
+		r.apply(&p)
+		ret = append(ret, &p)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,156 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+const (
+	pagePlaceholder = "{page}"
+
+	defaultFirstPage = 1
+	defaultMaxPages  = 10
+)
+
+// paginationConfig configures how the results pages of a name query are walked.
+// The next page is determined either by the NextPage selector, or by replacing
+// the {page} placeholder in the query URL with the next page number.
+type paginationConfig struct {
+	// Selector returning the URL of the next results page. Relative URLs are
+	// resolved against the URL of the current page.
+	NextPage string `yaml:"nextPage"`
+
+	// Page number to use for the first page when using the {page} placeholder.
+	// Defaults to 1.
+	FirstPage *int `yaml:"firstPage"`
+
+	// Maximum number of pages to load. Defaults to 10.
+	MaxPages int `yaml:"maxPages"`
+}
+
+func (c paginationConfig) validate(queryURL string) error {
+	if c.MaxPages < 0 {
+		return errors.New("pagination maxPages must not be negative")
+	}
+
+	if c.NextPage == "" && !strings.Contains(queryURL, pagePlaceholder) {
+		return errors.New("pagination requires nextPage or a " + pagePlaceholder + " placeholder in queryURL")
+	}
+
+	return nil
+}
+
+func (c paginationConfig) firstPage() int {
+	if c.FirstPage != nil {
+		return *c.FirstPage
+	}
+
+	return defaultFirstPage
+}
+
+func (c paginationConfig) maxPages() int {
+	if c.MaxPages > 0 {
+		return c.MaxPages
+	}
+
+	return defaultMaxPages
+}
+
+func (c paginationConfig) pageURL(queryURL string, page int) string {
+	return strings.ReplaceAll(queryURL, pagePlaceholder, strconv.Itoa(page))
+}
+
+// nextURL returns the URL of the page following the current page. It returns
+// an empty string if there is no next page.
+func (c paginationConfig) nextURL(q mappedQuery, queryURL string, currentURL string, nextPage int) string {
+	if c.NextPage == "" {
+		return c.pageURL(queryURL, nextPage)
+	}
+
+	found, err := q.runQuery(c.NextPage)
+	if err != nil {
+		logger.Warnf("error running next page selector: %v", err)
+		return ""
+	}
+
+	if len(found) == 0 {
+		return ""
+	}
+
+	next, err := url.Parse(found[0])
+	if err != nil {
+		logger.Warnf("invalid next page URL %q: %v", found[0], err)
+		return ""
+	}
+
+	base, err := url.Parse(currentURL)
+	if err != nil {
+		return next.String()
+	}
+
+	return base.ResolveReference(next).String()
+}
+
+type pageLoader func(ctx context.Context, url string) (mappedQuery, error)
+
+// scrapePages loads the results pages for queryURL and calls scrapePage for
+// each of them. scrapePage returns the number of results found on the page.
+// If pagination is nil, only queryURL is loaded. Otherwise pages are loaded
+// until a page returns no results, there is no next page, a page has already
+// been visited, or the maximum number of pages has been reached.
+func scrapePages(ctx context.Context, queryURL string, pagination *paginationConfig, load pageLoader, scrapePage func(q mappedQuery) (int, error)) error {
+	if pagination == nil {
+		q, err := load(ctx, queryURL)
+		if err != nil {
+			return err
+		}
+
+		_, err = scrapePage(q)
+		return err
+	}
+
+	page := pagination.firstPage()
+	pageURL := pagination.pageURL(queryURL, page)
+	visited := make(map[string]bool)
+
+	for i := 0; i < pagination.maxPages(); i++ {
+		if visited[pageURL] {
+			break
+		}
+		visited[pageURL] = true
+
+		q, err := load(ctx, pageURL)
+		if err != nil {
+			// return the error if the first page fails, otherwise return
+			// what we have so far
+			if i == 0 {
+				return err
+			}
+
+			logger.Warnf("error loading results page %s: %v", pageURL, err)
+			break
+		}
+
+		n, err := scrapePage(q)
+		if err != nil {
+			return err
+		}
+
+		if n == 0 {
+			break
+		}
+
+		page++
+		pageURL = pagination.nextURL(q, queryURL, pageURL, page)
+		if pageURL == "" {
+			break
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,172 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func scrapePerformerNames(t *testing.T, yamlStr string, name string) []string {
+	t.Helper()
+
+	c := &config{}
+	if err := yaml.Unmarshal([]byte(yamlStr), &c); err != nil {
+		t.Fatalf("Error loading yaml: %v", err)
+	}
+
+	if err := c.validate(); err != nil {
+		t.Fatalf("Error validating config: %v", err)
+	}
+
+	s := newGroupScraper(*c, mockGlobalConfig{})
+	ns, ok := s.(nameScraper)
+	if !ok {
+		t.Fatal("couldn't convert scraper into name scraper")
+	}
+
+	content, err := ns.viaName(context.Background(), &http.Client{}, name, ScrapeContentTypePerformer)
+	if err != nil {
+		t.Fatalf("Error scraping performers: %v", err)
+	}
+
+	var ret []string
+	for _, c := range content {
+		p, ok := c.(*models.ScrapedPerformer)
+		if !ok {
+			t.Fatal("couldn't convert scraped content into a performer")
+		}
+		ret = append(ret, *p.Name)
+	}
+
+	return ret
+}
+
+func TestPaginationNextPage(t *testing.T) {
+	pages := map[string]string{
+		"/search":   `<div><span>One</span><span>Two</span><a class="next" href="/search/2">Next</a></div>`,
+		"/search/2": `<div><span>Three</span><a class="next" href="3">Next</a></div>`,
+		"/search/3": `<div><span>Four</span></div>`,
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, pages[r.URL.Path])
+	}))
+	defer ts.Close()
+
+	yamlStr := `name: Test
+performerByName:
+  action: scrapeXPath
+  queryURL: ` + ts.URL + `/search?q={}
+  scraper: performerSearch
+  pagination:
+    nextPage: //a[@class="next"]/@href
+xPathScrapers:
+  performerSearch:
+    performer:
+      Name: //span
+`
+
+	names := scrapePerformerNames(t, yamlStr, "name")
+	assert.Equal(t, []string{"One", "Two", "Three", "Four"}, names)
+}
+
+func TestPaginationPagePlaceholder(t *testing.T) {
+	var requested []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		requested = append(requested, page)
+		fmt.Fprintf(w, `{"results": [{"name": "%s-a"}, {"name": "%s-b"}]}`, page, page)
+	}))
+	defer ts.Close()
+
+	yamlStr := `name: Test
+performerByName:
+  action: scrapeJson
+  queryURL: ` + ts.URL + `/search?q={}&page={page}
+  scraper: performerSearch
+  pagination:
+    firstPage: 0
+    maxPages: 3
+jsonScrapers:
+  performerSearch:
+    performer:
+      Name: results.#.name
+`
+
+	names := scrapePerformerNames(t, yamlStr, "name")
+	assert.Equal(t, []string{"0-a", "0-b", "1-a", "1-b", "2-a", "2-b"}, names)
+	assert.Equal(t, []string{"0", "1", "2"}, requested)
+}
+
+func TestPaginationStopsOnEmptyPage(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("page") == "1" {
+			fmt.Fprint(w, `<div><span>One</span></div>`)
+		} else {
+			fmt.Fprint(w, `<div></div>`)
+		}
+	}))
+	defer ts.Close()
+
+	yamlStr := `name: Test
+performerByName:
+  action: scrapeXPath
+  queryURL: ` + ts.URL + `/search?q={}&page={page}
+  scraper: performerSearch
+  pagination:
+    maxPages: 5
+xPathScrapers:
+  performerSearch:
+    performer:
+      Name: //span
+`
+
+	names := scrapePerformerNames(t, yamlStr, "name")
+	assert.Equal(t, []string{"One"}, names)
+	assert.Equal(t, 2, requests)
+}
+
+func TestPaginationValidate(t *testing.T) {
+	c := scraperTypeConfig{
+		Action:     scraperActionXPath,
+		QueryURL:   "http://example.com/search?q={}",
+		Pagination: &paginationConfig{},
+	}
+
+	assert.Error(t, c.validate())
+
+	c.QueryURL = "http://example.com/search?q={}&page={page}"
+	assert.NoError(t, c.validate())
+
+	c.Pagination.MaxPages = -1
+	assert.Error(t, c.validate())
+
+	c.Pagination.MaxPages = 0
+	c.Action = scraperActionScript
+	c.Script = []string{"script.py"}
+	assert.Error(t, c.validate())
+}
+
+func TestPaginationOnlyForNameScrapers(t *testing.T) {
+	const yamlStr = `name: Test
+sceneByFragment:
+  action: scrapeXPath
+  queryURL: http://example.com/search?q={filename}&page={page}
+  scraper: sceneScraper
+  pagination:
+    maxPages: 2
+`
+
+	_, err := loadConfigFromYAML("test", strings.NewReader(yamlStr))
+	assert.Error(t, err)
+}
@@ -38,6 +38,12 @@ func (c Cache) postScrape(ctx context.Context, content ScrapedContent) (ScrapedC
 		}
 	case models.ScrapedMovie:
 		return c.postScrapeMovie(ctx, v)
+	case *models.ScrapedStudio:
+		if v != nil {
+			return c.postScrapeStudio(ctx, *v)
+		}
+	case models.ScrapedStudio:
+		return c.postScrapeStudio(ctx, v)
 	}
 
 	// If nothing matches, pass the content through
@@ -91,6 +97,24 @@ func (c Cache) postScrapeMovie(ctx context.Context, m models.ScrapedMovie) (Scra
 	return m, nil
 }
 
+func (c Cache) postScrapeStudio(ctx context.Context, s models.ScrapedStudio) (ScrapedContent, error) {
+	if s.Parent != nil {
+		r := c.repository
+		if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+			return match.ScrapedStudio(ctx, r.StudioFinder, s.Parent, nil)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// post-process - set the image if applicable
+	if err := setStudioImage(ctx, c.client, &s, c.globalConfig); err != nil {
+		logger.Warnf("could not set image using URL %s: %v", *s.Image, err)
+	}
+
+	return s, nil
+}
+
 func (c Cache) postScrapeScenePerformer(ctx context.Context, p models.ScrapedPerformer) error {
 	tqb := c.repository.TagFinder
 
@@ -19,6 +19,7 @@ type ScrapedScene struct {
 	Tags         []*models.ScrapedTag          `json:"tags"`
 	Performers   []*models.ScrapedPerformer    `json:"performers"`
 	Movies       []*models.ScrapedMovie        `json:"movies"`
+	Markers      []*models.ScrapedSceneMarker  `json:"markers"`
 	RemoteSiteID *string                       `json:"remote_site_id"`
 	Duration     *int                          `json:"duration"`
 	Fingerprints []*models.StashBoxFingerprint `json:"fingerprints"`
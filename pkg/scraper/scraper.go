@@ -33,6 +33,7 @@ const (
 	ScrapeContentTypeMovie     ScrapeContentType = "MOVIE"
 	ScrapeContentTypePerformer ScrapeContentType = "PERFORMER"
 	ScrapeContentTypeScene     ScrapeContentType = "SCENE"
+	ScrapeContentTypeStudio    ScrapeContentType = "STUDIO"
 )
 
 var AllScrapeContentType = []ScrapeContentType{
@@ -40,11 +41,12 @@ var AllScrapeContentType = []ScrapeContentType{
 	ScrapeContentTypeMovie,
 	ScrapeContentTypePerformer,
 	ScrapeContentTypeScene,
+	ScrapeContentTypeStudio,
 }
 
 func (e ScrapeContentType) IsValid() bool {
 	switch e {
-	case ScrapeContentTypeGallery, ScrapeContentTypeMovie, ScrapeContentTypePerformer, ScrapeContentTypeScene:
+	case ScrapeContentTypeGallery, ScrapeContentTypeMovie, ScrapeContentTypePerformer, ScrapeContentTypeScene, ScrapeContentTypeStudio:
 		return true
 	}
 	return false
@@ -82,6 +84,10 @@ type Scraper struct {
 	Gallery *ScraperSpec `json:"gallery"`
 	// Details for movie scraper
 	Movie *ScraperSpec `json:"movie"`
+	// Details for studio scraper
+	Studio *ScraperSpec `json:"studio"`
+	// User-configurable settings
+	Settings []ScraperSetting `json:"settings"`
 }
 
 type ScraperSpec struct {
@@ -99,17 +105,20 @@ const (
 	ScrapeTypeFragment ScrapeType = "FRAGMENT"
 	// From URL
 	ScrapeTypeURL ScrapeType = "URL"
+	// From file fingerprints
+	ScrapeTypeFingerprint ScrapeType = "FINGERPRINT"
 )
 
 var AllScrapeType = []ScrapeType{
 	ScrapeTypeName,
 	ScrapeTypeFragment,
 	ScrapeTypeURL,
+	ScrapeTypeFingerprint,
 }
 
 func (e ScrapeType) IsValid() bool {
 	switch e {
-	case ScrapeTypeName, ScrapeTypeFragment, ScrapeTypeURL:
+	case ScrapeTypeName, ScrapeTypeFragment, ScrapeTypeURL, ScrapeTypeFingerprint:
 		return true
 	}
 	return false
@@ -220,3 +229,11 @@ type galleryScraper interface {
 
 	viaGallery(ctx context.Context, client *http.Client, gallery *models.Gallery) (*ScrapedGallery, error)
 }
+
+// fingerprintScraper is a scraper which supports scene scrapes using the
+// fingerprints of the scene files
+type fingerprintScraper interface {
+	scraper
+
+	viaFingerprints(ctx context.Context, client *http.Client, scenes []*models.Scene) ([][]*ScrapedScene, error)
+}
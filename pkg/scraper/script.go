@@ -61,6 +61,8 @@ func (s *scriptScraper) runScraperScript(ctx context.Context, inString string, o
 		return err
 	}
 
+	inString = s.config.scriptInput(inString)
+
 	go func() {
 		defer stdin.Close()
 
@@ -147,6 +149,15 @@ func (s *scriptScraper) scrapeByName(ctx context.Context, name string, ty Scrape
 				ret = append(ret, &v)
 			}
 		}
+	case ScrapeContentTypeStudio:
+		var studios []models.ScrapedStudio
+		err = s.runScraperScript(ctx, input, &studios)
+		if err == nil {
+			for _, s := range studios {
+				v := s
+				ret = append(ret, &v)
+			}
+		}
 	default:
 		return nil, ErrNotSupported
 	}
@@ -199,6 +210,10 @@ func (s *scriptScraper) scrape(ctx context.Context, input string, ty ScrapeConte
 		var movie *models.ScrapedMovie
 		err := s.runScraperScript(ctx, input, &movie)
 		return movie, err
+	case ScrapeContentTypeStudio:
+		var studio *models.ScrapedStudio
+		err := s.runScraperScript(ctx, input, &studio)
+		return studio, err
 	}
 
 	return nil, ErrNotSupported
@@ -218,6 +233,18 @@ func (s *scriptScraper) scrapeSceneByScene(ctx context.Context, scene *models.Sc
 	return ret, err
 }
 
+func (s *scriptScraper) scrapeScenesByFingerprints(ctx context.Context, scenes []sceneFingerprints) ([][]*ScrapedScene, error) {
+	inString, err := json.Marshal(scriptFingerprintsInput{Scenes: scenes})
+	if err != nil {
+		return nil, err
+	}
+
+	var ret [][]*ScrapedScene
+	err = s.runScraperScript(ctx, string(inString), &ret)
+
+	return ret, err
+}
+
 func (s *scriptScraper) scrapeGalleryByGallery(ctx context.Context, gallery *models.Gallery) (*ScrapedGallery, error) {
 	inString, err := json.Marshal(galleryToUpdateInput(gallery))
 
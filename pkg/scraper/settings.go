@@ -0,0 +1,131 @@
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/plugin"
+)
+
+// settingPlaceholderRE matches {setting.<name>} placeholders.
+var settingPlaceholderRE = regexp.MustCompile(`\{setting\.([^}]+)\}`)
+
+type settingConfig struct {
+	// defaults to string
+	Type plugin.PluginSettingTypeEnum `yaml:"type"`
+	// defaults to key name
+	DisplayName string `yaml:"displayName"`
+	Description string `yaml:"description"`
+}
+
+type ScraperSetting struct {
+	Name        string                       `json:"name"`
+	DisplayName string                       `json:"display_name"`
+	Description string                       `json:"description"`
+	Type        plugin.PluginSettingTypeEnum `json:"type"`
+}
+
+func (c config) getSettings() []ScraperSetting {
+	var keys []string
+	for k := range c.Settings {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	var ret []ScraperSetting
+	for _, k := range keys {
+		o := c.Settings[k]
+		t := o.Type
+		if t == "" {
+			t = plugin.PluginSettingTypeEnumString
+		}
+
+		ret = append(ret, ScraperSetting{
+			Name:        k,
+			DisplayName: o.DisplayName,
+			Description: o.Description,
+			Type:        t,
+		})
+	}
+
+	return ret
+}
+
+// expandSettings replaces {setting.<name>} placeholders in s with the
+// configured values of the scraper settings. Placeholders of settings
+// without a value are replaced with an empty string.
+func (c config) expandSettings(s string) string {
+	return settingPlaceholderRE.ReplaceAllStringFunc(s, func(m string) string {
+		return c.settingValue(settingPlaceholderRE.FindStringSubmatch(m)[1])
+	})
+}
+
+// expandURLSettings replaces {setting.<name>} placeholders in the URL
+// template u with the escaped values of the scraper settings. Values are
+// query escaped after the start of the query string, and path escaped
+// before it.
+//
+// It must only be used on URL templates from the scraper configuration.
+// Settings are never expanded in URLs provided by the user, so that they
+// cannot be sent to another host.
+func (c config) expandURLSettings(u string) string {
+	queryStart := strings.Index(u, "?")
+
+	var sb strings.Builder
+	last := 0
+	for _, m := range settingPlaceholderRE.FindAllStringSubmatchIndex(u, -1) {
+		sb.WriteString(u[last:m[0]])
+
+		v := c.settingValue(u[m[2]:m[3]])
+		if queryStart != -1 && m[0] > queryStart {
+			sb.WriteString(url.QueryEscape(v))
+		} else {
+			sb.WriteString(url.PathEscape(v))
+		}
+
+		last = m[1]
+	}
+	sb.WriteString(u[last:])
+
+	return sb.String()
+}
+
+func (c config) settingValue(name string) string {
+	v, found := c.settings[name]
+	if !found || v == nil {
+		logger.Debugf("[scraper] %s: setting %s is not set", c.ID, name)
+		return ""
+	}
+
+	return fmt.Sprint(v)
+}
+
+// scriptInput adds the scraper settings to the JSON object passed to a
+// script scraper, under the settings key.
+func (c config) scriptInput(input string) string {
+	if len(c.settings) == 0 {
+		return input
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(input), &obj); err != nil {
+		logger.Warnf("[scraper] %s: could not add settings to script input: %v", c.ID, err)
+		return input
+	}
+
+	obj["settings"] = c.settings
+
+	ret, err := json.Marshal(obj)
+	if err != nil {
+		logger.Warnf("[scraper] %s: could not add settings to script input: %v", c.ID, err)
+		return input
+	}
+
+	return string(ret)
+}
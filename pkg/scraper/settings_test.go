@@ -0,0 +1,85 @@
+package scraper
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandSettings(t *testing.T) {
+	c := config{
+		settings: map[string]interface{}{
+			"apiKey": "abc123",
+			"limit":  10,
+		},
+	}
+
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"no placeholder", "https://example.com/api", "https://example.com/api"},
+		{"string", "https://example.com/api?key={setting.apiKey}", "https://example.com/api?key=abc123"},
+		{"multiple", "{setting.apiKey}/{setting.limit}", "abc123/10"},
+		{"unset", "key={setting.missing}", "key="},
+		{"name placeholder untouched", "https://example.com/search?q={}&key={setting.apiKey}", "https://example.com/search?q={}&key=abc123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, c.expandSettings(tt.s))
+		})
+	}
+}
+
+func TestExpandURLSettings(t *testing.T) {
+	c := config{
+		settings: map[string]interface{}{
+			"apiKey": "a&b=c",
+			"path":   "x/y?z",
+		},
+	}
+
+	tests := []struct {
+		name string
+		u    string
+		want string
+	}{
+		{"no placeholder", "https://example.com/api", "https://example.com/api"},
+		{"query", "https://example.com/api?q={}&key={setting.apiKey}", "https://example.com/api?q={}&key=a%26b%3Dc"},
+		{"path", "https://example.com/{setting.path}/search", "https://example.com/x%2Fy%3Fz/search"},
+		{"path and query", "https://example.com/{setting.path}?key={setting.apiKey}", "https://example.com/x%2Fy%3Fz?key=a%26b%3Dc"},
+		{"unset", "https://example.com/?key={setting.missing}", "https://example.com/?key="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, c.expandURLSettings(tt.u))
+		})
+	}
+}
+
+func TestScriptInput(t *testing.T) {
+	c := config{}
+	input := `{"name": "test"}`
+
+	assert.Equal(t, input, c.scriptInput(input))
+
+	c.settings = map[string]interface{}{
+		"apiKey": "abc123",
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(c.scriptInput(input)), &got); err != nil {
+		t.Fatalf("invalid script input: %v", err)
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"name": "test",
+		"settings": map[string]interface{}{
+			"apiKey": "abc123",
+		},
+	}, got)
+}
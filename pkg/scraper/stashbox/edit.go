@@ -0,0 +1,447 @@
+package stashbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper/stashbox/graphql"
+	"github.com/stashapp/stash/pkg/sliceutil"
+)
+
+var (
+	// ErrNotLinked is returned when submitting an edit for an entity that
+	// is not linked to the stash-box endpoint.
+	ErrNotLinked = errors.New("not linked to stash-box endpoint")
+	// ErrAlreadyLinked is returned when submitting a draft for an entity
+	// that is already linked to the stash-box endpoint.
+	ErrAlreadyLinked = errors.New("already linked to stash-box endpoint")
+	// ErrNoEditChanges is returned when submitting an edit for an entity
+	// that does not differ from the upstream entity.
+	ErrNoEditChanges = errors.New("no differences from stash-box to submit")
+)
+
+const sceneEditDocument = `mutation SceneEdit ($input: SceneEditInput!) {
+	sceneEdit(input: $input) {
+		id
+	}
+}
+`
+
+const performerEditDocument = `mutation PerformerEdit ($input: PerformerEditInput!) {
+	performerEdit(input: $input) {
+		id
+	}
+}
+`
+
+const studioEditDocument = `mutation StudioEdit ($input: StudioEditInput!) {
+	studioEdit(input: $input) {
+		id
+	}
+}
+`
+
+func newEditInput(operation graphql.OperationEnum, id string, comment *string) *graphql.EditInput {
+	ret := &graphql.EditInput{
+		Operation: operation,
+	}
+
+	if id != "" {
+		ret.ID = &id
+	}
+	if comment != nil && *comment != "" {
+		ret.Comment = comment
+	}
+
+	return ret
+}
+
+// submitEdit submits an edit using the provided mutation document and
+// returns the id of the created edit.
+func (c Client) submitEdit(ctx context.Context, operation string, document string, field string, input interface{}) (*string, error) {
+	vars := map[string]interface{}{
+		"input": input,
+	}
+
+	type editResult struct {
+		ID string `graphql:"id"`
+	}
+	var res struct {
+		SceneEdit     *editResult `graphql:"sceneEdit"`
+		PerformerEdit *editResult `graphql:"performerEdit"`
+		StudioEdit    *editResult `graphql:"studioEdit"`
+	}
+	if err := c.client.Client.Post(ctx, operation, document, &res, vars); err != nil {
+		return nil, err
+	}
+
+	var edit *editResult
+	switch field {
+	case "sceneEdit":
+		edit = res.SceneEdit
+	case "performerEdit":
+		edit = res.PerformerEdit
+	case "studioEdit":
+		edit = res.StudioEdit
+	}
+	if edit == nil {
+		return nil, fmt.Errorf("no edit returned from %s", field)
+	}
+
+	return &edit.ID, nil
+}
+
+// SubmitSceneEdit submits an edit that updates the linked stash-box scene
+// with the local scene values that differ from the upstream values. Local
+// values that are empty are not submitted. Returns the id of the edit.
+func (c Client) SubmitSceneEdit(ctx context.Context, scene *models.Scene, endpoint string, comment *string) (*string, error) {
+	r := c.repository
+
+	if err := scene.LoadStashIDs(ctx, r.Scene); err != nil {
+		return nil, err
+	}
+
+	remoteID := remoteIDFor(scene.StashIDs.List(), endpoint)
+	if remoteID == "" {
+		return nil, fmt.Errorf("scene %d is %w %s", scene.ID, ErrNotLinked, endpoint)
+	}
+
+	upstream, err := c.client.FindSceneByID(ctx, remoteID)
+	if err != nil {
+		return nil, err
+	}
+	if upstream.FindScene == nil {
+		return nil, fmt.Errorf("scene %s not found in stash-box", remoteID)
+	}
+
+	var studioID *string
+	if scene.StudioID != nil {
+		stashIDs, err := r.Studio.GetStashIDs(ctx, *scene.StudioID)
+		if err != nil {
+			return nil, err
+		}
+		if id := remoteIDFor(stashIDs, endpoint); id != "" {
+			studioID = &id
+		}
+	}
+
+	performers, err := r.Performer.FindBySceneID(ctx, scene.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// performers are only submitted if all of them are linked, otherwise
+	// the unlinked performers would be removed from the scene
+	performerIDs := []string{}
+	for _, p := range performers {
+		stashIDs, err := r.Performer.GetStashIDs(ctx, p.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		id := remoteIDFor(stashIDs, endpoint)
+		if id == "" {
+			performerIDs = nil
+			break
+		}
+		performerIDs = append(performerIDs, id)
+	}
+
+	details, changed := getSceneEditDetails(scene, studioID, performerIDs, upstream.FindScene)
+	if !changed {
+		return nil, ErrNoEditChanges
+	}
+
+	input := graphql.SceneEditInput{
+		Edit:    newEditInput(graphql.OperationEnumModify, remoteID, comment),
+		Details: details,
+	}
+
+	return c.submitEdit(ctx, "SceneEdit", sceneEditDocument, "sceneEdit", input)
+}
+
+// editString returns the local value if it is set and differs from the
+// upstream value.
+func editString(local string, upstream *string) *string {
+	if local == "" || (upstream != nil && *upstream == local) {
+		return nil
+	}
+	return &local
+}
+
+func getSceneEditDetails(scene *models.Scene, studioID *string, performerIDs []string, upstream *graphql.SceneFragment) (*graphql.SceneEditDetailsInput, bool) {
+	ret := &graphql.SceneEditDetailsInput{
+		Title:    editString(scene.Title, upstream.Title),
+		Code:     editString(scene.Code, upstream.Code),
+		Details:  editString(scene.Details, upstream.Details),
+		Director: editString(scene.Director, upstream.Director),
+	}
+	changed := ret.Title != nil || ret.Code != nil || ret.Details != nil || ret.Director != nil
+
+	if scene.Date != nil {
+		ret.Date = editString(scene.Date.String(), upstream.Date)
+		changed = changed || ret.Date != nil
+	}
+
+	if studioID != nil && (upstream.Studio == nil || upstream.Studio.ID != *studioID) {
+		ret.StudioID = studioID
+		changed = true
+	}
+
+	if len(performerIDs) > 0 {
+		// keep the performer aliases of existing appearances
+		as := make(map[string]*string)
+		var upstreamIDs []string
+		for _, p := range upstream.Performers {
+			as[p.Performer.ID] = p.As
+			upstreamIDs = append(upstreamIDs, p.Performer.ID)
+		}
+
+		if !sliceutil.SliceSame(performerIDs, upstreamIDs) {
+			for _, id := range performerIDs {
+				ret.Performers = append(ret.Performers, &graphql.PerformerAppearanceInput{
+					PerformerID: id,
+					As:          as[id],
+				})
+			}
+			changed = true
+		}
+	}
+
+	return ret, changed
+}
+
+// SubmitPerformerEdit submits an edit that updates the linked stash-box
+// performer with the local performer values that differ from the upstream
+// values. Local values that are empty are not submitted. Returns the id of
+// the edit.
+func (c Client) SubmitPerformerEdit(ctx context.Context, performer *models.Performer, endpoint string, comment *string) (*string, error) {
+	pqb := c.repository.Performer
+
+	if err := performer.LoadAliases(ctx, pqb); err != nil {
+		return nil, err
+	}
+	if err := performer.LoadStashIDs(ctx, pqb); err != nil {
+		return nil, err
+	}
+
+	remoteID := remoteIDFor(performer.StashIDs.List(), endpoint)
+	if remoteID == "" {
+		return nil, fmt.Errorf("performer %d is %w %s", performer.ID, ErrNotLinked, endpoint)
+	}
+
+	upstream, err := c.client.FindPerformerByID(ctx, remoteID)
+	if err != nil {
+		return nil, err
+	}
+	if upstream.FindPerformer == nil {
+		return nil, fmt.Errorf("performer %s not found in stash-box", remoteID)
+	}
+
+	details, changed := getPerformerEditDetails(performer, *upstream.FindPerformer)
+	if !changed {
+		return nil, ErrNoEditChanges
+	}
+
+	input := graphql.PerformerEditInput{
+		Edit:    newEditInput(graphql.OperationEnumModify, upstream.FindPerformer.ID, comment),
+		Details: details,
+	}
+
+	return c.submitEdit(ctx, "PerformerEdit", performerEditDocument, "performerEdit", input)
+}
+
+// editEnum converts a local enum-like value to a stash-box enum value.
+// Returns nil if the value is not valid.
+func editEnum[T interface {
+	~string
+	IsValid() bool
+}](v string) *T {
+	ret := T(strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(v), " ", "_")))
+	if !ret.IsValid() {
+		return nil
+	}
+	return &ret
+}
+
+// editEnumValue returns the local enum value if it is valid and differs
+// from the upstream value.
+func editEnumValue[T interface {
+	~string
+	IsValid() bool
+}](local string, upstream *T) *T {
+	v := editEnum[T](local)
+	if v == nil || (upstream != nil && *upstream == *v) {
+		return nil
+	}
+	return v
+}
+
+func editInt(local *int, upstream *int) *int {
+	if local == nil || (upstream != nil && *upstream == *local) {
+		return nil
+	}
+	return local
+}
+
+func getPerformerEditDetails(performer *models.Performer, upstream graphql.PerformerFragment) (*graphql.PerformerEditDetailsInput, bool) {
+	ret := &graphql.PerformerEditDetailsInput{
+		Name:           editString(performer.Name, &upstream.Name),
+		Disambiguation: editString(performer.Disambiguation, upstream.Disambiguation),
+		Country:        editString(performer.Country, upstream.Country),
+		Ethnicity:      editEnumValue(performer.Ethnicity, upstream.Ethnicity),
+		EyeColor:       editEnumValue(performer.EyeColor, upstream.EyeColor),
+		HairColor:      editEnumValue(performer.HairColor, upstream.HairColor),
+		BreastType:     editEnumValue(performer.FakeTits, upstream.BreastType),
+		Height:         editInt(performer.Height, upstream.Height),
+	}
+	changed := ret.Name != nil || ret.Disambiguation != nil || ret.Country != nil ||
+		ret.Ethnicity != nil || ret.EyeColor != nil || ret.HairColor != nil ||
+		ret.BreastType != nil || ret.Height != nil
+
+	if performer.Gender != nil {
+		ret.Gender = editEnumValue(performer.Gender.String(), upstream.Gender)
+		changed = changed || ret.Gender != nil
+	}
+
+	if performer.Birthdate != nil {
+		var upstreamBirthdate *string
+		if upstream.Birthdate != nil {
+			upstreamBirthdate = &upstream.Birthdate.Date
+		}
+		ret.Birthdate = editString(performer.Birthdate.String(), upstreamBirthdate)
+		changed = changed || ret.Birthdate != nil
+	}
+
+	if performer.CareerLength != "" {
+		start, end := parseCareerLength(performer.CareerLength)
+		ret.CareerStartYear = editInt(start, upstream.CareerStartYear)
+		ret.CareerEndYear = editInt(end, upstream.CareerEndYear)
+		changed = changed || ret.CareerStartYear != nil || ret.CareerEndYear != nil
+	}
+
+	aliases := performer.Aliases.List()
+	upstreamAliases := sliceutil.Filter(upstream.Aliases, func(s string) bool {
+		return !strings.EqualFold(s, upstream.Name)
+	})
+	if len(aliases) > 0 && !sliceutil.SliceSame(aliases, upstreamAliases) {
+		ret.Aliases = aliases
+		changed = true
+	}
+
+	return ret, changed
+}
+
+// SubmitStudioEdit submits an edit that updates the linked stash-box studio
+// with the local studio name and parent studio if they differ from the
+// upstream values. Returns the id of the edit.
+func (c Client) SubmitStudioEdit(ctx context.Context, studio *models.Studio, endpoint string, comment *string) (*string, error) {
+	sqb := c.repository.Studio
+
+	if err := studio.LoadStashIDs(ctx, sqb); err != nil {
+		return nil, err
+	}
+
+	remoteID := remoteIDFor(studio.StashIDs.List(), endpoint)
+	if remoteID == "" {
+		return nil, fmt.Errorf("studio %d is %w %s", studio.ID, ErrNotLinked, endpoint)
+	}
+
+	upstream, err := c.client.FindStudio(ctx, &remoteID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if upstream.FindStudio == nil {
+		return nil, fmt.Errorf("studio %s not found in stash-box", remoteID)
+	}
+
+	parentID, err := c.getParentStudioID(ctx, studio, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	details, changed := getStudioEditDetails(studio, parentID, *upstream.FindStudio)
+	if !changed {
+		return nil, ErrNoEditChanges
+	}
+
+	input := graphql.StudioEditInput{
+		Edit:    newEditInput(graphql.OperationEnumModify, upstream.FindStudio.ID, comment),
+		Details: details,
+	}
+
+	return c.submitEdit(ctx, "StudioEdit", studioEditDocument, "studioEdit", input)
+}
+
+// getParentStudioID returns the stash id of the parent studio of the
+// studio, or nil if there is no parent or the parent is not linked.
+func (c Client) getParentStudioID(ctx context.Context, studio *models.Studio, endpoint string) (*string, error) {
+	if studio.ParentID == nil {
+		return nil, nil
+	}
+
+	stashIDs, err := c.repository.Studio.GetStashIDs(ctx, *studio.ParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if id := remoteIDFor(stashIDs, endpoint); id != "" {
+		return &id, nil
+	}
+
+	return nil, nil
+}
+
+func getStudioEditDetails(studio *models.Studio, parentID *string, upstream graphql.StudioFragment) (*graphql.StudioEditDetailsInput, bool) {
+	ret := &graphql.StudioEditDetailsInput{
+		Name: editString(studio.Name, &upstream.Name),
+	}
+	changed := ret.Name != nil
+
+	if parentID != nil && (upstream.Parent == nil || upstream.Parent.ID != *parentID) {
+		ret.ParentID = parentID
+		changed = true
+	}
+
+	return ret, changed
+}
+
+// SubmitStudioDraft submits an edit that creates a new stash-box studio
+// from the local studio. stash-box requires a site for each studio URL, so
+// the studio URL is added to the edit note instead. The parent studio is
+// only submitted if it is linked. Returns the id of the edit.
+func (c Client) SubmitStudioDraft(ctx context.Context, studio *models.Studio, endpoint string) (*string, error) {
+	sqb := c.repository.Studio
+
+	if err := studio.LoadStashIDs(ctx, sqb); err != nil {
+		return nil, err
+	}
+
+	if remoteID := remoteIDFor(studio.StashIDs.List(), endpoint); remoteID != "" {
+		return nil, fmt.Errorf("studio %d is %w %s", studio.ID, ErrAlreadyLinked, endpoint)
+	}
+
+	parentID, err := c.getParentStudioID(ctx, studio, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var comment *string
+	if studio.URL != "" {
+		v := "URL: " + studio.URL
+		comment = &v
+	}
+
+	input := graphql.StudioEditInput{
+		Edit: newEditInput(graphql.OperationEnumCreate, "", comment),
+		Details: &graphql.StudioEditDetailsInput{
+			Name:     &studio.Name,
+			ParentID: parentID,
+		},
+	}
+
+	return c.submitEdit(ctx, "StudioEdit", studioEditDocument, "studioEdit", input)
+}
@@ -0,0 +1,222 @@
+package stashbox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stashapp/stash/pkg/scraper/stashbox/graphql"
+)
+
+func Test_getSceneEditDetails(t *testing.T) {
+	var (
+		title         = "title"
+		upstreamTitle = "upstreamTitle"
+		date          = "2000-01-01"
+		studioID      = "studio"
+		as            = "as"
+	)
+
+	d, _ := models.ParseDate(date)
+	scene := &models.Scene{
+		Title: title,
+		Date:  &d,
+	}
+
+	upstream := &graphql.SceneFragment{
+		Title: &upstreamTitle,
+		Date:  &date,
+		Studio: &graphql.StudioFragment{
+			ID: studioID,
+		},
+		Performers: []*graphql.PerformerAppearanceFragment{
+			{As: &as, Performer: graphql.PerformerFragment{ID: "p1"}},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		studioID     *string
+		performerIDs []string
+		want         *graphql.SceneEditDetailsInput
+	}{
+		{
+			"title only",
+			&studioID,
+			[]string{"p1"},
+			&graphql.SceneEditDetailsInput{
+				Title: &title,
+			},
+		},
+		{
+			"performers",
+			nil,
+			[]string{"p2", "p1"},
+			&graphql.SceneEditDetailsInput{
+				Title: &title,
+				Performers: []*graphql.PerformerAppearanceInput{
+					{PerformerID: "p2"},
+					{PerformerID: "p1", As: &as},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := getSceneEditDetails(scene, tt.studioID, tt.performerIDs, upstream)
+			assert.True(t, changed)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, changed := getSceneEditDetails(&models.Scene{Title: upstreamTitle}, &studioID, nil, upstream)
+	assert.False(t, changed, "unchanged scene")
+}
+
+func Test_getPerformerEditDetails(t *testing.T) {
+	var (
+		name        = "name"
+		height      = 160
+		otherHeight = 150
+		startYear   = 2010
+		ethnicity   = graphql.EthnicityEnumCaucasian
+		gender      = models.GenderEnumFemale
+		female      = graphql.GenderEnumFemale
+		blonde      = graphql.HairColorEnumBlonde
+		birthdate   = "2000-01-01"
+	)
+
+	bd, _ := models.ParseDate(birthdate)
+
+	upstream := graphql.PerformerFragment{
+		Name:            name,
+		Aliases:         []string{"alias1", "NAME"},
+		Gender:          &female,
+		Ethnicity:       &ethnicity,
+		Height:          &otherHeight,
+		CareerStartYear: &startYear,
+		Birthdate:       &graphql.FuzzyDateFragment{Date: birthdate},
+	}
+
+	tests := []struct {
+		name      string
+		performer *models.Performer
+		want      *graphql.PerformerEditDetailsInput
+		changed   bool
+	}{
+		{
+			"unchanged",
+			&models.Performer{
+				Name:         name,
+				Gender:       &gender,
+				Ethnicity:    "Caucasian",
+				Height:       &otherHeight,
+				Birthdate:    &bd,
+				CareerLength: "2010 -",
+				Aliases:      models.NewRelatedStrings([]string{"alias1"}),
+			},
+			&graphql.PerformerEditDetailsInput{},
+			false,
+		},
+		{
+			"changed",
+			&models.Performer{
+				Name:         name,
+				HairColor:    "Blonde",
+				EyeColor:     "invalid",
+				Height:       &height,
+				CareerLength: "2010 - 2015",
+				Aliases:      models.NewRelatedStrings([]string{"alias1", "alias2"}),
+			},
+			&graphql.PerformerEditDetailsInput{
+				HairColor:     &blonde,
+				Height:        &height,
+				CareerEndYear: intPtr(2015),
+				Aliases:       []string{"alias1", "alias2"},
+			},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := getPerformerEditDetails(tt.performer, upstream)
+			assert.Equal(t, tt.changed, changed)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestClient_SubmitStudioDraft(t *testing.T) {
+	const (
+		studioID = iota + 1
+		parentID
+		linkedID
+	)
+
+	var request struct {
+		Query     string `json:"query"`
+		Variables struct {
+			Input graphql.StudioEditInput `json:"input"`
+		} `json:"variables"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+
+		_, _ = w.Write([]byte(`{"data": {"studioEdit": {"id": "edit"}}}`))
+	}))
+	defer server.Close()
+
+	db := mocks.NewDatabase()
+	db.Studio.On("GetStashIDs", mock.Anything, studioID).Return(nil, nil)
+	db.Studio.On("GetStashIDs", mock.Anything, parentID).Return([]models.StashID{
+		{Endpoint: server.URL, StashID: "parent"},
+	}, nil)
+	db.Studio.On("GetStashIDs", mock.Anything, linkedID).Return([]models.StashID{
+		{Endpoint: server.URL, StashID: "linked"},
+	}, nil)
+
+	c := NewClient(models.StashBox{Endpoint: server.URL}, Repository{
+		TxnManager: db,
+		Studio:     db.Studio,
+	})
+
+	parent := parentID
+	studio := &models.Studio{
+		ID:       studioID,
+		Name:     "studio",
+		URL:      "https://studio.com",
+		ParentID: &parent,
+	}
+
+	got, err := c.SubmitStudioDraft(context.Background(), studio, server.URL)
+	if err != nil {
+		t.Fatalf("SubmitStudioDraft() error = %v", err)
+	}
+
+	assert.Equal(t, "edit", *got)
+	assert.Contains(t, request.Query, "studioEdit(input: $input)")
+
+	input := request.Variables.Input
+	assert.Equal(t, graphql.OperationEnumCreate, input.Edit.Operation)
+	assert.Nil(t, input.Edit.ID)
+	assert.Equal(t, "URL: https://studio.com", *input.Edit.Comment)
+	assert.Equal(t, "studio", *input.Details.Name)
+	assert.Equal(t, "parent", *input.Details.ParentID)
+
+	_, err = c.SubmitStudioDraft(context.Background(), &models.Studio{ID: linkedID}, server.URL)
+	assert.ErrorIs(t, err, ErrAlreadyLinked)
+}
@@ -0,0 +1,258 @@
+package stashbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper/stashbox/graphql"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+const (
+	// fingerprintQueueBatchSize is the number of queued scenes submitted
+	// between updates of the queue.
+	fingerprintQueueBatchSize = 25
+
+	// MaxFingerprintSubmissionAttempts is the number of queue runs in which
+	// the submission of a scene may fail before it is marked as failed.
+	MaxFingerprintSubmissionAttempts = 5
+
+	// fingerprintSubmitRetries is the number of times the submission of a
+	// single fingerprint is retried within a queue run.
+	fingerprintSubmitRetries = 2
+
+	// DefaultFingerprintRetryDelay is the default delay before the first
+	// retry of a failed fingerprint submission.
+	DefaultFingerprintRetryDelay = 5 * time.Second
+)
+
+var (
+	ErrNoFingerprints          = errors.New("scene has no fingerprints to submit")
+	errFingerprintNotSubmitted = errors.New("fingerprint was not accepted")
+)
+
+// FingerprintQueueRepository provides the stores used when submitting queued
+// fingerprints.
+type FingerprintQueueRepository struct {
+	TxnManager models.TxnManager
+
+	Scene                 SceneReader
+	FingerprintSubmission models.FingerprintSubmissionReaderWriter
+}
+
+func NewFingerprintQueueRepository(repo models.Repository) FingerprintQueueRepository {
+	return FingerprintQueueRepository{
+		TxnManager:            repo.TxnManager,
+		Scene:                 repo.Scene,
+		FingerprintSubmission: repo.FingerprintSubmission,
+	}
+}
+
+// FingerprintQueueResult is the number of queued scenes in each state after a
+// queue run.
+type FingerprintQueueResult struct {
+	Submitted int `json:"submitted"`
+	// Scenes that failed, and will be retried in the next queue run.
+	Pending int `json:"pending"`
+	Failed  int `json:"failed"`
+}
+
+// FingerprintQueue submits the queued fingerprint submissions for the
+// stash-box endpoint of the client.
+//
+// Each fingerprint submission is retried a few times. If the fingerprints of
+// a scene still could not be submitted, the submission stays queued until it
+// has failed MaxFingerprintSubmissionAttempts times.
+type FingerprintQueue struct {
+	Client     *Client
+	Repository FingerprintQueueRepository
+
+	// Queue the scenes linked to the endpoint that were never queued before
+	// submitting.
+	QueueUnsubmitted bool
+
+	// Delay before the first retry of a failed fingerprint submission.
+	RetryDelay time.Duration
+}
+
+func (q *FingerprintQueue) endpoint() string {
+	return q.Client.box.Endpoint
+}
+
+func (q *FingerprintQueue) Submit(ctx context.Context, progress *job.Progress) (*FingerprintQueueResult, error) {
+	r := q.Repository
+	endpoint := q.endpoint()
+
+	if q.QueueUnsubmitted {
+		if err := txn.WithTxn(ctx, r.TxnManager, func(ctx context.Context) error {
+			n, err := r.FingerprintSubmission.QueueUnsubmitted(ctx, endpoint)
+			if n > 0 {
+				logger.Infof("Queued fingerprints of %d linked scenes", n)
+			}
+			return err
+		}); err != nil {
+			return nil, fmt.Errorf("queueing unsubmitted scenes: %w", err)
+		}
+	}
+
+	var pending []*models.FingerprintSubmission
+	if err := txn.WithReadTxn(ctx, r.TxnManager, func(ctx context.Context) error {
+		var err error
+		pending, err = r.FingerprintSubmission.FindPending(ctx, endpoint)
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("finding queued submissions: %w", err)
+	}
+
+	progress.SetTotal(len(pending))
+
+	ret := &FingerprintQueueResult{}
+	for start := 0; start < len(pending); start += fingerprintQueueBatchSize {
+		if job.IsCancelled(ctx) {
+			return ret, nil
+		}
+
+		end := start + fingerprintQueueBatchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+
+		if err := q.submitBatch(ctx, pending[start:end], ret, progress); err != nil {
+			return ret, err
+		}
+	}
+
+	return ret, nil
+}
+
+func (q *FingerprintQueue) submitBatch(ctx context.Context, batch []*models.FingerprintSubmission, result *FingerprintQueueResult, progress *job.Progress) error {
+	r := q.Repository
+
+	fingerprints := make([][]graphql.FingerprintSubmission, len(batch))
+	if err := txn.WithReadTxn(ctx, r.TxnManager, func(ctx context.Context) error {
+		for i, s := range batch {
+			scene, err := r.Scene.Find(ctx, s.SceneID)
+			if err != nil {
+				return err
+			}
+
+			if scene == nil {
+				continue
+			}
+
+			if err := scene.LoadFiles(ctx, r.Scene); err != nil {
+				return err
+			}
+
+			fingerprints[i] = sceneFingerprints(scene, s.StashID)
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("loading scene fingerprints: %w", err)
+	}
+
+	for i, s := range batch {
+		var err error
+		progress.ExecuteTask(fmt.Sprintf("Submitting fingerprints of scene %d", s.SceneID), func() {
+			err = q.submitScene(ctx, fingerprints[i])
+		})
+
+		if job.IsCancelled(ctx) {
+			// leave the remaining submissions queued
+			batch = batch[:i]
+			break
+		}
+
+		now := time.Now()
+		s.LastAttemptAt = &now
+		s.UpdatedAt = now
+		applySubmissionResult(s, err)
+
+		switch s.Status {
+		case models.FingerprintSubmissionStatusSubmitted:
+			result.Submitted++
+		case models.FingerprintSubmissionStatusPending:
+			logger.Warnf("Error submitting fingerprints of scene %d, will retry: %v", s.SceneID, err)
+			result.Pending++
+		default:
+			logger.Errorf("Error submitting fingerprints of scene %d: %v", s.SceneID, err)
+			result.Failed++
+		}
+
+		progress.Increment()
+	}
+
+	return txn.WithTxn(ctx, r.TxnManager, func(ctx context.Context) error {
+		for _, s := range batch {
+			if err := r.FingerprintSubmission.Update(ctx, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// applySubmissionResult sets the status of a submission from the error of
+// an attempt to submit its fingerprints.
+func applySubmissionResult(s *models.FingerprintSubmission, err error) {
+	switch {
+	case err == nil:
+		s.Status = models.FingerprintSubmissionStatusSubmitted
+		s.Error = ""
+	case errors.Is(err, ErrNoFingerprints):
+		// retrying will not help until the scene is requeued
+		s.Status = models.FingerprintSubmissionStatusFailed
+		s.Error = err.Error()
+	default:
+		s.Attempts++
+		s.Error = err.Error()
+		s.Status = models.FingerprintSubmissionStatusPending
+		if s.Attempts >= MaxFingerprintSubmissionAttempts {
+			s.Status = models.FingerprintSubmissionStatusFailed
+		}
+	}
+}
+
+func (q *FingerprintQueue) submitScene(ctx context.Context, fingerprints []graphql.FingerprintSubmission) error {
+	if len(fingerprints) == 0 {
+		return ErrNoFingerprints
+	}
+
+	for _, fp := range fingerprints {
+		if err := q.submitFingerprint(ctx, fp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (q *FingerprintQueue) submitFingerprint(ctx context.Context, fp graphql.FingerprintSubmission) error {
+	delay := q.RetryDelay
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		var res *graphql.SubmitFingerprint
+		res, err = q.Client.client.SubmitFingerprint(ctx, fp)
+		if err == nil && !res.SubmitFingerprint {
+			err = errFingerprintNotSubmitted
+		}
+
+		if err == nil || attempt >= fingerprintSubmitRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+	}
+}
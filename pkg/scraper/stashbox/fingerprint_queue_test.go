@@ -0,0 +1,129 @@
+package stashbox
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper/stashbox/graphql"
+)
+
+func TestFingerprintQueue_submitScene(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []string
+		wantErr   bool
+		wantCalls int
+	}{
+		{
+			"submitted",
+			[]string{`{"data": {"submitFingerprint": true}}`},
+			false,
+			1,
+		},
+		{
+			"retried",
+			[]string{
+				`{"errors": [{"message": "unavailable"}]}`,
+				`{"data": {"submitFingerprint": false}}`,
+				`{"data": {"submitFingerprint": true}}`,
+			},
+			false,
+			3,
+		},
+		{
+			"failed",
+			[]string{
+				`{"errors": [{"message": "unavailable"}]}`,
+				`{"errors": [{"message": "unavailable"}]}`,
+				`{"errors": [{"message": "unavailable"}]}`,
+			},
+			true,
+			3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				resp := tt.responses[len(tt.responses)-1]
+				if calls < len(tt.responses) {
+					resp = tt.responses[calls]
+				}
+				calls++
+				_, _ = w.Write([]byte(resp))
+			}))
+			defer server.Close()
+
+			q := &FingerprintQueue{
+				Client: NewClient(models.StashBox{Endpoint: server.URL}, Repository{}),
+			}
+
+			err := q.submitScene(context.Background(), []graphql.FingerprintSubmission{
+				{SceneID: "scene", Fingerprint: &graphql.FingerprintInput{Hash: "hash", Algorithm: graphql.FingerprintAlgorithmMd5}},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("submitScene() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
+
+func TestFingerprintQueue_submitSceneNoFingerprints(t *testing.T) {
+	q := &FingerprintQueue{}
+	err := q.submitScene(context.Background(), nil)
+	assert.ErrorIs(t, err, ErrNoFingerprints)
+}
+
+func Test_applySubmissionResult(t *testing.T) {
+	submissionErr := errors.New("error")
+
+	tests := []struct {
+		name     string
+		attempts int
+		err      error
+		want     models.FingerprintSubmission
+	}{
+		{
+			"submitted",
+			2,
+			nil,
+			models.FingerprintSubmission{Status: models.FingerprintSubmissionStatusSubmitted, Attempts: 2},
+		},
+		{
+			"retry",
+			0,
+			submissionErr,
+			models.FingerprintSubmission{Status: models.FingerprintSubmissionStatusPending, Attempts: 1, Error: "error"},
+		},
+		{
+			"max attempts",
+			MaxFingerprintSubmissionAttempts - 1,
+			submissionErr,
+			models.FingerprintSubmission{Status: models.FingerprintSubmissionStatusFailed, Attempts: MaxFingerprintSubmissionAttempts, Error: "error"},
+		},
+		{
+			"no fingerprints",
+			0,
+			ErrNoFingerprints,
+			models.FingerprintSubmission{Status: models.FingerprintSubmissionStatusFailed, Error: ErrNoFingerprints.Error()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := models.FingerprintSubmission{
+				Status:   models.FingerprintSubmissionStatusPending,
+				Attempts: tt.attempts,
+				Error:    "previous",
+			}
+			applySubmissionResult(&s, tt.err)
+			assert.Equal(t, tt.want, s)
+		})
+	}
+}
@@ -290,47 +290,7 @@ func (c Client) SubmitStashBoxFingerprints(ctx context.Context, sceneIDs []strin
 			}
 
 			if sceneStashID != "" {
-				for _, f := range scene.Files.List() {
-					duration := f.Duration
-
-					if duration != 0 {
-						if checksum := f.Fingerprints.GetString(models.FingerprintTypeMD5); checksum != "" {
-							fingerprint := graphql.FingerprintInput{
-								Hash:      checksum,
-								Algorithm: graphql.FingerprintAlgorithmMd5,
-								Duration:  int(duration),
-							}
-							fingerprints = append(fingerprints, graphql.FingerprintSubmission{
-								SceneID:     sceneStashID,
-								Fingerprint: &fingerprint,
-							})
-						}
-
-						if oshash := f.Fingerprints.GetString(models.FingerprintTypeOshash); oshash != "" {
-							fingerprint := graphql.FingerprintInput{
-								Hash:      oshash,
-								Algorithm: graphql.FingerprintAlgorithmOshash,
-								Duration:  int(duration),
-							}
-							fingerprints = append(fingerprints, graphql.FingerprintSubmission{
-								SceneID:     sceneStashID,
-								Fingerprint: &fingerprint,
-							})
-						}
-
-						if phash := f.Fingerprints.GetInt64(models.FingerprintTypePhash); phash != 0 {
-							fingerprint := graphql.FingerprintInput{
-								Hash:      utils.PhashToString(phash),
-								Algorithm: graphql.FingerprintAlgorithmPhash,
-								Duration:  int(duration),
-							}
-							fingerprints = append(fingerprints, graphql.FingerprintSubmission{
-								SceneID:     sceneStashID,
-								Fingerprint: &fingerprint,
-							})
-						}
-					}
-				}
+				fingerprints = append(fingerprints, sceneFingerprints(scene, sceneStashID)...)
 			}
 		}
 
@@ -342,6 +302,55 @@ func (c Client) SubmitStashBoxFingerprints(ctx context.Context, sceneIDs []strin
 	return c.submitStashBoxFingerprints(ctx, fingerprints)
 }
 
+// sceneFingerprints returns the fingerprints of the files of a scene to
+// submit for the stash-box scene. The files of the scene must be loaded.
+func sceneFingerprints(scene *models.Scene, sceneStashID string) []graphql.FingerprintSubmission {
+	var fingerprints []graphql.FingerprintSubmission
+	for _, f := range scene.Files.List() {
+		duration := f.Duration
+
+		if duration != 0 {
+			if checksum := f.Fingerprints.GetString(models.FingerprintTypeMD5); checksum != "" {
+				fingerprint := graphql.FingerprintInput{
+					Hash:      checksum,
+					Algorithm: graphql.FingerprintAlgorithmMd5,
+					Duration:  int(duration),
+				}
+				fingerprints = append(fingerprints, graphql.FingerprintSubmission{
+					SceneID:     sceneStashID,
+					Fingerprint: &fingerprint,
+				})
+			}
+
+			if oshash := f.Fingerprints.GetString(models.FingerprintTypeOshash); oshash != "" {
+				fingerprint := graphql.FingerprintInput{
+					Hash:      oshash,
+					Algorithm: graphql.FingerprintAlgorithmOshash,
+					Duration:  int(duration),
+				}
+				fingerprints = append(fingerprints, graphql.FingerprintSubmission{
+					SceneID:     sceneStashID,
+					Fingerprint: &fingerprint,
+				})
+			}
+
+			if phash := f.Fingerprints.GetInt64(models.FingerprintTypePhash); phash != 0 {
+				fingerprint := graphql.FingerprintInput{
+					Hash:      utils.PhashToString(phash),
+					Algorithm: graphql.FingerprintAlgorithmPhash,
+					Duration:  int(duration),
+				}
+				fingerprints = append(fingerprints, graphql.FingerprintSubmission{
+					SceneID:     sceneStashID,
+					Fingerprint: &fingerprint,
+				})
+			}
+		}
+	}
+
+	return fingerprints
+}
+
 func (c Client) submitStashBoxFingerprints(ctx context.Context, fingerprints []graphql.FingerprintSubmission) (bool, error) {
 	for _, fingerprint := range fingerprints {
 		_, err := c.client.SubmitFingerprint(ctx, fingerprint)
@@ -567,6 +576,22 @@ func formatCareerLength(start, end *int) *string {
 	return &ret
 }
 
+// parseCareerLength parses a career length in the format returned by
+// formatCareerLength.
+func parseCareerLength(v string) (start *int, end *int) {
+	career := strings.Split(v, "-")
+	if i, err := strconv.Atoi(strings.TrimSpace(career[0])); err == nil {
+		start = &i
+	}
+	if len(career) == 2 {
+		if y, err := strconv.Atoi(strings.TrimSpace(career[1])); err == nil {
+			end = &y
+		}
+	}
+
+	return start, end
+}
+
 func formatBodyModifications(m []*graphql.BodyModificationFragment) *string {
 	if len(m) == 0 {
 		return nil
@@ -704,6 +729,10 @@ func studioFragmentToScrapedStudio(s graphql.StudioFragment) *models.ScrapedStud
 }
 
 func getFirstImage(ctx context.Context, client *http.Client, images []*graphql.ImageFragment) *string {
+	if scraper.KeepImageURLs(ctx) {
+		return &images[0].URL
+	}
+
 	ret, err := fetchImage(ctx, client, images[0].URL)
 	if err != nil && !errors.Is(err, context.Canceled) {
 		logger.Warnf("Error fetching image %s: %s", images[0].URL, err.Error())
@@ -1178,15 +1207,7 @@ func (c Client) SubmitPerformerDraft(ctx context.Context, performer *models.Perf
 		draft.Aliases = &aliases
 	}
 	if performer.CareerLength != "" {
-		var career = strings.Split(performer.CareerLength, "-")
-		if i, err := strconv.Atoi(strings.TrimSpace(career[0])); err == nil {
-			draft.CareerStartYear = &i
-		}
-		if len(career) == 2 {
-			if y, err := strconv.Atoi(strings.TrimSpace(career[1])); err == nil {
-				draft.CareerEndYear = &y
-			}
-		}
+		draft.CareerStartYear, draft.CareerEndYear = parseCareerLength(performer.CareerLength)
 	}
 
 	var urls []string
@@ -0,0 +1,770 @@
+package stashbox
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/performer"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/scraper/stashbox/graphql"
+	"github.com/stashapp/stash/pkg/sliceutil"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+	"github.com/stashapp/stash/pkg/studio"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+// SyncRepository provides the stores used when syncing linked entities.
+type SyncRepository struct {
+	TxnManager models.TxnManager
+
+	Scene     models.SceneReaderWriter
+	Performer models.PerformerReaderWriter
+	Studio    models.StudioReaderWriter
+
+	FingerprintSubmission models.FingerprintSubmissionUpdater
+}
+
+func NewSyncRepository(repo models.Repository) SyncRepository {
+	return SyncRepository{
+		TxnManager: repo.TxnManager,
+		Scene:      repo.Scene,
+		Performer:  repo.Performer,
+		Studio:     repo.Studio,
+
+		FingerprintSubmission: repo.FingerprintSubmission,
+	}
+}
+
+type SyncStatus string
+
+const (
+	// The entity is up to date.
+	SyncStatusUnchanged SyncStatus = "UNCHANGED"
+	// The entity was updated with the upstream data.
+	SyncStatusUpdated SyncStatus = "UPDATED"
+	// The upstream entity was deleted or could not be found.
+	// The entity is not changed.
+	SyncStatusDeleted SyncStatus = "DELETED"
+	// An error occurred syncing the entity.
+	SyncStatusError SyncStatus = "ERROR"
+)
+
+// SyncReport describes the changes made to an entity when syncing.
+type SyncReport struct {
+	Type     SyncType   `json:"type"`
+	ID       int        `json:"id"`
+	Name     string     `json:"name"`
+	RemoteID string     `json:"remote_id"`
+	Status   SyncStatus `json:"status"`
+	// Set if the upstream entity was merged into another entity. The stash
+	// id of the entity is remapped to this id.
+	MergedInto    string   `json:"merged_into,omitempty"`
+	ChangedFields []string `json:"changed_fields,omitempty"`
+	Error         string   `json:"error,omitempty"`
+}
+
+// SyncReporter receives the report of each synced entity.
+type SyncReporter interface {
+	ReportSync(report *SyncReport)
+}
+
+// Syncer refreshes the scenes, performers and studios linked to the
+// stash-box endpoint of the client with the current upstream data.
+//
+// Upstream entities are fetched in batches. Entities that were merged
+// upstream have their stash id remapped to the merge target. Entities that
+// were deleted upstream are reported, but not changed.
+//
+// Field values are never cleared if the upstream value is empty. Scene
+// performers, tags and images are not synced.
+type Syncer struct {
+	Client     *Client
+	Repository SyncRepository
+	Options    SyncOptions
+	Reporter   SyncReporter
+}
+
+// linkedEntity is a local entity linked to an upstream entity.
+type linkedEntity struct {
+	id       int
+	name     string
+	remoteID string
+}
+
+type upstreamEntity interface {
+	upstreamID() string
+	isDeleted() bool
+}
+
+func (s *syncScene) upstreamID() string     { return s.ID }
+func (s *syncScene) isDeleted() bool        { return s.Deleted }
+func (p *syncPerformer) upstreamID() string { return p.ID }
+func (p *syncPerformer) isDeleted() bool    { return p.Deleted }
+func (s *syncStudio) upstreamID() string    { return s.ID }
+func (s *syncStudio) isDeleted() bool       { return s.Deleted }
+
+func toUpstream[T any, PT interface {
+	*T
+	upstreamEntity
+}](m map[string]*T) map[string]upstreamEntity {
+	ret := make(map[string]upstreamEntity, len(m))
+	for k, v := range m {
+		// avoid storing typed nil values
+		if v != nil {
+			ret[k] = PT(v)
+		}
+	}
+	return ret
+}
+
+type syncTypeHandler struct {
+	ty    SyncType
+	find  func(ctx context.Context) ([]linkedEntity, error)
+	fetch func(ctx context.Context, ids []string) (map[string]upstreamEntity, error)
+	apply func(ctx context.Context, e linkedEntity, upstream upstreamEntity, report *SyncReport) error
+}
+
+func (s *Syncer) endpoint() string {
+	return s.Client.box.Endpoint
+}
+
+func (s *Syncer) handlers() []syncTypeHandler {
+	c := s.Client
+	return []syncTypeHandler{
+		{
+			ty:   SyncTypeStudio,
+			find: s.findLinkedStudios,
+			fetch: func(ctx context.Context, ids []string) (map[string]upstreamEntity, error) {
+				m, err := c.findStudiosByIDs(ctx, ids)
+				return toUpstream(m), err
+			},
+			apply: s.syncStudio,
+		},
+		{
+			ty:   SyncTypePerformer,
+			find: s.findLinkedPerformers,
+			fetch: func(ctx context.Context, ids []string) (map[string]upstreamEntity, error) {
+				m, err := c.findPerformersByIDs(ctx, ids)
+				return toUpstream(m), err
+			},
+			apply: s.syncPerformer,
+		},
+		{
+			ty:   SyncTypeScene,
+			find: s.findLinkedScenes,
+			fetch: func(ctx context.Context, ids []string) (map[string]upstreamEntity, error) {
+				m, err := c.findScenesByIDs(ctx, ids)
+				return toUpstream(m), err
+			},
+			apply: s.syncScene,
+		},
+	}
+}
+
+// Sync syncs all linked entities of the selected types. Studios are synced
+// first, so that scenes and studios can be linked to updated parent studios.
+func (s *Syncer) Sync(ctx context.Context, progress *job.Progress) error {
+	type toSync struct {
+		handler  syncTypeHandler
+		entities []linkedEntity
+	}
+
+	var all []toSync
+	total := 0
+	if err := txn.WithReadTxn(ctx, s.Repository.TxnManager, func(ctx context.Context) error {
+		for _, h := range s.handlers() {
+			if !s.Options.includesType(h.ty) {
+				continue
+			}
+
+			entities, err := h.find(ctx)
+			if err != nil {
+				return fmt.Errorf("finding linked %s entities: %w", h.ty, err)
+			}
+
+			all = append(all, toSync{handler: h, entities: entities})
+			total += len(entities)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	progress.SetTotal(total)
+
+	for _, v := range all {
+		for start := 0; start < len(v.entities); start += syncBatchSize {
+			if job.IsCancelled(ctx) {
+				return nil
+			}
+
+			end := start + syncBatchSize
+			if end > len(v.entities) {
+				end = len(v.entities)
+			}
+
+			s.syncBatch(ctx, v.handler, v.entities[start:end], progress)
+		}
+	}
+
+	return nil
+}
+
+func (s *Syncer) syncBatch(ctx context.Context, h syncTypeHandler, batch []linkedEntity, progress *job.Progress) {
+	ids := sliceutil.Map(batch, func(e linkedEntity) string { return e.remoteID })
+
+	var upstream map[string]upstreamEntity
+	var fetchErr error
+	progress.ExecuteTask(fmt.Sprintf("Fetching %d %s entities from stash-box", len(batch), h.ty), func() {
+		upstream, fetchErr = h.fetch(ctx, sliceutil.Unique(ids))
+	})
+
+	if fetchErr != nil {
+		logger.Errorf("Error fetching %s entities from stash-box: %v", h.ty, fetchErr)
+	}
+
+	for _, e := range batch {
+		report := &SyncReport{
+			Type:     h.ty,
+			ID:       e.id,
+			Name:     e.name,
+			RemoteID: e.remoteID,
+			Status:   SyncStatusUnchanged,
+		}
+
+		if fetchErr != nil {
+			report.Status = SyncStatusError
+			report.Error = fetchErr.Error()
+		} else {
+			s.syncEntity(ctx, h, e, upstream[e.remoteID], report)
+		}
+
+		if s.Reporter != nil {
+			s.Reporter.ReportSync(report)
+		}
+
+		progress.Increment()
+	}
+}
+
+func (s *Syncer) syncEntity(ctx context.Context, h syncTypeHandler, e linkedEntity, upstream upstreamEntity, report *SyncReport) {
+	if upstream == nil || upstream.isDeleted() {
+		logger.Infof("%s %s was deleted from stash-box", h.ty, e.remoteID)
+		report.Status = SyncStatusDeleted
+		return
+	}
+
+	if id := upstream.upstreamID(); id != e.remoteID {
+		logger.Infof("%s %s was merged into %s in stash-box", h.ty, e.remoteID, id)
+		report.MergedInto = id
+	}
+
+	if err := txn.WithTxn(ctx, s.Repository.TxnManager, func(ctx context.Context) error {
+		return h.apply(ctx, e, upstream, report)
+	}); err != nil {
+		logger.Errorf("Error syncing %s %s: %v", h.ty, e.name, err)
+		report.Status = SyncStatusError
+		report.Error = err.Error()
+		report.ChangedFields = nil
+		return
+	}
+
+	if len(report.ChangedFields) > 0 {
+		report.Status = SyncStatusUpdated
+	}
+}
+
+// remoteIDFor returns the stash id of the endpoint, or an empty string if
+// there is none.
+func remoteIDFor(stashIDs []models.StashID, endpoint string) string {
+	for _, id := range stashIDs {
+		if id.Endpoint == endpoint {
+			return id.StashID
+		}
+	}
+	return ""
+}
+
+// remapStashIDs replaces the stash id of a merged upstream entity with the
+// id of the entity it was merged into.
+func remapStashIDs(existing []models.StashID, endpoint string, from string, to string) *models.UpdateStashIDs {
+	ret := &models.UpdateStashIDs{
+		Mode: models.RelationshipUpdateModeSet,
+	}
+
+	for _, id := range existing {
+		if id.Endpoint == endpoint && id.StashID == from {
+			id.StashID = to
+		}
+		ret.AddUnique(id)
+	}
+
+	return ret
+}
+
+func (s *Syncer) findLinkedStudios(ctx context.Context) ([]linkedEntity, error) {
+	qb := s.Repository.Studio
+	studios, err := qb.FindByStashIDStatus(ctx, true, s.endpoint())
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []linkedEntity
+	for _, st := range studios {
+		if err := st.LoadStashIDs(ctx, qb); err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, linkedEntity{
+			id:       st.ID,
+			name:     st.Name,
+			remoteID: remoteIDFor(st.StashIDs.List(), s.endpoint()),
+		})
+	}
+
+	return ret, nil
+}
+
+func (s *Syncer) findLinkedPerformers(ctx context.Context) ([]linkedEntity, error) {
+	qb := s.Repository.Performer
+	performers, err := qb.FindByStashIDStatus(ctx, true, s.endpoint())
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []linkedEntity
+	for _, p := range performers {
+		if err := p.LoadStashIDs(ctx, qb); err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, linkedEntity{
+			id:       p.ID,
+			name:     p.Name,
+			remoteID: remoteIDFor(p.StashIDs.List(), s.endpoint()),
+		})
+	}
+
+	return ret, nil
+}
+
+func (s *Syncer) findLinkedScenes(ctx context.Context) ([]linkedEntity, error) {
+	qb := s.Repository.Scene
+	endpoint := s.endpoint()
+	perPage := -1
+
+	scenes, err := scene.Query(ctx, qb, &models.SceneFilterType{
+		StashIDEndpoint: &models.StashIDCriterionInput{
+			Endpoint: &endpoint,
+			Modifier: models.CriterionModifierNotNull,
+		},
+	}, &models.FindFilterType{
+		PerPage: &perPage,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []linkedEntity
+	for _, sc := range scenes {
+		if err := sc.LoadStashIDs(ctx, qb); err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, linkedEntity{
+			id:       sc.ID,
+			name:     sc.DisplayName(),
+			remoteID: remoteIDFor(sc.StashIDs.List(), endpoint),
+		})
+	}
+
+	return ret, nil
+}
+
+// findStudioID returns the id of the local studio linked to the upstream
+// studio, or nil if there is none.
+func (s *Syncer) findStudioID(ctx context.Context, remoteID string) (*int, error) {
+	studios, err := s.Repository.Studio.FindByStashID(ctx, models.StashID{
+		StashID:  remoteID,
+		Endpoint: s.endpoint(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(studios) == 0 {
+		return nil, nil
+	}
+
+	return &studios[0].ID, nil
+}
+
+func (s *Syncer) syncStudio(ctx context.Context, e linkedEntity, u upstreamEntity, report *SyncReport) error {
+	qb := s.Repository.Studio
+	upstream := u.(*syncStudio)
+
+	existing, err := qb.Find(ctx, e.id)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("studio with id %d not found", e.id)
+	}
+
+	if err := existing.LoadStashIDs(ctx, qb); err != nil {
+		return err
+	}
+
+	var parentID *int
+	if upstream.Parent != nil {
+		parentID, err = s.findStudioID(ctx, upstream.Parent.ID)
+		if err != nil {
+			return fmt.Errorf("finding parent studio: %w", err)
+		}
+	}
+
+	changes := newSyncChanges(s.Options)
+	partial := getStudioSyncPartial(existing, studioFragmentToScrapedStudio(upstream.StudioFragment), parentID, changes)
+
+	if report.MergedInto != "" {
+		partial.StashIDs = remapStashIDs(existing.StashIDs.List(), s.endpoint(), e.remoteID, report.MergedInto)
+		changes.add("stash_ids")
+	}
+
+	if len(changes.fields) == 0 {
+		return nil
+	}
+
+	if err := studio.ValidateModify(ctx, partial, qb); err != nil {
+		return err
+	}
+
+	if _, err := qb.UpdatePartial(ctx, partial); err != nil {
+		return err
+	}
+
+	report.ChangedFields = changes.fields
+	return nil
+}
+
+func getStudioSyncPartial(existing *models.Studio, upstream *models.ScrapedStudio, parentID *int, changes *syncChanges) models.StudioPartial {
+	partial := models.NewStudioPartial()
+	partial.ID = existing.ID
+
+	if v := changes.string("name", existing.Name, &upstream.Name); v != nil {
+		partial.Name = models.NewOptionalString(*v)
+	}
+	if v := changes.string("url", existing.URL, upstream.URL); v != nil {
+		partial.URL = models.NewOptionalString(*v)
+	}
+	// never set a studio as its own parent
+	if parentID != nil && *parentID != existing.ID {
+		if v := changes.id("parent_studio", existing.ParentID, *parentID); v != nil {
+			partial.ParentID = models.NewOptionalInt(*v)
+		}
+	}
+
+	return partial
+}
+
+func (s *Syncer) syncPerformer(ctx context.Context, e linkedEntity, u upstreamEntity, report *SyncReport) error {
+	qb := s.Repository.Performer
+	upstream := u.(*syncPerformer)
+
+	existing, err := qb.Find(ctx, e.id)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("performer with id %d not found", e.id)
+	}
+
+	if err := existing.LoadAliases(ctx, qb); err != nil {
+		return err
+	}
+	if err := existing.LoadStashIDs(ctx, qb); err != nil {
+		return err
+	}
+
+	changes := newSyncChanges(s.Options)
+	partial := getPerformerSyncPartial(existing, performerFragmentToScrapedPerformer(upstream.PerformerFragment), changes)
+
+	if report.MergedInto != "" {
+		partial.StashIDs = remapStashIDs(existing.StashIDs.List(), s.endpoint(), e.remoteID, report.MergedInto)
+		changes.add("stash_ids")
+	}
+
+	if len(changes.fields) == 0 {
+		return nil
+	}
+
+	if err := performer.ValidateUpdate(ctx, existing.ID, partial, qb); err != nil {
+		return err
+	}
+
+	if _, err := qb.UpdatePartial(ctx, existing.ID, partial); err != nil {
+		return err
+	}
+
+	report.ChangedFields = changes.fields
+	return nil
+}
+
+func getPerformerSyncPartial(existing *models.Performer, upstream *models.ScrapedPerformer, changes *syncChanges) models.PerformerPartial {
+	partial := models.NewPerformerPartial()
+
+	setString := func(field string, existing string, upstream *string, set *models.OptionalString) {
+		if v := changes.string(field, existing, upstream); v != nil {
+			*set = models.NewOptionalString(*v)
+		}
+	}
+
+	setString("name", existing.Name, upstream.Name, &partial.Name)
+	setString("disambiguation", existing.Disambiguation, upstream.Disambiguation, &partial.Disambiguation)
+
+	existingGender := ""
+	if existing.Gender != nil {
+		existingGender = existing.Gender.String()
+	}
+	setString("gender", existingGender, upstream.Gender, &partial.Gender)
+
+	if upstream.Birthdate != nil {
+		if d, err := models.ParseDate(*upstream.Birthdate); err == nil {
+			existingBirthdate := ""
+			if existing.Birthdate != nil {
+				existingBirthdate = existing.Birthdate.String()
+			}
+			v := d.String()
+			if changes.string("birthdate", existingBirthdate, &v) != nil {
+				partial.Birthdate = models.NewOptionalDate(d)
+			}
+		}
+	}
+
+	setString("country", existing.Country, upstream.Country, &partial.Country)
+	setString("ethnicity", existing.Ethnicity, upstream.Ethnicity, &partial.Ethnicity)
+	setString("eye_color", existing.EyeColor, upstream.EyeColor, &partial.EyeColor)
+	setString("hair_color", existing.HairColor, upstream.HairColor, &partial.HairColor)
+
+	if upstream.Height != nil {
+		if h, err := strconv.Atoi(*upstream.Height); err == nil {
+			existingHeight := ""
+			if existing.Height != nil {
+				existingHeight = strconv.Itoa(*existing.Height)
+			}
+			if changes.string("height", existingHeight, upstream.Height) != nil {
+				partial.Height = models.NewOptionalInt(h)
+			}
+		}
+	}
+
+	setString("measurements", existing.Measurements, upstream.Measurements, &partial.Measurements)
+	setString("fake_tits", existing.FakeTits, upstream.FakeTits, &partial.FakeTits)
+	setString("career_length", existing.CareerLength, upstream.CareerLength, &partial.CareerLength)
+	setString("tattoos", existing.Tattoos, upstream.Tattoos, &partial.Tattoos)
+	setString("piercings", existing.Piercings, upstream.Piercings, &partial.Piercings)
+	setString("twitter", existing.Twitter, upstream.Twitter, &partial.Twitter)
+
+	if upstream.Aliases != nil {
+		partial.Aliases = changes.strings("aliases", existing.Aliases.List(), stringslice.FromString(*upstream.Aliases, ","))
+	}
+
+	return partial
+}
+
+func (s *Syncer) syncScene(ctx context.Context, e linkedEntity, u upstreamEntity, report *SyncReport) error {
+	qb := s.Repository.Scene
+	upstream := u.(*syncScene)
+
+	existing, err := qb.Find(ctx, e.id)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("scene with id %d not found", e.id)
+	}
+
+	if err := existing.LoadURLs(ctx, qb); err != nil {
+		return err
+	}
+	if err := existing.LoadStashIDs(ctx, qb); err != nil {
+		return err
+	}
+
+	var studioID *int
+	if upstream.Studio != nil {
+		studioID, err = s.findStudioID(ctx, upstream.Studio.ID)
+		if err != nil {
+			return fmt.Errorf("finding studio: %w", err)
+		}
+	}
+
+	changes := newSyncChanges(s.Options)
+	partial := getSceneSyncPartial(existing, &upstream.SceneFragment, studioID, changes)
+
+	if report.MergedInto != "" {
+		partial.StashIDs = remapStashIDs(existing.StashIDs.List(), s.endpoint(), e.remoteID, report.MergedInto)
+		changes.add("stash_ids")
+	}
+
+	if len(changes.fields) == 0 {
+		return nil
+	}
+
+	if _, err := qb.UpdatePartial(ctx, existing.ID, partial); err != nil {
+		return err
+	}
+
+	if partial.StashIDs != nil {
+		if err := s.Repository.FingerprintSubmission.SyncScene(ctx, existing.ID); err != nil {
+			return err
+		}
+	}
+
+	report.ChangedFields = changes.fields
+	return nil
+}
+
+func getSceneSyncPartial(existing *models.Scene, upstream *graphql.SceneFragment, studioID *int, changes *syncChanges) models.ScenePartial {
+	partial := models.NewScenePartial()
+
+	if v := changes.string("title", existing.Title, upstream.Title); v != nil {
+		partial.Title = models.NewOptionalString(*v)
+	}
+	if v := changes.string("code", existing.Code, upstream.Code); v != nil {
+		partial.Code = models.NewOptionalString(*v)
+	}
+	if v := changes.string("details", existing.Details, upstream.Details); v != nil {
+		partial.Details = models.NewOptionalString(*v)
+	}
+	if v := changes.string("director", existing.Director, upstream.Director); v != nil {
+		partial.Director = models.NewOptionalString(*v)
+	}
+
+	if upstream.Date != nil {
+		if d, err := models.ParseDate(*upstream.Date); err == nil {
+			existingDate := ""
+			if existing.Date != nil {
+				existingDate = existing.Date.String()
+			}
+			v := d.String()
+			if changes.string("date", existingDate, &v) != nil {
+				partial.Date = models.NewOptionalDate(d)
+			}
+		}
+	}
+
+	urls := sliceutil.Map(upstream.Urls, func(u *graphql.URLFragment) string { return u.URL })
+	partial.URLs = changes.strings("urls", existing.URLs.List(), urls)
+
+	if studioID != nil {
+		if v := changes.id("studio", existing.StudioID, *studioID); v != nil {
+			partial.StudioID = models.NewOptionalInt(*v)
+		}
+	}
+
+	return partial
+}
+
+// syncChanges applies the field strategies to field values, and records
+// the fields that are changed.
+type syncChanges struct {
+	strategies map[string]SyncFieldStrategy
+	fields     []string
+}
+
+func newSyncChanges(options SyncOptions) *syncChanges {
+	return &syncChanges{
+		strategies: options.fieldStrategies(),
+	}
+}
+
+func (c *syncChanges) strategy(field string) SyncFieldStrategy {
+	if s, ok := c.strategies[field]; ok && s.IsValid() {
+		return s
+	}
+	return SyncFieldStrategyOverwrite
+}
+
+func (c *syncChanges) add(field string) {
+	c.fields = append(c.fields, field)
+}
+
+// string returns the value to set for a single-value field, or nil if the
+// field should not be changed.
+func (c *syncChanges) string(field string, existing string, upstream *string) *string {
+	if upstream == nil || *upstream == "" || *upstream == existing {
+		return nil
+	}
+
+	switch c.strategy(field) {
+	case SyncFieldStrategyIgnore:
+		return nil
+	case SyncFieldStrategyMerge:
+		if existing != "" {
+			return nil
+		}
+	}
+
+	c.add(field)
+	return upstream
+}
+
+// id returns the id to set for a related object field, or nil if the field
+// should not be changed.
+func (c *syncChanges) id(field string, existing *int, upstream int) *int {
+	if existing != nil && *existing == upstream {
+		return nil
+	}
+
+	switch c.strategy(field) {
+	case SyncFieldStrategyIgnore:
+		return nil
+	case SyncFieldStrategyMerge:
+		if existing != nil {
+			return nil
+		}
+	}
+
+	c.add(field)
+	return &upstream
+}
+
+// strings returns the update for a multi-value field, or nil if the field
+// should not be changed.
+func (c *syncChanges) strings(field string, existing []string, upstream []string) *models.UpdateStrings {
+	upstream = sliceutil.Filter(upstream, func(s string) bool { return s != "" })
+	if len(upstream) == 0 {
+		return nil
+	}
+
+	var ret *models.UpdateStrings
+	switch c.strategy(field) {
+	case SyncFieldStrategyIgnore:
+		return nil
+	case SyncFieldStrategyMerge:
+		missing := sliceutil.Exclude(upstream, existing)
+		if len(missing) == 0 {
+			return nil
+		}
+		ret = &models.UpdateStrings{
+			Values: missing,
+			Mode:   models.RelationshipUpdateModeAdd,
+		}
+	default:
+		if sliceutil.SliceSame(existing, upstream) {
+			return nil
+		}
+		ret = &models.UpdateStrings{
+			Values: upstream,
+			Mode:   models.RelationshipUpdateModeSet,
+		}
+	}
+
+	c.add(field)
+	return ret
+}
@@ -0,0 +1,139 @@
+package stashbox
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// SyncOptions are the options used when syncing linked entities with
+// stash-box.
+type SyncOptions struct {
+	// Entity types to sync. All types are synced if empty.
+	Types []SyncType `json:"types"`
+	// Strategies of individual fields. Fields without options are
+	// overwritten.
+	FieldOptions []*SyncFieldOptions `json:"fieldOptions"`
+}
+
+func (o SyncOptions) includesType(ty SyncType) bool {
+	if len(o.Types) == 0 {
+		return true
+	}
+
+	for _, t := range o.Types {
+		if t == ty {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o SyncOptions) fieldStrategies() map[string]SyncFieldStrategy {
+	ret := make(map[string]SyncFieldStrategy)
+	for _, f := range o.FieldOptions {
+		if f != nil {
+			ret[f.Field] = f.Strategy
+		}
+	}
+
+	return ret
+}
+
+type SyncFieldOptions struct {
+	Field    string            `json:"field"`
+	Strategy SyncFieldStrategy `json:"strategy"`
+}
+
+type SyncType string
+
+const (
+	SyncTypeScene     SyncType = "SCENE"
+	SyncTypePerformer SyncType = "PERFORMER"
+	SyncTypeStudio    SyncType = "STUDIO"
+)
+
+var AllSyncType = []SyncType{
+	SyncTypeScene,
+	SyncTypePerformer,
+	SyncTypeStudio,
+}
+
+func (e SyncType) IsValid() bool {
+	switch e {
+	case SyncTypeScene, SyncTypePerformer, SyncTypeStudio:
+		return true
+	}
+	return false
+}
+
+func (e SyncType) String() string {
+	return string(e)
+}
+
+func (e *SyncType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SyncType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid StashBoxSyncType", str)
+	}
+	return nil
+}
+
+func (e SyncType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SyncFieldStrategy string
+
+const (
+	// Never sets the field value
+	SyncFieldStrategyIgnore SyncFieldStrategy = "IGNORE"
+	// For multi-value fields, adds missing upstream values.
+	// For single-value fields, only sets the value if not already set
+	SyncFieldStrategyMerge SyncFieldStrategy = "MERGE"
+	// Replaces the value if the upstream value differs.
+	//   For multi-value fields, the existing values are replaced with the
+	//   upstream values.
+	SyncFieldStrategyOverwrite SyncFieldStrategy = "OVERWRITE"
+)
+
+var AllSyncFieldStrategy = []SyncFieldStrategy{
+	SyncFieldStrategyIgnore,
+	SyncFieldStrategyMerge,
+	SyncFieldStrategyOverwrite,
+}
+
+func (e SyncFieldStrategy) IsValid() bool {
+	switch e {
+	case SyncFieldStrategyIgnore, SyncFieldStrategyMerge, SyncFieldStrategyOverwrite:
+		return true
+	}
+	return false
+}
+
+func (e SyncFieldStrategy) String() string {
+	return string(e)
+}
+
+func (e *SyncFieldStrategy) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SyncFieldStrategy(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid StashBoxSyncFieldStrategy", str)
+	}
+	return nil
+}
+
+func (e SyncFieldStrategy) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -0,0 +1,106 @@
+package stashbox
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/scraper/stashbox/graphql"
+)
+
+// syncBatchSize is the number of entities fetched from stash-box in a single
+// request when syncing.
+const syncBatchSize = 25
+
+type syncScene struct {
+	graphql.SceneFragment
+	Deleted bool `json:"deleted" graphql:"deleted"`
+}
+
+type syncPerformer struct {
+	graphql.PerformerFragment
+	Deleted bool `json:"deleted" graphql:"deleted"`
+}
+
+type syncStudio struct {
+	graphql.StudioFragment
+	Deleted bool `json:"deleted" graphql:"deleted"`
+}
+
+// fragmentsOf returns the fragment definitions of a generated query document,
+// so that they can be reused in batched queries.
+func fragmentsOf(document string) string {
+	i := strings.Index(document, "fragment ")
+	if i == -1 {
+		return ""
+	}
+	return document[i:]
+}
+
+// batchQueryDocument returns a query document that finds n entities by id
+// in a single request, using the aliases e0 to en-1.
+func batchQueryDocument(operation string, field string, fragment string, fragments string, n int) string {
+	vars := make([]string, n)
+	for i := range vars {
+		vars[i] = fmt.Sprintf("$id%d: ID!", i)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "query %s (%s) {\n", operation, strings.Join(vars, ", "))
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&sb, "\te%d: %s(id: $id%d) {\n\t\t... %s\n\t\tdeleted\n\t}\n", i, field, i, fragment)
+	}
+	sb.WriteString("}\n")
+	sb.WriteString(fragments)
+
+	return sb.String()
+}
+
+// findByIDs finds the entities with the provided ids using a batched query.
+// The returned map is keyed by the requested id. Entities that were not
+// found are nil. The id of a returned entity may differ from the requested
+// id if the requested entity has been merged into another.
+func findByIDs[T any](ctx context.Context, c Client, operation string, field string, fragment string, fragments string, ids []string) (map[string]*T, error) {
+	vars := make(map[string]interface{}, len(ids))
+	for i, id := range ids {
+		vars[fmt.Sprintf("id%d", i)] = id
+	}
+
+	document := batchQueryDocument(operation, field, fragment, fragments, len(ids))
+
+	// the generated client decodes into structs only, so the response type
+	// has one field for each alias
+	fields := make([]reflect.StructField, len(ids))
+	for i := range fields {
+		fields[i] = reflect.StructField{
+			Name: fmt.Sprintf("E%d", i),
+			Type: reflect.TypeOf((*T)(nil)),
+			Tag:  reflect.StructTag(fmt.Sprintf(`graphql:"e%d"`, i)),
+		}
+	}
+
+	res := reflect.New(reflect.StructOf(fields))
+	if err := c.client.Client.Post(ctx, operation, document, res.Interface(), vars); err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string]*T, len(ids))
+	for i, id := range ids {
+		ret[id] = res.Elem().Field(i).Interface().(*T)
+	}
+
+	return ret, nil
+}
+
+func (c Client) findScenesByIDs(ctx context.Context, ids []string) (map[string]*syncScene, error) {
+	return findByIDs[syncScene](ctx, c, "FindScenesByIDs", "findScene", "SceneFragment", fragmentsOf(graphql.FindSceneByIDDocument), ids)
+}
+
+func (c Client) findPerformersByIDs(ctx context.Context, ids []string) (map[string]*syncPerformer, error) {
+	return findByIDs[syncPerformer](ctx, c, "FindPerformersByIDs", "findPerformer", "PerformerFragment", fragmentsOf(graphql.FindPerformerByIDDocument), ids)
+}
+
+func (c Client) findStudiosByIDs(ctx context.Context, ids []string) (map[string]*syncStudio, error) {
+	return findByIDs[syncStudio](ctx, c, "FindStudiosByIDs", "findStudio", "StudioFragment", fragmentsOf(graphql.FindStudioDocument), ids)
+}
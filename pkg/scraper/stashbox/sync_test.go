@@ -0,0 +1,283 @@
+package stashbox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+)
+
+const testEndpoint = "http://stash-box/graphql"
+
+func TestClient_findPerformersByIDs(t *testing.T) {
+	var request struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "apikey", r.Header.Get("ApiKey"))
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+
+		_, _ = w.Write([]byte(`{"data": {
+			"e0": {"id": "a", "name": "A", "deleted": false},
+			"e1": {"id": "c", "name": "C", "merged_ids": ["b"], "deleted": false},
+			"e2": null,
+			"e3": {"id": "d", "name": "D", "deleted": true}
+		}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(models.StashBox{Endpoint: server.URL, APIKey: "apikey"}, Repository{})
+
+	got, err := c.findPerformersByIDs(context.Background(), []string{"a", "b", "x", "d"})
+	if err != nil {
+		t.Fatalf("findPerformersByIDs() error = %v", err)
+	}
+
+	assert.Equal(t, map[string]interface{}{"id0": "a", "id1": "b", "id2": "x", "id3": "d"}, request.Variables)
+	assert.Contains(t, request.Query, "e3: findPerformer(id: $id3)")
+	assert.Contains(t, request.Query, "fragment PerformerFragment on Performer")
+
+	assert.Equal(t, "a", got["a"].ID)
+	assert.Equal(t, "c", got["b"].ID)
+	assert.Nil(t, got["x"])
+	assert.True(t, got["d"].Deleted)
+}
+
+func TestClient_findPerformersByIDs_error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errors": [{"message": "not authorized"}], "data": null}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(models.StashBox{Endpoint: server.URL}, Repository{})
+
+	_, err := c.findPerformersByIDs(context.Background(), []string{"a"})
+	if assert.Error(t, err) {
+		assert.True(t, strings.Contains(err.Error(), "not authorized"))
+	}
+}
+
+func TestSyncer_syncEntity(t *testing.T) {
+	const (
+		mergedID = iota + 1
+		deletedID
+		unchangedID
+	)
+
+	var (
+		name      = "name"
+		newName   = "new name"
+		country   = "country"
+		oldRemote = "old"
+		newRemote = "new"
+	)
+
+	db := mocks.NewDatabase()
+
+	otherStashID := models.StashID{Endpoint: "other", StashID: oldRemote}
+	db.Performer.On("Find", mock.Anything, mergedID).Return(&models.Performer{
+		ID:   mergedID,
+		Name: name,
+	}, nil)
+	db.Performer.On("Find", mock.Anything, unchangedID).Return(&models.Performer{
+		ID:      unchangedID,
+		Name:    newName,
+		Country: country,
+	}, nil)
+	db.Performer.On("GetAliases", mock.Anything, mock.Anything).Return(nil, nil)
+	db.Performer.On("GetStashIDs", mock.Anything, mergedID).Return([]models.StashID{
+		otherStashID,
+		{Endpoint: testEndpoint, StashID: oldRemote},
+	}, nil)
+	db.Performer.On("GetStashIDs", mock.Anything, unchangedID).Return([]models.StashID{
+		{Endpoint: testEndpoint, StashID: newRemote},
+	}, nil)
+	db.Performer.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(nil, 0, nil)
+
+	db.Performer.On("UpdatePartial", mock.Anything, mergedID, mock.MatchedBy(func(p models.PerformerPartial) bool {
+		return p.Name.Value == newName && p.Country.Value == country &&
+			assert.ObjectsAreEqual([]models.StashID{otherStashID, {Endpoint: testEndpoint, StashID: newRemote}}, p.StashIDs.StashIDs)
+	})).Return(nil, nil).Once()
+
+	s := &Syncer{
+		Client: NewClient(models.StashBox{Endpoint: testEndpoint}, Repository{}),
+		Repository: SyncRepository{
+			TxnManager: db,
+			Performer:  db.Performer,
+		},
+	}
+
+	h := syncTypeHandler{
+		ty:    SyncTypePerformer,
+		apply: s.syncPerformer,
+	}
+
+	upstream := &syncPerformer{}
+	upstream.ID = newRemote
+	upstream.Name = newName
+	upstream.Country = &country
+
+	tests := []struct {
+		name     string
+		entity   linkedEntity
+		upstream upstreamEntity
+		want     *SyncReport
+	}{
+		{
+			"merged",
+			linkedEntity{id: mergedID, remoteID: oldRemote},
+			upstream,
+			&SyncReport{Status: SyncStatusUpdated, MergedInto: newRemote, ChangedFields: []string{"name", "country", "stash_ids"}},
+		},
+		{
+			"not found",
+			linkedEntity{id: deletedID, remoteID: oldRemote},
+			nil,
+			&SyncReport{Status: SyncStatusDeleted},
+		},
+		{
+			"deleted",
+			linkedEntity{id: deletedID, remoteID: oldRemote},
+			&syncPerformer{Deleted: true},
+			&SyncReport{Status: SyncStatusDeleted},
+		},
+		{
+			"unchanged",
+			linkedEntity{id: unchangedID, remoteID: newRemote},
+			upstream,
+			&SyncReport{Status: SyncStatusUnchanged},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &SyncReport{Status: SyncStatusUnchanged}
+			s.syncEntity(context.Background(), h, tt.entity, tt.upstream, report)
+			assert.Equal(t, tt.want, report)
+		})
+	}
+
+	db.AssertExpectations(t)
+}
+
+func Test_getPerformerSyncPartial(t *testing.T) {
+	var (
+		existingCountry = "existingCountry"
+		existingAlias   = "alias1"
+		existingHeight  = 150
+
+		upstreamCountry   = "upstreamCountry"
+		upstreamHeight    = "160"
+		upstreamBirthdate = "2000-01-01"
+		upstreamAliases   = "alias1, alias2"
+		upstreamTattoos   = "tattoos"
+		empty             = ""
+	)
+
+	birthdate, _ := models.ParseDate(upstreamBirthdate)
+
+	existing := &models.Performer{
+		Country: existingCountry,
+		Height:  &existingHeight,
+		Aliases: models.NewRelatedStrings([]string{existingAlias}),
+	}
+
+	upstream := &models.ScrapedPerformer{
+		Name:      &empty,
+		Country:   &upstreamCountry,
+		Height:    &upstreamHeight,
+		Birthdate: &upstreamBirthdate,
+		Aliases:   &upstreamAliases,
+		Tattoos:   &upstreamTattoos,
+	}
+
+	tests := []struct {
+		name         string
+		fieldOptions []*SyncFieldOptions
+		want         models.PerformerPartial
+		wantFields   []string
+	}{
+		{
+			"overwrite",
+			nil,
+			models.PerformerPartial{
+				Birthdate: models.NewOptionalDate(birthdate),
+				Country:   models.NewOptionalString(upstreamCountry),
+				Height:    models.NewOptionalInt(160),
+				Tattoos:   models.NewOptionalString(upstreamTattoos),
+				Aliases: &models.UpdateStrings{
+					Values: []string{"alias1", "alias2"},
+					Mode:   models.RelationshipUpdateModeSet,
+				},
+			},
+			[]string{"birthdate", "country", "height", "tattoos", "aliases"},
+		},
+		{
+			"merge and ignore",
+			[]*SyncFieldOptions{
+				{Field: "country", Strategy: SyncFieldStrategyMerge},
+				{Field: "height", Strategy: SyncFieldStrategyMerge},
+				{Field: "aliases", Strategy: SyncFieldStrategyMerge},
+				{Field: "birthdate", Strategy: SyncFieldStrategyIgnore},
+			},
+			models.PerformerPartial{
+				Tattoos: models.NewOptionalString(upstreamTattoos),
+				Aliases: &models.UpdateStrings{
+					Values: []string{"alias2"},
+					Mode:   models.RelationshipUpdateModeAdd,
+				},
+			},
+			[]string{"tattoos", "aliases"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := newSyncChanges(SyncOptions{FieldOptions: tt.fieldOptions})
+			got := getPerformerSyncPartial(existing, upstream, changes)
+
+			// UpdatedAt is always set
+			got.UpdatedAt = models.OptionalTime{}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantFields, changes.fields)
+		})
+	}
+}
+
+func Test_remapStashIDs(t *testing.T) {
+	other := models.StashID{Endpoint: "other", StashID: "a"}
+
+	tests := []struct {
+		name     string
+		existing []models.StashID
+		want     []models.StashID
+	}{
+		{
+			"remapped",
+			[]models.StashID{other, {Endpoint: testEndpoint, StashID: "a"}},
+			[]models.StashID{other, {Endpoint: testEndpoint, StashID: "b"}},
+		},
+		{
+			"already linked to target",
+			[]models.StashID{{Endpoint: testEndpoint, StashID: "b"}, {Endpoint: testEndpoint, StashID: "a"}},
+			[]models.StashID{{Endpoint: testEndpoint, StashID: "b"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := remapStashIDs(tt.existing, testEndpoint, "a", "b")
+			assert.Equal(t, models.RelationshipUpdateModeSet, got.Mode)
+			assert.Equal(t, tt.want, got.StashIDs)
+		})
+	}
+}
@@ -0,0 +1,396 @@
+package stashbox
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper/stashbox/graphql"
+	"github.com/stashapp/stash/pkg/sliceutil"
+	"github.com/stashapp/stash/pkg/tag"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+// tagImportPageSize is the number of tags fetched from stash-box in a
+// single request when importing tags.
+const tagImportPageSize = 100
+
+const queryTagsDocument = `query QueryTags ($input: TagQueryInput!) {
+	queryTags(input: $input) {
+		count
+		tags {
+			id
+			name
+			description
+			aliases
+			deleted
+			category {
+				id
+				name
+				group
+				description
+			}
+		}
+	}
+}
+`
+
+// queryTags returns a page of the tags of the stash-box endpoint, sorted
+// by name.
+func (c Client) queryTags(ctx context.Context, page int, perPage int) (*graphql.QueryTagsResultType, error) {
+	input := graphql.TagQueryInput{
+		Page:      page,
+		PerPage:   perPage,
+		Direction: graphql.SortDirectionEnumAsc,
+		Sort:      graphql.TagSortEnumName,
+	}
+
+	var ret struct {
+		QueryTags graphql.QueryTagsResultType `graphql:"queryTags"`
+	}
+	if err := c.client.Client.Post(ctx, "QueryTags", queryTagsDocument, &ret, map[string]interface{}{"input": input}); err != nil {
+		return nil, err
+	}
+
+	return &ret.QueryTags, nil
+}
+
+type TagImportStatus string
+
+const (
+	// A new tag was created for the upstream tag.
+	TagImportStatusCreated TagImportStatus = "CREATED"
+	// An existing tag was linked to the upstream tag.
+	TagImportStatusLinked TagImportStatus = "LINKED"
+	// A tag already linked to the upstream tag was updated.
+	TagImportStatusUpdated TagImportStatus = "UPDATED"
+	// The tag is up to date.
+	TagImportStatusUnchanged TagImportStatus = "UNCHANGED"
+	// An error occurred importing the tag.
+	TagImportStatusError TagImportStatus = "ERROR"
+)
+
+// TagImportReport describes the changes made to a local tag when importing
+// an upstream tag.
+type TagImportReport struct {
+	ID            int             `json:"id,omitempty"`
+	Name          string          `json:"name"`
+	RemoteID      string          `json:"remote_id"`
+	Status        TagImportStatus `json:"status"`
+	Category      string          `json:"category,omitempty"`
+	ChangedFields []string        `json:"changed_fields,omitempty"`
+	Error         string          `json:"error,omitempty"`
+}
+
+// TagImportReporter receives the report of each imported tag.
+type TagImportReporter interface {
+	ReportTagImport(report *TagImportReport)
+}
+
+// TagImporter imports the tag catalogue of the stash-box endpoint of the
+// client into the local tags.
+//
+// Each upstream tag is matched with a local tag by stash id, then by name
+// and alias. Missing tags are created. Upstream aliases that are not used by
+// another local tag are added to the matched tag, and the tag is linked
+// with the upstream tag. If MapCategories is true, the upstream category
+// of each tag is added as a parent tag with the same name, which is created
+// if it does not exist.
+//
+// Existing names, descriptions and parent tags are never removed.
+type TagImporter struct {
+	Client     *Client
+	TxnManager models.TxnManager
+	Tag        models.TagReaderWriter
+
+	MapCategories bool
+	Reporter      TagImportReporter
+}
+
+func (s *TagImporter) endpoint() string {
+	return s.Client.box.Endpoint
+}
+
+func (s *TagImporter) Import(ctx context.Context, progress *job.Progress) error {
+	for page := 1; ; page++ {
+		if job.IsCancelled(ctx) {
+			return nil
+		}
+
+		var result *graphql.QueryTagsResultType
+		var err error
+		progress.ExecuteTask(fmt.Sprintf("Fetching page %d of tags from stash-box", page), func() {
+			result, err = s.Client.queryTags(ctx, page, tagImportPageSize)
+		})
+		if err != nil {
+			return fmt.Errorf("querying stash-box tags: %w", err)
+		}
+
+		if page == 1 {
+			progress.SetTotal(result.Count)
+		}
+
+		for _, t := range result.Tags {
+			if job.IsCancelled(ctx) {
+				return nil
+			}
+
+			if !t.Deleted {
+				s.importTag(ctx, t)
+			}
+
+			progress.Increment()
+		}
+
+		if len(result.Tags) < tagImportPageSize || page*tagImportPageSize >= result.Count {
+			return nil
+		}
+	}
+}
+
+func (s *TagImporter) importTag(ctx context.Context, upstream *graphql.Tag) {
+	report := &TagImportReport{
+		Name:     upstream.Name,
+		RemoteID: upstream.ID,
+		Status:   TagImportStatusUnchanged,
+	}
+	if upstream.Category != nil {
+		report.Category = upstream.Category.Name
+	}
+
+	if err := txn.WithTxn(ctx, s.TxnManager, func(ctx context.Context) error {
+		return s.applyTag(ctx, upstream, report)
+	}); err != nil {
+		logger.Errorf("Error importing stash-box tag %s: %v", upstream.Name, err)
+		report.Status = TagImportStatusError
+		report.Error = err.Error()
+		report.ChangedFields = nil
+	}
+
+	if s.Reporter != nil {
+		s.Reporter.ReportTagImport(report)
+	}
+}
+
+func (s *TagImporter) applyTag(ctx context.Context, upstream *graphql.Tag, report *TagImportReport) error {
+	qb := s.Tag
+	stashID := models.StashID{
+		Endpoint: s.endpoint(),
+		StashID:  upstream.ID,
+	}
+
+	linked, err := qb.FindByStashID(ctx, stashID)
+	if err != nil {
+		return err
+	}
+
+	var t *models.Tag
+	if len(linked) > 0 {
+		t = linked[0]
+	} else {
+		t, err = findTagByNameOrAlias(ctx, qb, upstream.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	if t == nil {
+		newTag := models.NewTag()
+		newTag.Name = upstream.Name
+		if upstream.Description != nil {
+			newTag.Description = *upstream.Description
+		}
+
+		if err := qb.Create(ctx, &newTag); err != nil {
+			return fmt.Errorf("creating tag: %w", err)
+		}
+
+		t = &newTag
+		report.Status = TagImportStatusCreated
+	}
+
+	report.ID = t.ID
+	report.Name = t.Name
+
+	aliases, err := s.newAliases(ctx, t, upstream)
+	if err != nil {
+		return err
+	}
+
+	if len(aliases) > 0 {
+		existing, err := qb.GetAliases(ctx, t.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := qb.UpdateAliases(ctx, t.ID, append(existing, aliases...)); err != nil {
+			return fmt.Errorf("updating aliases: %w", err)
+		}
+		report.ChangedFields = append(report.ChangedFields, "aliases")
+	}
+
+	linkedNow, err := s.link(ctx, t.ID, stashID)
+	if err != nil {
+		return err
+	}
+	if linkedNow {
+		report.ChangedFields = append(report.ChangedFields, "stash_ids")
+		if report.Status == TagImportStatusUnchanged {
+			report.Status = TagImportStatusLinked
+		}
+	}
+
+	if s.MapCategories && upstream.Category != nil {
+		added, err := s.addCategoryParent(ctx, t, upstream.Category)
+		if err != nil {
+			return err
+		}
+		if added {
+			report.ChangedFields = append(report.ChangedFields, "parents")
+		}
+	}
+
+	if report.Status == TagImportStatusUnchanged && len(report.ChangedFields) > 0 {
+		report.Status = TagImportStatusUpdated
+	}
+
+	return nil
+}
+
+// newAliases returns the upstream name and aliases that are not yet used
+// by the tag, nor by any other local tag.
+func (s *TagImporter) newAliases(ctx context.Context, t *models.Tag, upstream *graphql.Tag) ([]string, error) {
+	existing, err := s.Tag.GetAliases(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	used := []string{t.Name}
+	used = append(used, existing...)
+
+	var ret []string
+	for _, v := range append([]string{upstream.Name}, upstream.Aliases...) {
+		v = strings.TrimSpace(v)
+		if v == "" || containsFold(used, v) {
+			continue
+		}
+
+		other, err := findTagByNameOrAlias(ctx, s.Tag, v)
+		if err != nil {
+			return nil, err
+		}
+
+		used = append(used, v)
+		if other != nil {
+			logger.Debugf("Not adding alias %s to tag %s: already used by tag %s", v, t.Name, other.Name)
+			continue
+		}
+
+		ret = append(ret, v)
+	}
+
+	return ret, nil
+}
+
+// link adds the stash id to the tag. It returns false if the tag is already
+// linked to the upstream tag, or to another tag of the same endpoint.
+func (s *TagImporter) link(ctx context.Context, tagID int, stashID models.StashID) (bool, error) {
+	existing, err := s.Tag.GetStashIDs(ctx, tagID)
+	if err != nil {
+		return false, err
+	}
+
+	if remoteID := remoteIDFor(existing, stashID.Endpoint); remoteID != "" {
+		if remoteID != stashID.StashID {
+			logger.Warnf("Not linking tag %d to stash-box tag %s: already linked to %s", tagID, stashID.StashID, remoteID)
+		}
+		return false, nil
+	}
+
+	if err := s.Tag.UpdateStashIDs(ctx, tagID, append(existing, stashID)); err != nil {
+		return false, fmt.Errorf("updating stash ids: %w", err)
+	}
+
+	return true, nil
+}
+
+// addCategoryParent adds the tag of the category as a parent of the tag, if
+// it is not already. The category tag is created if it does not exist.
+func (s *TagImporter) addCategoryParent(ctx context.Context, t *models.Tag, category *graphql.TagCategory) (bool, error) {
+	qb := s.Tag
+
+	parent, err := s.categoryTag(ctx, category)
+	if err != nil {
+		return false, err
+	}
+
+	parentID := parent.ID
+	if parentID == t.ID {
+		return false, nil
+	}
+
+	parents, err := qb.FindByChildTagID(ctx, t.ID)
+	if err != nil {
+		return false, err
+	}
+
+	parentIDs := tag.GetIDs(parents)
+	if sliceutil.Contains(parentIDs, parentID) {
+		return false, nil
+	}
+
+	parentIDs = append(parentIDs, parentID)
+	if err := tag.ValidateHierarchy(ctx, t, parentIDs, nil, qb); err != nil {
+		logger.Warnf("Not adding category %s to tag %s: %v", category.Name, t.Name, err)
+		return false, nil
+	}
+
+	if err := qb.UpdateParentTags(ctx, t.ID, parentIDs); err != nil {
+		return false, fmt.Errorf("updating parent tags: %w", err)
+	}
+
+	return true, nil
+}
+
+// categoryTag returns the local tag with the name of the category, creating
+// it if it does not exist.
+func (s *TagImporter) categoryTag(ctx context.Context, category *graphql.TagCategory) (*models.Tag, error) {
+	t, err := findTagByNameOrAlias(ctx, s.Tag, category.Name)
+	if err != nil || t != nil {
+		return t, err
+	}
+
+	newTag := models.NewTag()
+	newTag.Name = category.Name
+	if category.Description != nil {
+		newTag.Description = *category.Description
+	}
+
+	if err := s.Tag.Create(ctx, &newTag); err != nil {
+		return nil, fmt.Errorf("creating category tag: %w", err)
+	}
+
+	logger.Infof("Created tag %s for stash-box category", newTag.Name)
+	return &newTag, nil
+}
+
+func findTagByNameOrAlias(ctx context.Context, qb models.TagQueryer, name string) (*models.Tag, error) {
+	ret, err := tag.ByName(ctx, qb, name)
+	if err != nil || ret != nil {
+		return ret, err
+	}
+
+	return tag.ByAlias(ctx, qb, name)
+}
+
+func containsFold(values []string, v string) bool {
+	for _, vv := range values {
+		if strings.EqualFold(vv, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package stashbox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stashapp/stash/pkg/scraper/stashbox/graphql"
+)
+
+func TestClient_queryTags(t *testing.T) {
+	var request struct {
+		Query     string `json:"query"`
+		Variables struct {
+			Input graphql.TagQueryInput `json:"input"`
+		} `json:"variables"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+
+		_, _ = w.Write([]byte(`{"data": {"queryTags": {
+			"count": 2,
+			"tags": [
+				{"id": "a", "name": "A", "aliases": ["a1"], "deleted": false, "category": {"id": "c", "name": "C", "group": "ACTION"}},
+				{"id": "b", "name": "B", "aliases": [], "deleted": true, "category": null}
+			]
+		}}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(models.StashBox{Endpoint: server.URL}, Repository{})
+
+	got, err := c.queryTags(context.Background(), 2, 50)
+	if err != nil {
+		t.Fatalf("queryTags() error = %v", err)
+	}
+
+	assert.Contains(t, request.Query, "queryTags(input: $input)")
+	assert.Equal(t, 2, request.Variables.Input.Page)
+	assert.Equal(t, 50, request.Variables.Input.PerPage)
+
+	assert.Equal(t, 2, got.Count)
+	if assert.Len(t, got.Tags, 2) {
+		assert.Equal(t, []string{"a1"}, got.Tags[0].Aliases)
+		assert.Equal(t, "C", got.Tags[0].Category.Name)
+		assert.True(t, got.Tags[1].Deleted)
+	}
+}
+
+func matchTagName(name string) interface{} {
+	return mock.MatchedBy(func(f *models.TagFilterType) bool {
+		return f.Name != nil && f.Name.Value == name
+	})
+}
+
+func matchTagAlias(alias string) interface{} {
+	return mock.MatchedBy(func(f *models.TagFilterType) bool {
+		return f.Aliases != nil && f.Aliases.Value == alias
+	})
+}
+
+func TestTagImporter_applyTag(t *testing.T) {
+	const (
+		existingID = iota + 1
+		categoryID
+		otherID
+		createdID
+	)
+
+	db := mocks.NewDatabase()
+
+	existing := &models.Tag{ID: existingID, Name: "tag"}
+
+	db.Tag.On("FindByStashID", mock.Anything, mock.Anything).Return(nil, nil)
+	db.Tag.On("Query", mock.Anything, matchTagName("Tag"), mock.Anything).Return([]*models.Tag{existing}, 1, nil)
+	db.Tag.On("Query", mock.Anything, matchTagName("Used"), mock.Anything).Return([]*models.Tag{{ID: otherID}}, 1, nil)
+	db.Tag.On("Query", mock.Anything, matchTagName("Category"), mock.Anything).Return([]*models.Tag{{ID: categoryID}}, 1, nil)
+	db.Tag.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(nil, 0, nil)
+
+	db.Tag.On("GetAliases", mock.Anything, existingID).Return([]string{"existing"}, nil)
+	db.Tag.On("GetAliases", mock.Anything, createdID).Return(nil, nil)
+	db.Tag.On("UpdateAliases", mock.Anything, existingID, []string{"existing", "Alias"}).Return(nil).Once()
+
+	db.Tag.On("GetStashIDs", mock.Anything, mock.Anything).Return(nil, nil)
+	db.Tag.On("UpdateStashIDs", mock.Anything, existingID, []models.StashID{{Endpoint: testEndpoint, StashID: "r1"}}).Return(nil).Once()
+	db.Tag.On("UpdateStashIDs", mock.Anything, createdID, []models.StashID{{Endpoint: testEndpoint, StashID: "r2"}}).Return(nil).Once()
+
+	db.Tag.On("FindByChildTagID", mock.Anything, existingID).Return(nil, nil)
+	db.Tag.On("FindByParentTagID", mock.Anything, existingID).Return(nil, nil)
+	db.Tag.On("FindAllAncestors", mock.Anything, existingID, mock.Anything).Return(nil, nil)
+	db.Tag.On("FindAllDescendants", mock.Anything, existingID, mock.Anything).Return(nil, nil)
+	db.Tag.On("UpdateParentTags", mock.Anything, existingID, []int{categoryID}).Return(nil).Once()
+
+	db.Tag.On("Create", mock.Anything, mock.MatchedBy(func(t *models.Tag) bool {
+		return t.Name == "New" && t.Description == "description"
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Tag).ID = createdID
+	}).Return(nil).Once()
+
+	s := &TagImporter{
+		Client:        NewClient(models.StashBox{Endpoint: testEndpoint}, Repository{}),
+		TxnManager:    db,
+		Tag:           db.Tag,
+		MapCategories: true,
+	}
+
+	description := "description"
+
+	tests := []struct {
+		name     string
+		upstream *graphql.Tag
+		want     *TagImportReport
+	}{
+		{
+			"linked",
+			&graphql.Tag{
+				ID:       "r1",
+				Name:     "Tag",
+				Aliases:  []string{"Alias", "Used", "EXISTING"},
+				Category: &graphql.TagCategory{ID: "c", Name: "Category"},
+			},
+			&TagImportReport{
+				ID:            existingID,
+				Name:          "tag",
+				RemoteID:      "r1",
+				Status:        TagImportStatusLinked,
+				ChangedFields: []string{"aliases", "stash_ids", "parents"},
+			},
+		},
+		{
+			"created",
+			&graphql.Tag{
+				ID:          "r2",
+				Name:        "New",
+				Description: &description,
+			},
+			&TagImportReport{
+				ID:            createdID,
+				Name:          "New",
+				RemoteID:      "r2",
+				Status:        TagImportStatusCreated,
+				ChangedFields: []string{"stash_ids"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &TagImportReport{
+				Name:     tt.upstream.Name,
+				RemoteID: tt.upstream.ID,
+				Status:   TagImportStatusUnchanged,
+			}
+			if err := s.applyTag(context.Background(), tt.upstream, report); err != nil {
+				t.Fatalf("applyTag() error = %v", err)
+			}
+			assert.Equal(t, tt.want, report)
+		})
+	}
+
+	db.AssertExpectations(t)
+}
@@ -26,10 +26,17 @@ import (
 const scrapeDefaultSleep = time.Second * 2
 
 func loadURL(ctx context.Context, loadURL string, client *http.Client, scraperConfig config, globalConfig GlobalConfig) (io.Reader, error) {
+	session := scraperConfig.session
+	if session != nil {
+		if err := session.ensureLoggedIn(ctx, client, scraperConfig, globalConfig); err != nil {
+			return nil, err
+		}
+	}
+
 	driverOptions := scraperConfig.DriverOptions
 	if driverOptions != nil && driverOptions.UseCDP {
 		// get the page using chrome dp
-		return urlFromCDP(ctx, loadURL, *driverOptions, globalConfig)
+		return urlFromCDP(ctx, loadURL, scraperConfig, globalConfig)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, loadURL, nil)
@@ -58,10 +65,14 @@ func loadURL(ctx context.Context, loadURL string, client *http.Client, scraperCo
 		req.Header.Set("User-Agent", userAgent)
 	}
 
+	if session != nil {
+		session.apply(req)
+	}
+
 	if driverOptions != nil { // setting the Headers after the UA allows us to override it from inside the scraper
 		for _, h := range driverOptions.Headers {
 			if h.Key != "" {
-				req.Header.Set(h.Key, h.Value)
+				req.Header.Set(h.Key, scraperConfig.expandSettings(h.Value))
 				logger.Debugf("[scraper] adding header <%s:%s>", h.Key, h.Value)
 			}
 		}
@@ -77,6 +88,10 @@ func loadURL(ctx context.Context, loadURL string, client *http.Client, scraperCo
 
 	defer resp.Body.Close()
 
+	if session != nil {
+		session.update(resp)
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
@@ -90,7 +105,9 @@ func loadURL(ctx context.Context, loadURL string, client *http.Client, scraperCo
 // func urlFromCDP uses chrome cdp and DOM to load and process the url
 // if remote is set as true in the scraperConfig  it will try to use localhost:9222
 // else it will look for google-chrome in path
-func urlFromCDP(ctx context.Context, urlCDP string, driverOptions scraperDriverOptions, globalConfig GlobalConfig) (io.Reader, error) {
+func urlFromCDP(ctx context.Context, urlCDP string, scraperConfig config, globalConfig GlobalConfig) (io.Reader, error) {
+	driverOptions := *scraperConfig.DriverOptions
+	session := scraperConfig.session
 
 	if !driverOptions.UseCDP {
 		return nil, fmt.Errorf("url shouldn't be fetched through CDP")
@@ -102,6 +119,92 @@ func urlFromCDP(ctx context.Context, urlCDP string, driverOptions scraperDriverO
 		sleepDuration = time.Duration(driverOptions.Sleep) * time.Second
 	}
 
+	ctx, cancel, err := newCDPContext(ctx, globalConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	// add a fixed timeout for the http request
+	ctx, cancel = context.WithTimeout(ctx, scrapeGetTimeout)
+	defer cancel()
+
+	var res string
+	headers := cdpHeaders(scraperConfig)
+
+	if proxyUsesAuth(globalConfig.GetProxy()) {
+		_, user, pass := splitProxyAuth(globalConfig.GetProxy())
+
+		// Based on https://github.com/chromedp/examples/blob/master/proxy/main.go
+		lctx, lcancel := context.WithCancel(ctx)
+		chromedp.ListenTarget(lctx, func(ev interface{}) {
+			switch ev := ev.(type) {
+			case *fetch.EventRequestPaused:
+				go func() {
+					_ = chromedp.Run(ctx, fetch.ContinueRequest(ev.RequestID))
+				}()
+			case *fetch.EventAuthRequired:
+				if ev.AuthChallenge.Source == fetch.AuthChallengeSourceProxy {
+					go func() {
+						_ = chromedp.Run(ctx,
+							fetch.ContinueWithAuth(ev.RequestID, &fetch.AuthChallengeResponse{
+								Response: fetch.AuthChallengeResponseResponseProvideCredentials,
+								Username: user,
+								Password: pass,
+							}),
+							// Chrome will remember the credential for the current instance,
+							// so we can disable the fetch domain once credential is provided.
+							// Please file an issue if Chrome does not work in this way.
+							fetch.Disable(),
+						)
+						// and cancel the event handler too.
+						lcancel()
+					}()
+				}
+			}
+		})
+	}
+
+	if session != nil {
+		session.mutex.Lock()
+		for k, v := range session.headers {
+			headers[k] = v
+		}
+		session.mutex.Unlock()
+	}
+
+	err = chromedp.Run(ctx,
+		network.Enable(),
+		setCDPCookies(scraperConfig),
+		setCDPSessionCookies(session, urlCDP),
+		printCDPCookies(driverOptions, "Cookies found"),
+		network.SetExtraHTTPHeaders(network.Headers(headers)),
+		chromedp.Navigate(urlCDP),
+		chromedp.Sleep(sleepDuration),
+		setCDPClicks(driverOptions),
+		chromedp.OuterHTML("html", &res, chromedp.ByQuery),
+		printCDPCookies(driverOptions, "Cookies set"),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.NewReader(res), nil
+}
+
+// newCDPContext returns a new chromedp context. If scraperCDPPath is set, the
+// context is allocated using the remote address or chrome executable it
+// refers to. The returned cancel function must be called to release the
+// browser resources.
+func newCDPContext(ctx context.Context, globalConfig GlobalConfig) (context.Context, context.CancelFunc, error) {
+	var cleanup []func()
+	cancel := func() {
+		for i := len(cleanup) - 1; i >= 0; i-- {
+			cleanup[i]()
+		}
+	}
+
 	// if scraperCDPPath is a remote address, then allocate accordingly
 	cdpPath := globalConfig.GetScraperCDPPath()
 	if cdpPath != "" {
@@ -116,17 +219,17 @@ func urlFromCDP(ctx context.Context, urlCDP string, driverOptions scraperDriverO
 			// with host headers that are either IPs or `localhost`
 			cdpURL, err := url.Parse(remote)
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse CDP Path: %v", err)
+				return nil, nil, fmt.Errorf("failed to parse CDP Path: %v", err)
 			}
 			hostname := cdpURL.Hostname()
 			if hostname != "localhost" {
 				if net.ParseIP(hostname) == nil { // not an IP
 					addr, err := net.LookupIP(hostname)
 					if err != nil || len(addr) == 0 { // can not resolve to IP
-						return nil, fmt.Errorf("CDP: hostname <%s> can not be resolved", hostname)
+						return nil, nil, fmt.Errorf("CDP: hostname <%s> can not be resolved", hostname)
 					}
 					if len(addr[0]) == 0 { // nil IP
-						return nil, fmt.Errorf("CDP: hostname <%s> resolved to nil", hostname)
+						return nil, nil, fmt.Errorf("CDP: hostname <%s> resolved to nil", hostname)
 					}
 					// addr is a valid IP
 					// replace the host part of the cdpURL with the IP
@@ -142,7 +245,7 @@ func urlFromCDP(ctx context.Context, urlCDP string, driverOptions scraperDriverO
 				var err error
 				remote, err = getRemoteCDPWSAddress(ctx, remote)
 				if err != nil {
-					return nil, err
+					return nil, nil, err
 				}
 			}
 
@@ -151,9 +254,9 @@ func urlFromCDP(ctx context.Context, urlCDP string, driverOptions scraperDriverO
 			// use a temporary user directory for chrome
 			dir, err := os.MkdirTemp("", "stash-chromedp")
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
-			defer os.RemoveAll(dir)
+			cleanup = append(cleanup, func() { os.RemoveAll(dir) })
 
 			opts := append(chromedp.DefaultExecAllocatorOptions[:],
 				chromedp.UserDataDir(dir),
@@ -167,69 +270,13 @@ func urlFromCDP(ctx context.Context, urlCDP string, driverOptions scraperDriverO
 			ctx, cancelAct = chromedp.NewExecAllocator(ctx, opts...)
 		}
 
-		defer cancelAct()
+		cleanup = append(cleanup, cancelAct)
 	}
 
-	ctx, cancel := chromedp.NewContext(ctx)
-	defer cancel()
-
-	// add a fixed timeout for the http request
-	ctx, cancel = context.WithTimeout(ctx, scrapeGetTimeout)
-	defer cancel()
-
-	var res string
-	headers := cdpHeaders(driverOptions)
+	ctx, cancelCtx := chromedp.NewContext(ctx)
+	cleanup = append(cleanup, cancelCtx)
 
-	if proxyUsesAuth(globalConfig.GetProxy()) {
-		_, user, pass := splitProxyAuth(globalConfig.GetProxy())
-
-		// Based on https://github.com/chromedp/examples/blob/master/proxy/main.go
-		lctx, lcancel := context.WithCancel(ctx)
-		chromedp.ListenTarget(lctx, func(ev interface{}) {
-			switch ev := ev.(type) {
-			case *fetch.EventRequestPaused:
-				go func() {
-					_ = chromedp.Run(ctx, fetch.ContinueRequest(ev.RequestID))
-				}()
-			case *fetch.EventAuthRequired:
-				if ev.AuthChallenge.Source == fetch.AuthChallengeSourceProxy {
-					go func() {
-						_ = chromedp.Run(ctx,
-							fetch.ContinueWithAuth(ev.RequestID, &fetch.AuthChallengeResponse{
-								Response: fetch.AuthChallengeResponseResponseProvideCredentials,
-								Username: user,
-								Password: pass,
-							}),
-							// Chrome will remember the credential for the current instance,
-							// so we can disable the fetch domain once credential is provided.
-							// Please file an issue if Chrome does not work in this way.
-							fetch.Disable(),
-						)
-						// and cancel the event handler too.
-						lcancel()
-					}()
-				}
-			}
-		})
-	}
-
-	err := chromedp.Run(ctx,
-		network.Enable(),
-		setCDPCookies(driverOptions),
-		printCDPCookies(driverOptions, "Cookies found"),
-		network.SetExtraHTTPHeaders(network.Headers(headers)),
-		chromedp.Navigate(urlCDP),
-		chromedp.Sleep(sleepDuration),
-		setCDPClicks(driverOptions),
-		chromedp.OuterHTML("html", &res, chromedp.ByQuery),
-		printCDPCookies(driverOptions, "Cookies set"),
-	)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return strings.NewReader(res), nil
+	return ctx, cancel, nil
 }
 
 // click all xpaths listed in the scraper config
@@ -288,12 +335,12 @@ func getRemoteCDPWSAddress(ctx context.Context, url string) (string, error) {
 	return remote, err
 }
 
-func cdpHeaders(driverOptions scraperDriverOptions) map[string]interface{} {
+func cdpHeaders(scraperConfig config) map[string]interface{} {
 	headers := map[string]interface{}{}
-	if driverOptions.Headers != nil {
-		for _, h := range driverOptions.Headers {
+	if scraperConfig.DriverOptions != nil {
+		for _, h := range scraperConfig.DriverOptions.Headers {
 			if h.Key != "" {
-				headers[h.Key] = h.Value
+				headers[h.Key] = scraperConfig.expandSettings(h.Value)
 				logger.Debugf("[scraper] adding header <%s:%s>", h.Key, h.Value)
 			}
 		}
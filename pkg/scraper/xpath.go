@@ -71,6 +71,8 @@ func (s *xpathScraper) scrapeByURL(ctx context.Context, url string, ty ScrapeCon
 		return scraper.scrapeGallery(ctx, q)
 	case ScrapeContentTypeMovie:
 		return scraper.scrapeMovie(ctx, q)
+	case ScrapeContentTypeStudio:
+		return scraper.scrapeStudio(ctx, q)
 	}
 
 	return nil, ErrNotSupported
@@ -83,48 +85,45 @@ func (s *xpathScraper) scrapeByName(ctx context.Context, name string, ty ScrapeC
 		return nil, fmt.Errorf("%w: name %v", ErrNotFound, s.scraper.Scraper)
 	}
 
+	if ty != ScrapeContentTypePerformer && ty != ScrapeContentTypeScene && ty != ScrapeContentTypeStudio {
+		return nil, ErrNotSupported
+	}
+
 	const placeholder = "{}"
 
 	// replace the placeholder string with the URL-escaped name
 	escapedName := url.QueryEscape(name)
 
-	url := s.scraper.QueryURL
+	url := s.config.expandURLSettings(s.scraper.QueryURL)
 	url = strings.ReplaceAll(url, placeholder, escapedName)
 
-	doc, err := s.loadURL(ctx, url)
+	load := func(ctx context.Context, url string) (mappedQuery, error) {
+		doc, err := s.loadURL(ctx, url)
+		if err != nil {
+			return nil, err
+		}
 
-	if err != nil {
-		return nil, err
+		return s.getXPathQuery(doc), nil
 	}
 
-	q := s.getXPathQuery(doc)
-	q.setType(SearchQuery)
-
 	var content []ScrapedContent
-	switch ty {
-	case ScrapeContentTypePerformer:
-		performers, err := scraper.scrapePerformers(ctx, q)
-		if err != nil {
-			return nil, err
-		}
-		for _, p := range performers {
-			content = append(content, p)
-		}
+	scrapePage := func(q mappedQuery) (int, error) {
+		q.setType(SearchQuery)
 
-		return content, nil
-	case ScrapeContentTypeScene:
-		scenes, err := scraper.scrapeScenes(ctx, q)
+		results, err := scraper.scrapeSearchResults(ctx, q, ty)
 		if err != nil {
-			return nil, err
-		}
-		for _, s := range scenes {
-			content = append(content, s)
+			return 0, err
 		}
 
-		return content, nil
+		content = append(content, results...)
+		return len(results), nil
 	}
 
-	return nil, ErrNotSupported
+	if err := scrapePages(ctx, url, s.scraper.Pagination, load, scrapePage); err != nil {
+		return nil, err
+	}
+
+	return content, nil
 }
 
 func (s *xpathScraper) scrapeSceneByScene(ctx context.Context, scene *models.Scene) (*ScrapedScene, error) {
@@ -133,7 +132,7 @@ func (s *xpathScraper) scrapeSceneByScene(ctx context.Context, scene *models.Sce
 	if s.scraper.QueryURLReplacements != nil {
 		queryURL.applyReplacements(s.scraper.QueryURLReplacements)
 	}
-	url := queryURL.constructURL(s.scraper.QueryURL)
+	url := queryURL.constructURL(s.config.expandURLSettings(s.scraper.QueryURL))
 
 	scraper := s.getXpathScraper()
 
@@ -168,7 +167,7 @@ func (s *xpathScraper) scrapeByFragment(ctx context.Context, input Input) (Scrap
 	if s.scraper.QueryURLReplacements != nil {
 		queryURL.applyReplacements(s.scraper.QueryURLReplacements)
 	}
-	url := queryURL.constructURL(s.scraper.QueryURL)
+	url := queryURL.constructURL(s.config.expandURLSettings(s.scraper.QueryURL))
 
 	scraper := s.getXpathScraper()
 
@@ -192,7 +191,7 @@ func (s *xpathScraper) scrapeGalleryByGallery(ctx context.Context, gallery *mode
 	if s.scraper.QueryURLReplacements != nil {
 		queryURL.applyReplacements(s.scraper.QueryURLReplacements)
 	}
-	url := queryURL.constructURL(s.scraper.QueryURL)
+	url := queryURL.constructURL(s.config.expandURLSettings(s.scraper.QueryURL))
 
 	scraper := s.getXpathScraper()
 
@@ -210,7 +209,69 @@ func (s *xpathScraper) scrapeGalleryByGallery(ctx context.Context, gallery *mode
 	return scraper.scrapeGallery(ctx, q)
 }
 
+func (s *xpathScraper) scrapeScenesByFingerprints(ctx context.Context, scenes []sceneFingerprints) ([][]*ScrapedScene, error) {
+	scraper := s.getXpathScraper()
+
+	if scraper == nil {
+		return nil, errors.New("xpath scraper with name " + s.scraper.Scraper + " not found in config")
+	}
+
+	// html pages cannot be batched, so one request is made for each scene
+	ret := make([][]*ScrapedScene, len(scenes))
+	var errs []error
+	for i, fp := range scenes {
+		var err error
+		ret[i], err = s.scrapeSceneByFingerprints(ctx, scraper, fp)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("scene %s: %w", fp.ID, err))
+		}
+	}
+
+	if err := fingerprintErrors(s.config.ID, len(scenes), errs); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (s *xpathScraper) scrapeSceneByFingerprints(ctx context.Context, scraper *mappedScraper, fp sceneFingerprints) ([]*ScrapedScene, error) {
+	// construct the URL
+	queryURL := queryURLParametersFromFingerprints(fp)
+	if s.scraper.QueryURLReplacements != nil {
+		queryURL.applyReplacements(s.scraper.QueryURLReplacements)
+	}
+	url := queryURL.constructURL(s.config.expandURLSettings(s.scraper.QueryURL))
+
+	doc, err := s.loadURL(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	q := s.getXPathQuery(doc)
+	q.setType(SearchQuery)
+
+	return scraper.scrapeScenes(ctx, q)
+}
+
 func (s *xpathScraper) loadURL(ctx context.Context, url string) (*html.Node, error) {
+	ret, err := s.loadDocument(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.config.loggedOut(s.getXPathQuery(ret)) {
+		logger.Infof("[scraper] %s: session expired", s.config.ID)
+		if err := s.config.session.refresh(ctx, s.client, s.config, s.globalConfig); err != nil {
+			return nil, err
+		}
+
+		return s.loadDocument(ctx, url)
+	}
+
+	return ret, nil
+}
+
+func (s *xpathScraper) loadDocument(ctx context.Context, url string) (*html.Node, error) {
 	r, err := loadURL(ctx, url, s.client, s.config, s.globalConfig)
 	if err != nil {
 		return nil, err
@@ -838,6 +838,14 @@ func (mockGlobalConfig) GetProxy() string {
 	return ""
 }
 
+func (mockGlobalConfig) GetCachePath() string {
+	return ""
+}
+
+func (mockGlobalConfig) GetScraperConfiguration(scraperID string) map[string]interface{} {
+	return nil
+}
+
 func TestSubScrape(t *testing.T) {
 	retHTML := `
 	<div>
@@ -0,0 +1,34 @@
+package sqlite
+
+import (
+	"gopkg.in/guregu/null.v4/zero"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// autoTagRulesToJSON encodes the auto-tag rules of an object. Empty rules
+// are stored as null.
+func autoTagRulesToJSON(r *models.AutoTagRules) zero.String {
+	if r.IsEmpty() {
+		return zero.String{}
+	}
+
+	return zero.StringFrom(encodeJSONOrEmpty(r))
+}
+
+func autoTagRulesFromJSON(s zero.String) *models.AutoTagRules {
+	if s.String == "" {
+		return nil
+	}
+
+	ret := &models.AutoTagRules{}
+	decodeJSON(s.String, ret)
+	ret.Target = ret.GetTarget()
+	return ret
+}
+
+func (r *updateRecord) setAutoTagRules(destField string, v *models.AutoTagRules) {
+	if v != nil {
+		r.set(destField, autoTagRulesToJSON(v))
+	}
+}
@@ -33,7 +33,7 @@ const (
 	dbConnTimeout = 30
 )
 
-var appSchemaVersion uint = 54
+var appSchemaVersion uint = 59
 
 //go:embed migrations/*.sql
 var migrationsBox embed.FS
@@ -65,19 +65,21 @@ func (e *MismatchedSchemaVersionError) Error() string {
 }
 
 type Database struct {
-	Blobs          *BlobStore
-	File           *FileStore
-	Folder         *FolderStore
-	Image          *ImageStore
-	Gallery        *GalleryStore
-	GalleryChapter *GalleryChapterStore
-	Scene          *SceneStore
-	SceneMarker    *SceneMarkerStore
-	Performer      *PerformerStore
-	SavedFilter    *SavedFilterStore
-	Studio         *StudioStore
-	Tag            *TagStore
-	Movie          *MovieStore
+	Blobs                 *BlobStore
+	File                  *FileStore
+	Folder                *FolderStore
+	Image                 *ImageStore
+	Gallery               *GalleryStore
+	GalleryChapter        *GalleryChapterStore
+	Scene                 *SceneStore
+	SceneMarker           *SceneMarkerStore
+	Performer             *PerformerStore
+	SavedFilter           *SavedFilterStore
+	PendingSceneChange    *PendingSceneChangeStore
+	FingerprintSubmission *FingerprintSubmissionStore
+	Studio                *StudioStore
+	Tag                   *TagStore
+	Movie                 *MovieStore
 
 	db     *sqlx.DB
 	dbPath string
@@ -93,20 +95,22 @@ func NewDatabase() *Database {
 	blobStore := NewBlobStore(BlobStoreOptions{})
 
 	ret := &Database{
-		Blobs:          blobStore,
-		File:           fileStore,
-		Folder:         folderStore,
-		Scene:          NewSceneStore(fileStore, blobStore),
-		SceneMarker:    NewSceneMarkerStore(),
-		Image:          NewImageStore(fileStore),
-		Gallery:        NewGalleryStore(fileStore, folderStore),
-		GalleryChapter: NewGalleryChapterStore(),
-		Performer:      NewPerformerStore(blobStore),
-		Studio:         NewStudioStore(blobStore),
-		Tag:            NewTagStore(blobStore),
-		Movie:          NewMovieStore(blobStore),
-		SavedFilter:    NewSavedFilterStore(),
-		lockChan:       make(chan struct{}, 1),
+		Blobs:                 blobStore,
+		File:                  fileStore,
+		Folder:                folderStore,
+		Scene:                 NewSceneStore(fileStore, blobStore),
+		SceneMarker:           NewSceneMarkerStore(),
+		Image:                 NewImageStore(fileStore),
+		Gallery:               NewGalleryStore(fileStore, folderStore),
+		GalleryChapter:        NewGalleryChapterStore(),
+		Performer:             NewPerformerStore(blobStore),
+		Studio:                NewStudioStore(blobStore),
+		Tag:                   NewTagStore(blobStore),
+		Movie:                 NewMovieStore(blobStore),
+		SavedFilter:           NewSavedFilterStore(),
+		PendingSceneChange:    NewPendingSceneChangeStore(),
+		FingerprintSubmission: NewFingerprintSubmissionStore(),
+		lockChan:              make(chan struct{}, 1),
 	}
 
 	return ret
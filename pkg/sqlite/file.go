@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io/fs"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -637,13 +639,21 @@ func (qb *FileStore) FindAllByPath(ctx context.Context, p string) ([]models.File
 }
 
 func (qb *FileStore) allInPaths(q *goqu.SelectDataset, p []string) *goqu.SelectDataset {
+	table := qb.table()
 	folderTable := folderTableMgr.table
 
 	var conds []exp.Expression
 	for _, pp := range p {
 		ppWildcard := pp + string(filepath.Separator) + "%"
 
-		conds = append(conds, folderTable.Col("path").Eq(pp), folderTable.Col("path").Like(ppWildcard))
+		// paths may also be paths of individual files. These are matched on
+		// the folder path and basename so that the indexes can be used.
+		isFile := goqu.And(
+			folderTable.Col("path").Eq(filepath.Dir(pp)),
+			table.Col("basename").Eq(filepath.Base(pp)),
+		)
+
+		conds = append(conds, folderTable.Col("path").Eq(pp), folderTable.Col("path").Like(ppWildcard), isFile)
 	}
 
 	return q.Where(
@@ -723,6 +733,17 @@ func (qb *FileStore) FindByZipFileID(ctx context.Context, zipFileID models.FileI
 	return qb.getMany(ctx, q)
 }
 
+// FindByParentFolderID returns the files directly in the given folder.
+func (qb *FileStore) FindByParentFolderID(ctx context.Context, parentFolderID models.FolderID) ([]models.File, error) {
+	table := qb.table()
+
+	q := qb.selectDataset().Prepared(true).Where(
+		table.Col("parent_folder_id").Eq(parentFolderID),
+	)
+
+	return qb.getMany(ctx, q)
+}
+
 // FindByFileInfo finds files that match the base name, size, and mod time of the given file.
 func (qb *FileStore) FindByFileInfo(ctx context.Context, info fs.FileInfo, size int64) ([]models.File, error) {
 	table := qb.table()
@@ -782,6 +803,101 @@ func (qb *FileStore) IsPrimary(ctx context.Context, fileID models.FileID) (bool,
 	return ret > 0, nil
 }
 
+// FindDuplicates returns a page of the groups of files that have the same
+// fingerprint of the given type, and the total number of groups. Groups are
+// ordered by their total size, largest first. The sort of the filter is
+// ignored.
+func (qb *FileStore) FindDuplicates(ctx context.Context, fingerprintType string, findFilter *models.FindFilterType) ([][]models.File, int, error) {
+	table := qb.table()
+	fingerprintTable := fingerprintTableMgr.table
+
+	q := dialect.From(fingerprintTable).Select(
+		goqu.L("GROUP_CONCAT(?)", fingerprintTable.Col(fileIDColumn)).As("ids"),
+	).InnerJoin(
+		table,
+		goqu.On(table.Col(idColumn).Eq(fingerprintTable.Col(fileIDColumn))),
+	).Where(
+		fingerprintTable.Col("type").Eq(fingerprintType),
+	).GroupBy(
+		fingerprintTable.Col("fingerprint"),
+	).Having(
+		goqu.COUNT("*").Gt(1),
+	)
+
+	total, err := count(ctx, dialect.From(q.As("groups")).Select(goqu.COUNT("*")))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	q = q.Order(
+		goqu.SUM(table.Col("size")).Desc(),
+		fingerprintTable.Col("fingerprint").Asc(),
+	)
+
+	if findFilter == nil {
+		findFilter = &models.FindFilterType{}
+	}
+	if !findFilter.IsGetAll() {
+		page := findFilter.GetPage()
+		perPage := findFilter.GetPageSize()
+		q = q.Limit(uint(perPage)).Offset(uint((page - 1) * perPage))
+	}
+
+	var groups [][]models.FileID
+	var ids []models.FileID
+	if err := queryFunc(ctx, q, false, func(rows *sqlx.Rows) error {
+		var groupIDs string
+		if err := rows.Scan(&groupIDs); err != nil {
+			return err
+		}
+
+		var group []models.FileID
+		for _, idStr := range strings.Split(groupIDs, ",") {
+			id, err := strconv.Atoi(idStr)
+			if err != nil {
+				return fmt.Errorf("parsing file id %q: %w", idStr, err)
+			}
+			group = append(group, models.FileID(id))
+		}
+
+		groups = append(groups, group)
+		ids = append(ids, group...)
+		return nil
+	}); err != nil {
+		return nil, 0, err
+	}
+
+	if len(ids) == 0 {
+		return nil, total, nil
+	}
+
+	// find the files of all groups at once
+	files, err := qb.getMany(ctx, qb.selectDataset().Where(table.Col(idColumn).In(ids)))
+	if err != nil {
+		return nil, 0, fmt.Errorf("getting duplicate files: %w", err)
+	}
+
+	filesByID := make(map[models.FileID]models.File, len(files))
+	for _, f := range files {
+		filesByID[f.Base().ID] = f
+	}
+
+	ret := make([][]models.File, len(groups))
+	for i, group := range groups {
+		for _, id := range group {
+			if f := filesByID[id]; f != nil {
+				ret[i] = append(ret[i], f)
+			}
+		}
+
+		sort.Slice(ret[i], func(j, k int) bool {
+			return ret[i][j].Base().Path < ret[i][k].Base().Path
+		})
+	}
+
+	return ret, total, nil
+}
+
 func (qb *FileStore) validateFilter(fileFilter *models.FileFilterType) error {
 	const and = "AND"
 	const or = "OR"
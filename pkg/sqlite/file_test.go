@@ -562,6 +562,84 @@ func Test_FileStore_FindByPath(t *testing.T) {
 	}
 }
 
+func TestFileStore_FindByParentFolderID(t *testing.T) {
+	tests := []struct {
+		name     string
+		folderID models.FolderID
+		want     []models.File
+	}{
+		{
+			"valid",
+			folderIDs[folderIdxWithFiles],
+			[]models.File{makeFileWithID(fileIdxZip)},
+		},
+		{
+			"invalid",
+			invalidFolderID,
+			nil,
+		},
+	}
+
+	qb := db.File
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+			got, err := qb.FindByParentFolderID(ctx, tt.folderID)
+			if err != nil {
+				t.Errorf("FileStore.FindByParentFolderID() error = %v", err)
+				return
+			}
+
+			assert.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestFileStore_FindAllInPaths(t *testing.T) {
+	filePath := getFilePath(fileFolders[fileIdxZip], getFileBaseName(fileIdxZip))
+
+	tests := []struct {
+		name  string
+		paths []string
+		want  []models.File
+	}{
+		{
+			"file path",
+			[]string{filePath},
+			[]models.File{makeFileWithID(fileIdxZip)},
+		},
+		{
+			"invalid path",
+			[]string{"invalid path"},
+			nil,
+		},
+	}
+
+	qb := db.File
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+			got, err := qb.FindAllInPaths(ctx, tt.paths, -1, 0)
+			if err != nil {
+				t.Errorf("FileStore.FindAllInPaths() error = %v", err)
+				return
+			}
+
+			assert.Equal(tt.want, got)
+
+			count, err := qb.CountAllInPaths(ctx, tt.paths)
+			if err != nil {
+				t.Errorf("FileStore.CountAllInPaths() error = %v", err)
+				return
+			}
+
+			assert.Equal(len(tt.want), count)
+		})
+	}
+}
+
 func TestFileStore_FindByFingerprint(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -662,3 +740,96 @@ func TestFileStore_IsPrimary(t *testing.T) {
 		})
 	}
 }
+
+func TestFileStore_FindDuplicates(t *testing.T) {
+	qb := db.File
+
+	runWithRollbackTxn(t, "no duplicates", func(t *testing.T, ctx context.Context) {
+		got, count, err := qb.FindDuplicates(ctx, models.FingerprintTypeMD5, nil)
+		if err != nil {
+			t.Errorf("FileStore.FindDuplicates() error = %v", err)
+			return
+		}
+
+		assert.Len(t, got, 0)
+		assert.Equal(t, 0, count)
+	})
+
+	runWithRollbackTxn(t, "md5", func(t *testing.T, ctx context.Context) {
+		assert := assert.New(t)
+
+		// give the orphan file the same md5 as a scene file
+		if err := qb.ModifyFingerprints(ctx, fileIDs[fileIdxZip], []models.Fingerprint{
+			{
+				Type:        models.FingerprintTypeMD5,
+				Fingerprint: getSceneStringValue(sceneIdx1WithPerformer, checksumField),
+			},
+		}); err != nil {
+			t.Errorf("FileStore.ModifyFingerprints() error = %v", err)
+			return
+		}
+
+		got, count, err := qb.FindDuplicates(ctx, models.FingerprintTypeMD5, nil)
+		if err != nil {
+			t.Errorf("FileStore.FindDuplicates() error = %v", err)
+			return
+		}
+
+		assert.Equal(1, count)
+		if !assert.Len(got, 1) {
+			return
+		}
+
+		var ids []models.FileID
+		for _, f := range got[0] {
+			ids = append(ids, f.Base().ID)
+		}
+
+		assert.ElementsMatch([]models.FileID{fileIDs[fileIdxZip], sceneFileIDs[sceneIdx1WithPerformer]}, ids)
+	})
+
+	runWithRollbackTxn(t, "phash", func(t *testing.T, ctx context.Context) {
+		assert := assert.New(t)
+
+		allPerPage := -1
+		got, count, err := qb.FindDuplicates(ctx, models.FingerprintTypePhash, &models.FindFilterType{
+			PerPage: &allPerPage,
+		})
+		if err != nil {
+			t.Errorf("FileStore.FindDuplicates() error = %v", err)
+			return
+		}
+
+		assert.NotEmpty(got)
+		assert.Len(got, count)
+
+		// pages do not overlap and are in the same order
+		perPage := 1
+		for page := 1; page <= count; page++ {
+			p := page
+			pageGroups, pageCount, err := qb.FindDuplicates(ctx, models.FingerprintTypePhash, &models.FindFilterType{
+				Page:    &p,
+				PerPage: &perPage,
+			})
+			if err != nil {
+				t.Errorf("FileStore.FindDuplicates() error = %v", err)
+				return
+			}
+
+			assert.Equal(count, pageCount)
+			if assert.Len(pageGroups, 1) {
+				assert.Equal(got[page-1], pageGroups[0])
+			}
+		}
+		for _, group := range got {
+			if !assert.GreaterOrEqual(len(group), 2) {
+				continue
+			}
+
+			want := group[0].Base().Fingerprints.Get(models.FingerprintTypePhash)
+			for _, f := range group[1:] {
+				assert.Equal(want, f.Base().Fingerprints.Get(models.FingerprintTypePhash))
+			}
+		}
+	})
+}
@@ -0,0 +1,206 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jmoiron/sqlx"
+	"gopkg.in/guregu/null.v4/zero"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	fingerprintSubmissionTable = "scene_fingerprint_submissions"
+)
+
+type fingerprintSubmissionRow struct {
+	SceneID       int           `db:"scene_id"`
+	Endpoint      string        `db:"endpoint"`
+	StashID       string        `db:"stash_id"`
+	Status        string        `db:"status"`
+	Attempts      int           `db:"attempts"`
+	Error         zero.String   `db:"error"`
+	LastAttemptAt NullTimestamp `db:"last_attempt_at"`
+	CreatedAt     Timestamp     `db:"created_at"`
+	UpdatedAt     Timestamp     `db:"updated_at"`
+}
+
+func (r *fingerprintSubmissionRow) resolve() *models.FingerprintSubmission {
+	return &models.FingerprintSubmission{
+		SceneID:       r.SceneID,
+		Endpoint:      r.Endpoint,
+		StashID:       r.StashID,
+		Status:        models.FingerprintSubmissionStatus(r.Status),
+		Attempts:      r.Attempts,
+		Error:         r.Error.String,
+		LastAttemptAt: r.LastAttemptAt.TimePtr(),
+		CreatedAt:     r.CreatedAt.Timestamp,
+		UpdatedAt:     r.UpdatedAt.Timestamp,
+	}
+}
+
+type FingerprintSubmissionStore struct {
+	repository
+	table exp.IdentifierExpression
+}
+
+func NewFingerprintSubmissionStore() *FingerprintSubmissionStore {
+	return &FingerprintSubmissionStore{
+		repository: repository{
+			tableName: fingerprintSubmissionTable,
+			idColumn:  sceneIDColumn,
+		},
+		table: goqu.T(fingerprintSubmissionTable),
+	}
+}
+
+func (qb *FingerprintSubmissionStore) selectDataset() *goqu.SelectDataset {
+	return dialect.From(qb.table).Select(qb.table.All())
+}
+
+func (qb *FingerprintSubmissionStore) FindBySceneID(ctx context.Context, sceneID int) ([]*models.FingerprintSubmission, error) {
+	q := qb.selectDataset().Where(qb.table.Col(sceneIDColumn).Eq(sceneID)).Order(qb.table.Col("endpoint").Asc())
+	return qb.getMany(ctx, q)
+}
+
+func (qb *FingerprintSubmissionStore) FindPending(ctx context.Context, endpoint string) ([]*models.FingerprintSubmission, error) {
+	q := qb.selectDataset().Where(
+		qb.table.Col("endpoint").Eq(endpoint),
+		qb.table.Col("status").Eq(models.FingerprintSubmissionStatusPending),
+	).Order(qb.table.Col("updated_at").Asc(), qb.table.Col(sceneIDColumn).Asc())
+	return qb.getMany(ctx, q)
+}
+
+func (qb *FingerprintSubmissionStore) Update(ctx context.Context, updatedObject *models.FingerprintSubmission) error {
+	r := goqu.Record{
+		"status":          updatedObject.Status.String(),
+		"attempts":        updatedObject.Attempts,
+		"error":           zero.StringFrom(updatedObject.Error),
+		"last_attempt_at": NullTimestampFromTimePtr(updatedObject.LastAttemptAt),
+		"updated_at":      Timestamp{Timestamp: updatedObject.UpdatedAt},
+	}
+
+	q := dialect.Update(qb.table).Set(r).Where(
+		qb.table.Col(sceneIDColumn).Eq(updatedObject.SceneID),
+		qb.table.Col("endpoint").Eq(updatedObject.Endpoint),
+		qb.table.Col("stash_id").Eq(updatedObject.StashID),
+	)
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("updating fingerprint submission: %w", err)
+	}
+
+	return nil
+}
+
+func (qb *FingerprintSubmissionStore) Requeue(ctx context.Context, sceneIDs []int, endpoint string) error {
+	if len(sceneIDs) == 0 {
+		return nil
+	}
+
+	q := dialect.Update(qb.table).Set(goqu.Record{
+		"status":     models.FingerprintSubmissionStatusPending.String(),
+		"attempts":   0,
+		"error":      nil,
+		"updated_at": Timestamp{Timestamp: time.Now()},
+	}).Where(
+		qb.table.Col(sceneIDColumn).In(sceneIDs),
+		qb.table.Col("endpoint").Eq(endpoint),
+	)
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("requeueing fingerprint submissions: %w", err)
+	}
+
+	_, err := queueFingerprintSubmissions(ctx, scenesStashIDsJoinTable.Col(sceneIDColumn).In(sceneIDs), scenesStashIDsJoinTable.Col("endpoint").Eq(endpoint))
+	return err
+}
+
+func (qb *FingerprintSubmissionStore) QueueUnsubmitted(ctx context.Context, endpoint string) (int, error) {
+	return queueFingerprintSubmissions(ctx, scenesStashIDsJoinTable.Col("endpoint").Eq(endpoint))
+}
+
+func (qb *FingerprintSubmissionStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*models.FingerprintSubmission, error) {
+	const single = false
+	var ret []*models.FingerprintSubmission
+	if err := queryFunc(ctx, q, single, func(r *sqlx.Rows) error {
+		var f fingerprintSubmissionRow
+		if err := r.StructScan(&f); err != nil {
+			return err
+		}
+
+		ret = append(ret, f.resolve())
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// queueFingerprintSubmissions creates pending submissions for the scene
+// stash ids matching the expressions that have no submission. It returns
+// the number of submissions created.
+func queueFingerprintSubmissions(ctx context.Context, where ...exp.Expression) (int, error) {
+	table := goqu.T(fingerprintSubmissionTable)
+	stashIDs := scenesStashIDsJoinTable
+	now := Timestamp{Timestamp: time.Now()}
+
+	existing := dialect.From(table).Select(goqu.L("1")).Where(
+		table.Col(sceneIDColumn).Eq(stashIDs.Col(sceneIDColumn)),
+		table.Col("endpoint").Eq(stashIDs.Col("endpoint")),
+		table.Col("stash_id").Eq(stashIDs.Col("stash_id")),
+	)
+
+	where = append(where,
+		stashIDs.Col("endpoint").IsNotNull(),
+		stashIDs.Col("stash_id").IsNotNull(),
+		goqu.L("NOT EXISTS ?", existing),
+	)
+
+	sq := dialect.From(stashIDs).Select(
+		stashIDs.Col(sceneIDColumn),
+		stashIDs.Col("endpoint"),
+		stashIDs.Col("stash_id"),
+		goqu.V(models.FingerprintSubmissionStatusPending.String()),
+		goqu.V(0),
+		goqu.V(now),
+		goqu.V(now),
+	).Distinct().Where(where...)
+
+	q := dialect.Insert(table).Cols(sceneIDColumn, "endpoint", "stash_id", "status", "attempts", "created_at", "updated_at").FromQuery(sq)
+
+	ret, err := exec(ctx, q)
+	if err != nil {
+		return 0, fmt.Errorf("queueing fingerprint submissions: %w", err)
+	}
+
+	n, err := ret.RowsAffected()
+	return int(n), err
+}
+
+func (qb *FingerprintSubmissionStore) SyncScene(ctx context.Context, sceneID int) error {
+	table := qb.table
+	stashIDs := scenesStashIDsJoinTable
+
+	linked := dialect.From(stashIDs).Select(goqu.L("1")).Where(
+		stashIDs.Col(sceneIDColumn).Eq(table.Col(sceneIDColumn)),
+		stashIDs.Col("endpoint").Eq(table.Col("endpoint")),
+		stashIDs.Col("stash_id").Eq(table.Col("stash_id")),
+	)
+
+	q := dialect.Delete(table).Where(
+		table.Col(sceneIDColumn).Eq(sceneID),
+		goqu.L("NOT EXISTS ?", linked),
+	)
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("removing unlinked fingerprint submissions: %w", err)
+	}
+
+	_, err := queueFingerprintSubmissions(ctx, stashIDs.Col(sceneIDColumn).Eq(sceneID))
+	return err
+}
@@ -0,0 +1,215 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprintSubmissionSyncScene(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.FingerprintSubmission
+		sceneID := sceneIDs[sceneIdxWithMovie]
+		linked := sceneStashID(sceneIdxWithMovie)
+
+		got, err := qb.FindBySceneID(ctx, sceneID)
+		if err != nil {
+			t.Errorf("Error finding fingerprint submissions: %s", err.Error())
+			return nil
+		}
+
+		if assert.Len(t, got, 1) {
+			assert.Equal(t, linked.Endpoint, got[0].Endpoint)
+			assert.Equal(t, linked.StashID, got[0].StashID)
+			assert.Equal(t, models.FingerprintSubmissionStatusPending, got[0].Status)
+		}
+
+		relinked := models.StashID{
+			Endpoint: linked.Endpoint,
+			StashID:  "relinked",
+		}
+
+		if _, err := db.Scene.UpdatePartial(ctx, sceneID, models.ScenePartial{
+			StashIDs: &models.UpdateStashIDs{
+				StashIDs: []models.StashID{relinked},
+				Mode:     models.RelationshipUpdateModeSet,
+			},
+		}); err != nil {
+			t.Errorf("Error updating scene: %s", err.Error())
+			return nil
+		}
+
+		if err := qb.SyncScene(ctx, sceneID); err != nil {
+			t.Errorf("Error syncing fingerprint submissions: %s", err.Error())
+			return nil
+		}
+
+		got, err = qb.FindBySceneID(ctx, sceneID)
+		if err != nil {
+			t.Errorf("Error finding fingerprint submissions: %s", err.Error())
+			return nil
+		}
+
+		// the submission of the removed stash id is removed
+		if assert.Len(t, got, 1) {
+			assert.Equal(t, relinked.StashID, got[0].StashID)
+			assert.Equal(t, models.FingerprintSubmissionStatusPending, got[0].Status)
+		}
+
+		if _, err := db.Scene.UpdatePartial(ctx, sceneID, models.ScenePartial{
+			StashIDs: &models.UpdateStashIDs{
+				Mode: models.RelationshipUpdateModeSet,
+			},
+		}); err != nil {
+			t.Errorf("Error updating scene: %s", err.Error())
+			return nil
+		}
+
+		if err := qb.SyncScene(ctx, sceneID); err != nil {
+			t.Errorf("Error syncing fingerprint submissions: %s", err.Error())
+			return nil
+		}
+
+		got, err = qb.FindBySceneID(ctx, sceneID)
+		if err != nil {
+			t.Errorf("Error finding fingerprint submissions: %s", err.Error())
+			return nil
+		}
+
+		assert.Len(t, got, 0)
+
+		return nil
+	})
+}
+
+func TestFingerprintSubmissionUpdateRequeue(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.FingerprintSubmission
+		sceneID := sceneIDs[sceneIdxWithMovie]
+		endpoint := sceneStashID(sceneIdxWithMovie).Endpoint
+
+		got, err := qb.FindPending(ctx, endpoint)
+		if err != nil {
+			t.Errorf("Error finding pending fingerprint submissions: %s", err.Error())
+			return nil
+		}
+
+		if !assert.Len(t, got, 1) {
+			return nil
+		}
+
+		now := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+		failed := *got[0]
+		failed.Status = models.FingerprintSubmissionStatusFailed
+		failed.Attempts = 5
+		failed.Error = "error"
+		failed.LastAttemptAt = &now
+		failed.UpdatedAt = now
+
+		if err := qb.Update(ctx, &failed); err != nil {
+			t.Errorf("Error updating fingerprint submission: %s", err.Error())
+			return nil
+		}
+
+		got, err = qb.FindBySceneID(ctx, sceneID)
+		if err != nil {
+			t.Errorf("Error finding fingerprint submissions: %s", err.Error())
+			return nil
+		}
+
+		if assert.Len(t, got, 1) {
+			assert.Equal(t, &failed, got[0])
+		}
+
+		pending, err := qb.FindPending(ctx, endpoint)
+		if err != nil {
+			t.Errorf("Error finding pending fingerprint submissions: %s", err.Error())
+			return nil
+		}
+
+		assert.Len(t, pending, 0)
+
+		// already queued scenes are not queued again
+		n, err := qb.QueueUnsubmitted(ctx, endpoint)
+		if err != nil {
+			t.Errorf("Error queueing unsubmitted scenes: %s", err.Error())
+			return nil
+		}
+
+		assert.Equal(t, 0, n)
+
+		if err := qb.Requeue(ctx, []int{sceneID}, endpoint); err != nil {
+			t.Errorf("Error requeueing fingerprint submissions: %s", err.Error())
+			return nil
+		}
+
+		got, err = qb.FindBySceneID(ctx, sceneID)
+		if err != nil {
+			t.Errorf("Error finding fingerprint submissions: %s", err.Error())
+			return nil
+		}
+
+		if assert.Len(t, got, 1) {
+			assert.Equal(t, models.FingerprintSubmissionStatusPending, got[0].Status)
+			assert.Equal(t, 0, got[0].Attempts)
+			assert.Equal(t, "", got[0].Error)
+			assert.Equal(t, &now, got[0].LastAttemptAt)
+		}
+
+		return nil
+	})
+}
+
+func TestSceneQueryFingerprintSubmission(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		sceneID := sceneIDs[sceneIdxWithMovie]
+		endpoint := sceneStashID(sceneIdxWithMovie).Endpoint
+
+		notSubmitted := &models.SceneFilterType{
+			FingerprintSubmission: &models.FingerprintSubmissionCriterionInput{
+				Endpoint: &endpoint,
+				Status:   []models.FingerprintSubmissionStatus{models.FingerprintSubmissionStatusSubmitted},
+				Modifier: models.CriterionModifierExcludes,
+			},
+		}
+
+		scenes := queryScene(ctx, t, db.Scene, notSubmitted, nil)
+		assert.Len(t, scenes, 1)
+		if len(scenes) > 0 {
+			assert.Equal(t, sceneID, scenes[0].ID)
+		}
+
+		got, err := db.FingerprintSubmission.FindBySceneID(ctx, sceneID)
+		if err != nil || len(got) == 0 {
+			t.Errorf("Error finding fingerprint submissions: %v", err)
+			return nil
+		}
+
+		submitted := *got[0]
+		submitted.Status = models.FingerprintSubmissionStatusSubmitted
+		if err := db.FingerprintSubmission.Update(ctx, &submitted); err != nil {
+			t.Errorf("Error updating fingerprint submission: %s", err.Error())
+			return nil
+		}
+
+		scenes = queryScene(ctx, t, db.Scene, notSubmitted, nil)
+		assert.Len(t, scenes, 0)
+
+		scenes = queryScene(ctx, t, db.Scene, &models.SceneFilterType{
+			FingerprintSubmission: &models.FingerprintSubmissionCriterionInput{
+				Endpoint: &endpoint,
+				Status:   []models.FingerprintSubmissionStatus{models.FingerprintSubmissionStatusSubmitted},
+				Modifier: models.CriterionModifierIncludes,
+			},
+		}, nil)
+		assert.Len(t, scenes, 1)
+
+		return nil
+	})
+}
@@ -22,6 +22,7 @@ type folderRow struct {
 	ZipFileID      null.Int        `db:"zip_file_id"`
 	ParentFolderID null.Int        `db:"parent_folder_id"`
 	ModTime        Timestamp       `db:"mod_time"`
+	ChildCount     null.Int        `db:"child_count"`
 	CreatedAt      Timestamp       `db:"created_at"`
 	UpdatedAt      Timestamp       `db:"updated_at"`
 }
@@ -32,6 +33,7 @@ func (r *folderRow) fromFolder(o models.Folder) {
 	r.ZipFileID = nullIntFromFileIDPtr(o.ZipFileID)
 	r.ParentFolderID = nullIntFromFolderIDPtr(o.ParentFolderID)
 	r.ModTime = Timestamp{Timestamp: o.ModTime}
+	r.ChildCount = intFromPtr(o.ChildCount)
 	r.CreatedAt = Timestamp{Timestamp: o.CreatedAt}
 	r.UpdatedAt = Timestamp{Timestamp: o.UpdatedAt}
 }
@@ -52,6 +54,7 @@ func (r *folderQueryRow) resolve() *models.Folder {
 		},
 		Path:           string(r.Path),
 		ParentFolderID: nullIntFolderIDPtr(r.ParentFolderID),
+		ChildCount:     nullIntPtr(r.ChildCount),
 		CreatedAt:      r.CreatedAt.Timestamp,
 		UpdatedAt:      r.UpdatedAt.Timestamp,
 	}
@@ -144,6 +147,7 @@ func (qb *FolderStore) selectDataset() *goqu.SelectDataset {
 		table.Col("zip_file_id"),
 		table.Col("parent_folder_id"),
 		table.Col("mod_time"),
+		table.Col("child_count"),
 		table.Col("created_at"),
 		table.Col("updated_at"),
 		zipFileTable.Col("basename").As("zip_basename"),
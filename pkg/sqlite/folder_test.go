@@ -24,6 +24,7 @@ func Test_FolderStore_Create(t *testing.T) {
 		fileModTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
 		createdAt   = time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
 		updatedAt   = time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+		childCount  = 3
 	)
 
 	tests := []struct {
@@ -39,9 +40,10 @@ func Test_FolderStore_Create(t *testing.T) {
 					ZipFile:   makeZipFileWithID(fileIdxZip),
 					ModTime:   fileModTime,
 				},
-				Path:      path,
-				CreatedAt: createdAt,
-				UpdatedAt: updatedAt,
+				Path:       path,
+				ChildCount: &childCount,
+				CreatedAt:  createdAt,
+				UpdatedAt:  updatedAt,
 			},
 			false,
 		},
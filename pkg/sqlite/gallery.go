@@ -446,6 +446,20 @@ func (qb *GalleryStore) FindByFileID(ctx context.Context, fileID models.FileID)
 	return ret, nil
 }
 
+// FindByFileIDs returns the galleries with any of the given files.
+func (qb *GalleryStore) FindByFileIDs(ctx context.Context, fileIDs []models.FileID) ([]*models.Gallery, error) {
+	sq := dialect.From(galleriesFilesJoinTable).Select(galleriesFilesJoinTable.Col(galleryIDColumn)).Where(
+		galleriesFilesJoinTable.Col(fileIDColumn).In(fileIDs),
+	)
+
+	ret, err := qb.findBySubquery(ctx, sq)
+	if err != nil {
+		return nil, fmt.Errorf("getting galleries by file ids: %w", err)
+	}
+
+	return ret, nil
+}
+
 func (qb *GalleryStore) CountByFileID(ctx context.Context, fileID models.FileID) (int, error) {
 	joinTable := galleriesFilesJoinTable
 
@@ -6,10 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/sliceutil"
+	"github.com/stashapp/stash/pkg/utils"
 	"gopkg.in/guregu/null.v4"
 	"gopkg.in/guregu/null.v4/zero"
 
@@ -455,6 +459,20 @@ func (qb *ImageStore) FindByFileID(ctx context.Context, fileID models.FileID) ([
 	return ret, nil
 }
 
+// FindByFileIDs returns the images with any of the given files.
+func (qb *ImageStore) FindByFileIDs(ctx context.Context, fileIDs []models.FileID) ([]*models.Image, error) {
+	sq := dialect.From(imagesFilesJoinTable).Select(imagesFilesJoinTable.Col(imageIDColumn)).Where(
+		imagesFilesJoinTable.Col(fileIDColumn).In(fileIDs),
+	)
+
+	ret, err := qb.findBySubquery(ctx, sq)
+	if err != nil {
+		return nil, fmt.Errorf("getting images by file ids: %w", err)
+	}
+
+	return ret, nil
+}
+
 func (qb *ImageStore) CountByFileID(ctx context.Context, fileID models.FileID) (int, error) {
 	joinTable := imagesFilesJoinTable
 
@@ -613,6 +631,96 @@ func (qb *ImageStore) FindByZipFileID(ctx context.Context, zipFileID models.File
 	return ret, nil
 }
 
+var findExactDuplicateImagesQuery = `
+SELECT GROUP_CONCAT(DISTINCT images_files.image_id) as ids
+FROM images_files
+INNER JOIN files ON (images_files.file_id = files.id)
+INNER JOIN files_fingerprints ON (images_files.file_id = files_fingerprints.file_id AND files_fingerprints.type = 'phash')
+GROUP BY files_fingerprints.fingerprint
+HAVING COUNT(DISTINCT images_files.image_id) > 1
+ORDER BY SUM(files.size) DESC;
+`
+
+var findAllImagePhashesQuery = `
+SELECT images_files.image_id as id
+    , files_fingerprints.fingerprint as phash
+FROM images_files
+INNER JOIN files ON (images_files.file_id = files.id)
+INNER JOIN files_fingerprints ON (images_files.file_id = files_fingerprints.file_id AND files_fingerprints.type = 'phash')
+ORDER BY files.size DESC;
+`
+
+// FindDuplicates returns groups of images with files that have a phash within the given distance.
+func (qb *ImageStore) FindDuplicates(ctx context.Context, distance int) ([][]*models.Image, error) {
+	var dupeIds [][]int
+	if distance == 0 {
+		var ids []string
+		if err := qb.tx.Select(ctx, &ids, findExactDuplicateImagesQuery); err != nil {
+			return nil, err
+		}
+
+		for _, id := range ids {
+			var imageIds []int
+			for _, strId := range strings.Split(id, ",") {
+				if intId, err := strconv.Atoi(strId); err == nil {
+					imageIds = sliceutil.AppendUnique(imageIds, intId)
+				}
+			}
+
+			if len(imageIds) > 1 {
+				dupeIds = append(dupeIds, imageIds)
+			}
+		}
+	} else {
+		var hashes []*utils.Phash
+
+		if err := qb.queryFunc(ctx, findAllImagePhashesQuery, nil, false, func(rows *sqlx.Rows) error {
+			phash := utils.Phash{
+				Bucket:   -1,
+				Duration: -1,
+			}
+			if err := rows.StructScan(&phash); err != nil {
+				return err
+			}
+
+			hashes = append(hashes, &phash)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+
+		// images have no duration, so don't compare them
+		const durationDiff = -1
+		dupeIds = utils.FindDuplicates(hashes, distance, durationDiff)
+	}
+
+	var duplicates [][]*models.Image
+	for _, imageIds := range dupeIds {
+		images, err := qb.FindMany(ctx, imageIds)
+		if err != nil {
+			return nil, err
+		}
+
+		duplicates = append(duplicates, images)
+	}
+
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		return getFirstImagePath(duplicates[i]) < getFirstImagePath(duplicates[j])
+	})
+
+	return duplicates, nil
+}
+
+func getFirstImagePath(images []*models.Image) string {
+	var firstPath string
+	for i, image := range images {
+		if i == 0 || image.Path < firstPath {
+			firstPath = image.Path
+		}
+	}
+	return firstPath
+}
+
 func (qb *ImageStore) Count(ctx context.Context) (int, error) {
 	q := dialect.Select(goqu.COUNT("*")).From(qb.table())
 	return count(ctx, q)
@@ -695,6 +803,10 @@ func (qb *ImageStore) makeFilter(ctx context.Context, imageFilter *models.ImageF
 
 		stringCriterionHandler(imageFilter.Checksum, "fingerprints_md5.fingerprint")(ctx, f)
 	}))
+	query.handleCriterion(ctx, phashDistanceCriterionHandler(imageFilter.PhashDistance, func(f *filterBuilder) {
+		qb.addImagesFilesTable(f)
+		f.addLeftJoin(fingerprintTable, "fingerprints_phash", "images_files.file_id = fingerprints_phash.file_id AND fingerprints_phash.type = 'phash'")
+	}))
 	query.handleCriterion(ctx, stringCriterionHandler(imageFilter.Title, "images.title"))
 	query.handleCriterion(ctx, stringCriterionHandler(imageFilter.Code, "images.code"))
 	query.handleCriterion(ctx, stringCriterionHandler(imageFilter.Details, "images.details"))
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -3013,3 +3014,100 @@ func TestImageQueryPagination(t *testing.T) {
 // TODO Count
 // TODO SizeCount
 // TODO All
+
+func setImagePhashes(ctx context.Context, t *testing.T, phashes map[int]int64) bool {
+	for idx, phash := range phashes {
+		if err := db.File.ModifyFingerprints(ctx, imageFileIDs[idx], []models.Fingerprint{
+			{
+				Type:        models.FingerprintTypePhash,
+				Fingerprint: phash,
+			},
+		}); err != nil {
+			t.Errorf("FileStore.ModifyFingerprints() error = %v", err)
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestImageQueryPhashDistance(t *testing.T) {
+	const (
+		phash     = int64(0x0f0f)
+		nearPhash = int64(0x0f0e)
+	)
+
+	runWithRollbackTxn(t, "phash distance", func(t *testing.T, ctx context.Context) {
+		assert := assert.New(t)
+
+		if !setImagePhashes(ctx, t, map[int]int64{
+			imageIdxWithGallery:    phash,
+			imageIdx1WithPerformer: nearPhash,
+		}) {
+			return
+		}
+
+		// matches phashes with a distance less than this
+		distance := 2
+		images := queryImages(ctx, t, db.Image, &models.ImageFilterType{
+			PhashDistance: &models.PhashDistanceCriterionInput{
+				Value:    utils.PhashToString(phash),
+				Modifier: models.CriterionModifierEquals,
+			},
+		}, nil)
+
+		if assert.Len(images, 1) {
+			assert.Equal(imageIDs[imageIdxWithGallery], images[0].ID)
+		}
+
+		images = queryImages(ctx, t, db.Image, &models.ImageFilterType{
+			PhashDistance: &models.PhashDistanceCriterionInput{
+				Value:    utils.PhashToString(phash),
+				Modifier: models.CriterionModifierEquals,
+				Distance: &distance,
+			},
+		}, nil)
+
+		var ids []int
+		for _, i := range images {
+			ids = append(ids, i.ID)
+		}
+		assert.ElementsMatch([]int{imageIDs[imageIdxWithGallery], imageIDs[imageIdx1WithPerformer]}, ids)
+	})
+}
+
+func TestImageStore_FindDuplicates(t *testing.T) {
+	qb := db.Image
+
+	runWithRollbackTxn(t, "find duplicates", func(t *testing.T, ctx context.Context) {
+		assert := assert.New(t)
+
+		if !setImagePhashes(ctx, t, map[int]int64{
+			imageIdxWithGallery:    0x0f0f,
+			imageIdx1WithPerformer: 0x0f0f,
+			imageIdxWithTag:        0x0f0e,
+		}) {
+			return
+		}
+
+		got, err := qb.FindDuplicates(ctx, 0)
+		if err != nil {
+			t.Errorf("ImageStore.FindDuplicates() error = %v", err)
+			return
+		}
+
+		if assert.Len(got, 1) {
+			assert.Len(got[0], 2)
+		}
+
+		got, err = qb.FindDuplicates(ctx, 1)
+		if err != nil {
+			t.Errorf("ImageStore.FindDuplicates() error = %v", err)
+			return
+		}
+
+		if assert.Len(got, 1) {
+			assert.Len(got[0], 3)
+		}
+	})
+}
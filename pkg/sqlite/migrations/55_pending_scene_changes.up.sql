@@ -0,0 +1,10 @@
+CREATE TABLE `pending_scene_changes` (
+  `id` integer not null primary key autoincrement,
+  `scene_id` integer not null,
+  `source` varchar(255) not null,
+  `data` text not null,
+  `created_at` datetime not null,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE
+);
+
+CREATE UNIQUE INDEX `index_pending_scene_changes_on_scene_id` on `pending_scene_changes` (`scene_id`);
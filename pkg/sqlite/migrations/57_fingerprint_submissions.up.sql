@@ -0,0 +1,16 @@
+CREATE TABLE `scene_fingerprint_submissions` (
+  `scene_id` integer not null,
+  `endpoint` varchar(255) not null,
+  `stash_id` varchar(36) not null,
+  `status` varchar(255) not null,
+  `attempts` integer not null default 0,
+  `error` text,
+  `last_attempt_at` datetime,
+  `created_at` datetime not null,
+  `updated_at` datetime not null,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE,
+  PRIMARY KEY(`scene_id`, `endpoint`, `stash_id`)
+);
+
+CREATE INDEX `index_scene_fingerprint_submissions_on_endpoint_status` on `scene_fingerprint_submissions` (`endpoint`, `status`);
+
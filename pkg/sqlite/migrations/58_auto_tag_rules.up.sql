@@ -0,0 +1,3 @@
+ALTER TABLE `performers` ADD COLUMN `auto_tag_rules` text;
+ALTER TABLE `studios` ADD COLUMN `auto_tag_rules` text;
+ALTER TABLE `tags` ADD COLUMN `auto_tag_rules` text;
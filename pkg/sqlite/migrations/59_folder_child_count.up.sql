@@ -0,0 +1 @@
+ALTER TABLE `folders` ADD COLUMN `child_count` integer;
@@ -0,0 +1,205 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil"
+)
+
+const (
+	pendingSceneChangeTable = "pending_scene_changes"
+)
+
+type pendingSceneChangeRow struct {
+	ID        int       `db:"id" goqu:"skipinsert"`
+	SceneID   int       `db:"scene_id"`
+	Source    string    `db:"source"`
+	Data      string    `db:"data"`
+	CreatedAt Timestamp `db:"created_at"`
+}
+
+func (r *pendingSceneChangeRow) fromPendingSceneChange(o models.PendingSceneChange) {
+	r.ID = o.ID
+	r.SceneID = o.SceneID
+	r.Source = o.Source
+	r.Data = o.Data
+	r.CreatedAt = Timestamp{Timestamp: o.CreatedAt}
+}
+
+func (r *pendingSceneChangeRow) resolve() *models.PendingSceneChange {
+	return &models.PendingSceneChange{
+		ID:        r.ID,
+		SceneID:   r.SceneID,
+		Source:    r.Source,
+		Data:      r.Data,
+		CreatedAt: r.CreatedAt.Timestamp,
+	}
+}
+
+type PendingSceneChangeStore struct {
+	repository
+	tableMgr *table
+}
+
+func NewPendingSceneChangeStore() *PendingSceneChangeStore {
+	return &PendingSceneChangeStore{
+		repository: repository{
+			tableName: pendingSceneChangeTable,
+			idColumn:  idColumn,
+		},
+		tableMgr: pendingSceneChangeTableMgr,
+	}
+}
+
+func (qb *PendingSceneChangeStore) table() exp.IdentifierExpression {
+	return qb.tableMgr.table
+}
+
+func (qb *PendingSceneChangeStore) selectDataset() *goqu.SelectDataset {
+	return dialect.From(qb.table()).Select(qb.table().All())
+}
+
+func (qb *PendingSceneChangeStore) Create(ctx context.Context, newObject *models.PendingSceneChange) error {
+	// replace any existing pending change for the scene
+	q := dialect.Delete(qb.table()).Where(qb.table().Col(sceneIDColumn).Eq(newObject.SceneID))
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("destroying existing pending change: %w", err)
+	}
+
+	var r pendingSceneChangeRow
+	r.fromPendingSceneChange(*newObject)
+
+	id, err := qb.tableMgr.insertID(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	updated, err := qb.find(ctx, id)
+	if err != nil {
+		return fmt.Errorf("finding after create: %w", err)
+	}
+
+	*newObject = *updated
+
+	return nil
+}
+
+func (qb *PendingSceneChangeStore) Destroy(ctx context.Context, ids []int) error {
+	return qb.tableMgr.destroyExisting(ctx, ids)
+}
+
+// returns nil, nil if not found
+func (qb *PendingSceneChangeStore) Find(ctx context.Context, id int) (*models.PendingSceneChange, error) {
+	ret, err := qb.find(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return ret, err
+}
+
+func (qb *PendingSceneChangeStore) FindMany(ctx context.Context, ids []int) ([]*models.PendingSceneChange, error) {
+	ret := make([]*models.PendingSceneChange, len(ids))
+
+	q := qb.selectDataset().Prepared(true).Where(qb.table().Col(idColumn).In(ids))
+	unsorted, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range unsorted {
+		i := sliceutil.Index(ids, s.ID)
+		ret[i] = s
+	}
+
+	for i := range ret {
+		if ret[i] == nil {
+			return nil, fmt.Errorf("pending scene change with id %d not found", ids[i])
+		}
+	}
+
+	return ret, nil
+}
+
+// returns nil, nil if not found
+func (qb *PendingSceneChangeStore) FindBySceneID(ctx context.Context, sceneID int) (*models.PendingSceneChange, error) {
+	q := qb.selectDataset().Where(qb.table().Col(sceneIDColumn).Eq(sceneID))
+
+	ret, err := qb.get(ctx, q)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return ret, err
+}
+
+// Query returns pending scene changes ordered by creation time, oldest first.
+func (qb *PendingSceneChangeStore) Query(ctx context.Context, findFilter *models.FindFilterType) ([]*models.PendingSceneChange, int, error) {
+	if findFilter == nil {
+		findFilter = &models.FindFilterType{}
+	}
+
+	table := qb.table()
+
+	total, err := count(ctx, dialect.From(table).Select(goqu.COUNT("*")))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	q := qb.selectDataset().Order(table.Col("created_at").Asc(), table.Col(idColumn).Asc())
+	if !findFilter.IsGetAll() {
+		pageSize := findFilter.GetPageSize()
+		q = q.Limit(uint(pageSize)).Offset(uint((findFilter.GetPage() - 1) * pageSize))
+	}
+
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return ret, total, nil
+}
+
+// returns nil, sql.ErrNoRows if not found
+func (qb *PendingSceneChangeStore) find(ctx context.Context, id int) (*models.PendingSceneChange, error) {
+	q := qb.selectDataset().Where(qb.tableMgr.byID(id))
+
+	return qb.get(ctx, q)
+}
+
+func (qb *PendingSceneChangeStore) get(ctx context.Context, q *goqu.SelectDataset) (*models.PendingSceneChange, error) {
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return ret[0], nil
+}
+
+func (qb *PendingSceneChangeStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*models.PendingSceneChange, error) {
+	const single = false
+	var ret []*models.PendingSceneChange
+	if err := queryFunc(ctx, q, single, func(r *sqlx.Rows) error {
+		var f pendingSceneChangeRow
+		if err := r.StructScan(&f); err != nil {
+			return err
+		}
+
+		ret = append(ret, f.resolve())
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,121 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPendingSceneChangeCreate(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.PendingSceneChange
+		sceneID := sceneIDs[sceneIdxWithMovie]
+
+		first := models.PendingSceneChange{
+			SceneID:   sceneID,
+			Source:    "first",
+			Data:      `{"fields": []}`,
+			CreatedAt: time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+
+		if err := qb.Create(ctx, &first); err != nil {
+			t.Errorf("Error creating pending scene change: %s", err.Error())
+			return nil
+		}
+
+		found, err := qb.Find(ctx, first.ID)
+		if err != nil {
+			t.Errorf("Error finding pending scene change: %s", err.Error())
+			return nil
+		}
+
+		assert.Equal(t, &first, found)
+
+		// creating a second change for the same scene replaces the first
+		second := first
+		second.ID = 0
+		second.Source = "second"
+
+		if err := qb.Create(ctx, &second); err != nil {
+			t.Errorf("Error creating pending scene change: %s", err.Error())
+			return nil
+		}
+
+		found, err = qb.Find(ctx, first.ID)
+		if err != nil {
+			t.Errorf("Error finding pending scene change: %s", err.Error())
+			return nil
+		}
+		assert.Nil(t, found)
+
+		found, err = qb.FindBySceneID(ctx, sceneID)
+		if err != nil {
+			t.Errorf("Error finding pending scene change: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, &second, found)
+
+		return nil
+	})
+}
+
+func TestPendingSceneChangeQuery(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.PendingSceneChange
+
+		var ids []int
+		for i, idx := range []int{sceneIdxWithMovie, sceneIdxWithGallery, sceneIdxWithPerformer} {
+			c := models.PendingSceneChange{
+				SceneID:   sceneIDs[idx],
+				Source:    "source",
+				Data:      "{}",
+				CreatedAt: time.Date(2001, 1, i+1, 0, 0, 0, 0, time.UTC),
+			}
+
+			if err := qb.Create(ctx, &c); err != nil {
+				t.Errorf("Error creating pending scene change: %s", err.Error())
+				return nil
+			}
+
+			ids = append(ids, c.ID)
+		}
+
+		page := 2
+		perPage := 2
+		changes, count, err := qb.Query(ctx, &models.FindFilterType{
+			Page:    &page,
+			PerPage: &perPage,
+		})
+		if err != nil {
+			t.Errorf("Error querying pending scene changes: %s", err.Error())
+			return nil
+		}
+
+		assert.Equal(t, 3, count)
+		if assert.Len(t, changes, 1) {
+			assert.Equal(t, ids[2], changes[0].ID)
+		}
+
+		if err := qb.Destroy(ctx, ids[:2]); err != nil {
+			t.Errorf("Error destroying pending scene changes: %s", err.Error())
+			return nil
+		}
+
+		changes, count, err = qb.Query(ctx, nil)
+		if err != nil {
+			t.Errorf("Error querying pending scene changes: %s", err.Error())
+			return nil
+		}
+
+		assert.Equal(t, 1, count)
+		assert.Len(t, changes, 1)
+
+		return nil
+	})
+}
@@ -58,6 +58,7 @@ type performerRow struct {
 	HairColor     zero.String `db:"hair_color"`
 	Weight        null.Int    `db:"weight"`
 	IgnoreAutoTag bool        `db:"ignore_auto_tag"`
+	AutoTagRules  zero.String `db:"auto_tag_rules"`
 
 	// not used in resolution or updates
 	ImageBlob zero.String `db:"image_blob"`
@@ -96,6 +97,7 @@ func (r *performerRow) fromPerformer(o models.Performer) {
 	r.HairColor = zero.StringFrom(o.HairColor)
 	r.Weight = intFromPtr(o.Weight)
 	r.IgnoreAutoTag = o.IgnoreAutoTag
+	r.AutoTagRules = autoTagRulesToJSON(o.AutoTagRules)
 }
 
 func (r *performerRow) resolve() *models.Performer {
@@ -127,6 +129,7 @@ func (r *performerRow) resolve() *models.Performer {
 		HairColor:     r.HairColor.String,
 		Weight:        nullIntPtr(r.Weight),
 		IgnoreAutoTag: r.IgnoreAutoTag,
+		AutoTagRules:  autoTagRulesFromJSON(r.AutoTagRules),
 	}
 
 	if r.Gender.ValueOrZero() != "" {
@@ -174,6 +177,7 @@ func (r *performerRowRecord) fromPartial(o models.PerformerPartial) {
 	r.setNullString("hair_color", o.HairColor)
 	r.setNullInt("weight", o.Weight)
 	r.setBool("ignore_auto_tag", o.IgnoreAutoTag)
+	r.setAutoTagRules("auto_tag_rules", o.AutoTagRules)
 }
 
 type PerformerStore struct {
@@ -511,6 +515,9 @@ func (qb *PerformerStore) QueryForAutoTag(ctx context.Context, words []string) (
 		// whereClauses = append(whereClauses, performersAliasesJoinTable.Col("alias").Like(w+"%"))
 	}
 
+	// objects with auto-tag rules may match regardless of their name
+	whereClauses = append(whereClauses, table.Col("auto_tag_rules").IsNotNull())
+
 	sq = sq.Where(
 		goqu.Or(whereClauses...),
 		table.Col("ignore_auto_tag").Eq(0),
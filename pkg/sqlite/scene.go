@@ -17,6 +17,7 @@ import (
 	"gopkg.in/guregu/null.v4"
 	"gopkg.in/guregu/null.v4/zero"
 
+	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/sliceutil"
 	"github.com/stashapp/stash/pkg/utils"
@@ -71,6 +72,13 @@ INNER JOIN video_files ON (files.id == video_files.file_id)
 ORDER BY files.size DESC;
 `
 
+var findAllPhashSegmentsQuery = `
+SELECT scenes_files.scene_id as id
+    , files_fingerprints.fingerprint as segments
+FROM scenes_files
+INNER JOIN files_fingerprints ON (scenes_files.file_id = files_fingerprints.file_id AND files_fingerprints.type = 'phash_segments')
+`
+
 type sceneRow struct {
 	ID       int         `db:"id" goqu:"skipinsert"`
 	Title    zero.String `db:"title"`
@@ -579,6 +587,20 @@ func (qb *SceneStore) FindByFileID(ctx context.Context, fileID models.FileID) ([
 	return ret, nil
 }
 
+// FindByFileIDs returns the scenes with any of the given files.
+func (qb *SceneStore) FindByFileIDs(ctx context.Context, fileIDs []models.FileID) ([]*models.Scene, error) {
+	sq := dialect.From(scenesFilesJoinTable).Select(scenesFilesJoinTable.Col(sceneIDColumn)).Where(
+		scenesFilesJoinTable.Col(fileIDColumn).In(fileIDs),
+	)
+
+	ret, err := qb.findBySubquery(ctx, sq)
+	if err != nil {
+		return nil, fmt.Errorf("getting scenes by file ids: %w", err)
+	}
+
+	return ret, nil
+}
+
 func (qb *SceneStore) FindByPrimaryFileID(ctx context.Context, fileID models.FileID) ([]*models.Scene, error) {
 	sq := dialect.From(scenesFilesJoinTable).Select(scenesFilesJoinTable.Col(sceneIDColumn)).Where(
 		scenesFilesJoinTable.Col(fileIDColumn).Eq(fileID),
@@ -1003,6 +1025,7 @@ func (qb *SceneStore) makeFilter(ctx context.Context, sceneFilter *models.SceneF
 		stashIDTableAs:    "scene_stash_ids",
 		parentIDCol:       "scenes.id",
 	})
+	query.handleCriterion(ctx, sceneFingerprintSubmissionCriterionHandler(sceneFilter.FingerprintSubmission))
 
 	query.handleCriterion(ctx, boolCriterionHandler(sceneFilter.Interactive, "video_files.interactive", qb.addVideoFilesTable))
 	query.handleCriterion(ctx, intCriterionHandler(sceneFilter.InteractiveSpeed, "video_files.interactive_speed", qb.addVideoFilesTable))
@@ -1030,6 +1053,48 @@ func (qb *SceneStore) makeFilter(ctx context.Context, sceneFilter *models.SceneF
 	return query
 }
 
+func sceneFingerprintSubmissionCriterionHandler(c *models.FingerprintSubmissionCriterionInput) criterionHandlerFunc {
+	return func(ctx context.Context, f *filterBuilder) {
+		if c == nil {
+			return
+		}
+
+		var endpointClause string
+		var endpointArgs []interface{}
+		if c.Endpoint != nil && *c.Endpoint != "" {
+			endpointClause = " AND endpoint = ?"
+			endpointArgs = append(endpointArgs, *c.Endpoint)
+		}
+
+		linked := "EXISTS (SELECT 1 FROM scene_stash_ids WHERE scene_stash_ids.scene_id = scenes.id" + endpointClause + ")"
+		submissions := "SELECT 1 FROM " + fingerprintSubmissionTable + " WHERE " + fingerprintSubmissionTable + ".scene_id = scenes.id" + endpointClause
+		submissionArgs := append([]interface{}{}, endpointArgs...)
+
+		switch c.Modifier {
+		case models.CriterionModifierIncludes, models.CriterionModifierExcludes:
+			if len(c.Status) == 0 {
+				return
+			}
+
+			submissions += " AND status IN " + getInBinding(len(c.Status))
+			for _, s := range c.Status {
+				submissionArgs = append(submissionArgs, s.String())
+			}
+		case models.CriterionModifierIsNull, models.CriterionModifierNotNull:
+		default:
+			f.setError(fmt.Errorf("invalid fingerprint submission modifier: %s", c.Modifier))
+			return
+		}
+
+		exists := "EXISTS (" + submissions + ")"
+		if c.Modifier == models.CriterionModifierExcludes || c.Modifier == models.CriterionModifierIsNull {
+			exists = "NOT " + exists
+		}
+
+		f.addWhere(linked+" AND "+exists, append(endpointArgs, submissionArgs...)...)
+	}
+}
+
 func (qb *SceneStore) addSceneFilesTable(f *filterBuilder) {
 	f.addLeftJoin(scenesFilesTable, "", "scenes_files.scene_id = scenes.id")
 }
@@ -1470,42 +1535,10 @@ func scenePerformerTagsCriterionHandler(qb *SceneStore, tags *models.Hierarchica
 }
 
 func scenePhashDistanceCriterionHandler(qb *SceneStore, phashDistance *models.PhashDistanceCriterionInput) criterionHandlerFunc {
-	return func(ctx context.Context, f *filterBuilder) {
-		if phashDistance != nil {
-			qb.addSceneFilesTable(f)
-			f.addLeftJoin(fingerprintTable, "fingerprints_phash", "scenes_files.file_id = fingerprints_phash.file_id AND fingerprints_phash.type = 'phash'")
-
-			value, _ := utils.StringToPhash(phashDistance.Value)
-			distance := 0
-			if phashDistance.Distance != nil {
-				distance = *phashDistance.Distance
-			}
-
-			if distance == 0 {
-				// use the default handler
-				intCriterionHandler(&models.IntCriterionInput{
-					Value:    int(value),
-					Modifier: phashDistance.Modifier,
-				}, "fingerprints_phash.fingerprint", nil)(ctx, f)
-			}
-
-			switch {
-			case phashDistance.Modifier == models.CriterionModifierEquals && distance > 0:
-				// needed to avoid a type mismatch
-				f.addWhere("typeof(fingerprints_phash.fingerprint) = 'integer'")
-				f.addWhere("phash_distance(fingerprints_phash.fingerprint, ?) < ?", value, distance)
-			case phashDistance.Modifier == models.CriterionModifierNotEquals && distance > 0:
-				// needed to avoid a type mismatch
-				f.addWhere("typeof(fingerprints_phash.fingerprint) = 'integer'")
-				f.addWhere("phash_distance(fingerprints_phash.fingerprint, ?) > ?", value, distance)
-			default:
-				intCriterionHandler(&models.IntCriterionInput{
-					Value:    int(value),
-					Modifier: phashDistance.Modifier,
-				}, "fingerprints_phash.fingerprint", nil)(ctx, f)
-			}
-		}
-	}
+	return phashDistanceCriterionHandler(phashDistance, func(f *filterBuilder) {
+		qb.addSceneFilesTable(f)
+		f.addLeftJoin(fingerprintTable, "fingerprints_phash", "scenes_files.file_id = fingerprints_phash.file_id AND fingerprints_phash.type = 'phash'")
+	})
 }
 
 func (qb *SceneStore) setSceneSort(query *queryBuilder, findFilter *models.FindFilterType) {
@@ -1862,6 +1895,51 @@ func (qb *SceneStore) FindDuplicates(ctx context.Context, distance int, duration
 	return duplicates, nil
 }
 
+// FindSegmentOverlaps returns the parts of scenes of at least minOverlap seconds that are found in other scenes,
+// using the phash segments of the scene files.
+func (qb *SceneStore) FindSegmentOverlaps(ctx context.Context, distance int, minOverlap float64) ([]*models.SceneOverlap, error) {
+	var entries []utils.PhashSegmentsEntry
+
+	if err := qb.queryFunc(ctx, findAllPhashSegmentsQuery, nil, false, func(rows *sqlx.Rows) error {
+		var (
+			id       int
+			segments string
+		)
+		if err := rows.Scan(&id, &segments); err != nil {
+			return err
+		}
+
+		s, err := utils.ParsePhashSegments(segments)
+		if err != nil {
+			logger.Warnf("Invalid phash segments for scene %d: %v", id, err)
+			return nil
+		}
+
+		entries = append(entries, utils.PhashSegmentsEntry{
+			ID:       id,
+			Segments: *s,
+		})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	overlaps := utils.FindSegmentOverlaps(entries, distance, minOverlap)
+
+	ret := make([]*models.SceneOverlap, len(overlaps))
+	for i, o := range overlaps {
+		ret[i] = &models.SceneOverlap{
+			SceneID:      o.ID,
+			OtherSceneID: o.OtherID,
+			SceneStart:   o.Start,
+			OtherStart:   o.OtherStart,
+			Duration:     o.Duration,
+		}
+	}
+
+	return ret, nil
+}
+
 func sortByPath(scenes [][]*models.Scene) {
 	lessFunc := func(i int, j int) bool {
 		firstPathI := getFirstPath(scenes[i])
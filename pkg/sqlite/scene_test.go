@@ -16,6 +16,7 @@ import (
 
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/sliceutil"
+	"github.com/stashapp/stash/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -1936,6 +1937,25 @@ func Test_sceneStore_FindByFileID(t *testing.T) {
 	}
 }
 
+func Test_sceneStore_FindByFileIDs(t *testing.T) {
+	qb := db.Scene
+
+	runWithRollbackTxn(t, "valid and invalid", func(t *testing.T, ctx context.Context) {
+		assert := assert.New(t)
+		got, err := qb.FindByFileIDs(ctx, []models.FileID{
+			sceneFileIDs[sceneIdx1WithPerformer],
+			sceneFileIDs[sceneIdxWithGallery],
+			invalidFileID,
+		})
+		if err != nil {
+			t.Errorf("SceneStore.FindByFileIDs() error = %v", err)
+			return
+		}
+
+		assert.ElementsMatch(indexesToIDs(sceneIDs, []int{sceneIdx1WithPerformer, sceneIdxWithGallery}), scenesToIDs(got))
+	})
+}
+
 func Test_sceneStore_CountByFileID(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -4509,6 +4529,62 @@ func TestSceneStore_FindDuplicates(t *testing.T) {
 	})
 }
 
+func TestSceneStore_FindSegmentOverlaps(t *testing.T) {
+	qb := db.Scene
+
+	const interval = 5.
+
+	full := make([]uint64, 20)
+	for i := range full {
+		full[i] = uint64(i+1) * 0x0101010101010101
+	}
+
+	// sub-clip of the full scene, starting at the fifth segment
+	clip := append([]uint64{}, full[4:14]...)
+
+	segments := map[int]utils.PhashSegments{
+		sceneIdxWithGallery:    {Interval: interval, Hashes: full},
+		sceneIdx1WithPerformer: {Interval: interval, Hashes: clip},
+	}
+
+	runWithRollbackTxn(t, "find segment overlaps", func(t *testing.T, ctx context.Context) {
+		assert := assert.New(t)
+
+		for idx, s := range segments {
+			if err := db.File.ModifyFingerprints(ctx, sceneFileIDs[idx], []models.Fingerprint{
+				{
+					Type:        models.FingerprintTypePhashSegments,
+					Fingerprint: s.String(),
+				},
+			}); err != nil {
+				t.Errorf("FileStore.ModifyFingerprints() error = %v", err)
+				return
+			}
+		}
+
+		got, err := qb.FindSegmentOverlaps(ctx, 0, 30)
+		if err != nil {
+			t.Errorf("SceneStore.FindSegmentOverlaps() error = %v", err)
+			return
+		}
+
+		if assert.Len(got, 1) {
+			assert.Equal(sceneIDs[sceneIdx1WithPerformer], got[0].SceneID)
+			assert.Equal(sceneIDs[sceneIdxWithGallery], got[0].OtherSceneID)
+			assert.Equal(10*interval, got[0].Duration)
+			assert.Equal(4*interval, got[0].Offset())
+		}
+
+		got, err = qb.FindSegmentOverlaps(ctx, 0, 60)
+		if err != nil {
+			t.Errorf("SceneStore.FindSegmentOverlaps() error = %v", err)
+			return
+		}
+
+		assert.Len(got, 0)
+	})
+}
+
 func TestSceneStore_AssignFiles(t *testing.T) {
 	tests := []struct {
 		name    string
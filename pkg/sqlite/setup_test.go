@@ -1107,6 +1107,10 @@ func createScenes(ctx context.Context, n int) error {
 			return fmt.Errorf("Error creating scene %v+: %s", scene, err.Error())
 		}
 
+		if err := db.FingerprintSubmission.SyncScene(ctx, scene.ID); err != nil {
+			return fmt.Errorf("Error queueing fingerprint submissions of scene %d: %s", scene.ID, err.Error())
+		}
+
 		sceneIDs = append(sceneIDs, scene.ID)
 	}
 
@@ -38,6 +38,7 @@ type studioRow struct {
 	Rating        null.Int    `db:"rating"`
 	Details       zero.String `db:"details"`
 	IgnoreAutoTag bool        `db:"ignore_auto_tag"`
+	AutoTagRules  zero.String `db:"auto_tag_rules"`
 
 	// not used in resolutions or updates
 	ImageBlob zero.String `db:"image_blob"`
@@ -53,6 +54,7 @@ func (r *studioRow) fromStudio(o models.Studio) {
 	r.Rating = intFromPtr(o.Rating)
 	r.Details = zero.StringFrom(o.Details)
 	r.IgnoreAutoTag = o.IgnoreAutoTag
+	r.AutoTagRules = autoTagRulesToJSON(o.AutoTagRules)
 }
 
 func (r *studioRow) resolve() *models.Studio {
@@ -66,6 +68,7 @@ func (r *studioRow) resolve() *models.Studio {
 		Rating:        nullIntPtr(r.Rating),
 		Details:       r.Details.String,
 		IgnoreAutoTag: r.IgnoreAutoTag,
+		AutoTagRules:  autoTagRulesFromJSON(r.AutoTagRules),
 	}
 
 	return ret
@@ -84,6 +87,7 @@ func (r *studioRowRecord) fromPartial(o models.StudioPartial) {
 	r.setNullInt("rating", o.Rating)
 	r.setNullString("details", o.Details)
 	r.setBool("ignore_auto_tag", o.IgnoreAutoTag)
+	r.setAutoTagRules("auto_tag_rules", o.AutoTagRules)
 }
 
 type StudioStore struct {
@@ -434,6 +438,9 @@ func (qb *StudioStore) QueryForAutoTag(ctx context.Context, words []string) ([]*
 		whereClauses = append(whereClauses, studiosAliasesJoinTable.Col("alias").Like(w+"%"))
 	}
 
+	// objects with auto-tag rules may match regardless of their name
+	whereClauses = append(whereClauses, table.Col("auto_tag_rules").IsNotNull())
+
 	sq = sq.Where(
 		goqu.Or(whereClauses...),
 		table.Col("ignore_auto_tag").Eq(0),
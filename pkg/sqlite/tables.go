@@ -34,6 +34,8 @@ var (
 
 	studiosAliasesJoinTable  = goqu.T(studioAliasesTable)
 	studiosStashIDsJoinTable = goqu.T("studio_stash_ids")
+
+	tagsStashIDsJoinTable = goqu.T("tag_stash_ids")
 )
 
 var (
@@ -276,6 +278,13 @@ var (
 		table:    goqu.T(tagTable),
 		idColumn: goqu.T(tagTable).Col(idColumn),
 	}
+
+	tagsStashIDsTableMgr = &stashIDTable{
+		table: table{
+			table:    tagsStashIDsJoinTable,
+			idColumn: tagsStashIDsJoinTable.Col(tagIDColumn),
+		},
+	}
 )
 
 var (
@@ -298,3 +307,10 @@ var (
 		idColumn: goqu.T(savedFilterTable).Col(idColumn),
 	}
 )
+
+var (
+	pendingSceneChangeTableMgr = &table{
+		table:    goqu.T(pendingSceneChangeTable),
+		idColumn: goqu.T(pendingSceneChangeTable).Col(idColumn),
+	}
+)
@@ -31,6 +31,7 @@ type tagRow struct {
 	Name          null.String `db:"name"` // TODO: make schema non-nullable
 	Description   zero.String `db:"description"`
 	IgnoreAutoTag bool        `db:"ignore_auto_tag"`
+	AutoTagRules  zero.String `db:"auto_tag_rules"`
 	CreatedAt     Timestamp   `db:"created_at"`
 	UpdatedAt     Timestamp   `db:"updated_at"`
 
@@ -43,6 +44,7 @@ func (r *tagRow) fromTag(o models.Tag) {
 	r.Name = null.StringFrom(o.Name)
 	r.Description = zero.StringFrom(o.Description)
 	r.IgnoreAutoTag = o.IgnoreAutoTag
+	r.AutoTagRules = autoTagRulesToJSON(o.AutoTagRules)
 	r.CreatedAt = Timestamp{Timestamp: o.CreatedAt}
 	r.UpdatedAt = Timestamp{Timestamp: o.UpdatedAt}
 }
@@ -53,6 +55,7 @@ func (r *tagRow) resolve() *models.Tag {
 		Name:          r.Name.String,
 		Description:   r.Description.String,
 		IgnoreAutoTag: r.IgnoreAutoTag,
+		AutoTagRules:  autoTagRulesFromJSON(r.AutoTagRules),
 		CreatedAt:     r.CreatedAt.Timestamp,
 		UpdatedAt:     r.UpdatedAt.Timestamp,
 	}
@@ -82,6 +85,7 @@ func (r *tagRowRecord) fromPartial(o models.TagPartial) {
 	r.setString("name", o.Name)
 	r.setNullString("description", o.Description)
 	r.setBool("ignore_auto_tag", o.IgnoreAutoTag)
+	r.setAutoTagRules("auto_tag_rules", o.AutoTagRules)
 	r.setTimestamp("created_at", o.CreatedAt)
 	r.setTimestamp("updated_at", o.UpdatedAt)
 }
@@ -269,6 +273,18 @@ func (qb *TagStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*mode
 	return ret, nil
 }
 
+func (qb *TagStore) findBySubquery(ctx context.Context, sq *goqu.SelectDataset) ([]*models.Tag, error) {
+	table := qb.table()
+
+	q := qb.selectDataset().Where(
+		table.Col(idColumn).In(
+			sq,
+		),
+	)
+
+	return qb.getMany(ctx, q)
+}
+
 func (qb *TagStore) FindBySceneID(ctx context.Context, sceneID int) ([]*models.Tag, error) {
 	query := `
 		SELECT tags.* FROM tags
@@ -374,6 +390,20 @@ func (qb *TagStore) FindByNames(ctx context.Context, names []string, nocase bool
 	return ret, nil
 }
 
+func (qb *TagStore) FindByStashID(ctx context.Context, stashID models.StashID) ([]*models.Tag, error) {
+	sq := dialect.From(tagsStashIDsJoinTable).Select(tagsStashIDsJoinTable.Col(tagIDColumn)).Where(
+		tagsStashIDsJoinTable.Col("stash_id").Eq(stashID.StashID),
+		tagsStashIDsJoinTable.Col("endpoint").Eq(stashID.Endpoint),
+	)
+	ret, err := qb.findBySubquery(ctx, sq)
+
+	if err != nil {
+		return nil, fmt.Errorf("getting tags for stash ID %s: %w", stashID.StashID, err)
+	}
+
+	return ret, nil
+}
+
 func (qb *TagStore) FindByParentTagID(ctx context.Context, parentID int) ([]*models.Tag, error) {
 	query := `
 		SELECT tags.* FROM tags
@@ -443,6 +473,9 @@ func (qb *TagStore) QueryForAutoTag(ctx context.Context, words []string) ([]*mod
 		args = append(args, ww)
 	}
 
+	// tags with auto-tag rules may match regardless of their name
+	whereClauses = append(whereClauses, "tags.auto_tag_rules IS NOT NULL")
+
 	whereOr := "(" + strings.Join(whereClauses, " OR ") + ")"
 	where := strings.Join([]string{
 		"tags.ignore_auto_tag = 0",
@@ -500,6 +533,12 @@ func (qb *TagStore) makeFilter(ctx context.Context, tagFilter *models.TagFilterT
 
 	query.handleCriterion(ctx, stringCriterionHandler(tagFilter.Description, tagTable+".description"))
 	query.handleCriterion(ctx, boolCriterionHandler(tagFilter.IgnoreAutoTag, tagTable+".ignore_auto_tag", nil))
+	query.handleCriterion(ctx, &stashIDCriterionHandler{
+		c:                 tagFilter.StashIDEndpoint,
+		stashIDRepository: qb.stashIDRepository(),
+		stashIDTableAs:    "tag_stash_ids",
+		parentIDCol:       "tags.id",
+	})
 
 	query.handleCriterion(ctx, tagIsMissingCriterionHandler(qb, tagFilter.IsMissing))
 	query.handleCriterion(ctx, tagSceneCountCriterionHandler(qb, tagFilter.SceneCount))
@@ -575,6 +614,9 @@ func tagIsMissingCriterionHandler(qb *TagStore, isMissing *string) criterionHand
 			switch *isMissing {
 			case "image":
 				f.addWhere("tags.image_blob IS NULL")
+			case "stash_id":
+				qb.stashIDRepository().join(f, "tag_stash_ids", "tags.id")
+				f.addWhere("tag_stash_ids.tag_id IS NULL")
 			default:
 				f.addWhere("(tags." + *isMissing + " IS NULL OR TRIM(tags." + *isMissing + ") = '')")
 			}
@@ -959,6 +1001,24 @@ func (qb *TagStore) UpdateAliases(ctx context.Context, tagID int, aliases []stri
 	return qb.aliasRepository().replace(ctx, tagID, aliases)
 }
 
+func (qb *TagStore) stashIDRepository() *stashIDRepository {
+	return &stashIDRepository{
+		repository{
+			tx:        qb.tx,
+			tableName: "tag_stash_ids",
+			idColumn:  tagIDColumn,
+		},
+	}
+}
+
+func (qb *TagStore) GetStashIDs(ctx context.Context, tagID int) ([]models.StashID, error) {
+	return tagsStashIDsTableMgr.get(ctx, tagID)
+}
+
+func (qb *TagStore) UpdateStashIDs(ctx context.Context, tagID int, stashIDs []models.StashID) error {
+	return tagsStashIDsTableMgr.replaceJoins(ctx, tagID, stashIDs)
+}
+
 func (qb *TagStore) Merge(ctx context.Context, source []int, destination int) error {
 	if len(source) == 0 {
 		return nil
@@ -1018,6 +1078,13 @@ AND NOT EXISTS(SELECT 1 FROM `+table+` o WHERE o.`+idColumn+` = `+table+`.`+idCo
 		return err
 	}
 
+	// move stash ids that the destination doesn't already have
+	_, err = qb.tx.Exec(ctx, `UPDATE tag_stash_ids SET tag_id = ? WHERE tag_id IN `+inBinding+`
+AND NOT EXISTS(SELECT 1 FROM tag_stash_ids o WHERE o.tag_id = ? AND o.endpoint = tag_stash_ids.endpoint)`, args...)
+	if err != nil {
+		return err
+	}
+
 	for _, id := range source {
 		err = qb.Destroy(ctx, id)
 		if err != nil {
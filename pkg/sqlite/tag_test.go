@@ -826,6 +826,58 @@ func TestTagUpdateAlias(t *testing.T) {
 	}
 }
 
+func TestTagUpdateStashIDs(t *testing.T) {
+	if err := withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Tag
+
+		// create tag to test against
+		const name = "TestTagUpdateStashIDs"
+		tag := models.Tag{
+			Name: name,
+		}
+		if err := qb.Create(ctx, &tag); err != nil {
+			return fmt.Errorf("Error creating tag: %s", err.Error())
+		}
+
+		stashIDs := []models.StashID{
+			{
+				StashID:  "stashID",
+				Endpoint: "endpoint",
+			},
+		}
+		if err := qb.UpdateStashIDs(ctx, tag.ID, stashIDs); err != nil {
+			return fmt.Errorf("Error updating tag stash ids: %s", err.Error())
+		}
+
+		storedStashIDs, err := qb.GetStashIDs(ctx, tag.ID)
+		if err != nil {
+			return fmt.Errorf("Error getting stash ids: %s", err.Error())
+		}
+		assert.Equal(t, stashIDs, storedStashIDs)
+
+		found, err := qb.FindByStashID(ctx, stashIDs[0])
+		if err != nil {
+			return fmt.Errorf("Error finding by stash id: %s", err.Error())
+		}
+		assert.Len(t, found, 1)
+		assert.Equal(t, tag.ID, found[0].ID)
+
+		endpoint := "endpoint"
+		tags := queryTags(ctx, t, qb, &models.TagFilterType{
+			StashIDEndpoint: &models.StashIDCriterionInput{
+				Endpoint: &endpoint,
+				Modifier: models.CriterionModifierNotNull,
+			},
+		}, nil)
+		assert.Len(t, tags, 1)
+		assert.Equal(t, tag.ID, tags[0].ID)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
 func TestTagMerge(t *testing.T) {
 	assert := assert.New(t)
 
@@ -955,3 +1007,58 @@ func TestTagMerge(t *testing.T) {
 // TODO All
 // TODO AllSlim
 // TODO Query
+
+func TestTagUpdateAutoTagRules(t *testing.T) {
+	if err := withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Tag
+
+		// create tag to test against
+		const name = "TestTagUpdateAutoTagRules"
+		rules := &models.AutoTagRules{
+			Include:       []string{`abc[-_ ]?jd`},
+			Exclude:       []string{`/trailers/`},
+			Target:        models.AutoTagMatchTargetFilename,
+			MinWordLength: 3,
+		}
+		tag := models.Tag{
+			Name:         name,
+			AutoTagRules: rules,
+		}
+		if err := qb.Create(ctx, &tag); err != nil {
+			return fmt.Errorf("Error creating tag: %s", err.Error())
+		}
+
+		found, err := qb.Find(ctx, tag.ID)
+		if err != nil {
+			return fmt.Errorf("Error finding tag: %s", err.Error())
+		}
+		assert.Equal(t, rules, found.AutoTagRules)
+
+		// tags with rules are returned regardless of name
+		tags, err := qb.QueryForAutoTag(ctx, []string{"zz"})
+		if err != nil {
+			return fmt.Errorf("Error finding tags: %s", err.Error())
+		}
+		assert.Len(t, tags, 1)
+		if len(tags) > 0 {
+			assert.Equal(t, tag.ID, tags[0].ID)
+		}
+
+		// empty rules are removed
+		if _, err := qb.UpdatePartial(ctx, tag.ID, models.TagPartial{
+			AutoTagRules: &models.AutoTagRules{},
+		}); err != nil {
+			return fmt.Errorf("Error updating tag: %s", err.Error())
+		}
+
+		found, err = qb.Find(ctx, tag.ID)
+		if err != nil {
+			return fmt.Errorf("Error finding tag: %s", err.Error())
+		}
+		assert.Nil(t, found.AutoTagRules)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
@@ -125,18 +125,20 @@ func (db *Database) IsLocked(err error) bool {
 
 func (db *Database) Repository() models.Repository {
 	return models.Repository{
-		TxnManager:     db,
-		File:           db.File,
-		Folder:         db.Folder,
-		Gallery:        db.Gallery,
-		GalleryChapter: db.GalleryChapter,
-		Image:          db.Image,
-		Movie:          db.Movie,
-		Performer:      db.Performer,
-		Scene:          db.Scene,
-		SceneMarker:    db.SceneMarker,
-		Studio:         db.Studio,
-		Tag:            db.Tag,
-		SavedFilter:    db.SavedFilter,
+		TxnManager:            db,
+		File:                  db.File,
+		Folder:                db.Folder,
+		Gallery:               db.Gallery,
+		GalleryChapter:        db.GalleryChapter,
+		Image:                 db.Image,
+		Movie:                 db.Movie,
+		Performer:             db.Performer,
+		Scene:                 db.Scene,
+		SceneMarker:           db.SceneMarker,
+		Studio:                db.Studio,
+		Tag:                   db.Tag,
+		SavedFilter:           db.SavedFilter,
+		PendingSceneChange:    db.PendingSceneChange,
+		FingerprintSubmission: db.FingerprintSubmission,
 	}
 }
@@ -25,6 +25,7 @@ func ToJSON(ctx context.Context, reader FinderImageStashIDGetter, studio *models
 		URL:           studio.URL,
 		Details:       studio.Details,
 		IgnoreAutoTag: studio.IgnoreAutoTag,
+		AutoTagRules:  studio.AutoTagRules,
 		CreatedAt:     json.JSONTime{Time: studio.CreatedAt},
 		UpdatedAt:     json.JSONTime{Time: studio.UpdatedAt},
 	}
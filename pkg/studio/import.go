@@ -145,6 +145,7 @@ func studioJSONtoStudio(studioJSON jsonschema.Studio) models.Studio {
 		Aliases:       models.NewRelatedStrings(studioJSON.Aliases),
 		Details:       studioJSON.Details,
 		IgnoreAutoTag: studioJSON.IgnoreAutoTag,
+		AutoTagRules:  studioJSON.AutoTagRules,
 		CreatedAt:     studioJSON.CreatedAt.GetTime(),
 		UpdatedAt:     studioJSON.UpdatedAt.GetTime(),
 
@@ -14,6 +14,7 @@ import (
 type FinderAliasImageGetter interface {
 	GetAliases(ctx context.Context, studioID int) ([]string, error)
 	GetImage(ctx context.Context, tagID int) ([]byte, error)
+	GetStashIDs(ctx context.Context, tagID int) ([]models.StashID, error)
 	FindByChildTagID(ctx context.Context, childID int) ([]*models.Tag, error)
 }
 
@@ -23,6 +24,7 @@ func ToJSON(ctx context.Context, reader FinderAliasImageGetter, tag *models.Tag)
 		Name:          tag.Name,
 		Description:   tag.Description,
 		IgnoreAutoTag: tag.IgnoreAutoTag,
+		AutoTagRules:  tag.AutoTagRules,
 		CreatedAt:     json.JSONTime{Time: tag.CreatedAt},
 		UpdatedAt:     json.JSONTime{Time: tag.UpdatedAt},
 	}
@@ -34,6 +36,13 @@ func ToJSON(ctx context.Context, reader FinderAliasImageGetter, tag *models.Tag)
 
 	newTagJSON.Aliases = aliases
 
+	stashIDs, err := reader.GetStashIDs(ctx, tag.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting tag stash ids: %v", err)
+	}
+
+	newTagJSON.StashIDs = stashIDs
+
 	image, err := reader.GetImage(ctx, tag.ID)
 	if err != nil {
 		logger.Errorf("Error getting tag image: %v", err)
@@ -31,6 +31,12 @@ var (
 	autoTagIgnored = true
 	createTime     = time.Date(2001, 01, 01, 0, 0, 0, 0, time.UTC)
 	updateTime     = time.Date(2002, 01, 01, 0, 0, 0, 0, time.UTC)
+	stashIDs       = []models.StashID{
+		{
+			StashID:  "stashID",
+			Endpoint: "endpoint",
+		},
+	}
 )
 
 func createTag(id int) models.Tag {
@@ -44,7 +50,7 @@ func createTag(id int) models.Tag {
 	}
 }
 
-func createJSONTag(aliases []string, image string, parents []string) *jsonschema.Tag {
+func createJSONTag(aliases []string, image string, parents []string, stashIDs []models.StashID) *jsonschema.Tag {
 	return &jsonschema.Tag{
 		Name:          tagName,
 		Description:   description,
@@ -56,8 +62,9 @@ func createJSONTag(aliases []string, image string, parents []string) *jsonschema
 		UpdatedAt: json.JSONTime{
 			Time: updateTime,
 		},
-		Image:   image,
-		Parents: parents,
+		Image:    image,
+		Parents:  parents,
+		StashIDs: stashIDs,
 	}
 }
 
@@ -73,17 +80,17 @@ func initTestTable() {
 	scenarios = []testScenario{
 		{
 			createTag(tagID),
-			createJSONTag([]string{"alias"}, image, nil),
+			createJSONTag([]string{"alias"}, image, nil, stashIDs),
 			false,
 		},
 		{
 			createTag(noImageID),
-			createJSONTag(nil, "", nil),
+			createJSONTag(nil, "", nil, nil),
 			false,
 		},
 		{
 			createTag(errImageID),
-			createJSONTag(nil, "", nil),
+			createJSONTag(nil, "", nil, nil),
 			// getting the image should not cause an error
 			false,
 		},
@@ -94,7 +101,7 @@ func initTestTable() {
 		},
 		{
 			createTag(withParentsID),
-			createJSONTag(nil, image, []string{"parent"}),
+			createJSONTag(nil, image, []string{"parent"}, nil),
 			false,
 		},
 		{
@@ -121,6 +128,12 @@ func TestToJSON(t *testing.T) {
 	db.Tag.On("GetAliases", testCtx, withParentsID).Return(nil, nil).Once()
 	db.Tag.On("GetAliases", testCtx, errParentsID).Return(nil, nil).Once()
 
+	db.Tag.On("GetStashIDs", testCtx, tagID).Return(stashIDs, nil).Once()
+	db.Tag.On("GetStashIDs", testCtx, noImageID).Return(nil, nil).Once()
+	db.Tag.On("GetStashIDs", testCtx, errImageID).Return(nil, nil).Once()
+	db.Tag.On("GetStashIDs", testCtx, withParentsID).Return(nil, nil).Once()
+	db.Tag.On("GetStashIDs", testCtx, errParentsID).Return(nil, nil).Once()
+
 	db.Tag.On("GetImage", testCtx, tagID).Return(imageBytes, nil).Once()
 	db.Tag.On("GetImage", testCtx, noImageID).Return(nil, nil).Once()
 	db.Tag.On("GetImage", testCtx, errImageID).Return(nil, imageErr).Once()
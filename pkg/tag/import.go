@@ -40,6 +40,7 @@ func (i *Importer) PreImport(ctx context.Context) error {
 		Name:          i.Input.Name,
 		Description:   i.Input.Description,
 		IgnoreAutoTag: i.Input.IgnoreAutoTag,
+		AutoTagRules:  i.Input.AutoTagRules,
 		CreatedAt:     i.Input.CreatedAt.GetTime(),
 		UpdatedAt:     i.Input.UpdatedAt.GetTime(),
 	}
@@ -66,6 +67,10 @@ func (i *Importer) PostImport(ctx context.Context, id int) error {
 		return fmt.Errorf("error setting tag aliases: %v", err)
 	}
 
+	if err := i.ReaderWriter.UpdateStashIDs(ctx, id, i.Input.StashIDs); err != nil {
+		return fmt.Errorf("error setting tag stash ids: %v", err)
+	}
+
 	parents, err := i.getParents(ctx)
 	if err != nil {
 		return err
@@ -63,7 +63,8 @@ func TestImporterPostImport(t *testing.T) {
 	i := Importer{
 		ReaderWriter: db.Tag,
 		Input: jsonschema.Tag{
-			Aliases: []string{"alias"},
+			Aliases:  []string{"alias"},
+			StashIDs: stashIDs,
 		},
 		imageData: imageBytes,
 	}
@@ -77,6 +78,10 @@ func TestImporterPostImport(t *testing.T) {
 	db.Tag.On("UpdateAliases", testCtx, withParentsID, i.Input.Aliases).Return(nil).Once()
 	db.Tag.On("UpdateAliases", testCtx, errParentsID, i.Input.Aliases).Return(nil).Once()
 
+	db.Tag.On("UpdateStashIDs", testCtx, tagID, i.Input.StashIDs).Return(nil).Once()
+	db.Tag.On("UpdateStashIDs", testCtx, withParentsID, i.Input.StashIDs).Return(nil).Once()
+	db.Tag.On("UpdateStashIDs", testCtx, errParentsID, i.Input.StashIDs).Return(nil).Once()
+
 	db.Tag.On("UpdateImage", testCtx, tagID, imageBytes).Return(nil).Once()
 	db.Tag.On("UpdateImage", testCtx, errAliasID, imageBytes).Return(nil).Once()
 	db.Tag.On("UpdateImage", testCtx, errImageID, imageBytes).Return(updateTagImageErr).Once()
@@ -135,6 +140,7 @@ func TestImporterPostImportParentMissing(t *testing.T) {
 
 	db.Tag.On("UpdateImage", testCtx, mock.Anything, mock.Anything).Return(nil)
 	db.Tag.On("UpdateAliases", testCtx, mock.Anything, mock.Anything).Return(nil)
+	db.Tag.On("UpdateStashIDs", testCtx, mock.Anything, mock.Anything).Return(nil)
 
 	db.Tag.On("FindByName", testCtx, "Create", false).Return(nil, nil).Once()
 	db.Tag.On("FindByName", testCtx, "CreateError", false).Return(nil, nil).Once()
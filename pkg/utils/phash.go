@@ -2,6 +2,7 @@ package utils
 
 import (
 	"math"
+	"sort"
 	"strconv"
 
 	"github.com/corona10/goimagehash"
@@ -16,24 +17,35 @@ type Phash struct {
 	Bucket    int
 }
 
+// FindDuplicates returns groups of IDs whose hashes are within distance of each other.
+// If durationDiff is not negative, hashes are only compared if their durations differ by
+// at most durationDiff.
 func FindDuplicates(hashes []*Phash, distance int, durationDiff float64) [][]int {
-	for i, scene := range hashes {
+	forEachPhashCandidate(hashes, distance, func(i, j int) {
+		scene, neighbor := hashes[i], hashes[j]
+		if scene.SceneID == neighbor.SceneID {
+			return
+		}
+
+		neighbourDurationDistance := 0.
+		if scene.Duration > 0 && neighbor.Duration > 0 {
+			neighbourDurationDistance = math.Abs(scene.Duration - neighbor.Duration)
+		}
+		if (neighbourDurationDistance > durationDiff) && (durationDiff >= 0) {
+			return
+		}
+
 		sceneHash := goimagehash.NewImageHash(uint64(scene.Hash), goimagehash.PHash)
-		for j, neighbor := range hashes {
-			if i != j && scene.SceneID != neighbor.SceneID {
-				neighbourDurationDistance := 0.
-				if scene.Duration > 0 && neighbor.Duration > 0 {
-					neighbourDurationDistance = math.Abs(scene.Duration - neighbor.Duration)
-				}
-				if (neighbourDurationDistance <= durationDiff) || (durationDiff < 0) {
-					neighborHash := goimagehash.NewImageHash(uint64(neighbor.Hash), goimagehash.PHash)
-					neighborDistance, _ := sceneHash.Distance(neighborHash)
-					if neighborDistance <= distance {
-						scene.Neighbors = append(scene.Neighbors, j)
-					}
-				}
-			}
+		neighborHash := goimagehash.NewImageHash(uint64(neighbor.Hash), goimagehash.PHash)
+		neighborDistance, _ := sceneHash.Distance(neighborHash)
+		if neighborDistance <= distance {
+			scene.Neighbors = append(scene.Neighbors, j)
+			neighbor.Neighbors = append(neighbor.Neighbors, i)
 		}
+	})
+
+	for _, scene := range hashes {
+		sort.Ints(scene.Neighbors)
 	}
 
 	var buckets [][]int
@@ -53,6 +65,67 @@ func FindDuplicates(hashes []*Phash, distance int, durationDiff float64) [][]int
 	return buckets
 }
 
+// forEachPhashCandidate calls fn once for each pair of indexes into hashes, i < j,
+// that may be within distance of each other. The hashes are split into distance+1
+// bands: if two hashes differ in at most distance bits, then at least one band must
+// be equal, so only hashes that share a band value need to be compared. Pairs are
+// compared while scanning the band index, and pairs that share an earlier band are
+// skipped, so that the pairs do not need to be stored.
+func forEachPhashCandidate(hashes []*Phash, distance int, fn func(i, j int)) {
+	bands := distance + 1
+	if bands < 1 {
+		bands = 1
+	}
+	if bands > 64 {
+		bands = 64
+	}
+	bandWidth := 64 / bands
+
+	type bandKey struct {
+		band  int
+		value uint64
+	}
+
+	index := make(map[bandKey][]int)
+	for i, h := range hashes {
+		for b := 0; b < bands; b++ {
+			k := bandKey{band: b, value: hashBand(uint64(h.Hash), b, bandWidth)}
+			index[k] = append(index[k], i)
+		}
+	}
+
+	for k, ids := range index {
+		for x := range ids {
+			hx := uint64(hashes[ids[x]].Hash)
+			for y := x + 1; y < len(ids); y++ {
+				hy := uint64(hashes[ids[y]].Hash)
+				if sharesEarlierBand(hx, hy, k.band, bandWidth) {
+					continue
+				}
+
+				fn(ids[x], ids[y])
+			}
+		}
+	}
+}
+
+// sharesEarlierBand returns true if a and b have the same value in a band before band.
+func sharesEarlierBand(a, b uint64, band int, width int) bool {
+	for e := 0; e < band; e++ {
+		if hashBand(a, e, width) == hashBand(b, e, width) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hashBand returns the bits of band of h, where each band is width bits wide.
+func hashBand(h uint64, band int, width int) uint64 {
+	mask := uint64(1)<<uint(width) - 1
+	return (h >> uint(band*width)) & mask
+}
+
 func findNeighbors(bucket int, neighbors []int, hashes []*Phash, scenes *[]int) {
 	for _, id := range neighbors {
 		hash := hashes[id]
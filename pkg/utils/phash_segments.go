@@ -0,0 +1,326 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PhashSegments is a sequence of phashes of single video frames, taken at a fixed interval.
+// The first frame is taken at half of the interval.
+type PhashSegments struct {
+	// Interval between frames in seconds
+	Interval float64
+	Hashes   []uint64
+}
+
+// String returns the string representation of the segments, in the form
+// "<interval>:<hash>,<hash>,...", where the hashes are in hexadecimal.
+func (s PhashSegments) String() string {
+	var sb strings.Builder
+	sb.WriteString(strconv.FormatFloat(s.Interval, 'f', -1, 64))
+	sb.WriteByte(':')
+	for i, h := range s.Hashes {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(strconv.FormatUint(h, 16))
+	}
+
+	return sb.String()
+}
+
+// ParsePhashSegments parses the string representation of PhashSegments.
+func ParsePhashSegments(v string) (*PhashSegments, error) {
+	intervalStr, hashesStr, found := strings.Cut(v, ":")
+	if !found {
+		return nil, errors.New("missing interval")
+	}
+
+	interval, err := strconv.ParseFloat(intervalStr, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing interval %q: %w", intervalStr, err)
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid interval %v", interval)
+	}
+
+	ret := &PhashSegments{
+		Interval: interval,
+	}
+
+	if hashesStr == "" {
+		return ret, nil
+	}
+
+	for _, hs := range strings.Split(hashesStr, ",") {
+		h, err := strconv.ParseUint(hs, 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing hash %q: %w", hs, err)
+		}
+		ret.Hashes = append(ret.Hashes, h)
+	}
+
+	return ret, nil
+}
+
+// PhashSegmentsEntry is the PhashSegments of an object with the given ID.
+type PhashSegmentsEntry struct {
+	ID       int
+	Segments PhashSegments
+}
+
+// SegmentOverlap is a part of the object with ID that is also found in the object with OtherID.
+type SegmentOverlap struct {
+	ID      int
+	OtherID int
+	// Start of the overlap in the object with ID, in seconds
+	Start float64
+	// Start of the overlap in the object with OtherID, in seconds
+	OtherStart float64
+	// Duration of the overlap in seconds
+	Duration float64
+}
+
+// Offset returns the position in the other object at which the object starts, in seconds.
+func (o SegmentOverlap) Offset() float64 {
+	return o.OtherStart - o.Start
+}
+
+const (
+	// the maximum number of consecutive segments that may not match within an overlap
+	maxSegmentGap = 1
+
+	// hashes that are found in more entries than this are ignored when finding
+	// candidates. These are usually black or title frames.
+	maxCommonHashEntries = 20
+
+	maxHashBands = 4
+)
+
+type segmentKey struct {
+	entry int
+	index int
+}
+
+type segmentCandidate struct {
+	a, b int
+	// index in b minus index in a
+	shift int
+}
+
+// FindSegmentOverlaps returns the overlaps between entries of at least minOverlap seconds,
+// where the segment hashes are within the given distance of each other. Only entries with
+// the same interval are compared. At most one overlap is returned for each pair of IDs,
+// with ID being the shorter of the two entries. Overlaps are ordered by duration, longest first.
+func FindSegmentOverlaps(entries []PhashSegmentsEntry, distance int, minOverlap float64) []SegmentOverlap {
+	// candidates are found using bands of the hashes: if two hashes are within
+	// distance, then at least one of distance+1 bands must be equal. The number of
+	// bands is limited to keep the bands selective, so for larger distances
+	// overlaps are only found if at least one pair of segments is close enough.
+	bands := distance + 1
+	if bands > maxHashBands {
+		bands = maxHashBands
+	}
+	bandWidth := 64 / bands
+
+	type bandKey struct {
+		band  int
+		value uint64
+	}
+
+	common := commonHashes(entries)
+
+	index := make(map[bandKey][]segmentKey)
+	for i, e := range entries {
+		for j, h := range e.Segments.Hashes {
+			if common[h] {
+				continue
+			}
+
+			for b := 0; b < bands; b++ {
+				k := bandKey{band: b, value: hashBand(h, b, bandWidth)}
+				index[k] = append(index[k], segmentKey{entry: i, index: j})
+			}
+		}
+	}
+
+	candidates := make(map[segmentCandidate]struct{})
+	for _, keys := range index {
+		for x := range keys {
+			for y := x + 1; y < len(keys); y++ {
+				ka, kb := keys[x], keys[y]
+				if ka.entry == kb.entry {
+					continue
+				}
+
+				if ka.entry > kb.entry {
+					ka, kb = kb, ka
+				}
+
+				candidates[segmentCandidate{a: ka.entry, b: kb.entry, shift: kb.index - ka.index}] = struct{}{}
+			}
+		}
+	}
+
+	type pair struct {
+		a, b int
+	}
+
+	best := make(map[pair]SegmentOverlap)
+	for c := range candidates {
+		ea, eb := entries[c.a], entries[c.b]
+		if ea.ID == eb.ID || ea.Segments.Interval != eb.Segments.Interval {
+			continue
+		}
+
+		start, length := longestSegmentRun(ea.Segments.Hashes, eb.Segments.Hashes, c.shift, distance)
+		interval := ea.Segments.Interval
+		duration := float64(length) * interval
+		if length == 0 || duration < minOverlap {
+			continue
+		}
+
+		o := SegmentOverlap{
+			ID:         ea.ID,
+			OtherID:    eb.ID,
+			Start:      float64(start) * interval,
+			OtherStart: float64(start+c.shift) * interval,
+			Duration:   duration,
+		}
+
+		// report the overlap from the shorter entry
+		if len(ea.Segments.Hashes) > len(eb.Segments.Hashes) {
+			o.ID, o.OtherID = o.OtherID, o.ID
+			o.Start, o.OtherStart = o.OtherStart, o.Start
+		}
+
+		p := pair{a: o.ID, b: o.OtherID}
+		if existing, found := best[p]; !found || o.Duration > existing.Duration {
+			best[p] = o
+		}
+	}
+
+	ret := make([]SegmentOverlap, 0, len(best))
+	for _, o := range best {
+		ret = append(ret, o)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Duration != ret[j].Duration {
+			return ret[i].Duration > ret[j].Duration
+		}
+		if ret[i].ID != ret[j].ID {
+			return ret[i].ID < ret[j].ID
+		}
+		return ret[i].OtherID < ret[j].OtherID
+	})
+
+	return ret
+}
+
+// GroupSegmentOverlaps returns the groups of IDs that are connected by overlaps.
+func GroupSegmentOverlaps(overlaps []SegmentOverlap) [][]int {
+	parent := make(map[int]int)
+	var find func(id int) int
+	find = func(id int) int {
+		p, found := parent[id]
+		if !found || p == id {
+			parent[id] = id
+			return id
+		}
+
+		root := find(p)
+		parent[id] = root
+		return root
+	}
+
+	var ids []int
+	for _, o := range overlaps {
+		for _, id := range []int{o.ID, o.OtherID} {
+			if _, found := parent[id]; !found {
+				ids = append(ids, id)
+			}
+		}
+
+		parent[find(o.ID)] = find(o.OtherID)
+	}
+
+	groupIndex := make(map[int]int)
+	var ret [][]int
+	for _, id := range ids {
+		root := find(id)
+		i, found := groupIndex[root]
+		if !found {
+			i = len(ret)
+			groupIndex[root] = i
+			ret = append(ret, nil)
+		}
+
+		ret[i] = append(ret[i], id)
+	}
+
+	return ret
+}
+
+// commonHashes returns the hashes that are found in more than maxCommonHashEntries entries.
+func commonHashes(entries []PhashSegmentsEntry) map[uint64]bool {
+	counts := make(map[uint64]int)
+	for _, e := range entries {
+		seen := make(map[uint64]bool)
+		for _, h := range e.Segments.Hashes {
+			if !seen[h] {
+				seen[h] = true
+				counts[h]++
+			}
+		}
+	}
+
+	ret := make(map[uint64]bool)
+	for h, c := range counts {
+		if c > maxCommonHashEntries {
+			ret[h] = true
+		}
+	}
+
+	return ret
+}
+
+// longestSegmentRun returns the start index in a and length of the longest run of
+// segments of a matching the segments of b, where a[i] is compared to b[i+shift].
+// Up to maxSegmentGap consecutive segments within a run may not match.
+func longestSegmentRun(a, b []uint64, shift int, distance int) (start int, length int) {
+	runStart := -1
+	lastMatch := -1
+
+	for i := range a {
+		j := i + shift
+		if j < 0 {
+			continue
+		}
+		if j >= len(b) {
+			break
+		}
+
+		if bits.OnesCount64(a[i]^b[j]) > distance {
+			if runStart != -1 && i-lastMatch > maxSegmentGap {
+				runStart = -1
+			}
+			continue
+		}
+
+		if runStart == -1 {
+			runStart = i
+		}
+		lastMatch = i
+
+		if l := lastMatch - runStart + 1; l > length {
+			start, length = runStart, l
+		}
+	}
+
+	return start, length
+}
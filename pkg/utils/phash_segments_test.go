@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhashSegments_String(t *testing.T) {
+	s := PhashSegments{
+		Interval: 2.5,
+		Hashes:   []uint64{0xff, 0, 0xffffffffffffffff},
+	}
+
+	str := s.String()
+	assert.Equal(t, "2.5:ff,0,ffffffffffffffff", str)
+
+	got, err := ParsePhashSegments(str)
+	if assert.Nil(t, err) {
+		assert.Equal(t, s, *got)
+	}
+
+	for _, invalid := range []string{"", "ff,0", "0:ff", "x:ff", "5:ff,xyz"} {
+		_, err := ParsePhashSegments(invalid)
+		assert.NotNil(t, err, "ParsePhashSegments(%q)", invalid)
+	}
+}
+
+func randomHashes(r *rand.Rand, n int) []uint64 {
+	ret := make([]uint64, n)
+	for i := range ret {
+		ret[i] = r.Uint64()
+	}
+	return ret
+}
+
+func TestFindSegmentOverlaps(t *testing.T) {
+	r := rand.New(rand.NewSource(1234))
+
+	const interval = 5
+
+	full := randomHashes(r, 100)
+
+	// sub-clip of full, starting at segment 30, with one bit changed in each hash
+	// and one segment not matching
+	clip := make([]uint64, 40)
+	for i := range clip {
+		clip[i] = full[30+i] ^ 1
+	}
+	clip[10] = r.Uint64()
+
+	// the end of full, followed by new content
+	trimmed := append(append([]uint64{}, full[80:]...), randomHashes(r, 20)...)
+
+	unrelated := randomHashes(r, 100)
+
+	entries := []PhashSegmentsEntry{
+		{ID: 1, Segments: PhashSegments{Interval: interval, Hashes: full}},
+		{ID: 2, Segments: PhashSegments{Interval: interval, Hashes: clip}},
+		{ID: 3, Segments: PhashSegments{Interval: interval, Hashes: trimmed}},
+		{ID: 4, Segments: PhashSegments{Interval: interval, Hashes: unrelated}},
+		// different interval is not compared
+		{ID: 5, Segments: PhashSegments{Interval: interval * 2, Hashes: full}},
+	}
+
+	t.Run("exact", func(t *testing.T) {
+		got := FindSegmentOverlaps(entries, 0, 30)
+		assert.Equal(t, []SegmentOverlap{
+			{ID: 3, OtherID: 1, Start: 0, OtherStart: 80 * interval, Duration: 20 * interval},
+		}, got)
+	})
+
+	t.Run("distance", func(t *testing.T) {
+		got := FindSegmentOverlaps(entries, 1, 30)
+		assert.Equal(t, []SegmentOverlap{
+			{ID: 2, OtherID: 1, Start: 0, OtherStart: 30 * interval, Duration: 40 * interval},
+			{ID: 3, OtherID: 1, Start: 0, OtherStart: 80 * interval, Duration: 20 * interval},
+		}, got)
+
+		assert.Equal(t, 30.*interval, got[0].Offset())
+	})
+
+	t.Run("min overlap", func(t *testing.T) {
+		got := FindSegmentOverlaps(entries, 1, 150)
+		assert.Len(t, got, 1)
+	})
+
+	t.Run("group", func(t *testing.T) {
+		got := GroupSegmentOverlaps(FindSegmentOverlaps(entries, 1, 30))
+		if assert.Len(t, got, 1) {
+			assert.ElementsMatch(t, []int{1, 2, 3}, got[0])
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"math/bits"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPhashes(hashes []uint64, durations []float64) []*Phash {
+	ret := make([]*Phash, len(hashes))
+	for i, h := range hashes {
+		ret[i] = &Phash{
+			SceneID:  i + 1,
+			Hash:     int64(h),
+			Duration: durations[i],
+			Bucket:   -1,
+		}
+	}
+	return ret
+}
+
+func TestFindDuplicates(t *testing.T) {
+	const base = 0x0123456789abcdef
+
+	hashes := []uint64{
+		base,
+		base ^ 0x8000000000000001, // distance 2 from base
+		0xfedcba9876543210,
+		0xfedcba9876543210 ^ 0x0001000000000000, // distance 1 from previous
+		0x5555555555555555,
+	}
+	durations := []float64{10, 10, 20, 50, 30}
+
+	got := FindDuplicates(newTestPhashes(hashes, durations), 2, -1)
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}}, got)
+
+	got = FindDuplicates(newTestPhashes(hashes, durations), 1, -1)
+	assert.Equal(t, [][]int{{3, 4}}, got)
+
+	got = FindDuplicates(newTestPhashes(hashes, durations), 2, 5)
+	assert.Equal(t, [][]int{{1, 2}}, got)
+}
+
+func TestFindDuplicates_Exhaustive(t *testing.T) {
+	r := rand.New(rand.NewSource(1234))
+
+	// hashes near a few centres, so that there are matches at all distances
+	centres := []uint64{r.Uint64(), r.Uint64(), r.Uint64()}
+	var hashes []uint64
+	for i := 0; i < 200; i++ {
+		h := centres[r.Intn(len(centres))]
+		for n := r.Intn(12); n > 0; n-- {
+			h ^= 1 << uint(r.Intn(64))
+		}
+		hashes = append(hashes, h)
+	}
+
+	for _, distance := range []int{0, 1, 4, 10} {
+		var want [][2]int
+		for i := range hashes {
+			for j := i + 1; j < len(hashes); j++ {
+				if bits.OnesCount64(hashes[i]^hashes[j]) <= distance {
+					want = append(want, [2]int{i, j})
+				}
+			}
+		}
+
+		candidates := make(map[[2]int]bool)
+		forEachPhashCandidate(newTestPhashes(hashes, make([]float64, len(hashes))), distance, func(i, j int) {
+			c := [2]int{i, j}
+			assert.False(t, candidates[c], "distance %d: pair %v reported more than once", distance, c)
+			candidates[c] = true
+		})
+
+		for _, p := range want {
+			assert.True(t, candidates[p], "distance %d: pair %v not a candidate", distance, p)
+		}
+	}
+}